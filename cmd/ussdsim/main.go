@@ -0,0 +1,102 @@
+// Command ussdsim plays the part of a USSD gateway so the USSD menu can be
+// tried against a running API without a carrier:
+//
+//	go run ./cmd/ussdsim -phone +244923000000
+//
+// Each line typed is sent as the next input of the session, the way
+// Africa's Talking-style gateways do, and the screen returned is printed.
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const requestTimeout = 10 * time.Second
+
+func main() {
+	os.Exit(run())
+}
+
+func run() int {
+	endpoint := flag.String("url", "http://localhost:8000/api/v1/ussd", "USSD webhook URL")
+	phone := flag.String("phone", "+244923000000", "caller phone number")
+	serviceCode := flag.String("code", "*123#", "dialled USSD code")
+	token := flag.String("token", os.Getenv("USSD_GATEWAY_TOKEN"), "gateway token (defaults to $USSD_GATEWAY_TOKEN)")
+	flag.Parse()
+
+	sessionID := newSessionID()
+	client := &http.Client{Timeout: requestTimeout}
+	stdin := bufio.NewScanner(os.Stdin)
+
+	var inputs []string
+	for {
+		screen, err := send(client, *endpoint, *token, url.Values{
+			"sessionId":   {sessionID},
+			"phoneNumber": {*phone},
+			"serviceCode": {*serviceCode},
+			"text":        {strings.Join(inputs, "*")},
+		})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "ussdsim:", err)
+			return 1
+		}
+
+		end := strings.HasPrefix(screen, "END ")
+		fmt.Println(strings.TrimPrefix(strings.TrimPrefix(screen, "END "), "CON "))
+		if end {
+			return 0
+		}
+
+		fmt.Print("> ")
+		if !stdin.Scan() {
+			fmt.Println()
+			return 0
+		}
+		inputs = append(inputs, strings.TrimSpace(stdin.Text()))
+	}
+}
+
+func send(client *http.Client, endpoint, token string, form url.Values) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Gateway-Token", token)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("gateway callback returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	return string(body), nil
+}
+
+func newSessionID() string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return "sim-" + hex.EncodeToString(buf)
+}
@@ -29,6 +29,10 @@ func (a *redisCacheAdapter) Set(ctx context.Context, key, value string, ttl time
 	return a.redis.Set(ctx, key, value, ttl)
 }
 
+func (a *redisCacheAdapter) SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error) {
+	return a.redis.SetNX(ctx, key, value, ttl)
+}
+
 func (a *redisCacheAdapter) Delete(ctx context.Context, key string) error {
 	return a.redis.Delete(ctx, key)
 }
@@ -0,0 +1,102 @@
+package eventlistener
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/risk-place-angola/backend-risk-place/internal/adapter/service"
+	"github.com/risk-place-angola/backend-risk-place/internal/adapter/websocket"
+	"github.com/risk-place-angola/backend-risk-place/internal/application/port"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/event"
+	domainrepository "github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
+)
+
+func registerDangerZoneEnteredHandler(
+	dispatcher port.EventDispatcher,
+	hub *websocket.Hub,
+	userRepo domainrepository.UserRepository,
+	anonymousSessionRepo domainrepository.AnonymousSessionRepository,
+	notifierPush port.NotifierPushService,
+	translationService *service.TranslationService,
+) {
+	// warning renders the warning in the recipient's language, with the push
+	// token to send it to.
+	warning := func(ctx context.Context, e event.Event) (event.DangerZoneEnteredEvent, string, service.NotificationMessage, map[string]string, error) {
+		ev, ok := e.(event.DangerZoneEnteredEvent)
+		if !ok {
+			return ev, "", service.NotificationMessage{}, nil, errors.New("failed to cast event to DangerZoneEnteredEvent")
+		}
+
+		token, language := resolveDirectRecipient(ctx, userRepo, anonymousSessionRepo, ev.UserID, ev.DeviceID)
+		lang := translationService.ParseLanguage(language)
+
+		msg := translationService.Render("danger_zone_entered", lang, "", service.Params{
+			"count":      ev.IncidentCount,
+			"risk_level": riskLevelLabel(translationService, ev.RiskLevel, lang),
+		})
+
+		severity := "high"
+		if ev.RiskLevel == severityCritical {
+			severity = severityCritical
+		}
+
+		data := map[string]string{
+			"type":           "danger_zone_entered",
+			"zone_id":        ev.ZoneID.String(),
+			"grid_cell_id":   ev.GridCellID,
+			"risk_level":     ev.RiskLevel,
+			"incident_count": fmt.Sprintf("%d", ev.IncidentCount),
+			"latitude":       fmt.Sprintf("%f", ev.Latitude),
+			"longitude":      fmt.Sprintf("%f", ev.Longitude),
+			"message":        msg.Body,
+			// Entering another zone replaces the previous warning.
+			port.PushDataGroupKey: "danger_zone_entered",
+			port.PushDataSeverity: severity,
+		}
+
+		return ev, token, msg, data, nil
+	}
+
+	dispatcher.Register(event.DangerZoneEnteredEvent{}.Name(), "websocket", func(ctx context.Context, e event.Event) error {
+		ev, _, _, data, err := warning(ctx, e)
+		if err != nil {
+			return err
+		}
+
+		clientID := ev.DeviceID
+		if ev.UserID != uuid.Nil {
+			clientID = ev.UserID.String()
+		}
+		hub.NotifyUser(clientID, "danger_zone_entered", data)
+		return nil
+	})
+
+	dispatcher.Register(event.DangerZoneEnteredEvent{}.Name(), "push", func(ctx context.Context, e event.Event) error {
+		ev, token, msg, data, err := warning(ctx, e)
+		if err != nil {
+			return err
+		}
+
+		if token == "" {
+			slog.Debug("no push token for danger zone warning", "user_id", ev.UserID.String(), "device_id", ev.DeviceID)
+			return nil
+		}
+
+		if err := notifierPush.NotifyPush(ctx, token, msg.Title, msg.Body, data); err != nil {
+			return fmt.Errorf("failed to send danger zone warning: %w", err)
+		}
+		return nil
+	})
+}
+
+// riskLevelLabel renders a danger zone risk level such as "critical" in lang,
+// falling back to the level itself.
+func riskLevelLabel(translationService *service.TranslationService, level string, lang service.Language) string {
+	if msg, ok := translationService.Lookup("risk_level_"+level, lang, "", nil); ok {
+		return msg.Body
+	}
+	return level
+}
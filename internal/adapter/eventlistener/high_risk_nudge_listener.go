@@ -0,0 +1,149 @@
+package eventlistener
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/risk-place-angola/backend-risk-place/internal/adapter/service"
+	"github.com/risk-place-angola/backend-risk-place/internal/adapter/websocket"
+	"github.com/risk-place-angola/backend-risk-place/internal/application/port"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/event"
+	domainrepository "github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
+)
+
+const maxNudgeRiskTypes = 2
+
+func registerHighRiskNudgeHandler(
+	dispatcher port.EventDispatcher,
+	hub *websocket.Hub,
+	userRepo domainrepository.UserRepository,
+	anonymousSessionRepo domainrepository.AnonymousSessionRepository,
+	notifierPush port.NotifierPushService,
+	translationService *service.TranslationService,
+) {
+	// nudge renders the nudge in the recipient's language, with the push
+	// token to send it to.
+	nudge := func(ctx context.Context, e event.Event) (event.HighRiskNudgeEvent, string, service.NotificationMessage, map[string]string, error) {
+		ev, ok := e.(event.HighRiskNudgeEvent)
+		if !ok {
+			return ev, "", service.NotificationMessage{}, nil, errors.New("failed to cast event to HighRiskNudgeEvent")
+		}
+
+		token, language := resolveDirectRecipient(ctx, userRepo, anonymousSessionRepo, ev.UserID, ev.DeviceID)
+		lang := translationService.ParseLanguage(language)
+
+		var msg service.NotificationMessage
+		if ev.IncidentCount > 0 {
+			msg = translationService.Render("high_risk_nudge", lang, "", service.Params{
+				"count":      ev.IncidentCount,
+				"risk_types": summarizeRiskTypes(ev.RiskTypeCounts),
+				"distance":   int(ev.NearestIncidentMeters),
+			})
+		} else {
+			msg = translationService.Render("high_risk_nudge_zone", lang, "", service.Params{
+				"risk_level": riskLevelLabel(translationService, ev.DangerZoneRiskLevel, lang),
+			})
+		}
+
+		data := map[string]string{
+			"type":              "high_risk_nudge",
+			"incident_count":    fmt.Sprintf("%d", ev.IncidentCount),
+			"danger_zone_level": ev.DangerZoneRiskLevel,
+			"message":           msg.Body,
+			// One nudge at a time; a newer one replaces it.
+			port.PushDataGroupKey: "high_risk_nudge",
+		}
+
+		return ev, token, msg, data, nil
+	}
+
+	dispatcher.Register("HighRiskNudge", "websocket", func(ctx context.Context, e event.Event) error {
+		ev, _, _, data, err := nudge(ctx, e)
+		if err != nil {
+			return err
+		}
+
+		clientID := ev.DeviceID
+		if ev.UserID != uuid.Nil {
+			clientID = ev.UserID.String()
+		}
+		hub.NotifyUser(clientID, "high_risk_nudge", data)
+		return nil
+	})
+
+	dispatcher.Register("HighRiskNudge", "push", func(ctx context.Context, e event.Event) error {
+		ev, token, msg, data, err := nudge(ctx, e)
+		if err != nil {
+			return err
+		}
+
+		if token == "" {
+			slog.Debug("no push token for high risk nudge", "user_id", ev.UserID.String(), "device_id", ev.DeviceID)
+			return nil
+		}
+
+		if err := notifierPush.NotifyPush(ctx, token, msg.Title, msg.Body, data); err != nil {
+			return fmt.Errorf("failed to send high risk nudge: %w", err)
+		}
+		return nil
+	})
+}
+
+// resolveDirectRecipient finds the push token and language of a single
+// user, or of an anonymous device when userID is nil.
+//
+//nolint:nonamedreturns // token and language are returned together
+func resolveDirectRecipient(
+	ctx context.Context,
+	userRepo domainrepository.UserRepository,
+	anonymousSessionRepo domainrepository.AnonymousSessionRepository,
+	userID uuid.UUID,
+	deviceID string,
+) (token, language string) {
+	if userID != uuid.Nil {
+		tokens, err := userRepo.ListDeviceTokensByUserIDs(ctx, []uuid.UUID{userID})
+		if err != nil || len(tokens) == 0 {
+			return "", ""
+		}
+
+		language, _, err = userRepo.GetUserLanguageAndPhone(ctx, userID)
+		if err != nil {
+			slog.Debug("failed to get user language", "error", err, "user_id", userID.String())
+		}
+
+		return tokens[0], language
+	}
+
+	session, err := anonymousSessionRepo.FindByDeviceID(ctx, deviceID)
+	if err != nil || session == nil {
+		return "", ""
+	}
+
+	return session.DeviceFCMToken, session.DeviceLanguage
+}
+
+// summarizeRiskTypes renders the most frequent risk types, e.g. "robbery, theft".
+func summarizeRiskTypes(counts map[string]int) string {
+	types := make([]string, 0, len(counts))
+	for riskType := range counts {
+		types = append(types, riskType)
+	}
+
+	sort.Slice(types, func(i, j int) bool {
+		if counts[types[i]] == counts[types[j]] {
+			return types[i] < types[j]
+		}
+		return counts[types[i]] > counts[types[j]]
+	})
+
+	if len(types) > maxNudgeRiskTypes {
+		types = types[:maxNudgeRiskTypes]
+	}
+
+	return strings.Join(types, ", ")
+}
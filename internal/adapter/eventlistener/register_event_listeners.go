@@ -2,26 +2,36 @@ package eventlistener
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"math"
 
 	"github.com/google/uuid"
 	"github.com/risk-place-angola/backend-risk-place/internal/adapter/service"
 	"github.com/risk-place-angola/backend-risk-place/internal/adapter/websocket"
 	"github.com/risk-place-angola/backend-risk-place/internal/application/port"
 	"github.com/risk-place-angola/backend-risk-place/internal/domain/event"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
 	domainrepository "github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
 	domainservice "github.com/risk-place-angola/backend-risk-place/internal/domain/service"
 )
 
+const severityCritical = "critical"
+
 func RegisterEventListeners(
 	dispatcher port.EventDispatcher,
 	hub *websocket.Hub,
 	userRepo domainrepository.UserRepository,
 	anonymousSessionRepo domainrepository.AnonymousSessionRepository,
 	settingsRepo domainrepository.SafetySettingsRepository,
+	heldNotificationRepo domainrepository.HeldNotificationRepository,
+	emergencyContactRepo domainrepository.EmergencyContactRepository,
+	tripRepo domainrepository.TripRepository,
 	notifierPush port.NotifierPushService,
 	notifierSMS port.NotifierSMSService,
 	translationService *service.TranslationService,
+	notificationService *service.NotificationService,
 ) {
 	settingsChecker := domainservice.NewSettingsChecker(settingsRepo, anonymousSessionRepo)
 
@@ -31,12 +41,14 @@ func RegisterEventListeners(
 		userRepo,
 		anonymousSessionRepo,
 		settingsChecker,
+		heldNotificationRepo,
 		notifierPush,
 		notifierSMS,
 		translationService,
+		notificationService,
 		"AlertCreated",
 		func(ctx context.Context, h *websocket.Hub, ev event.AlertCreatedEvent) {
-			h.BroadcastAlert(ctx, ev.AlertID.String(), ev.Message, ev.Latitude, ev.Longitude, ev.Radius, ev.Severity)
+			h.BroadcastAlert(ctx, ev.AlertID.String(), ev.Message, ev.Latitude, ev.Longitude, ev.Radius, ev.Severity, ev.RiskTypeID, ev.RiskTopicID)
 		},
 		"alert_id",
 	)
@@ -47,21 +59,52 @@ func RegisterEventListeners(
 		userRepo,
 		anonymousSessionRepo,
 		settingsChecker,
+		heldNotificationRepo,
 		notifierPush,
 		notifierSMS,
 		translationService,
+		notificationService,
 		"ReportCreated",
 		func(ctx context.Context, h *websocket.Hub, ev event.ReportCreatedEvent) {
-			h.BroadcastReport(ctx, ev.ReportID.String(), ev.Message, ev.Latitude, ev.Longitude, ev.Radius, ev.IsVerified)
+			h.BroadcastReport(ctx, ev.ReportID.String(), ev.Message, ev.Latitude, ev.Longitude, ev.Radius, ev.IsVerified, ev.RiskTypeID, ev.RiskTopicID)
 		},
 		"report_id",
 	)
 
-	dispatcher.Register("ReportResolved", func(e event.Event) {
+	registerHighRiskNudgeHandler(
+		dispatcher,
+		hub,
+		userRepo,
+		anonymousSessionRepo,
+		notifierPush,
+		translationService,
+	)
+
+	registerDangerZoneEnteredHandler(
+		dispatcher,
+		hub,
+		userRepo,
+		anonymousSessionRepo,
+		notifierPush,
+		translationService,
+	)
+
+	registerTripHandlers(
+		dispatcher,
+		hub,
+		userRepo,
+		anonymousSessionRepo,
+		emergencyContactRepo,
+		tripRepo,
+		notifierPush,
+		notifierSMS,
+		translationService,
+	)
+
+	dispatcher.Register("ReportResolved", "websocket", func(_ context.Context, e event.Event) error {
 		ev, ok := e.(event.ReportResolvedEvent)
 		if !ok {
-			slog.Error("failed to cast event to ReportResolvedEvent")
-			return
+			return errors.New("failed to cast event to ReportResolvedEvent")
 		}
 
 		for _, uid := range ev.UserIDs {
@@ -70,139 +113,286 @@ func RegisterEventListeners(
 				"message":   ev.Message,
 			})
 		}
+		return nil
 	})
 
-	dispatcher.Register("ReportVerified", func(e event.Event) {
+	dispatcher.Register("ReportVerified", "websocket", func(_ context.Context, e event.Event) error {
 		ev, ok := e.(event.ReportVerifiedEvent)
 		if !ok {
-			slog.Error("failed to cast event to ReportVerifiedEvent")
-			return
+			return errors.New("failed to cast event to ReportVerifiedEvent")
 		}
 
 		hub.NotifyUser(ev.UserID.String(), "report_verified", map[string]string{
 			"report_id": ev.ReportID.String(),
 			"message":   "Seu relatório foi verificado.",
 		})
+		return nil
 	})
 }
 
+// broadcastTarget is what the push and email handlers need to know about a
+// broadcast event.
+type broadcastTarget struct {
+	userIDs []uuid.UUID
+	// distances holds each of userIDs' distance from the incident, in metres.
+	distances        []int
+	lat, lon, radius float64
+	riskType         string
+	id               string
+	critical         bool
+	severity, group  string
+	filter           model.EmailAlertFilter
+}
+
+func broadcastTargetOf(ev any) broadcastTarget {
+	switch v := ev.(type) {
+	case event.AlertCreatedEvent:
+		id := v.AlertID.String()
+		return broadcastTarget{
+			userIDs:   v.UserID,
+			distances: recipientDistances(v.UserID, v.UserDistances, v.Radius),
+			lat:       v.Latitude,
+			lon:       v.Longitude,
+			radius:    v.Radius,
+			riskType:  v.RiskType,
+			id:        id,
+			critical:  v.Severity == severityCritical,
+			severity:  v.Severity,
+			group:     "alert:" + id,
+			filter: model.EmailAlertFilter{
+				Severity:    v.Severity,
+				RiskTypeID:  v.RiskTypeID,
+				RiskTopicID: v.RiskTopicID,
+			},
+		}
+	case event.ReportCreatedEvent:
+		return broadcastTarget{
+			userIDs:   v.UserID,
+			distances: recipientDistances(v.UserID, v.UserDistances, v.Radius),
+			lat:       v.Latitude,
+			lon:       v.Longitude,
+			radius:    v.Radius,
+			riskType:  v.RiskType,
+			id:        v.ReportID.String(),
+			group:     reportAreaGroup(v.RiskType, v.Latitude, v.Longitude),
+			filter: model.EmailAlertFilter{
+				Report:      true,
+				IsVerified:  v.IsVerified,
+				RiskTypeID:  v.RiskTypeID,
+				RiskTopicID: v.RiskTopicID,
+			},
+		}
+	}
+	return broadcastTarget{}
+}
+
+// recipientDistances returns each user's distance from the incident in whole
+// metres, rounded up. Events published before distances were recorded fall
+// back to the broadcast radius.
+func recipientDistances(userIDs []uuid.UUID, distances []float64, radius float64) []int {
+	out := make([]int, len(userIDs))
+	for i := range userIDs {
+		distance := radius
+		if i < len(distances) {
+			distance = distances[i]
+		}
+		out[i] = int(math.Ceil(distance))
+	}
+	return out
+}
+
+// broadcastRecipients lists the device tokens of users and anonymous
+// sessions whose settings let the event through.
+//
+//nolint:nonamedreturns // authenticated and anonymous tokens are returned together
+func broadcastRecipients(
+	ctx context.Context,
+	userRepo domainrepository.UserRepository,
+	anonymousSessionRepo domainrepository.AnonymousSessionRepository,
+	ev any,
+	target broadcastTarget,
+) (deviceTokens, anonymousTokens []model.DeviceToken, err error) {
+	switch v := ev.(type) {
+	case event.AlertCreatedEvent:
+		deviceTokens, err = userRepo.ListDeviceTokensForAlertNotification(ctx, target.userIDs, target.distances, v.Severity, v.RiskTypeID, v.RiskTopicID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list device tokens for alert: %w", err)
+		}
+
+		anonymousTokens, err = anonymousSessionRepo.GetFCMTokensForAlertNotification(ctx, target.lat, target.lon, target.radius, v.Severity, v.RiskTypeID, v.RiskTopicID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list anonymous tokens for alert: %w", err)
+		}
+
+	case event.ReportCreatedEvent:
+		deviceTokens, err = userRepo.ListDeviceTokensForReportNotification(ctx, target.userIDs, target.distances, v.IsVerified, v.RiskTypeID, v.RiskTopicID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list device tokens for report: %w", err)
+		}
+
+		anonymousTokens, err = anonymousSessionRepo.GetFCMTokensForReportNotification(ctx, target.lat, target.lon, target.radius, v.IsVerified, v.RiskTypeID, v.RiskTopicID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list anonymous tokens for report: %w", err)
+		}
+	}
+
+	return deviceTokens, anonymousTokens, nil
+}
+
+// registerBroadcastHandler registers the websocket, push and email handlers
+// of a broadcast event. They are tracked apart, so a failed push is retried
+// without repeating the emails.
 func registerBroadcastHandler[T any](
 	dispatcher port.EventDispatcher,
 	hub *websocket.Hub,
 	userRepo domainrepository.UserRepository,
 	anonymousSessionRepo domainrepository.AnonymousSessionRepository,
-	_ domainservice.SettingsChecker,
+	settingsChecker domainservice.SettingsChecker,
+	heldNotificationRepo domainrepository.HeldNotificationRepository,
 	notifierPush port.NotifierPushService,
 	_ port.NotifierSMSService,
 	translationService *service.TranslationService,
+	notificationService *service.NotificationService,
 	eventName string,
 	broadcast func(context.Context, *websocket.Hub, T),
 	idKey string,
 ) {
-	registerEventHandlers(dispatcher, eventName, func(e event.Event) {
+	eventKey := "alert_created"
+	if eventName == "ReportCreated" {
+		eventKey = "report_created"
+	}
+
+	registerEventHandlers(dispatcher, eventName, "websocket", func(ctx context.Context, e event.Event) error {
 		ev, ok := e.(T)
 		if !ok {
-			slog.Error("failed to cast event", "event_name", eventName)
-			return
+			return fmt.Errorf("failed to cast event %s", eventName)
 		}
 
-		ctx := context.Background()
 		broadcast(ctx, hub, ev)
+		return nil
+	})
 
-		var userID []uuid.UUID
-		var lat, lon, radius float64
-		var riskType string
-		var id string
-		var deviceTokens []string
-		var anonymousTokens []string
-
-		switch v := any(ev).(type) {
-		case event.AlertCreatedEvent:
-			userID = v.UserID
-			lat = v.Latitude
-			lon = v.Longitude
-			radius = v.Radius
-			riskType = v.RiskType
-			id = v.AlertID.String()
-
-			distanceMeters := int(radius)
-
-			authTokens, err := userRepo.ListDeviceTokensForAlertNotification(ctx, userID, v.Severity, distanceMeters)
-			if err != nil {
-				slog.Error("failed to list device tokens for alert", "error", err)
-			} else {
-				for _, token := range authTokens {
-					deviceTokens = append(deviceTokens, token.FCMToken)
-				}
-			}
+	registerEventHandlers(dispatcher, eventName, "push", func(ctx context.Context, e event.Event) error {
+		ev, ok := e.(T)
+		if !ok {
+			return fmt.Errorf("failed to cast event %s", eventName)
+		}
+		target := broadcastTargetOf(ev)
 
-			anonTokens, err := anonymousSessionRepo.GetFCMTokensForAlertNotification(ctx, lat, lon, radius, v.Severity)
-			if err != nil {
-				slog.Error("failed to list anonymous tokens for alert", "error", err)
-			} else {
-				for _, token := range anonTokens {
-					anonymousTokens = append(anonymousTokens, token.FCMToken)
-				}
+		deviceTokens, anonymousTokens, err := broadcastRecipients(ctx, userRepo, anonymousSessionRepo, ev, target)
+		if err != nil {
+			return err
+		}
+
+		recipients := make([]model.DeviceToken, 0, len(deviceTokens)+len(anonymousTokens))
+		recipients = append(recipients, deviceTokens...)
+		recipients = append(recipients, anonymousTokens...)
+
+		sendNow, held := applyQuietHours(ctx, settingsChecker, recipients, target.critical, eventKey, target.riskType, target.id)
+		if len(held) > 0 {
+			if err := heldNotificationRepo.Hold(ctx, held); err != nil {
+				return fmt.Errorf("failed to hold notifications for quiet hours: %w", err)
 			}
+			slog.Info("held notifications for quiet hours digest",
+				slog.String("event", eventName),
+				slog.Int("held", len(held)))
+		}
 
-		case event.ReportCreatedEvent:
-			userID = v.UserID
-			lat = v.Latitude
-			lon = v.Longitude
-			radius = v.Radius
-			riskType = v.RiskType
-			id = v.ReportID.String()
+		if len(sendNow) == 0 {
+			slog.Debug("no users eligible for notification after settings filter", "event_name", eventName)
+			return nil
+		}
 
-			distanceMeters := int(radius)
+		slog.Info("sending push notifications with settings filters",
+			slog.String("event", eventName),
+			slog.Int("authenticated_users", len(deviceTokens)),
+			slog.Int("anonymous_sessions", len(anonymousTokens)),
+			slog.Int("held", len(held)),
+			slog.Int("total", len(sendNow)))
 
-			authTokens, err := userRepo.ListDeviceTokensForReportNotification(ctx, userID, v.IsVerified, distanceMeters)
-			if err != nil {
-				slog.Error("failed to list device tokens for report", "error", err)
-			} else {
-				for _, token := range authTokens {
-					deviceTokens = append(deviceTokens, token.FCMToken)
-				}
-			}
+		// Only a broadcast that reached no language group is retried, so a
+		// retry never pushes the same event twice to anyone.
+		groups := groupTokensByLanguage(translationService, sendNow)
+		var errs []error
+		for lang, tokens := range groups {
+			msg := translationService.GetMessage(eventKey, lang, target.riskType)
 
-			anonTokens, err := anonymousSessionRepo.GetFCMTokensForReportNotification(ctx, lat, lon, radius, v.IsVerified)
+			err := notifierPush.NotifyPushMulti(ctx, tokens, msg.Title, msg.Body, map[string]string{
+				idKey:                 target.id,
+				port.PushDataGroupKey: target.group,
+				port.PushDataSeverity: target.severity,
+			})
 			if err != nil {
-				slog.Error("failed to list anonymous tokens for report", "error", err)
-			} else {
-				for _, token := range anonTokens {
-					anonymousTokens = append(anonymousTokens, token.FCMToken)
-				}
+				slog.Error("failed to send push notification", "event_name", eventName, "language", lang, "error", err)
+				errs = append(errs, err)
 			}
 		}
+		if len(errs) == len(groups) {
+			return errors.Join(errs...)
+		}
+		return nil
+	})
 
-		allTokens := make([]string, 0, len(deviceTokens)+len(anonymousTokens))
-		allTokens = append(allTokens, deviceTokens...)
-		allTokens = append(allTokens, anonymousTokens...)
+	registerEventHandlers(dispatcher, eventName, "email", func(ctx context.Context, e event.Event) error {
+		ev, ok := e.(T)
+		if !ok {
+			return fmt.Errorf("failed to cast event %s", eventName)
+		}
+		target := broadcastTargetOf(ev)
 
-		if len(allTokens) > 0 {
-			slog.Info("sending push notifications with settings filters",
-				slog.String("event", eventName),
-				slog.Int("authenticated_users", len(deviceTokens)),
-				slog.Int("anonymous_sessions", len(anonymousTokens)),
-				slog.Int("total", len(allTokens)))
+		return notificationService.SendEmailNear(ctx, target.userIDs, target.distances, target.lat, target.lon, target.radius, target.filter, target.riskType, eventKey, map[string]string{
+			idKey: target.id,
+		})
+	})
+}
 
-			eventKey := "alert_created"
-			if eventName == "ReportCreated" {
-				eventKey = "report_created"
-			}
+// reportAreaGroup groups pushes for reports of the same risk type within
+// roughly a square kilometre, so a cluster of reports about one incident
+// updates a single notification.
+func reportAreaGroup(riskType string, lat, lon float64) string {
+	return fmt.Sprintf("report:%s:%.2f:%.2f", riskType, lat, lon)
+}
 
-			msg := translationService.GetMessage(eventKey, service.LanguagePortuguese, riskType)
+// applyQuietHours splits recipients into tokens to notify now and
+// notifications held until the recipient's night mode ends. Critical
+// alerts always break through quiet hours, without looking up settings.
+func applyQuietHours(
+	ctx context.Context,
+	settingsChecker domainservice.SettingsChecker,
+	recipients []model.DeviceToken,
+	critical bool,
+	eventKey, riskType, referenceID string,
+) ([]model.DeviceToken, []*model.HeldNotification) {
+	if critical {
+		return recipients, nil
+	}
 
-			err := notifierPush.NotifyPushMulti(ctx, allTokens, msg.Title, msg.Body, map[string]string{
-				idKey: id,
-			})
-			if err != nil {
-				slog.Error("failed to send push notification", "event_name", eventName, "error", err)
-			}
-		} else {
-			slog.Debug("no users eligible for notification after settings filter", "event_name", eventName)
+	tokens := make([]model.DeviceToken, 0, len(recipients))
+	var held []*model.HeldNotification
+
+	for i, releaseAt := range settingsChecker.QuietHoursUntilAll(ctx, recipients) {
+		if releaseAt.IsZero() {
+			tokens = append(tokens, recipients[i])
+			continue
 		}
-	})
+
+		held = append(held, model.NewHeldNotification(recipients[i], eventKey, riskType, referenceID, releaseAt))
+	}
+
+	return tokens, held
 }
-func registerEventHandlers(dispatcher port.EventDispatcher, eventName string, handler func(e event.Event)) {
-	dispatcher.Register(eventName, handler)
+
+// groupTokensByLanguage buckets recipients by their resolved locale so each
+// group gets one multicast in its own language.
+func groupTokensByLanguage(translationService *service.TranslationService, recipients []model.DeviceToken) map[service.Language][]string {
+	groups := make(map[service.Language][]string)
+	for _, recipient := range recipients {
+		lang := translationService.ParseLanguage(recipient.Language)
+		groups[lang] = append(groups[lang], recipient.FCMToken)
+	}
+	return groups
+}
+
+func registerEventHandlers(dispatcher port.EventDispatcher, eventName, handlerName string, handler event.EventHandler) {
+	dispatcher.Register(eventName, handlerName, handler)
 }
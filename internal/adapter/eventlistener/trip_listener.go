@@ -0,0 +1,193 @@
+package eventlistener
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/risk-place-angola/backend-risk-place/internal/adapter/service"
+	"github.com/risk-place-angola/backend-risk-place/internal/adapter/websocket"
+	"github.com/risk-place-angola/backend-risk-place/internal/application/port"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/event"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+	domainrepository "github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
+)
+
+func registerTripHandlers(
+	dispatcher port.EventDispatcher,
+	hub *websocket.Hub,
+	userRepo domainrepository.UserRepository,
+	anonymousSessionRepo domainrepository.AnonymousSessionRepository,
+	emergencyContactRepo domainrepository.EmergencyContactRepository,
+	tripRepo domainrepository.TripRepository,
+	notifierPush port.NotifierPushService,
+	notifierSMS port.NotifierSMSService,
+	translationService *service.TranslationService,
+) {
+	// checkIn renders the check-in in the traveller's language, with the push
+	// token to send it to.
+	checkIn := func(ctx context.Context, e event.Event) (event.TripCheckInRequestedEvent, string, service.NotificationMessage, map[string]string, error) {
+		ev, ok := e.(event.TripCheckInRequestedEvent)
+		if !ok {
+			return ev, "", service.NotificationMessage{}, nil, errors.New("failed to cast event to TripCheckInRequestedEvent")
+		}
+
+		token, language := resolveDirectRecipient(ctx, userRepo, anonymousSessionRepo, ev.UserID, "")
+		msg := translationService.Render("trip_check_in_"+ev.Anomaly, translationService.ParseLanguage(language), "", service.Params{
+			"minutes": int(model.TripCheckInTimeout.Minutes()),
+		})
+
+		data := map[string]string{
+			"type":    "trip_check_in",
+			"trip_id": ev.TripID.String(),
+			"reason":  ev.Anomaly,
+			"message": msg.Body,
+			// Only the latest check-in of a trip needs an answer.
+			port.PushDataGroupKey: "trip_check_in_" + ev.TripID.String(),
+			port.PushDataSeverity: port.PushSeverityCritical,
+		}
+
+		return ev, token, msg, data, nil
+	}
+
+	dispatcher.Register("TripCheckInRequested", "websocket", func(ctx context.Context, e event.Event) error {
+		ev, _, _, data, err := checkIn(ctx, e)
+		if err != nil {
+			return err
+		}
+
+		hub.NotifyUser(ev.UserID.String(), "trip_check_in", data)
+		return nil
+	})
+
+	dispatcher.Register("TripCheckInRequested", "push", func(ctx context.Context, e event.Event) error {
+		ev, token, msg, data, err := checkIn(ctx, e)
+		if err != nil {
+			return err
+		}
+
+		if token == "" {
+			slog.Debug("no push token for trip check-in", "user_id", ev.UserID.String())
+			return nil
+		}
+		if err := notifierPush.NotifyPush(ctx, token, msg.Title, msg.Body, data); err != nil {
+			return fmt.Errorf("failed to send trip check-in: %w", err)
+		}
+		return nil
+	})
+
+	// notifyTripEscalated tells the traveller how many emergency contacts
+	// have been alerted. Each notice replaces the previous one.
+	notifyTripEscalated := func(ctx context.Context, ev event.TripEscalatedEvent, lang service.Language, notified int) {
+		msg := translationService.Render("trip_escalated", lang, "", nil)
+		data := map[string]string{
+			"type":              "trip_escalated",
+			"trip_id":           ev.TripID.String(),
+			"reason":            ev.Anomaly,
+			"contacts_notified": fmt.Sprintf("%d", notified),
+			"message":           msg.Body,
+			// Replaces the check-in it escalates.
+			port.PushDataGroupKey: "trip_check_in_" + ev.TripID.String(),
+			port.PushDataSeverity: port.PushSeverityCritical,
+		}
+		hub.NotifyUser(ev.UserID.String(), "trip_escalated", data)
+
+		token, _ := resolveDirectRecipient(ctx, userRepo, anonymousSessionRepo, ev.UserID, "")
+		if token == "" {
+			return
+		}
+		if err := notifierPush.NotifyPush(ctx, token, msg.Title, msg.Body, data); err != nil {
+			slog.Error("failed to send trip escalation notice", "error", err, "trip_id", ev.TripID.String())
+		}
+	}
+
+	// The escalation is retried until every emergency contact got the SMS.
+	// Contacts already texted are recorded and skipped on retries, and the
+	// traveller hears about it whenever more of them were reached.
+	dispatcher.Register("TripEscalated", "escalation", func(ctx context.Context, e event.Event) error {
+		ev, ok := e.(event.TripEscalatedEvent)
+		if !ok {
+			return errors.New("failed to cast event to TripEscalatedEvent")
+		}
+
+		user, err := userRepo.FindByID(ctx, ev.UserID)
+		if err != nil {
+			return fmt.Errorf("failed to find user of escalated trip: %w", err)
+		}
+		if user == nil {
+			slog.Error("user of escalated trip not found", "trip_id", ev.TripID.String())
+			return nil
+		}
+		contacts, err := emergencyContactRepo.FindByUserID(ctx, ev.UserID)
+		if err != nil {
+			return fmt.Errorf("failed to find emergency contacts of escalated trip: %w", err)
+		}
+
+		// Contacts get the SMS in the traveller's language, as with the
+		// emergency alert the traveller sends themselves.
+		language, _, err := userRepo.GetUserLanguageAndPhone(ctx, ev.UserID)
+		if err != nil {
+			slog.Debug("failed to get user language", "error", err, "user_id", ev.UserID.String())
+		}
+		lang := translationService.ParseLanguage(language)
+
+		reason := translationService.Render("trip_escalation_reason_"+ev.Anomaly, lang, "", nil).Body
+		sms := translationService.Render("trip_escalation_sms", lang, "", service.Params{
+			"name":     user.Name,
+			"phone":    user.Phone,
+			"reason":   reason,
+			"time":     time.Now().Format("2006-01-02 15:04:05"),
+			"map_link": fmt.Sprintf("https://maps.google.com/?q=%.6f,%.6f", ev.Latitude, ev.Longitude),
+		}).Body
+
+		delivered, err := tripRepo.ListEscalationDeliveries(ctx, ev.TripID)
+		if err != nil {
+			return fmt.Errorf("failed to list emergency contacts alerted of trip: %w", err)
+		}
+
+		reached := 0
+		var errs []error
+		for _, contact := range contacts {
+			if delivered[contact.ID] {
+				continue
+			}
+			if err := notifierSMS.NotifySMS(ctx, contact.Phone, sms); err != nil {
+				slog.Error("failed to alert emergency contact of escalated trip",
+					"error", err,
+					"trip_id", ev.TripID.String(),
+					"contact_id", contact.ID.String())
+				errs = append(errs, err)
+				continue
+			}
+			if err := tripRepo.RecordEscalationDelivery(ctx, ev.TripID, contact.ID); err != nil {
+				slog.Warn("failed to record emergency contact alerted of trip",
+					"error", err,
+					"trip_id", ev.TripID.String(),
+					"contact_id", contact.ID.String())
+			}
+			delivered[contact.ID] = true
+			reached++
+		}
+
+		notified := 0
+		for _, contact := range contacts {
+			if delivered[contact.ID] {
+				notified++
+			}
+		}
+		slog.Info("emergency contacts alerted for trip",
+			"trip_id", ev.TripID.String(),
+			"contacts_notified", notified,
+			"contacts", len(contacts))
+
+		if reached > 0 || len(contacts) == 0 {
+			notifyTripEscalated(ctx, ev, lang, notified)
+		}
+		if len(errs) > 0 {
+			return fmt.Errorf("%d emergency contacts of trip %s could not be alerted: %w", len(errs), ev.TripID, errors.Join(errs...))
+		}
+		return nil
+	})
+}
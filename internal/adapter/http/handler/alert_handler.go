@@ -11,6 +11,7 @@ import (
 	"github.com/risk-place-angola/backend-risk-place/internal/adapter/repository/postgres/sqlc"
 	"github.com/risk-place-angola/backend-risk-place/internal/application"
 	"github.com/risk-place-angola/backend-risk-place/internal/application/dto"
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
 	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
 	"github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
 )
@@ -50,7 +51,7 @@ func (h *AlertHandler) CreateAlert(w http.ResponseWriter, r *http.Request) {
 
 	var req dto.Alert
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		util.Error(w, "invalid request", http.StatusBadRequest)
+		util.Error(w, domainErrors.ErrInvalidRequestBody, http.StatusBadRequest)
 		return
 	}
 
@@ -65,13 +66,13 @@ func (h *AlertHandler) createAuthenticatedAlert(w http.ResponseWriter, r *http.R
 	userIDStr, ok := util.GetUserIDFromContext(r.Context())
 	if !ok {
 		slog.Error("failed to get user ID from context")
-		util.Error(w, "unauthorized", http.StatusUnauthorized)
+		util.Error(w, domainErrors.ErrUnauthorized, http.StatusUnauthorized)
 		return
 	}
 	req.UserID = userIDStr
 	err := h.alertUseCase.AlertUseCase.TriggerAlert(r.Context(), req)
 	if err != nil {
-		util.Error(w, err.Error(), http.StatusInternalServerError)
+		util.Error(w, err, http.StatusInternalServerError)
 		return
 	}
 	util.Response(w, map[string]string{"status": "alert triggered"}, http.StatusCreated)
@@ -86,7 +87,7 @@ func (h *AlertHandler) createAnonymousAlert(w http.ResponseWriter, r *http.Reque
 
 	riskTypeID, parseErr := uuid.Parse(req.RiskTypeID)
 	if parseErr != nil {
-		util.Error(w, "invalid risk type ID", http.StatusBadRequest)
+		util.Error(w, domainErrors.InvalidID("risk type"), http.StatusBadRequest)
 		return
 	}
 
@@ -94,7 +95,7 @@ func (h *AlertHandler) createAnonymousAlert(w http.ResponseWriter, r *http.Reque
 	if req.RiskTopicID != "" {
 		topicID, topicErr := uuid.Parse(req.RiskTopicID)
 		if topicErr != nil {
-			util.Error(w, "invalid risk topic ID", http.StatusBadRequest)
+			util.Error(w, domainErrors.InvalidID("risk topic"), http.StatusBadRequest)
 			return
 		}
 		riskTopicIDNullUUID = uuid.NullUUID{UUID: topicID, Valid: true}
@@ -2,12 +2,17 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/risk-place-angola/backend-risk-place/internal/adapter/http/util"
 	"github.com/risk-place-angola/backend-risk-place/internal/application"
 	"github.com/risk-place-angola/backend-risk-place/internal/application/dto"
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
 )
 
 type DangerZoneHandler struct {
@@ -21,23 +26,34 @@ func NewDangerZoneHandler(app *application.Application) *DangerZoneHandler {
 // GetDangerZonesNearby godoc.
 // @Summary Get nearby danger zones.
 // @Description Retrieves danger zones near a specific location based on incident density and risk score.
+// @Description Zones come from a geohash grid with several resolutions configured per region; the resolution
+// @Description follows the map zoom when given, otherwise the radius. Each zone carries its risk per weekday/weekend
+// @Description four-hour slot and the riskiest slot; pass "at" to score the zones for that moment instead of the week.
+// @Description With format=geojson, gpx or kml, or the matching Accept header, zones come as polygons: their grid
+// @Description cell, or the area drawn for manual zones.
 // @Tags danger-zones
 // @Accept json
-// @Produce json
+// @Produce json,application/geo+json,application/gpx+xml,application/vnd.google-earth.kml+xml
 // @Security OptionalAuth
 // @Param X-Device-Id header string false "Device ID for anonymous users"
 // @Param request body dto.GetDangerZonesRequest true "Location and radius"
+// @Param format query string false "Export format" Enums(json, geojson, gpx, kml)
 // @Success 200 {object} dto.GetDangerZonesResponse
 // @Failure 400 {object} util.ErrorResponse
 // @Failure 500 {object} util.ErrorResponse
 // @Router /danger-zones/nearby [post].
 func (h *DangerZoneHandler) GetDangerZonesNearby(w http.ResponseWriter, r *http.Request) {
+	format, ok := exportFormat(w, r)
+	if !ok {
+		return
+	}
+
 	var req dto.GetDangerZonesRequest
 	ctx := r.Context()
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		slog.Error("failed to decode danger zones request", "error", err)
-		util.Error(w, "invalid request body", http.StatusBadRequest)
+		util.Error(w, domainErrors.ErrInvalidRequestBody, http.StatusBadRequest)
 		return
 	}
 
@@ -48,5 +64,248 @@ func (h *DangerZoneHandler) GetDangerZonesNearby(w http.ResponseWriter, r *http.
 		return
 	}
 
+	respondExport(w, format, response, "danger-zones", func() []util.GeoFeature {
+		return dangerZoneFeatures(response.Zones)
+	})
+}
+
+// ExplainDangerZone godoc.
+// @Summary Explain a danger zone.
+// @Description Shows why a grid cell is a danger zone: the reports behind it, the recency and verification
+// @Description weight of each, and the totals per risk type, as recorded by the calculation in effect at "at", or
+// @Description the latest one. Explanations are kept for 30 days. Private reports are listed without ID and with
+// @Description the day only.
+// @Tags danger-zones
+// @Produce json
+// @Security OptionalAuth
+// @Param grid_cell_id path string true "Geohash of the zone's cell"
+// @Param at query string false "RFC3339 time whose calculation to explain"
+// @Success 200 {object} dto.DangerZoneExplanationDTO
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 404 {object} util.ErrorResponse
+// @Failure 500 {object} util.ErrorResponse
+// @Router /danger-zones/{grid_cell_id} [get].
+func (h *DangerZoneHandler) ExplainDangerZone(w http.ResponseWriter, r *http.Request) {
+	gridCellID := r.PathValue("grid_cell_id")
+
+	var at time.Time
+	if value := r.URL.Query().Get("at"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			util.Error(w, domainErrors.ErrInvalidRequest, http.StatusBadRequest)
+			return
+		}
+		at = parsed
+	}
+
+	response, err := h.app.DangerZoneUseCase.ExplainDangerZone(r.Context(), gridCellID, at)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrInvalidGeohash):
+			util.Error(w, domainErrors.InvalidID("grid cell"), http.StatusBadRequest)
+		case errors.Is(err, domainErrors.ErrDangerZoneNotFound):
+			util.Error(w, domainErrors.ErrDangerZoneNotFound, http.StatusNotFound)
+		default:
+			slog.Error("failed to explain danger zone", "error", err, "grid_cell_id", gridCellID)
+			util.Error(w, "failed to retrieve danger zone", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	util.Response(w, response, http.StatusOK)
+}
+
+// GetDangerZoneTrends godoc.
+// @Summary Get danger zone trends.
+// @Description Returns the daily risk history of one grid cell, or of every cell of a precision inside a bounding
+// @Description box, with the week-over-week change and a rising, cooling or stable trend. The history comes from
+// @Description snapshots of every danger zone calculation, kept for 90 days. The bounding box can span at most 0.5
+// @Description degrees each way and the 200 fastest rising cells are returned.
+// @Tags danger-zones
+// @Accept json
+// @Produce json
+// @Security OptionalAuth
+// @Param request body dto.GetDangerZoneTrendsRequest true "Grid cell or bounding box"
+// @Success 200 {object} dto.GetDangerZoneTrendsResponse
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 500 {object} util.ErrorResponse
+// @Router /danger-zones/trends [post].
+func (h *DangerZoneHandler) GetDangerZoneTrends(w http.ResponseWriter, r *http.Request) {
+	var req dto.GetDangerZoneTrendsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.Error(w, domainErrors.ErrInvalidRequestBody, http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.app.DangerZoneUseCase.GetDangerZoneTrends(r.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrInvalidGeohash):
+			util.Error(w, domainErrors.InvalidID("grid cell"), http.StatusBadRequest)
+		case errors.Is(err, domainErrors.ErrInvalidRequest):
+			util.Error(w, err, http.StatusBadRequest)
+		default:
+			slog.Error("failed to get danger zone trends", "error", err)
+			util.Error(w, "failed to retrieve danger zone trends", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	util.Response(w, response, http.StatusOK)
+}
+
+// CreateManualDangerZone godoc.
+// @Summary Create a manual danger zone.
+// @Description Draws a polygon danger zone with a level, reason, source and validity window, such as a flood plain
+// @Description during the rainy season. Active manual zones show up next to the computed ones, trigger entry
+// @Description warnings and lower the score of routes that cross them. Requires the danger_zone:manage permission.
+// @Tags danger-zones
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.ManualDangerZoneRequest true "Manual danger zone"
+// @Success 201 {object} dto.ManualDangerZoneDTO
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 401 {object} util.ErrorResponse
+// @Failure 403 {object} util.ErrorResponse
+// @Failure 500 {object} util.ErrorResponse
+// @Router /admin/danger-zones [post].
+func (h *DangerZoneHandler) CreateManualDangerZone(w http.ResponseWriter, r *http.Request) {
+	userIDStr, ok := util.GetUserIDFromContext(r.Context())
+	if !ok {
+		util.Error(w, domainErrors.ErrUnauthorized, http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		util.Error(w, domainErrors.ErrUnauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	var req dto.ManualDangerZoneRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.Error(w, domainErrors.ErrInvalidRequestBody, http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.app.ManualDangerZoneUseCase.Create(r.Context(), userID, &req)
+	if err != nil {
+		h.manualDangerZoneError(w, err)
+		return
+	}
+
+	util.Response(w, response, http.StatusCreated)
+}
+
+// ListManualDangerZones godoc.
+// @Summary List manual danger zones.
+// @Description Lists every manual danger zone, including expired and upcoming ones, also as GeoJSON, GPX or KML
+// @Description polygons with format or the matching Accept header. Requires the danger_zone:manage permission.
+// @Tags danger-zones
+// @Produce json,application/geo+json,application/gpx+xml,application/vnd.google-earth.kml+xml
+// @Security BearerAuth
+// @Param format query string false "Export format" Enums(json, geojson, gpx, kml)
+// @Success 200 {object} dto.ListManualDangerZonesResponse
+// @Failure 401 {object} util.ErrorResponse
+// @Failure 403 {object} util.ErrorResponse
+// @Failure 500 {object} util.ErrorResponse
+// @Router /admin/danger-zones [get].
+func (h *DangerZoneHandler) ListManualDangerZones(w http.ResponseWriter, r *http.Request) {
+	format, ok := exportFormat(w, r)
+	if !ok {
+		return
+	}
+
+	response, err := h.app.ManualDangerZoneUseCase.List(r.Context())
+	if err != nil {
+		h.manualDangerZoneError(w, err)
+		return
+	}
+
+	respondExport(w, format, response, "manual-danger-zones", func() []util.GeoFeature {
+		features := make([]util.GeoFeature, 0, len(response.Zones))
+		for _, zone := range response.Zones {
+			features = append(features, manualZoneFeature(zone))
+		}
+		return features
+	})
+}
+
+// UpdateManualDangerZone godoc.
+// @Summary Update a manual danger zone.
+// @Description Replaces a manual danger zone's polygon, level, reason, source and validity window. Requires the
+// @Description danger_zone:manage permission.
+// @Tags danger-zones
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Manual danger zone ID"
+// @Param request body dto.ManualDangerZoneRequest true "Manual danger zone"
+// @Success 200 {object} dto.ManualDangerZoneDTO
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 401 {object} util.ErrorResponse
+// @Failure 403 {object} util.ErrorResponse
+// @Failure 404 {object} util.ErrorResponse
+// @Failure 500 {object} util.ErrorResponse
+// @Router /admin/danger-zones/{id} [put].
+func (h *DangerZoneHandler) UpdateManualDangerZone(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		util.Error(w, domainErrors.InvalidID("danger zone"), http.StatusBadRequest)
+		return
+	}
+
+	var req dto.ManualDangerZoneRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.Error(w, domainErrors.ErrInvalidRequestBody, http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.app.ManualDangerZoneUseCase.Update(r.Context(), id, &req)
+	if err != nil {
+		h.manualDangerZoneError(w, err)
+		return
+	}
+
 	util.Response(w, response, http.StatusOK)
 }
+
+// DeleteManualDangerZone godoc.
+// @Summary Delete a manual danger zone.
+// @Description Removes a manual danger zone. Requires the danger_zone:manage permission.
+// @Tags danger-zones
+// @Security BearerAuth
+// @Param id path string true "Manual danger zone ID"
+// @Success 204
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 401 {object} util.ErrorResponse
+// @Failure 403 {object} util.ErrorResponse
+// @Failure 404 {object} util.ErrorResponse
+// @Failure 500 {object} util.ErrorResponse
+// @Router /admin/danger-zones/{id} [delete].
+func (h *DangerZoneHandler) DeleteManualDangerZone(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		util.Error(w, domainErrors.InvalidID("danger zone"), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.app.ManualDangerZoneUseCase.Delete(r.Context(), id); err != nil {
+		h.manualDangerZoneError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *DangerZoneHandler) manualDangerZoneError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domainErrors.ErrInvalidRequest):
+		util.Error(w, err, http.StatusBadRequest)
+	case errors.Is(err, domainErrors.ErrDangerZoneNotFound):
+		util.Error(w, domainErrors.ErrDangerZoneNotFound, http.StatusNotFound)
+	default:
+		slog.Error("failed to manage manual danger zone", "error", err)
+		util.Error(w, "failed to manage danger zone", http.StatusInternalServerError)
+	}
+}
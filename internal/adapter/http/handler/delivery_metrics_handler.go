@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/risk-place-angola/backend-risk-place/internal/adapter/http/util"
+	"github.com/risk-place-angola/backend-risk-place/internal/adapter/notifier"
+)
+
+type deliveryMetricsSource interface {
+	Snapshot() map[string]notifier.ProviderStats
+}
+
+type DeliveryMetricsHandler struct {
+	metrics deliveryMetricsSource
+}
+
+func NewDeliveryMetricsHandler(metrics deliveryMetricsSource) *DeliveryMetricsHandler {
+	return &DeliveryMetricsHandler{metrics: metrics}
+}
+
+// GetMetrics godoc
+// @Summary Get notification delivery metrics
+// @Description Per-provider push and SMS delivery counters since the server started, including retries, pruned tokens and circuit breaker state. Requires notification:read permission.
+// @Tags notifications
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} map[string]notifier.ProviderStats
+// @Failure 401 {object} util.ErrorResponse
+// @Failure 403 {object} util.ErrorResponse
+// @Router /admin/notifications/metrics [get]
+func (h *DeliveryMetricsHandler) GetMetrics(w http.ResponseWriter, _ *http.Request) {
+	util.Response(w, h.metrics.Snapshot(), http.StatusOK)
+}
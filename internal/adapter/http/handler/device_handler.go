@@ -8,6 +8,7 @@ import (
 	httputil "github.com/risk-place-angola/backend-risk-place/internal/adapter/http/util"
 	"github.com/risk-place-angola/backend-risk-place/internal/application/dto"
 	"github.com/risk-place-angola/backend-risk-place/internal/application/usecase/device"
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
 )
 
 type DeviceHandler struct {
@@ -40,14 +41,14 @@ func (h *DeviceHandler) RegisterDevice(w http.ResponseWriter, r *http.Request) {
 	var req dto.RegisterDeviceRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		slog.Error("failed to decode request", slog.Any("error", err))
-		httputil.Error(w, "Invalid request body", http.StatusBadRequest)
+		httputil.Error(w, domainErrors.ErrInvalidRequestBody, http.StatusBadRequest)
 		return
 	}
 
 	resp, err := h.registerDeviceUC.Execute(r.Context(), req)
 	if err != nil {
 		slog.Error("failed to register device", slog.Any("error", err))
-		httputil.Error(w, err.Error(), http.StatusInternalServerError)
+		httputil.Error(w, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -69,14 +70,14 @@ func (h *DeviceHandler) UpdateDeviceLocation(w http.ResponseWriter, r *http.Requ
 	var req dto.UpdateDeviceLocationRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		slog.Error("failed to decode request", slog.Any("error", err))
-		httputil.Error(w, "Invalid request body", http.StatusBadRequest)
+		httputil.Error(w, domainErrors.ErrInvalidRequestBody, http.StatusBadRequest)
 		return
 	}
 
 	err := h.updateDeviceLocationUC.Execute(r.Context(), req)
 	if err != nil {
 		slog.Error("failed to update device location", slog.Any("error", err))
-		httputil.Error(w, err.Error(), http.StatusInternalServerError)
+		httputil.Error(w, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/risk-place-angola/backend-risk-place/internal/adapter/http/util"
+	"github.com/risk-place-angola/backend-risk-place/internal/application"
+	"github.com/risk-place-angola/backend-risk-place/internal/application/dto"
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
+)
+
+type DigestHandler struct {
+	app *application.Application
+}
+
+func NewDigestHandler(app *application.Application) *DigestHandler {
+	return &DigestHandler{app: app}
+}
+
+// GetPreferences godoc
+// @Summary Get periodic digest preferences
+// @Description Retrieve the daily/weekly safety digest preferences for the authenticated user. Digests are off by default.
+// @Tags digests
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} dto.DigestPreferencesResponse
+// @Failure 401 {object} util.ErrorResponse
+// @Failure 500 {object} util.ErrorResponse
+// @Router /users/me/digest [get]
+func (h *DigestHandler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	userIDStr, ok := util.GetUserIDFromContext(r.Context())
+	if !ok {
+		util.Error(w, domainErrors.ErrUnauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	uid, err := dto.ParseUUID(userIDStr)
+	if err != nil {
+		util.Error(w, domainErrors.ErrInvalidUserID, http.StatusBadRequest)
+		return
+	}
+
+	prefs, err := h.app.DigestUseCase.GetPreferences(r.Context(), uid)
+	if err != nil {
+		slog.Error("error fetching digest preferences", "user_id", uid, "error", err)
+		util.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	util.Response(w, prefs, http.StatusOK)
+}
+
+// UpdatePreferences godoc
+// @Summary Update periodic digest preferences
+// @Description Opt in to or out of daily/weekly safety digests summarising activity around home, work and current location, delivered by push and/or email. All fields are optional.
+// @Tags digests
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param preferences body dto.UpdateDigestPreferencesInput true "Digest preferences"
+// @Success 200 {object} dto.DigestPreferencesResponse
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 401 {object} util.ErrorResponse
+// @Failure 500 {object} util.ErrorResponse
+// @Router /users/me/digest [put]
+func (h *DigestHandler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	userIDStr, ok := util.GetUserIDFromContext(r.Context())
+	if !ok {
+		util.Error(w, domainErrors.ErrUnauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	uid, err := dto.ParseUUID(userIDStr)
+	if err != nil {
+		util.Error(w, domainErrors.ErrInvalidUserID, http.StatusBadRequest)
+		return
+	}
+
+	var input dto.UpdateDigestPreferencesInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		util.Error(w, domainErrors.ErrInvalidRequestBody, http.StatusBadRequest)
+		return
+	}
+
+	prefs, err := h.app.DigestUseCase.UpdatePreferences(r.Context(), uid, input)
+	if err != nil {
+		if errors.Is(err, domainErrors.ErrInvalidDigestFrequency) || errors.Is(err, domainErrors.ErrDigestChannelRequired) {
+			util.Error(w, err, http.StatusBadRequest)
+			return
+		}
+		slog.Error("error updating digest preferences", "user_id", uid, "error", err)
+		util.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	util.Response(w, prefs, http.StatusOK)
+}
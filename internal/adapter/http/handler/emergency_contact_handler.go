@@ -9,6 +9,7 @@ import (
 	"github.com/risk-place-angola/backend-risk-place/internal/adapter/http/util"
 	"github.com/risk-place-angola/backend-risk-place/internal/application"
 	"github.com/risk-place-angola/backend-risk-place/internal/application/dto"
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
 )
 
 type EmergencyContactHandler struct {
@@ -35,21 +36,21 @@ func (h *EmergencyContactHandler) GetEmergencyContacts(w http.ResponseWriter, r
 	userIDStr, ok := util.GetUserIDFromContext(r.Context())
 	if !ok {
 		slog.Error("failed to get user ID from context")
-		util.Error(w, "unauthorized", http.StatusUnauthorized)
+		util.Error(w, domainErrors.ErrUnauthorized, http.StatusUnauthorized)
 		return
 	}
 
 	uid, err := dto.ParseUUID(userIDStr)
 	if err != nil {
 		slog.Error("invalid user ID in context", "error", err)
-		util.Error(w, "unauthorized", http.StatusUnauthorized)
+		util.Error(w, domainErrors.ErrUnauthorized, http.StatusUnauthorized)
 		return
 	}
 
 	contacts, err := h.app.EmergencyContactUseCase.GetAll(r.Context(), uid)
 	if err != nil {
 		slog.Error("error fetching emergency contacts", "user_id", uid, "error", err)
-		util.Error(w, err.Error(), http.StatusInternalServerError)
+		util.Error(w, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -73,27 +74,27 @@ func (h *EmergencyContactHandler) CreateEmergencyContact(w http.ResponseWriter,
 	userIDStr, ok := util.GetUserIDFromContext(r.Context())
 	if !ok {
 		slog.Error("failed to get user ID from context")
-		util.Error(w, "unauthorized", http.StatusUnauthorized)
+		util.Error(w, domainErrors.ErrUnauthorized, http.StatusUnauthorized)
 		return
 	}
 
 	uid, err := dto.ParseUUID(userIDStr)
 	if err != nil {
 		slog.Error("invalid user ID in context", "error", err)
-		util.Error(w, "unauthorized", http.StatusUnauthorized)
+		util.Error(w, domainErrors.ErrUnauthorized, http.StatusUnauthorized)
 		return
 	}
 
 	var input dto.CreateEmergencyContactInput
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		util.Error(w, "invalid request body", http.StatusBadRequest)
+		util.Error(w, domainErrors.ErrInvalidRequestBody, http.StatusBadRequest)
 		return
 	}
 
 	contact, err := h.app.EmergencyContactUseCase.Create(r.Context(), uid, input)
 	if err != nil {
 		slog.Error("error creating emergency contact", "user_id", userIDStr, "error", err)
-		util.Error(w, err.Error(), http.StatusBadRequest)
+		util.Error(w, err, http.StatusBadRequest)
 		return
 	}
 
@@ -119,32 +120,32 @@ func (h *EmergencyContactHandler) UpdateEmergencyContact(w http.ResponseWriter,
 	userIDStr, ok := util.GetUserIDFromContext(r.Context())
 	if !ok {
 		slog.Error("failed to get user ID from context")
-		util.Error(w, "unauthorized", http.StatusUnauthorized)
+		util.Error(w, domainErrors.ErrUnauthorized, http.StatusUnauthorized)
 		return
 	}
 
 	uid, err := dto.ParseUUID(userIDStr)
 	if err != nil {
 		slog.Error("invalid user ID in context", "error", err)
-		util.Error(w, "unauthorized", http.StatusUnauthorized)
+		util.Error(w, domainErrors.ErrUnauthorized, http.StatusUnauthorized)
 		return
 	}
 
 	contactID := r.PathValue("id")
 	if contactID == "" {
-		util.Error(w, "contact ID is required", http.StatusBadRequest)
+		util.Error(w, domainErrors.IDRequired("contact"), http.StatusBadRequest)
 		return
 	}
 
 	cid, err := uuid.Parse(contactID)
 	if err != nil {
-		util.Error(w, "invalid contact ID", http.StatusBadRequest)
+		util.Error(w, domainErrors.InvalidID("contact"), http.StatusBadRequest)
 		return
 	}
 
 	var input dto.UpdateEmergencyContactInput
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		util.Error(w, "invalid request body", http.StatusBadRequest)
+		util.Error(w, domainErrors.ErrInvalidRequestBody, http.StatusBadRequest)
 		return
 	}
 
@@ -152,10 +153,10 @@ func (h *EmergencyContactHandler) UpdateEmergencyContact(w http.ResponseWriter,
 	if err != nil {
 		slog.Error("error updating emergency contact", "user_id", userIDStr, "contact_id", contactID, "error", err)
 		if err.Error() == "emergency contact not found" {
-			util.Error(w, err.Error(), http.StatusNotFound)
+			util.Error(w, err, http.StatusNotFound)
 			return
 		}
-		util.Error(w, err.Error(), http.StatusBadRequest)
+		util.Error(w, err, http.StatusBadRequest)
 		return
 	}
 
@@ -179,36 +180,36 @@ func (h *EmergencyContactHandler) DeleteEmergencyContact(w http.ResponseWriter,
 	userIDStr, ok := util.GetUserIDFromContext(r.Context())
 	if !ok {
 		slog.Error("failed to get user ID from context")
-		util.Error(w, "unauthorized", http.StatusUnauthorized)
+		util.Error(w, domainErrors.ErrUnauthorized, http.StatusUnauthorized)
 		return
 	}
 
 	uid, err := dto.ParseUUID(userIDStr)
 	if err != nil {
 		slog.Error("invalid user ID in context", "error", err)
-		util.Error(w, "unauthorized", http.StatusUnauthorized)
+		util.Error(w, domainErrors.ErrUnauthorized, http.StatusUnauthorized)
 		return
 	}
 
 	contactID := r.PathValue("id")
 	if contactID == "" {
-		util.Error(w, "contact ID is required", http.StatusBadRequest)
+		util.Error(w, domainErrors.IDRequired("contact"), http.StatusBadRequest)
 		return
 	}
 
 	cid, err := uuid.Parse(contactID)
 	if err != nil {
-		util.Error(w, "invalid contact ID", http.StatusBadRequest)
+		util.Error(w, domainErrors.InvalidID("contact"), http.StatusBadRequest)
 		return
 	}
 
 	if err := h.app.EmergencyContactUseCase.Delete(r.Context(), uid, cid); err != nil {
 		slog.Error("error deleting emergency contact", "user_id", userIDStr, "contact_id", contactID, "error", err)
 		if err.Error() == "emergency contact not found" {
-			util.Error(w, err.Error(), http.StatusNotFound)
+			util.Error(w, err, http.StatusNotFound)
 			return
 		}
-		util.Error(w, err.Error(), http.StatusInternalServerError)
+		util.Error(w, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -232,32 +233,32 @@ func (h *EmergencyContactHandler) SendEmergencyAlert(w http.ResponseWriter, r *h
 	userIDStr, ok := util.GetUserIDFromContext(r.Context())
 	if !ok {
 		slog.Error("failed to get user ID from context")
-		util.Error(w, "unauthorized", http.StatusUnauthorized)
+		util.Error(w, domainErrors.ErrUnauthorized, http.StatusUnauthorized)
 		return
 	}
 
 	uid, err := dto.ParseUUID(userIDStr)
 	if err != nil {
 		slog.Error("invalid user ID in context", "error", err)
-		util.Error(w, "unauthorized", http.StatusUnauthorized)
+		util.Error(w, domainErrors.ErrUnauthorized, http.StatusUnauthorized)
 		return
 	}
 
 	var input dto.EmergencyAlertInput
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		util.Error(w, "invalid request body", http.StatusBadRequest)
+		util.Error(w, domainErrors.ErrInvalidRequestBody, http.StatusBadRequest)
 		return
 	}
 
 	if input.Latitude == 0 || input.Longitude == 0 {
-		util.Error(w, "latitude and longitude are required", http.StatusBadRequest)
+		util.Error(w, domainErrors.ErrLocationRequired, http.StatusBadRequest)
 		return
 	}
 
 	result, err := h.app.EmergencyAlertUseCase.SendEmergencyAlert(r.Context(), uid, input)
 	if err != nil {
 		slog.Error("error sending emergency alert", "user_id", userIDStr, "error", err)
-		util.Error(w, err.Error(), http.StatusInternalServerError)
+		util.Error(w, err, http.StatusInternalServerError)
 		return
 	}
 
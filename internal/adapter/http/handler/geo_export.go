@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/risk-place-angola/backend-risk-place/internal/adapter/http/util"
+	"github.com/risk-place-angola/backend-risk-place/internal/application/dto"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+)
+
+// exportFormat negotiates the representation of a response that can also
+// be exported as GeoJSON, GPX or KML.
+func exportFormat(w http.ResponseWriter, r *http.Request) (util.GeoFormat, bool) {
+	w.Header().Add("Vary", "Accept")
+	format, err := util.NegotiateGeoFormat(r)
+	if err != nil {
+		util.Error(w, err, http.StatusBadRequest)
+		return "", false
+	}
+	return format, true
+}
+
+// respondExport writes response as JSON, or its features in the negotiated
+// geo format.
+func respondExport(w http.ResponseWriter, format util.GeoFormat, response any, filename string, features func() []util.GeoFeature) {
+	if format == util.GeoFormatJSON {
+		util.Response(w, response, http.StatusOK)
+		return
+	}
+	util.GeoResponse(w, format, filename, features())
+}
+
+// safeRouteFeatures exports the route and its alternatives as lines, the
+// incidents along them as points and the manual danger zones they cross
+// as polygons. The response already leaves private reports out.
+func safeRouteFeatures(route *dto.SafeRouteResponse) []util.GeoFeature {
+	routes := append([]dto.SafeRouteResponse{*route}, route.Alternatives...)
+
+	var (
+		lines, points, zones []util.GeoFeature
+		seenIncidents        = map[string]bool{}
+		seenZones            = map[string]bool{}
+	)
+	for i, r := range routes {
+		line := util.GeoFeature{
+			Name:       routeName(i, r.Labels),
+			Geometry:   util.GeoGeometryLineString,
+			Properties: util.GeoProperties(r, "waypoints", "incidents", "danger_zones", "alternatives"),
+		}
+		for _, wp := range r.Waypoints {
+			line.Coordinates = append(line.Coordinates, util.GeoCoordinate{Latitude: wp.Latitude, Longitude: wp.Longitude})
+		}
+		lines = append(lines, line)
+
+		for _, incident := range r.Incidents {
+			if seenIncidents[incident.ReportID] {
+				continue
+			}
+			seenIncidents[incident.ReportID] = true
+			properties := util.GeoProperties(incident, "latitude", "longitude", "distance_km", "weight_factor")
+			points = append(points, util.NewGeoPoint(incidentName(incident.RiskType, incident.RiskTopic), incident.Latitude, incident.Longitude, properties))
+		}
+
+		for _, zone := range r.DangerZones {
+			if seenZones[zone.ID] {
+				continue
+			}
+			seenZones[zone.ID] = true
+			zones = append(zones, manualZoneFeature(zone))
+		}
+	}
+
+	return append(append(lines, points...), zones...)
+}
+
+func heatmapFeatures(heatmap *dto.HeatmapResponse) []util.GeoFeature {
+	features := make([]util.GeoFeature, 0, len(heatmap.Points))
+	for _, p := range heatmap.Points {
+		features = append(features, util.NewGeoPoint(p.IncidentType, p.Latitude, p.Longitude, util.GeoProperties(p, "latitude", "longitude")))
+	}
+	return features
+}
+
+// reportFeatures exports reports as points; the report lists already leave
+// private reports out.
+func reportFeatures(reports []dto.ReportDTO) []util.GeoFeature {
+	features := make([]util.GeoFeature, 0, len(reports))
+	for _, report := range reports {
+		features = append(features, util.NewGeoPoint(
+			incidentName(report.RiskTypeName, report.RiskTopicName),
+			report.Latitude,
+			report.Longitude,
+			util.GeoProperties(report, "latitude", "longitude"),
+		))
+	}
+	return features
+}
+
+func nearbyReportFeatures(reports []dto.ReportWithDistance) []util.GeoFeature {
+	features := make([]util.GeoFeature, 0, len(reports))
+	for _, report := range reports {
+		features = append(features, util.NewGeoPoint(
+			incidentName(report.RiskTypeName, report.RiskTopicName),
+			report.Latitude,
+			report.Longitude,
+			util.GeoProperties(report, "latitude", "longitude"),
+		))
+	}
+	return features
+}
+
+// dangerZoneFeatures exports computed zones as their grid cell and manual
+// zones as the polygon drawn for them.
+func dangerZoneFeatures(zones []dto.DangerZoneDTO) []util.GeoFeature {
+	features := make([]util.GeoFeature, 0, len(zones))
+	for _, zone := range zones {
+		name := "Danger zone " + zone.GridCellID + " (" + zone.RiskLevel + ")"
+		properties := util.GeoProperties(zone, "latitude", "longitude", "manual")
+
+		if zone.Manual != nil {
+			feature := manualZoneFeature(*zone.Manual)
+			for k, v := range properties {
+				if _, ok := feature.Properties[k]; !ok {
+					feature.Properties[k] = v
+				}
+			}
+			features = append(features, feature)
+			continue
+		}
+
+		cell, err := model.DecodeGeohash(zone.GridCellID)
+		if err != nil {
+			features = append(features, util.NewGeoPoint(name, zone.Latitude, zone.Longitude, properties))
+			continue
+		}
+		features = append(features, util.GeoFeature{
+			Name:     name,
+			Geometry: util.GeoGeometryPolygon,
+			Coordinates: []util.GeoCoordinate{
+				{Latitude: cell.MinLat, Longitude: cell.MinLon},
+				{Latitude: cell.MinLat, Longitude: cell.MaxLon},
+				{Latitude: cell.MaxLat, Longitude: cell.MaxLon},
+				{Latitude: cell.MaxLat, Longitude: cell.MinLon},
+			},
+			Properties: properties,
+		})
+	}
+	return features
+}
+
+func manualZoneFeature(zone dto.ManualDangerZoneDTO) util.GeoFeature {
+	feature := util.GeoFeature{
+		Name:       zone.Reason,
+		Geometry:   util.GeoGeometryPolygon,
+		Properties: util.GeoProperties(zone, "polygon"),
+	}
+	for _, p := range zone.Polygon {
+		feature.Coordinates = append(feature.Coordinates, util.GeoCoordinate{Latitude: p.Latitude, Longitude: p.Longitude})
+	}
+	return feature
+}
+
+func routeName(index int, labels []string) string {
+	if len(labels) > 0 {
+		return fmt.Sprintf("Route %d (%s)", index+1, strings.Join(labels, ", "))
+	}
+	return fmt.Sprintf("Route %d", index+1)
+}
+
+func incidentName(riskType, riskTopic string) string {
+	if riskTopic == "" {
+		return riskType
+	}
+	return riskType + ": " + riskTopic
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 
@@ -9,10 +10,7 @@ import (
 	"github.com/risk-place-angola/backend-risk-place/internal/adapter/http/util"
 	"github.com/risk-place-angola/backend-risk-place/internal/application"
 	"github.com/risk-place-angola/backend-risk-place/internal/application/dto"
-)
-
-const (
-	errLocationSharingNotFound = "location sharing not found"
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
 )
 
 type LocationSharingHandler struct {
@@ -42,13 +40,13 @@ func (h *LocationSharingHandler) CreateLocationSharing(w http.ResponseWriter, r
 	identifier, isAuthenticated := util.GetIdentifierFromContext(r.Context())
 	if identifier == "" {
 		slog.Error("failed to get identifier from context")
-		util.Error(w, "unauthorized: JWT or X-Device-ID required", http.StatusUnauthorized)
+		util.Error(w, domainErrors.ErrCredentialsRequired, http.StatusUnauthorized)
 		return
 	}
 
 	var req dto.CreateLocationSharingRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		util.Error(w, "invalid request", http.StatusBadRequest)
+		util.Error(w, domainErrors.ErrInvalidRequestBody, http.StatusBadRequest)
 		return
 	}
 
@@ -59,7 +57,7 @@ func (h *LocationSharingHandler) CreateLocationSharing(w http.ResponseWriter, r
 		userID, parseErr := uuid.Parse(identifier)
 		if parseErr != nil {
 			slog.Error("invalid user ID", "error", parseErr)
-			util.Error(w, "invalid user ID", http.StatusBadRequest)
+			util.Error(w, domainErrors.ErrInvalidUserID, http.StatusBadRequest)
 			return
 		}
 		response, err = h.app.LocationSharingUseCase.CreateLocationSharingForUser(r.Context(), userID, req)
@@ -68,7 +66,7 @@ func (h *LocationSharingHandler) CreateLocationSharing(w http.ResponseWriter, r
 	}
 
 	if err != nil {
-		util.Error(w, err.Error(), http.StatusInternalServerError)
+		util.Error(w, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -95,15 +93,15 @@ func (h *LocationSharingHandler) GetPublicLocationSharing(w http.ResponseWriter,
 
 	response, err := h.app.LocationSharingUseCase.GetLocationSharingByToken(r.Context(), token)
 	if err != nil {
-		if err.Error() == errLocationSharingNotFound {
-			util.Error(w, errLocationSharingNotFound, http.StatusNotFound)
+		if errors.Is(err, domainErrors.ErrLocationSharingNotFound) {
+			util.Error(w, domainErrors.ErrLocationSharingNotFound, http.StatusNotFound)
 			return
 		}
-		if err.Error() == "location sharing is expired or inactive" {
-			util.Error(w, "location sharing expired", http.StatusGone)
+		if errors.Is(err, domainErrors.ErrLocationSharingExpired) {
+			util.Error(w, domainErrors.ErrLocationSharingExpired, http.StatusGone)
 			return
 		}
-		util.Error(w, err.Error(), http.StatusInternalServerError)
+		util.Error(w, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -129,25 +127,25 @@ func (h *LocationSharingHandler) UpdateLocationSharing(w http.ResponseWriter, r
 	identifier, isAuthenticated := util.GetIdentifierFromContext(r.Context())
 	if identifier == "" {
 		slog.Error("failed to get identifier from context")
-		util.Error(w, "unauthorized: JWT or X-Device-ID required", http.StatusUnauthorized)
+		util.Error(w, domainErrors.ErrCredentialsRequired, http.StatusUnauthorized)
 		return
 	}
 
 	sharingIDStr := r.PathValue("id")
 	if sharingIDStr == "" {
-		util.Error(w, "id is required", http.StatusBadRequest)
+		util.Error(w, domainErrors.ErrIDRequired, http.StatusBadRequest)
 		return
 	}
 
 	sharingID, err := uuid.Parse(sharingIDStr)
 	if err != nil {
-		util.Error(w, "invalid id", http.StatusBadRequest)
+		util.Error(w, domainErrors.ErrInvalidID, http.StatusBadRequest)
 		return
 	}
 
 	var req dto.UpdateLocationRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		util.Error(w, "invalid request", http.StatusBadRequest)
+		util.Error(w, domainErrors.ErrInvalidRequestBody, http.StatusBadRequest)
 		return
 	}
 
@@ -155,7 +153,7 @@ func (h *LocationSharingHandler) UpdateLocationSharing(w http.ResponseWriter, r
 		userID, parseErr := uuid.Parse(identifier)
 		if parseErr != nil {
 			slog.Error("invalid user ID", "error", parseErr)
-			util.Error(w, "invalid user ID", http.StatusBadRequest)
+			util.Error(w, domainErrors.ErrInvalidUserID, http.StatusBadRequest)
 			return
 		}
 		err = h.app.LocationSharingUseCase.UpdateLocationForUser(r.Context(), sharingID, userID, req)
@@ -164,19 +162,19 @@ func (h *LocationSharingHandler) UpdateLocationSharing(w http.ResponseWriter, r
 	}
 
 	if err != nil {
-		if err.Error() == errLocationSharingNotFound {
-			util.Error(w, errLocationSharingNotFound, http.StatusNotFound)
+		if errors.Is(err, domainErrors.ErrLocationSharingNotFound) {
+			util.Error(w, domainErrors.ErrLocationSharingNotFound, http.StatusNotFound)
 			return
 		}
-		if err.Error() == "unauthorized" {
-			util.Error(w, "unauthorized", http.StatusForbidden)
+		if errors.Is(err, domainErrors.ErrUnauthorized) {
+			util.Error(w, domainErrors.ErrUnauthorized, http.StatusForbidden)
 			return
 		}
-		if err.Error() == "location sharing is expired or inactive" {
-			util.Error(w, "location sharing expired", http.StatusGone)
+		if errors.Is(err, domainErrors.ErrLocationSharingExpired) {
+			util.Error(w, domainErrors.ErrLocationSharingExpired, http.StatusGone)
 			return
 		}
-		util.Error(w, err.Error(), http.StatusInternalServerError)
+		util.Error(w, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -201,19 +199,19 @@ func (h *LocationSharingHandler) DeleteLocationSharing(w http.ResponseWriter, r
 	identifier, isAuthenticated := util.GetIdentifierFromContext(r.Context())
 	if identifier == "" {
 		slog.Error("failed to get identifier from context")
-		util.Error(w, "unauthorized: JWT or X-Device-ID required", http.StatusUnauthorized)
+		util.Error(w, domainErrors.ErrCredentialsRequired, http.StatusUnauthorized)
 		return
 	}
 
 	sharingIDStr := r.PathValue("id")
 	if sharingIDStr == "" {
-		util.Error(w, "id is required", http.StatusBadRequest)
+		util.Error(w, domainErrors.ErrIDRequired, http.StatusBadRequest)
 		return
 	}
 
 	sharingID, err := uuid.Parse(sharingIDStr)
 	if err != nil {
-		util.Error(w, "invalid id", http.StatusBadRequest)
+		util.Error(w, domainErrors.ErrInvalidID, http.StatusBadRequest)
 		return
 	}
 
@@ -221,7 +219,7 @@ func (h *LocationSharingHandler) DeleteLocationSharing(w http.ResponseWriter, r
 		userID, parseErr := uuid.Parse(identifier)
 		if parseErr != nil {
 			slog.Error("invalid user ID", "error", parseErr)
-			util.Error(w, "invalid user ID", http.StatusBadRequest)
+			util.Error(w, domainErrors.ErrInvalidUserID, http.StatusBadRequest)
 			return
 		}
 		err = h.app.LocationSharingUseCase.DeleteLocationSharingForUser(r.Context(), sharingID, userID)
@@ -230,15 +228,15 @@ func (h *LocationSharingHandler) DeleteLocationSharing(w http.ResponseWriter, r
 	}
 
 	if err != nil {
-		if err.Error() == errLocationSharingNotFound {
-			util.Error(w, errLocationSharingNotFound, http.StatusNotFound)
+		if errors.Is(err, domainErrors.ErrLocationSharingNotFound) {
+			util.Error(w, domainErrors.ErrLocationSharingNotFound, http.StatusNotFound)
 			return
 		}
-		if err.Error() == "unauthorized" {
-			util.Error(w, "unauthorized", http.StatusForbidden)
+		if errors.Is(err, domainErrors.ErrUnauthorized) {
+			util.Error(w, domainErrors.ErrUnauthorized, http.StatusForbidden)
 			return
 		}
-		util.Error(w, err.Error(), http.StatusInternalServerError)
+		util.Error(w, err, http.StatusInternalServerError)
 		return
 	}
 
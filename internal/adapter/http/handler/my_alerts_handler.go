@@ -46,21 +46,21 @@ func (h *MyAlertsHandler) GetMyCreatedAlerts(w http.ResponseWriter, r *http.Requ
 	userIDStr, ok := util.GetUserIDFromContext(r.Context())
 	if !ok {
 		slog.Error("failed to get user ID from context")
-		util.Error(w, "unauthorized", http.StatusUnauthorized)
+		util.Error(w, domainErrors.ErrUnauthorized, http.StatusUnauthorized)
 		return
 	}
 
 	uid, err := dto.ParseUUID(userIDStr)
 	if err != nil {
 		slog.Error("invalid user ID in context", "error", err)
-		util.Error(w, "unauthorized", http.StatusUnauthorized)
+		util.Error(w, domainErrors.ErrUnauthorized, http.StatusUnauthorized)
 		return
 	}
 
 	alerts, err := h.app.MyAlertsUseCase.GetMyCreatedAlerts(r.Context(), uid)
 	if err != nil {
 		slog.Error("error fetching user alerts", "user_id", uid, "error", err)
-		util.Error(w, err.Error(), http.StatusInternalServerError)
+		util.Error(w, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -81,21 +81,21 @@ func (h *MyAlertsHandler) GetMySubscribedAlerts(w http.ResponseWriter, r *http.R
 	userIDStr, ok := util.GetUserIDFromContext(r.Context())
 	if !ok {
 		slog.Error("failed to get user ID from context")
-		util.Error(w, "unauthorized", http.StatusUnauthorized)
+		util.Error(w, domainErrors.ErrUnauthorized, http.StatusUnauthorized)
 		return
 	}
 
 	uid, err := dto.ParseUUID(userIDStr)
 	if err != nil {
 		slog.Error("invalid user ID in context", "error", err)
-		util.Error(w, "unauthorized", http.StatusUnauthorized)
+		util.Error(w, domainErrors.ErrUnauthorized, http.StatusUnauthorized)
 		return
 	}
 
 	alerts, err := h.app.MyAlertsUseCase.GetMySubscribedAlerts(r.Context(), uid)
 	if err != nil {
 		slog.Error("error fetching subscribed alerts", "user_id", uid, "error", err)
-		util.Error(w, err.Error(), http.StatusInternalServerError)
+		util.Error(w, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -122,32 +122,32 @@ func (h *MyAlertsHandler) UpdateAlert(w http.ResponseWriter, r *http.Request) {
 	userIDStr, ok := util.GetUserIDFromContext(r.Context())
 	if !ok {
 		slog.Error("failed to get user ID from context")
-		util.Error(w, "unauthorized", http.StatusUnauthorized)
+		util.Error(w, domainErrors.ErrUnauthorized, http.StatusUnauthorized)
 		return
 	}
 
 	uid, err := dto.ParseUUID(userIDStr)
 	if err != nil {
 		slog.Error("invalid user ID in context", "error", err)
-		util.Error(w, "unauthorized", http.StatusUnauthorized)
+		util.Error(w, domainErrors.ErrUnauthorized, http.StatusUnauthorized)
 		return
 	}
 
 	alertID := r.PathValue("id")
 	if alertID == "" {
-		util.Error(w, "alert ID is required", http.StatusBadRequest)
+		util.Error(w, domainErrors.IDRequired("alert"), http.StatusBadRequest)
 		return
 	}
 
 	aid, err := uuid.Parse(alertID)
 	if err != nil {
-		util.Error(w, "invalid alert ID", http.StatusBadRequest)
+		util.Error(w, domainErrors.InvalidID("alert"), http.StatusBadRequest)
 		return
 	}
 
 	var input dto.UpdateAlertInput
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		util.Error(w, "invalid request body", http.StatusBadRequest)
+		util.Error(w, domainErrors.ErrInvalidRequestBody, http.StatusBadRequest)
 		return
 	}
 
@@ -155,14 +155,14 @@ func (h *MyAlertsHandler) UpdateAlert(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		slog.Error("error updating alert", "user_id", uid, "alert_id", aid, "error", err)
 		if errors.Is(err, domainErrors.ErrAlertNotFound) {
-			util.Error(w, err.Error(), http.StatusNotFound)
+			util.Error(w, err, http.StatusNotFound)
 			return
 		}
 		if err.Error() == "unauthorized: you can only update your own alerts" {
-			util.Error(w, err.Error(), http.StatusForbidden)
+			util.Error(w, err, http.StatusForbidden)
 			return
 		}
-		util.Error(w, err.Error(), http.StatusBadRequest)
+		util.Error(w, err, http.StatusBadRequest)
 		return
 	}
 
@@ -186,39 +186,39 @@ func (h *MyAlertsHandler) UpdateAlert(w http.ResponseWriter, r *http.Request) {
 func (h *MyAlertsHandler) DeleteAlert(w http.ResponseWriter, r *http.Request) {
 	userID, ok := r.Context().Value("user_id").(string)
 	if !ok || userID == "" {
-		util.Error(w, "unauthorized", http.StatusUnauthorized)
+		util.Error(w, domainErrors.ErrUnauthorized, http.StatusUnauthorized)
 		return
 	}
 
 	uid, err := uuid.Parse(userID)
 	if err != nil {
-		util.Error(w, "invalid user ID", http.StatusBadRequest)
+		util.Error(w, domainErrors.ErrInvalidUserID, http.StatusBadRequest)
 		return
 	}
 
 	alertID := r.PathValue("id")
 	if alertID == "" {
-		util.Error(w, "alert ID is required", http.StatusBadRequest)
+		util.Error(w, domainErrors.IDRequired("alert"), http.StatusBadRequest)
 		return
 	}
 
 	aid, err := uuid.Parse(alertID)
 	if err != nil {
-		util.Error(w, "invalid alert ID", http.StatusBadRequest)
+		util.Error(w, domainErrors.InvalidID("alert"), http.StatusBadRequest)
 		return
 	}
 
 	if err := h.app.MyAlertsUseCase.DeleteAlert(r.Context(), uid, aid); err != nil {
 		slog.Error("error deleting alert", "user_id", uid, "alert_id", aid, "error", err)
 		if errors.Is(err, domainErrors.ErrAlertNotFound) {
-			util.Error(w, err.Error(), http.StatusNotFound)
+			util.Error(w, err, http.StatusNotFound)
 			return
 		}
 		if err.Error() == "unauthorized: you can only delete your own alerts" {
-			util.Error(w, err.Error(), http.StatusForbidden)
+			util.Error(w, err, http.StatusForbidden)
 			return
 		}
-		util.Error(w, err.Error(), http.StatusInternalServerError)
+		util.Error(w, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -290,7 +290,7 @@ func (h *MyAlertsHandler) UnsubscribeFromAlert(w http.ResponseWriter, r *http.Re
 func (h *MyAlertsHandler) subscribeAuthenticatedUser(w http.ResponseWriter, r *http.Request, aid uuid.UUID, userIDStr string) {
 	uid, err := uuid.Parse(userIDStr)
 	if err != nil {
-		util.Error(w, "invalid user ID", http.StatusBadRequest)
+		util.Error(w, domainErrors.ErrInvalidUserID, http.StatusBadRequest)
 		return
 	}
 
@@ -298,10 +298,10 @@ func (h *MyAlertsHandler) subscribeAuthenticatedUser(w http.ResponseWriter, r *h
 	if err != nil {
 		slog.Error("failed to subscribe to alert", "user_id", uid, "alert_id", aid, "error", err)
 		if errors.Is(err, domainErrors.ErrAlertNotFound) {
-			util.Error(w, err.Error(), http.StatusNotFound)
+			util.Error(w, err, http.StatusNotFound)
 			return
 		}
-		util.Error(w, err.Error(), http.StatusBadRequest)
+		util.Error(w, err, http.StatusBadRequest)
 		return
 	}
 
@@ -375,7 +375,7 @@ func (h *MyAlertsHandler) getOrCreateSession(r *http.Request, deviceID string) (
 func (h *MyAlertsHandler) unsubscribeAuthenticatedUser(w http.ResponseWriter, r *http.Request, aid uuid.UUID, userIDStr string) {
 	uid, err := uuid.Parse(userIDStr)
 	if err != nil {
-		util.Error(w, "invalid user ID", http.StatusBadRequest)
+		util.Error(w, domainErrors.ErrInvalidUserID, http.StatusBadRequest)
 		return
 	}
 
@@ -383,10 +383,10 @@ func (h *MyAlertsHandler) unsubscribeAuthenticatedUser(w http.ResponseWriter, r
 	if err != nil {
 		slog.Error("error unsubscribing from alert", "user_id", uid, "alert_id", aid, "error", err)
 		if err.Error() == "you are not subscribed to this alert" {
-			util.Error(w, err.Error(), http.StatusBadRequest)
+			util.Error(w, err, http.StatusBadRequest)
 			return
 		}
-		util.Error(w, err.Error(), http.StatusInternalServerError)
+		util.Error(w, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -397,7 +397,7 @@ func (h *MyAlertsHandler) unsubscribeAnonymousUser(w http.ResponseWriter, r *htt
 	session, err := h.anonymousSessionRepo.FindByDeviceID(r.Context(), deviceID)
 	if err != nil {
 		slog.Error("anonymous session not found", "device_id", deviceID, "error", err)
-		util.Error(w, "session not found", http.StatusNotFound)
+		util.Error(w, domainErrors.ErrSessionNotFound, http.StatusNotFound)
 		return
 	}
 
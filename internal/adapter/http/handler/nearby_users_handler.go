@@ -9,6 +9,7 @@ import (
 
 	httputil "github.com/risk-place-angola/backend-risk-place/internal/adapter/http/util"
 	"github.com/risk-place-angola/backend-risk-place/internal/application/port"
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
 )
 
 const (
@@ -92,7 +93,7 @@ func (h *NearbyUsersHandler) UpdateLocation(w http.ResponseWriter, r *http.Reque
 	var req UpdateLocationRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		slog.Error("failed to decode request", slog.Any("error", err))
-		httputil.Error(w, "invalid request body", http.StatusBadRequest)
+		httputil.Error(w, domainErrors.ErrInvalidRequestBody, http.StatusBadRequest)
 		return
 	}
 
@@ -126,14 +127,14 @@ func (h *NearbyUsersHandler) GetNearbyUsers(w http.ResponseWriter, r *http.Reque
 	}
 
 	if !h.checkRateLimit(identifier.UserID) {
-		httputil.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+		httputil.Error(w, domainErrors.ErrRateLimited, http.StatusTooManyRequests)
 		return
 	}
 
 	var req GetNearbyUsersRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		slog.Error("failed to decode request", slog.Any("error", err))
-		httputil.Error(w, "invalid request body", http.StatusBadRequest)
+		httputil.Error(w, domainErrors.ErrInvalidRequestBody, http.StatusBadRequest)
 		return
 	}
 
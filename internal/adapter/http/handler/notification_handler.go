@@ -2,21 +2,40 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"html/template"
+	"log/slog"
 	"net/http"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/risk-place-angola/backend-risk-place/internal/adapter/http/util"
 	"github.com/risk-place-angola/backend-risk-place/internal/application"
 	"github.com/risk-place-angola/backend-risk-place/internal/application/dto"
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
 )
 
+// unsubscribePageHTML asks for confirmation before unsubscribing; the form
+// posts back to the link it was opened from, token included.
+const unsubscribePageHTML = `<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1"><title>Risk Place</title></head>
+<body style="font-family: sans-serif; max-width: 32rem; margin: 3rem auto; padding: 0 1rem;">
+<p>{{.Message}}</p>
+{{if .Button}}<form method="post"><input type="hidden" name="token" value="{{.Token}}"><button type="submit">{{.Button}}</button></form>{{end}}
+</body>
+</html>
+`
+
 type NotificationHandler struct {
-	app *application.Application
+	app             *application.Application
+	unsubscribePage *template.Template
 }
 
 func NewNotificationHandler(app *application.Application) *NotificationHandler {
 	return &NotificationHandler{
-		app: app,
+		app:             app,
+		unsubscribePage: template.Must(template.New("unsubscribe").Parse(unsubscribePageHTML)),
 	}
 }
 
@@ -37,19 +56,19 @@ func (h *NotificationHandler) UpdateDeviceInfo(w http.ResponseWriter, r *http.Re
 	var req dto.UpdateDeviceInfoRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		util.Error(w, "invalid request", http.StatusBadRequest)
+		util.Error(w, domainErrors.ErrInvalidRequestBody, http.StatusBadRequest)
 		return
 	}
 
 	userIDStr, ok := util.GetUserIDFromContext(r.Context())
 	if !ok {
-		util.Error(w, "unauthorized", http.StatusUnauthorized)
+		util.Error(w, domainErrors.ErrUnauthorized, http.StatusUnauthorized)
 		return
 	}
 
 	uid, err := dto.ParseUUID(userIDStr)
 	if err != nil {
-		util.Error(w, "invalid user ID", http.StatusBadRequest)
+		util.Error(w, domainErrors.ErrInvalidUserID, http.StatusBadRequest)
 		return
 	}
 
@@ -64,7 +83,8 @@ func (h *NotificationHandler) UpdateDeviceInfo(w http.ResponseWriter, r *http.Re
 
 // UpdateNotificationPreferences godoc
 // @Summary Update notification preferences
-// @Description Update push and SMS notification preferences for authenticated users or anonymous sessions
+// @Description Update push, SMS and email notification preferences for authenticated users or anonymous sessions.
+// @Description Email alerts can only be enabled by registered users.
 // @Tags notifications
 // @Accept json
 // @Produce json
@@ -72,13 +92,14 @@ func (h *NotificationHandler) UpdateDeviceInfo(w http.ResponseWriter, r *http.Re
 // @Param preferences body dto.NotificationPreferencesRequest true "Notification preferences"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} util.ErrorResponse
+// @Failure 403 {object} util.ErrorResponse
 // @Failure 500 {object} util.ErrorResponse
 // @Router /users/me/notifications/preferences [put]
 func (h *NotificationHandler) UpdateNotificationPreferences(w http.ResponseWriter, r *http.Request) {
 	var req dto.NotificationPreferencesRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		util.Error(w, "invalid request", http.StatusBadRequest)
+		util.Error(w, domainErrors.ErrInvalidRequestBody, http.StatusBadRequest)
 		return
 	}
 
@@ -90,7 +111,7 @@ func (h *NotificationHandler) UpdateNotificationPreferences(w http.ResponseWrite
 	if identifier.IsAuthenticated {
 		uid, err := dto.ParseUUID(identifier.UserID)
 		if err != nil {
-			util.Error(w, "invalid user ID", http.StatusBadRequest)
+			util.Error(w, domainErrors.ErrInvalidUserID, http.StatusBadRequest)
 			return
 		}
 
@@ -99,7 +120,19 @@ func (h *NotificationHandler) UpdateNotificationPreferences(w http.ResponseWrite
 			util.Error(w, "failed to update preferences", http.StatusInternalServerError)
 			return
 		}
+
+		if req.EmailEnabled != nil {
+			if err := h.app.EmailNotificationUseCase.SetEnabled(r.Context(), uid, *req.EmailEnabled); err != nil {
+				util.Error(w, err, http.StatusInternalServerError)
+				return
+			}
+		}
 	} else {
+		if req.EmailEnabled != nil && *req.EmailEnabled {
+			util.Error(w, domainErrors.ErrEmailRequiresAccount, http.StatusForbidden)
+			return
+		}
+
 		err := h.app.UserUseCase.UpdateNotificationPreferences(r.Context(), uuid.Nil, identifier.DeviceID, req.PushEnabled, req.SMSEnabled)
 		if err != nil {
 			util.Error(w, "failed to update preferences", http.StatusInternalServerError)
@@ -112,7 +145,7 @@ func (h *NotificationHandler) UpdateNotificationPreferences(w http.ResponseWrite
 
 // GetNotificationPreferences godoc
 // @Summary Get notification preferences
-// @Description Get push and SMS notification preferences for authenticated users or anonymous sessions
+// @Description Get push, SMS and email notification preferences for authenticated users or anonymous sessions
 // @Tags notifications
 // @Produce json
 // @Param X-Device-Id header string false "Device ID for anonymous users"
@@ -126,13 +159,13 @@ func (h *NotificationHandler) GetNotificationPreferences(w http.ResponseWriter,
 		return
 	}
 
-	var pushEnabled, smsEnabled bool
+	var pushEnabled, smsEnabled, emailEnabled bool
 	var err error
 
 	if identifier.IsAuthenticated {
 		uid, parseErr := dto.ParseUUID(identifier.UserID)
 		if parseErr != nil {
-			util.Error(w, "invalid user ID", http.StatusBadRequest)
+			util.Error(w, domainErrors.ErrInvalidUserID, http.StatusBadRequest)
 			return
 		}
 
@@ -141,6 +174,12 @@ func (h *NotificationHandler) GetNotificationPreferences(w http.ResponseWriter,
 			util.Error(w, "failed to get preferences", http.StatusInternalServerError)
 			return
 		}
+
+		emailEnabled, err = h.app.EmailNotificationUseCase.IsEnabled(r.Context(), uid)
+		if err != nil {
+			util.Error(w, err, http.StatusInternalServerError)
+			return
+		}
 	} else {
 		pushEnabled, smsEnabled, err = h.app.UserUseCase.GetNotificationPreferences(r.Context(), uuid.Nil, identifier.DeviceID)
 		if err != nil {
@@ -150,7 +189,78 @@ func (h *NotificationHandler) GetNotificationPreferences(w http.ResponseWriter,
 	}
 
 	util.Response(w, dto.NotificationPreferencesResponse{
-		PushEnabled: pushEnabled,
-		SMSEnabled:  smsEnabled,
+		PushEnabled:  pushEnabled,
+		SMSEnabled:   smsEnabled,
+		EmailEnabled: emailEnabled,
 	}, http.StatusOK)
 }
+
+// UnsubscribeEmailPage godoc
+// @Summary Confirm unsubscribing from email alerts
+// @Description Render the page the link in every alert email opens. It only asks for confirmation, so mail scanners
+// @Description and link prefetchers that open the link do not unsubscribe the user.
+// @Tags notifications
+// @Produce html
+// @Param token query string true "Signed unsubscribe token"
+// @Success 200 {string} string "confirmation page"
+// @Failure 400 {object} util.ErrorResponse
+// @Router /notifications/email/unsubscribe [get]
+func (h *NotificationHandler) UnsubscribeEmailPage(w http.ResponseWriter, r *http.Request) {
+	page, err := h.app.EmailNotificationUseCase.UnsubscribePage(r.Context(), r.URL.Query().Get("token"))
+	if err != nil {
+		util.Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	h.renderUnsubscribePage(w, page.Question, page)
+}
+
+// UnsubscribeEmail godoc
+// @Summary Unsubscribe from email alerts
+// @Description Turn email alerts off using the signed token from the link in every alert email.
+// @Description Supports RFC 8058 one-click unsubscribe from mail clients and the form on the confirmation page.
+// @Tags notifications
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param token query string true "Signed unsubscribe token"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 500 {object} util.ErrorResponse
+// @Router /notifications/email/unsubscribe [post]
+func (h *NotificationHandler) UnsubscribeEmail(w http.ResponseWriter, r *http.Request) {
+	token := r.FormValue("token")
+	if token == "" {
+		util.Error(w, domainErrors.ErrInvalidUnsubscribeToken, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.app.EmailNotificationUseCase.Unsubscribe(r.Context(), token); err != nil {
+		if errors.Is(err, domainErrors.ErrInvalidUnsubscribeToken) {
+			util.Error(w, err, http.StatusBadRequest)
+			return
+		}
+		util.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	message := h.app.EmailNotificationUseCase.Unsubscribed(r.Context())
+	if strings.Contains(r.Header.Get("Accept"), "text/html") {
+		h.renderUnsubscribePage(w, message, dto.EmailUnsubscribePage{})
+		return
+	}
+
+	util.Response(w, map[string]string{"message": message}, http.StatusOK)
+}
+
+func (h *NotificationHandler) renderUnsubscribePage(w http.ResponseWriter, message string, page dto.EmailUnsubscribePage) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+
+	err := h.unsubscribePage.Execute(w, struct {
+		Message, Button, Token string
+	}{Message: message, Button: page.Button, Token: page.Token})
+	if err != nil {
+		slog.Error("failed to render unsubscribe page", "error", err)
+	}
+}
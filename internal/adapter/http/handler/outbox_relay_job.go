@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+const outboxRelayInterval = time.Second
+
+type outboxRelayer interface {
+	RelayPending(ctx context.Context) error
+}
+
+func StartOutboxRelayJob(ctx context.Context, relay outboxRelayer) {
+	go func() {
+		ticker := time.NewTicker(outboxRelayInterval)
+		defer ticker.Stop()
+
+		slog.Info("starting outbox relay job", "interval", outboxRelayInterval)
+
+		for {
+			select {
+			case <-ctx.Done():
+				slog.Info("outbox relay job stopped")
+				return
+			case <-ticker.C:
+				if err := relay.RelayPending(ctx); err != nil {
+					slog.Error("outbox relay failed", "error", err)
+				}
+			}
+		}
+	}()
+}
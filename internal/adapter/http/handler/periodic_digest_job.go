@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+const periodicDigestInterval = 15 * time.Minute
+
+type periodicDigestSender interface {
+	SendDueDigests(ctx context.Context) error
+}
+
+func StartPeriodicDigestJob(ctx context.Context, digestService periodicDigestSender) {
+	go func() {
+		ticker := time.NewTicker(periodicDigestInterval)
+		defer ticker.Stop()
+
+		slog.Info("starting periodic digest job", "interval", periodicDigestInterval)
+
+		for {
+			select {
+			case <-ctx.Done():
+				slog.Info("periodic digest job stopped")
+				return
+			case <-ticker.C:
+				if err := digestService.SendDueDigests(ctx); err != nil {
+					slog.Error("periodic digest failed", "error", err)
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+const quietHoursDigestInterval = 5 * time.Minute
+
+type quietHoursDigestSender interface {
+	SendDueDigests(ctx context.Context) error
+}
+
+func StartQuietHoursDigestJob(ctx context.Context, digestService quietHoursDigestSender) {
+	go func() {
+		ticker := time.NewTicker(quietHoursDigestInterval)
+		defer ticker.Stop()
+
+		slog.Info("starting quiet hours digest job", "interval", quietHoursDigestInterval)
+
+		for {
+			select {
+			case <-ctx.Done():
+				slog.Info("quiet hours digest job stopped")
+				return
+			case <-ticker.C:
+				if err := digestService.SendDueDigests(ctx); err != nil {
+					slog.Error("quiet hours digest failed", "error", err)
+				}
+			}
+		}
+	}()
+}
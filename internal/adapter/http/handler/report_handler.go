@@ -13,6 +13,7 @@ import (
 
 	"github.com/risk-place-angola/backend-risk-place/internal/adapter/http/util"
 	"github.com/risk-place-angola/backend-risk-place/internal/application/dto"
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
 )
 
 type ReportHandler struct {
@@ -45,13 +46,13 @@ func (h *ReportHandler) Create(w http.ResponseWriter, r *http.Request) {
 	userIDStr, ok := util.GetUserIDFromContext(r.Context())
 	if !ok {
 		slog.Error("failed to get user ID from context")
-		util.Error(w, "unauthorized", http.StatusUnauthorized)
+		util.Error(w, domainErrors.ErrUnauthorized, http.StatusUnauthorized)
 		return
 	}
 
 	var req dto.ReportCreate
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		util.Error(w, "Invalid JSON", http.StatusBadRequest)
+		util.Error(w, domainErrors.ErrInvalidRequestBody, http.StatusBadRequest)
 		return
 	}
 
@@ -59,7 +60,7 @@ func (h *ReportHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 	report, err := h.reportUseCase.ReportUseCase.Create(r.Context(), req)
 	if err != nil {
-		util.Error(w, err.Error(), http.StatusInternalServerError)
+		util.Error(w, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -69,21 +70,28 @@ func (h *ReportHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 // List godoc
 // @Summary List all reports with pagination
-// @Description List all reports in the system with pagination and filters
+// @Description List all reports in the system with pagination and filters. With format=geojson, gpx or kml, or the
+// @Description matching Accept header, the page comes as points; private reports are never listed.
 // @Tags reports
 // @Accept json
-// @Produce json
+// @Produce json,application/geo+json,application/gpx+xml,application/vnd.google-earth.kml+xml
 // @Security BearerAuth
 // @Param page query int false "Page number (default: 1)"
 // @Param limit query int false "Items per page (default: 20, max: 100)"
 // @Param status query string false "Filter by status (pending, verified, resolved)"
 // @Param sort query string false "Sort field (default: created_at)"
 // @Param order query string false "Sort order (asc, desc) (default: desc)"
+// @Param format query string false "Export format" Enums(json, geojson, gpx, kml)
 // @Success 200 {object} dto.ListReportsResponse
 // @Failure 400 {object} util.ErrorResponse
 // @Failure 500 {object} util.ErrorResponse
 // @Router /reports [get]
 func (h *ReportHandler) List(w http.ResponseWriter, r *http.Request) {
+	format, ok := exportFormat(w, r)
+	if !ok {
+		return
+	}
+
 	// Parse query parameters
 	pageStr := r.URL.Query().Get("page")
 	limitStr := r.URL.Query().Get("limit")
@@ -124,29 +132,38 @@ func (h *ReportHandler) List(w http.ResponseWriter, r *http.Request) {
 	response, err := h.reportUseCase.ReportUseCase.List(r.Context(), params)
 	if err != nil {
 		slog.Error("failed to list reports", "error", err)
-		util.Error(w, err.Error(), http.StatusInternalServerError)
+		util.Error(w, err, http.StatusInternalServerError)
 		return
 	}
 
-	util.Response(w, response, http.StatusOK)
+	respondExport(w, format, response, "reports", func() []util.GeoFeature {
+		return reportFeatures(response.Reports)
+	})
 }
 
 // ListNearby godoc
 // @Summary List nearby reports with distance
-// @Description List reports near the specified location with calculated distance
+// @Description List reports near the specified location with calculated distance, also as GeoJSON, GPX or KML
+// @Description points with format or the matching Accept header; private reports are never listed.
 // @Tags reports
 // @Accept json
-// @Produce json
+// @Produce json,application/geo+json,application/gpx+xml,application/vnd.google-earth.kml+xml
 // @Security BearerAuth
 // @Param latitude query number true "Latitude"
 // @Param longitude query number true "Longitude"
 // @Param radius query number true "Radius in meters"
 // @Param limit query int false "Maximum number of results (default: 50)"
+// @Param format query string false "Export format" Enums(json, geojson, gpx, kml)
 // @Success 200 {object} dto.NearbyReportsResponse
 // @Failure 400 {object} util.ErrorResponse
 // @Failure 500 {object} util.ErrorResponse
 // @Router /reports/nearby [get]
 func (h *ReportHandler) ListNearby(w http.ResponseWriter, r *http.Request) {
+	format, ok := exportFormat(w, r)
+	if !ok {
+		return
+	}
+
 	latStr := r.URL.Query().Get("latitude")
 	lonStr := r.URL.Query().Get("longitude")
 	radiusStr := r.URL.Query().Get("radius")
@@ -154,11 +171,11 @@ func (h *ReportHandler) ListNearby(w http.ResponseWriter, r *http.Request) {
 
 	// Validate required parameters
 	if latStr == "" {
-		util.Error(w, "latitude is required", http.StatusBadRequest)
+		util.Error(w, domainErrors.ErrLocationRequired, http.StatusBadRequest)
 		return
 	}
 	if lonStr == "" {
-		util.Error(w, "longitude is required", http.StatusBadRequest)
+		util.Error(w, domainErrors.ErrLocationRequired, http.StatusBadRequest)
 		return
 	}
 	if radiusStr == "" {
@@ -169,13 +186,13 @@ func (h *ReportHandler) ListNearby(w http.ResponseWriter, r *http.Request) {
 	// Parse parameters
 	lat, err := strconv.ParseFloat(latStr, 64)
 	if err != nil {
-		util.Error(w, "Invalid latitude", http.StatusBadRequest)
+		util.Error(w, domainErrors.ErrInvalidCoordinates, http.StatusBadRequest)
 		return
 	}
 
 	lon, err := strconv.ParseFloat(lonStr, 64)
 	if err != nil {
-		util.Error(w, "Invalid longitude", http.StatusBadRequest)
+		util.Error(w, domainErrors.ErrInvalidCoordinates, http.StatusBadRequest)
 		return
 	}
 
@@ -204,11 +221,13 @@ func (h *ReportHandler) ListNearby(w http.ResponseWriter, r *http.Request) {
 	response, err := h.reportUseCase.ReportUseCase.ListNearbyWithDistance(r.Context(), params)
 	if err != nil {
 		slog.Error("failed to list nearby reports", "error", err)
-		util.Error(w, err.Error(), http.StatusInternalServerError)
+		util.Error(w, err, http.StatusInternalServerError)
 		return
 	}
 
-	util.Response(w, response, http.StatusOK)
+	respondExport(w, format, response, "nearby-reports", func() []util.GeoFeature {
+		return nearbyReportFeatures(response.Reports)
+	})
 }
 
 // Verify godoc
@@ -230,7 +249,7 @@ func (h *ReportHandler) Verify(w http.ResponseWriter, r *http.Request) {
 	var req dto.VerifyReportRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		util.Error(w, "Invalid JSON", http.StatusBadRequest)
+		util.Error(w, domainErrors.ErrInvalidRequestBody, http.StatusBadRequest)
 		return
 	}
 
@@ -240,7 +259,7 @@ func (h *ReportHandler) Verify(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.reportUseCase.ReportUseCase.Verify(r.Context(), id); err != nil {
-		util.Error(w, err.Error(), http.StatusInternalServerError)
+		util.Error(w, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -269,12 +288,12 @@ func (h *ReportHandler) Resolve(w http.ResponseWriter, r *http.Request) {
 	var req dto.ResolveReportRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		util.Error(w, "Invalid JSON", http.StatusBadRequest)
+		util.Error(w, domainErrors.ErrInvalidRequestBody, http.StatusBadRequest)
 		return
 	}
 
 	if err := h.reportUseCase.ReportUseCase.Resolve(r.Context(), id, req.ModeratorID); err != nil {
-		util.Error(w, err.Error(), http.StatusInternalServerError)
+		util.Error(w, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -302,31 +321,31 @@ func (h *ReportHandler) Resolve(w http.ResponseWriter, r *http.Request) {
 func (h *ReportHandler) UpdateLocation(w http.ResponseWriter, r *http.Request) {
 	reportID := r.PathValue("id")
 	if reportID == "" {
-		util.Error(w, "report ID is required", http.StatusBadRequest)
+		util.Error(w, domainErrors.IDRequired("report"), http.StatusBadRequest)
 		return
 	}
 
 	if _, err := uuid.Parse(reportID); err != nil {
 		slog.Error("invalid report ID format", "reportID", reportID, "error", err)
-		util.Error(w, "invalid report ID format", http.StatusBadRequest)
+		util.Error(w, domainErrors.InvalidID("report"), http.StatusBadRequest)
 		return
 	}
 
 	var req dto.UpdateReportLocationRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		util.Error(w, "Invalid JSON", http.StatusBadRequest)
+		util.Error(w, domainErrors.ErrInvalidRequestBody, http.StatusBadRequest)
 		return
 	}
 
 	if h.reportUseCase == nil || h.reportUseCase.ReportUseCase == nil {
 		slog.Error("reportUseCase is nil")
-		util.Error(w, "internal server error: use case not initialized", http.StatusInternalServerError)
+		util.Error(w, domainErrors.ErrInternalServer, http.StatusInternalServerError)
 		return
 	}
 
 	if err := h.reportUseCase.ReportUseCase.UpdateLocation(r.Context(), reportID, req); err != nil {
 		slog.Error("failed to update report location", "reportID", reportID, "error", err)
-		util.Error(w, err.Error(), http.StatusInternalServerError)
+		util.Error(w, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -354,13 +373,13 @@ func (h *ReportHandler) VoteReport(w http.ResponseWriter, r *http.Request) {
 	reportIDStr := r.PathValue("id")
 	reportID, err := uuid.Parse(reportIDStr)
 	if err != nil {
-		util.Error(w, "invalid report ID", http.StatusBadRequest)
+		util.Error(w, domainErrors.InvalidID("report"), http.StatusBadRequest)
 		return
 	}
 
 	var req dto.VoteReportRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		util.Error(w, "invalid JSON", http.StatusBadRequest)
+		util.Error(w, domainErrors.ErrInvalidRequestBody, http.StatusBadRequest)
 		return
 	}
 
@@ -380,7 +399,7 @@ func (h *ReportHandler) VoteReport(w http.ResponseWriter, r *http.Request) {
 	if identifier.IsAuthenticated {
 		uid, err := dto.ParseUUID(identifier.UserID)
 		if err != nil {
-			util.Error(w, "invalid user ID", http.StatusBadRequest)
+			util.Error(w, domainErrors.ErrInvalidUserID, http.StatusBadRequest)
 			return
 		}
 		userID = &uid
@@ -389,7 +408,7 @@ func (h *ReportHandler) VoteReport(w http.ResponseWriter, r *http.Request) {
 		session, err := h.anonymousSessionRepo.FindByDeviceID(r.Context(), identifier.DeviceID)
 		if err != nil {
 			slog.Error("failed to find anonymous session by device ID", "error", err, "deviceID", identifier.DeviceID)
-			util.Error(w, "anonymous session not found", http.StatusNotFound)
+			util.Error(w, domainErrors.ErrSessionNotFound, http.StatusNotFound)
 			return
 		}
 		anonymousSessionID = &session.ID
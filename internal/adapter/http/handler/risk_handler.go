@@ -2,11 +2,15 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 
+	"github.com/google/uuid"
 	"github.com/risk-place-angola/backend-risk-place/internal/adapter/http/util"
 	"github.com/risk-place-angola/backend-risk-place/internal/application"
+	"github.com/risk-place-angola/backend-risk-place/internal/application/dto"
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
 )
 
 type RiskHandler struct {
@@ -54,7 +58,7 @@ func (h *RiskHandler) ListRiskTypes(w http.ResponseWriter, r *http.Request) {
 func (h *RiskHandler) GetRiskType(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	if id == "" {
-		util.Error(w, "risk type ID is required", http.StatusBadRequest)
+		util.Error(w, domainErrors.IDRequired("risk type"), http.StatusBadRequest)
 		return
 	}
 
@@ -111,7 +115,7 @@ func (h *RiskHandler) ListRiskTopics(w http.ResponseWriter, r *http.Request) {
 func (h *RiskHandler) GetRiskTopic(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	if id == "" {
-		util.Error(w, "risk topic ID is required", http.StatusBadRequest)
+		util.Error(w, domainErrors.IDRequired("risk topic"), http.StatusBadRequest)
 		return
 	}
 
@@ -128,7 +132,7 @@ func (h *RiskHandler) GetRiskTopic(w http.ResponseWriter, r *http.Request) {
 func (h *RiskHandler) UpdateRiskTypeIsEnabled(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	if id == "" {
-		util.Error(w, "risk type ID is required", http.StatusBadRequest)
+		util.Error(w, domainErrors.IDRequired("risk type"), http.StatusBadRequest)
 		return
 	}
 
@@ -137,7 +141,7 @@ func (h *RiskHandler) UpdateRiskTypeIsEnabled(w http.ResponseWriter, r *http.Req
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		util.Error(w, "invalid request body", http.StatusBadRequest)
+		util.Error(w, domainErrors.ErrInvalidRequestBody, http.StatusBadRequest)
 		return
 	}
 
@@ -153,3 +157,99 @@ func (h *RiskHandler) UpdateRiskTypeIsEnabled(w http.ResponseWriter, r *http.Req
 		"is_enabled": req.IsEnabled,
 	}, http.StatusOK)
 }
+
+// ListIncidentWeightRules godoc.
+// @Summary List incident weight rules.
+// @Description Lists the rules that set how heavily each risk type's and topic's incidents count towards safe
+// @Description route, heatmap and danger zone scores. Incidents without a rule use severity 1, a 7-day half-life and
+// @Description a 1000 m proximity falloff. Requires the incident_weight:manage permission.
+// @Tags risks
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.IncidentWeightRulesListResponse
+// @Failure 401 {object} util.ErrorResponse
+// @Failure 403 {object} util.ErrorResponse
+// @Failure 500 {object} util.ErrorResponse
+// @Router /admin/incident-weights [get].
+func (h *RiskHandler) ListIncidentWeightRules(w http.ResponseWriter, r *http.Request) {
+	response, err := h.app.IncidentWeightUseCase.List(r.Context())
+	if err != nil {
+		h.incidentWeightError(w, err)
+		return
+	}
+
+	util.Response(w, response, http.StatusOK)
+}
+
+// SaveIncidentWeightRule godoc.
+// @Summary Set an incident weight rule.
+// @Description Sets the severity, recency half-life and proximity falloff of a risk type's incidents, or of one
+// @Description of its topics' when risk_topic_id is given, replacing the rule already there. Topic rules take
+// @Description precedence over their risk type's. Requires the incident_weight:manage permission.
+// @Tags risks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.IncidentWeightRuleRequest true "Incident weight rule"
+// @Success 200 {object} dto.IncidentWeightRuleResponse
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 401 {object} util.ErrorResponse
+// @Failure 403 {object} util.ErrorResponse
+// @Failure 500 {object} util.ErrorResponse
+// @Router /admin/incident-weights [put].
+func (h *RiskHandler) SaveIncidentWeightRule(w http.ResponseWriter, r *http.Request) {
+	var req dto.IncidentWeightRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.Error(w, domainErrors.ErrInvalidRequestBody, http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.app.IncidentWeightUseCase.Save(r.Context(), &req)
+	if err != nil {
+		h.incidentWeightError(w, err)
+		return
+	}
+
+	util.Response(w, response, http.StatusOK)
+}
+
+// DeleteIncidentWeightRule godoc.
+// @Summary Delete an incident weight rule.
+// @Description Removes a rule; its incidents fall back to their risk type's rule or the defaults. Requires the
+// @Description incident_weight:manage permission.
+// @Tags risks
+// @Security BearerAuth
+// @Param id path string true "Incident weight rule ID"
+// @Success 204
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 401 {object} util.ErrorResponse
+// @Failure 403 {object} util.ErrorResponse
+// @Failure 404 {object} util.ErrorResponse
+// @Failure 500 {object} util.ErrorResponse
+// @Router /admin/incident-weights/{id} [delete].
+func (h *RiskHandler) DeleteIncidentWeightRule(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		util.Error(w, domainErrors.InvalidID("incident weight rule"), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.app.IncidentWeightUseCase.Delete(r.Context(), id); err != nil {
+		h.incidentWeightError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *RiskHandler) incidentWeightError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domainErrors.ErrInvalidRequest):
+		util.Error(w, err, http.StatusBadRequest)
+	case errors.Is(err, domainErrors.ErrWeightRuleNotFound):
+		util.Error(w, domainErrors.ErrWeightRuleNotFound, http.StatusNotFound)
+	default:
+		slog.Error("failed to manage incident weight rules", "error", err)
+		util.Error(w, "failed to manage incident weight rules", http.StatusInternalServerError)
+	}
+}
@@ -2,12 +2,14 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 
 	"github.com/risk-place-angola/backend-risk-place/internal/adapter/http/util"
 	"github.com/risk-place-angola/backend-risk-place/internal/application"
 	"github.com/risk-place-angola/backend-risk-place/internal/application/dto"
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
 )
 
 type SafeRouteHandler struct {
@@ -27,36 +29,93 @@ func (h *SafeRouteHandler) decodeAndHandle(
 	req interface{},
 	handler func() (interface{}, error),
 	errorMsg string,
+	filename string,
+	features func() []util.GeoFeature,
 ) {
+	format, ok := exportFormat(w, r)
+	if !ok {
+		return
+	}
+
 	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
-		util.Error(w, "invalid request body", http.StatusBadRequest)
+		util.Error(w, domainErrors.ErrInvalidRequestBody, http.StatusBadRequest)
 		return
 	}
 
 	response, err := handler()
+	if errors.Is(err, domainErrors.ErrInvalidRequest) {
+		util.Error(w, err, http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, domainErrors.ErrRouteNotFound) {
+		util.Error(w, err, http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		slog.Error(errorMsg, "error", err)
 		util.Error(w, errorMsg, http.StatusInternalServerError)
 		return
 	}
 
-	util.Response(w, response, http.StatusOK)
+	respondExport(w, format, response, filename, features)
 }
 
+// CalculateSafeRoute godoc
+// @Summary Calculate a safe route
+// @Description Calculate the safest routes between two points. With format=geojson, gpx or kml, or the matching
+// @Description Accept header, the routes come as lines, the incidents along them as points and the manual danger
+// @Description zones they cross as polygons. Private reports weigh on the score but are never listed, in any format.
+// @Tags routes
+// @Accept json
+// @Produce json,application/geo+json,application/gpx+xml,application/vnd.google-earth.kml+xml
+// @Param request body dto.SafeRouteRequest true "Origin and destination"
+// @Param format query string false "Export format" Enums(json, geojson, gpx, kml)
+// @Success 200 {object} dto.SafeRouteResponse
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 404 {object} util.ErrorResponse
+// @Failure 500 {object} util.ErrorResponse
+// @Router /routes/safe-route [post]
 func (h *SafeRouteHandler) CalculateSafeRoute(w http.ResponseWriter, r *http.Request) {
-	var req dto.SafeRouteRequest
+	var (
+		req   dto.SafeRouteRequest
+		route *dto.SafeRouteResponse
+	)
 	ctx := r.Context()
 	h.decodeAndHandle(w, r, &req, func() (interface{}, error) {
-		return h.app.SafeRouteUseCase.CalculateSafeRoute(ctx, &req)
-	}, "failed to calculate safe route")
+		var err error
+		route, err = h.app.SafeRouteUseCase.CalculateSafeRoute(ctx, &req)
+		return route, err
+	}, "failed to calculate safe route", "safe-route", func() []util.GeoFeature {
+		return safeRouteFeatures(route)
+	})
 }
 
+// GetIncidentsHeatmap godoc
+// @Summary Get the incidents heatmap
+// @Description Weighted incident points within the bounds, also as GeoJSON, GPX or KML points with format or the
+// @Description matching Accept header.
+// @Tags routes
+// @Accept json
+// @Produce json,application/geo+json,application/gpx+xml,application/vnd.google-earth.kml+xml
+// @Param request body dto.HeatmapRequest true "Bounds and filters"
+// @Param format query string false "Export format" Enums(json, geojson, gpx, kml)
+// @Success 200 {object} dto.HeatmapResponse
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 500 {object} util.ErrorResponse
+// @Router /routes/incidents-heatmap [post]
 func (h *SafeRouteHandler) GetIncidentsHeatmap(w http.ResponseWriter, r *http.Request) {
-	var req dto.HeatmapRequest
+	var (
+		req     dto.HeatmapRequest
+		heatmap *dto.HeatmapResponse
+	)
 	ctx := r.Context()
 	h.decodeAndHandle(w, r, &req, func() (interface{}, error) {
-		return h.app.SafeRouteUseCase.GetIncidentsHeatmap(ctx, &req)
-	}, "failed to get incidents heatmap")
+		var err error
+		heatmap, err = h.app.SafeRouteUseCase.GetIncidentsHeatmap(ctx, &req)
+		return heatmap, err
+	}, "failed to get incidents heatmap", "incidents-heatmap", func() []util.GeoFeature {
+		return heatmapFeatures(heatmap)
+	})
 }
 
 // navigateToSavedLocation is a helper to reduce duplication between NavigateToHome and NavigateToWork
@@ -64,30 +123,36 @@ func (h *SafeRouteHandler) navigateToSavedLocation(
 	w http.ResponseWriter,
 	r *http.Request,
 	useCase func(r *http.Request, currentLat, currentLon float64) (*dto.SafeRouteResponse, error),
-	notConfiguredMsg, errorMsg string,
+	errNotConfigured error,
+	errorMsg string,
 ) {
+	format, ok := exportFormat(w, r)
+	if !ok {
+		return
+	}
+
 	userIDStr, ok := util.GetUserIDFromContext(r.Context())
 	if !ok {
-		util.Error(w, "unauthorized", http.StatusUnauthorized)
+		util.Error(w, domainErrors.ErrUnauthorized, http.StatusUnauthorized)
 		return
 	}
 
 	_, err := dto.ParseUUID(userIDStr)
 	if err != nil {
-		util.Error(w, "invalid user ID", http.StatusUnauthorized)
+		util.Error(w, domainErrors.ErrInvalidUserID, http.StatusUnauthorized)
 		return
 	}
 
 	var req dto.NavigateToSavedLocationRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		util.Error(w, "invalid request body", http.StatusBadRequest)
+		util.Error(w, domainErrors.ErrInvalidRequestBody, http.StatusBadRequest)
 		return
 	}
 
 	response, err := useCase(r, req.CurrentLat, req.CurrentLon)
 	if err != nil {
-		if err.Error() == notConfiguredMsg {
-			util.Error(w, notConfiguredMsg, http.StatusNotFound)
+		if errors.Is(err, errNotConfigured) || errors.Is(err, domainErrors.ErrRouteNotFound) {
+			util.Error(w, err, http.StatusNotFound)
 			return
 		}
 		slog.Error(errorMsg, "error", err)
@@ -95,7 +160,9 @@ func (h *SafeRouteHandler) navigateToSavedLocation(
 		return
 	}
 
-	util.Response(w, response, http.StatusOK)
+	respondExport(w, format, response, "safe-route", func() []util.GeoFeature {
+		return safeRouteFeatures(response)
+	})
 }
 
 // NavigateToHome godoc
@@ -103,9 +170,10 @@ func (h *SafeRouteHandler) navigateToSavedLocation(
 // @Description Calculate a safe route from current location to the user's saved home address
 // @Tags routes
 // @Accept json
-// @Produce json
+// @Produce json,application/geo+json,application/gpx+xml,application/vnd.google-earth.kml+xml
 // @Security BearerAuth
 // @Param location body dto.NavigateToSavedLocationRequest true "Current location coordinates"
+// @Param format query string false "Export format" Enums(json, geojson, gpx, kml)
 // @Success 200 {object} dto.SafeRouteResponse "Safe route calculated successfully"
 // @Failure 400 {object} util.ErrorResponse "Invalid request body"
 // @Failure 401 {object} util.ErrorResponse "Unauthorized - missing or invalid JWT token"
@@ -119,7 +187,7 @@ func (h *SafeRouteHandler) NavigateToHome(w http.ResponseWriter, r *http.Request
 			userID, _ := dto.ParseUUID(userIDStr)
 			return h.app.SafeRouteUseCase.NavigateToHome(req.Context(), userID, currentLat, currentLon)
 		},
-		"home address not configured",
+		domainErrors.ErrHomeAddressNotConfigured,
 		"failed to navigate to home",
 	)
 }
@@ -129,9 +197,10 @@ func (h *SafeRouteHandler) NavigateToHome(w http.ResponseWriter, r *http.Request
 // @Description Calculate a safe route from current location to the user's saved work address
 // @Tags routes
 // @Accept json
-// @Produce json
+// @Produce json,application/geo+json,application/gpx+xml,application/vnd.google-earth.kml+xml
 // @Security BearerAuth
 // @Param location body dto.NavigateToSavedLocationRequest true "Current location coordinates"
+// @Param format query string false "Export format" Enums(json, geojson, gpx, kml)
 // @Success 200 {object} dto.SafeRouteResponse "Safe route calculated successfully"
 // @Failure 400 {object} util.ErrorResponse "Invalid request body"
 // @Failure 401 {object} util.ErrorResponse "Unauthorized - missing or invalid JWT token"
@@ -145,7 +214,7 @@ func (h *SafeRouteHandler) NavigateToWork(w http.ResponseWriter, r *http.Request
 			userID, _ := dto.ParseUUID(userIDStr)
 			return h.app.SafeRouteUseCase.NavigateToWork(req.Context(), userID, currentLat, currentLon)
 		},
-		"work address not configured",
+		domainErrors.ErrWorkAddressNotConfigured,
 		"failed to navigate to work",
 	)
 }
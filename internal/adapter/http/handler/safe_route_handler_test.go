@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/risk-place-angola/backend-risk-place/internal/application"
+	"github.com/risk-place-angola/backend-risk-place/internal/application/usecase/saferoute"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
+	domainService "github.com/risk-place-angola/backend-risk-place/internal/domain/service"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSafeRouteRepo struct {
+	repository.SafeRouteRepository
+	incidents []model.IncidentNearRoute
+}
+
+func (r *fakeSafeRouteRepo) GetIncidentsForRoute(context.Context, []model.Waypoint, float64) ([]model.IncidentNearRoute, error) {
+	return r.incidents, nil
+}
+
+type fakeDangerZoneService struct {
+	domainService.DangerZoneService
+}
+
+func (fakeDangerZoneService) ManualZonesAlongRoute(context.Context, []model.Waypoint, time.Time) []*model.ManualDangerZone {
+	return nil
+}
+
+type fakeWeights struct{}
+
+func (fakeWeights) Weighting(context.Context) *model.IncidentWeighting {
+	return model.NewIncidentWeighting(nil)
+}
+
+func (fakeWeights) Refresh(context.Context) error { return nil }
+
+type noRoadGraph struct{}
+
+func (noRoadGraph) RoadGraph() *model.RoadGraph { return nil }
+
+func TestCalculateSafeRoute_NeverListsPrivateReports(t *testing.T) {
+	public := model.IncidentNearRoute{
+		ReportID:  uuid.New(),
+		RiskType:  "crime",
+		Latitude:  -8.8385,
+		Longitude: 13.2400,
+		CreatedAt: time.Now().Add(-time.Hour),
+	}
+	private := model.IncidentNearRoute{
+		ReportID:  uuid.New(),
+		RiskType:  "crime",
+		Latitude:  -8.818181,
+		Longitude: 13.272727,
+		CreatedAt: time.Now().Add(-time.Hour),
+		IsPrivate: true,
+	}
+
+	repo := &fakeSafeRouteRepo{incidents: []model.IncidentNearRoute{public, private}}
+	zones := fakeDangerZoneService{}
+	planner := domainService.NewSafeRoutePlanner(noRoadGraph{}, repo, zones, fakeWeights{}, 0)
+	h := NewSafeRouteHandler(&application.Application{
+		SafeRouteUseCase: saferoute.NewSafeRouteUseCase(repo, nil, zones, planner, fakeWeights{}),
+	})
+
+	for _, format := range []string{"json", "geojson", "gpx", "kml"} {
+		t.Run(format, func(t *testing.T) {
+			body := `{"origin_lat": -8.8383, "origin_lon": 13.2344, "destination_lat": -8.8390, "destination_lon": 13.2444}`
+			r := httptest.NewRequest(http.MethodPost, "/api/v1/routes/safe-route?format="+format, strings.NewReader(body))
+			w := httptest.NewRecorder()
+
+			h.CalculateSafeRoute(w, r)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			out := w.Body.String()
+			assert.Contains(t, out, public.ReportID.String())
+			assert.NotContains(t, out, private.ReportID.String())
+			assert.NotContains(t, out, "8.818181")
+			assert.NotContains(t, out, "13.272727")
+		})
+	}
+}
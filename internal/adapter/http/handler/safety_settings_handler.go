@@ -8,6 +8,7 @@ import (
 	"github.com/risk-place-angola/backend-risk-place/internal/adapter/http/util"
 	"github.com/risk-place-angola/backend-risk-place/internal/application"
 	"github.com/risk-place-angola/backend-risk-place/internal/application/dto"
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
 	"github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
 )
 
@@ -45,7 +46,7 @@ func (h *SafetySettingsHandler) GetSettings(w http.ResponseWriter, r *http.Reque
 	if identifier.IsAuthenticated {
 		uid, parseErr := dto.ParseUUID(identifier.UserID)
 		if parseErr != nil {
-			util.Error(w, "invalid user ID", http.StatusBadRequest)
+			util.Error(w, domainErrors.ErrInvalidUserID, http.StatusBadRequest)
 			return
 		}
 		settings, err = h.app.SafetySettingsUseCase.GetSettings(r.Context(), uid)
@@ -55,7 +56,7 @@ func (h *SafetySettingsHandler) GetSettings(w http.ResponseWriter, r *http.Reque
 
 	if err != nil {
 		slog.Error("error fetching safety settings", "identifier", identifier, "error", err)
-		util.Error(w, err.Error(), http.StatusInternalServerError)
+		util.Error(w, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -83,7 +84,7 @@ func (h *SafetySettingsHandler) UpdateSettings(w http.ResponseWriter, r *http.Re
 
 	var input dto.UpdateSafetySettingsInput
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		util.Error(w, "invalid request body", http.StatusBadRequest)
+		util.Error(w, domainErrors.ErrInvalidRequestBody, http.StatusBadRequest)
 		return
 	}
 
@@ -93,7 +94,7 @@ func (h *SafetySettingsHandler) UpdateSettings(w http.ResponseWriter, r *http.Re
 	if identifier.IsAuthenticated {
 		uid, parseErr := dto.ParseUUID(identifier.UserID)
 		if parseErr != nil {
-			util.Error(w, "invalid user ID", http.StatusBadRequest)
+			util.Error(w, domainErrors.ErrInvalidUserID, http.StatusBadRequest)
 			return
 		}
 		settings, err = h.app.SafetySettingsUseCase.UpdateSettings(r.Context(), uid, input)
@@ -112,7 +113,7 @@ func (h *SafetySettingsHandler) UpdateSettings(w http.ResponseWriter, r *http.Re
 			err.Error() == "invalid night_mode_end_time format, expected HH:MM" {
 			statusCode = http.StatusBadRequest
 		}
-		util.Error(w, err.Error(), statusCode)
+		util.Error(w, err, statusCode)
 		return
 	}
 
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/risk-place-angola/backend-risk-place/internal/application"
+	"github.com/risk-place-angola/backend-risk-place/internal/application/dto"
+)
+
+// emptyTwiML acknowledges the webhook without a reply message; the
+// confirmation is sent separately so it goes through the resilient notifier.
+const emptyTwiML = `<?xml version="1.0" encoding="UTF-8"?><Response></Response>`
+
+type SMSInboundHandler struct {
+	app *application.Application
+}
+
+func NewSMSInboundHandler(app *application.Application) *SMSInboundHandler {
+	return &SMSInboundHandler{app: app}
+}
+
+// ReceiveSMS godoc
+// @Summary Receive an inbound SMS report
+// @Description Twilio-compatible webhook for reports sent by SMS, e.g. "ASSALTO Cazenga rua 12".
+// @Description The sender gets an SMS back with a reference code. Requests must carry a valid X-Twilio-Signature.
+// @Tags sms
+// @Accept x-www-form-urlencoded
+// @Produce xml
+// @Param From formData string true "Sender phone number in E.164 format"
+// @Param Body formData string true "Message text"
+// @Param MessageSid formData string false "Provider message ID"
+// @Success 200 {string} string "Empty TwiML response"
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 403 {object} util.ErrorResponse
+// @Router /sms/inbound [post]
+func (h *SMSInboundHandler) ReceiveSMS(w http.ResponseWriter, r *http.Request) {
+	in := dto.InboundSMS{
+		From:      r.FormValue("From"),
+		Body:      r.FormValue("Body"),
+		MessageID: r.FormValue("MessageSid"),
+	}
+
+	if in.From == "" || in.Body == "" {
+		w.Header().Set("Content-Type", "text/xml")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(emptyTwiML))
+		return
+	}
+
+	// Failures are logged and still acknowledged: a non-2xx answer only makes
+	// the provider retry a message we cannot store.
+	if _, err := h.app.SMSReportUseCase.HandleInbound(r.Context(), in); err != nil {
+		slog.Error("failed to handle inbound sms", "message_id", in.MessageID, "error", err)
+	}
+
+	w.Header().Set("Content-Type", "text/xml")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(emptyTwiML))
+}
@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/risk-place-angola/backend-risk-place/internal/adapter/http/util"
+	"github.com/risk-place-angola/backend-risk-place/internal/application"
+	"github.com/risk-place-angola/backend-risk-place/internal/application/dto"
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
+)
+
+type TripHandler struct {
+	app *application.Application
+}
+
+func NewTripHandler(app *application.Application) *TripHandler {
+	return &TripHandler{
+		app: app,
+	}
+}
+
+// StartTrip godoc.
+// @Summary Start a monitored trip.
+// @Description Follows the user along a route, usually one returned by /routes/safe-route, through the location
+// @Description updates they already send (POST /users/location or the update_location websocket message). When
+// @Description the user strays from the route, stops for long or runs late, they get a trip_check_in push and
+// @Description websocket message; without an answer within 5 minutes their emergency contacts get an SMS. The
+// @Description trip ends by itself on arrival. Requires at least one emergency contact.
+// @Tags trips
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.StartTripRequest true "Route to follow"
+// @Success 201 {object} dto.TripResponse
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 401 {object} util.ErrorResponse
+// @Failure 409 {object} util.ErrorResponse
+// @Failure 500 {object} util.ErrorResponse
+// @Router /trips [post].
+func (h *TripHandler) StartTrip(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.StartTripRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.Error(w, domainErrors.ErrInvalidRequestBody, http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.app.TripUseCase.Start(r.Context(), userID, &req)
+	if err != nil {
+		h.tripError(w, err)
+		return
+	}
+
+	util.Response(w, response, http.StatusCreated)
+}
+
+// GetActiveTrip godoc.
+// @Summary Get the active trip.
+// @Description Returns the user's trip in progress, with the check-in waiting for an answer, if any.
+// @Tags trips
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.TripResponse
+// @Failure 401 {object} util.ErrorResponse
+// @Failure 404 {object} util.ErrorResponse
+// @Failure 500 {object} util.ErrorResponse
+// @Router /trips/active [get].
+func (h *TripHandler) GetActiveTrip(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	response, err := h.app.TripUseCase.GetActive(r.Context(), userID)
+	if err != nil {
+		h.tripError(w, err)
+		return
+	}
+
+	util.Response(w, response, http.StatusOK)
+}
+
+// CheckInTrip godoc.
+// @Summary Answer a trip check-in.
+// @Description With ok true, confirms the user is fine and silences check-ins for 15 minutes. With ok false,
+// @Description alerts the emergency contacts right away, with or without a pending check-in.
+// @Tags trips
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Trip ID"
+// @Param request body dto.TripCheckInRequest true "Check-in answer"
+// @Success 200 {object} dto.TripResponse
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 401 {object} util.ErrorResponse
+// @Failure 404 {object} util.ErrorResponse
+// @Failure 500 {object} util.ErrorResponse
+// @Router /trips/{id}/check-in [post].
+func (h *TripHandler) CheckInTrip(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	tripID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		util.Error(w, domainErrors.InvalidID("trip"), http.StatusBadRequest)
+		return
+	}
+
+	var req dto.TripCheckInRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.Error(w, domainErrors.ErrInvalidRequestBody, http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.app.TripUseCase.CheckIn(r.Context(), userID, tripID, &req)
+	if err != nil {
+		h.tripError(w, err)
+		return
+	}
+
+	util.Response(w, response, http.StatusOK)
+}
+
+// EndTrip godoc.
+// @Summary End a trip.
+// @Description Stops following the trip, as completed when arrived is true and as cancelled otherwise.
+// @Tags trips
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Trip ID"
+// @Param request body dto.EndTripRequest true "How the trip ended"
+// @Success 200 {object} dto.TripResponse
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 401 {object} util.ErrorResponse
+// @Failure 404 {object} util.ErrorResponse
+// @Failure 500 {object} util.ErrorResponse
+// @Router /trips/{id}/end [post].
+func (h *TripHandler) EndTrip(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	tripID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		util.Error(w, domainErrors.InvalidID("trip"), http.StatusBadRequest)
+		return
+	}
+
+	var req dto.EndTripRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.Error(w, domainErrors.ErrInvalidRequestBody, http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.app.TripUseCase.End(r.Context(), userID, tripID, &req)
+	if err != nil {
+		h.tripError(w, err)
+		return
+	}
+
+	util.Response(w, response, http.StatusOK)
+}
+
+func (h *TripHandler) userID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	userIDStr, ok := util.GetUserIDFromContext(r.Context())
+	if !ok {
+		util.Error(w, domainErrors.ErrUnauthorized, http.StatusUnauthorized)
+		return uuid.Nil, false
+	}
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		util.Error(w, domainErrors.ErrUnauthorized, http.StatusUnauthorized)
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+func (h *TripHandler) tripError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domainErrors.ErrInvalidRequest):
+		util.Error(w, err, http.StatusBadRequest)
+	case errors.Is(err, domainErrors.ErrNoEmergencyContacts):
+		util.Error(w, domainErrors.ErrNoEmergencyContacts, http.StatusBadRequest)
+	case errors.Is(err, domainErrors.ErrTripNotFound):
+		util.Error(w, domainErrors.ErrTripNotFound, http.StatusNotFound)
+	case errors.Is(err, domainErrors.ErrTripAlreadyActive):
+		util.Error(w, domainErrors.ErrTripAlreadyActive, http.StatusConflict)
+	default:
+		slog.Error("failed to manage trip", "error", err)
+		util.Error(w, "failed to manage trip", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// tripMonitorInterval keeps the check-in timeout, five minutes, close to
+// what travellers are told.
+const tripMonitorInterval = 30 * time.Second
+
+type tripChecker interface {
+	CheckTrips(ctx context.Context) error
+}
+
+func StartTripMonitorJob(ctx context.Context, monitor tripChecker) {
+	go func() {
+		ticker := time.NewTicker(tripMonitorInterval)
+		defer ticker.Stop()
+
+		slog.Info("starting trip monitor job", "interval", tripMonitorInterval)
+
+		for {
+			select {
+			case <-ctx.Done():
+				slog.Info("trip monitor job stopped")
+				return
+			case <-ticker.C:
+				if err := monitor.CheckTrips(ctx); err != nil {
+					slog.Error("trip monitor failed", "error", err)
+				}
+			}
+		}
+	}()
+}
@@ -36,7 +36,7 @@ func NewUserHandler(userUseCase *application.Application) *UserHandler {
 // @Router /auth/signup [post]
 func (h *UserHandler) Signup(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		util.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		util.Error(w, domainErrors.ErrMethodNotAllowed, http.StatusMethodNotAllowed)
 		return
 	}
 
@@ -126,7 +126,7 @@ func (h *UserHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 		RefreshToken string `json:"refresh_token"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		util.Error(w, "invalid request", http.StatusBadRequest)
+		util.Error(w, domainErrors.ErrInvalidRequestBody, http.StatusBadRequest)
 		return
 	}
 
@@ -143,9 +143,9 @@ func (h *UserHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 		case errors.Is(err, domainErrors.ErrInvalidToken):
 			util.Error(w, "invalid refresh token", http.StatusUnauthorized)
 		case errors.Is(err, domainErrors.ErrAccountNotVerified):
-			util.Error(w, "account not verified", http.StatusForbidden)
+			util.Error(w, domainErrors.ErrAccountNotVerified, http.StatusForbidden)
 		default:
-			util.Error(w, "internal error", http.StatusInternalServerError)
+			util.Error(w, domainErrors.ErrInternalServer, http.StatusInternalServerError)
 		}
 		return
 	}
@@ -164,12 +164,12 @@ func (h *UserHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 func (h *UserHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	userID, ok := r.Context().Value("user_id").(uuid.UUID)
 	if !ok {
-		util.Error(w, "unauthorized", http.StatusUnauthorized)
+		util.Error(w, domainErrors.ErrUnauthorized, http.StatusUnauthorized)
 		return
 	}
 
 	if err := h.userUseCase.UserUseCase.Logout(r.Context(), userID); err != nil {
-		util.Error(w, "internal error", http.StatusInternalServerError)
+		util.Error(w, domainErrors.ErrInternalServer, http.StatusInternalServerError)
 		return
 	}
 
@@ -358,24 +358,24 @@ func (h *UserHandler) Me(w http.ResponseWriter, r *http.Request) {
 	userIDStr, ok := util.GetUserIDFromContext(r.Context())
 	if !ok {
 		slog.Error("failed to get user ID from context")
-		util.Error(w, "unauthorized", http.StatusUnauthorized)
+		util.Error(w, domainErrors.ErrUnauthorized, http.StatusUnauthorized)
 		return
 	}
 
 	userID, err := dto.ParseUUID(userIDStr)
 	if err != nil {
 		slog.Error("invalid user ID in context", slog.Any("error", err))
-		util.Error(w, "unauthorized", http.StatusUnauthorized)
+		util.Error(w, domainErrors.ErrUnauthorized, http.StatusUnauthorized)
 		return
 	}
 
 	userOut, err := h.userUseCase.UserUseCase.FindUserByID(r.Context(), userID)
 	if err != nil {
 		if errors.Is(err, domainErrors.ErrUserNotFound) {
-			util.Error(w, "user not found", http.StatusNotFound)
+			util.Error(w, domainErrors.ErrUserNotFound, http.StatusNotFound)
 			return
 		}
-		util.Error(w, "internal error", http.StatusInternalServerError)
+		util.Error(w, domainErrors.ErrInternalServer, http.StatusInternalServerError)
 		return
 	}
 
@@ -400,30 +400,30 @@ func (h *UserHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
 	userIDStr, ok := util.GetUserIDFromContext(r.Context())
 	if !ok {
 		slog.Error("failed to get user ID from context")
-		util.Error(w, "unauthorized", http.StatusUnauthorized)
+		util.Error(w, domainErrors.ErrUnauthorized, http.StatusUnauthorized)
 		return
 	}
 
 	userID, err := dto.ParseUUID(userIDStr)
 	if err != nil {
 		slog.Error("invalid user ID in context", slog.Any("error", err))
-		util.Error(w, "unauthorized", http.StatusUnauthorized)
+		util.Error(w, domainErrors.ErrUnauthorized, http.StatusUnauthorized)
 		return
 	}
 
 	var req dto.UpdateProfileRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		util.Error(w, "invalid request body", http.StatusBadRequest)
+		util.Error(w, domainErrors.ErrInvalidRequestBody, http.StatusBadRequest)
 		return
 	}
 
 	if err := h.userUseCase.UserUseCase.UpdateUserProfile(r.Context(), userID, &req); err != nil {
 		if errors.Is(err, domainErrors.ErrUserNotFound) {
-			util.Error(w, "user not found", http.StatusNotFound)
+			util.Error(w, domainErrors.ErrUserNotFound, http.StatusNotFound)
 			return
 		}
 		slog.Error("failed to update user profile", "error", err)
-		util.Error(w, "internal error", http.StatusInternalServerError)
+		util.Error(w, domainErrors.ErrInternalServer, http.StatusInternalServerError)
 		return
 	}
 
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/risk-place-angola/backend-risk-place/internal/adapter/http/util"
+	"github.com/risk-place-angola/backend-risk-place/internal/application"
+	"github.com/risk-place-angola/backend-risk-place/internal/application/dto"
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
+)
+
+type USSDHandler struct {
+	app *application.Application
+}
+
+func NewUSSDHandler(app *application.Application) *USSDHandler {
+	return &USSDHandler{app: app}
+}
+
+// ussdJSONResponse is returned to gateways that ask for JSON.
+type ussdJSONResponse struct {
+	Message string `json:"message"`
+	End     bool   `json:"end"`
+}
+
+// HandleSession godoc
+// @Summary USSD session callback
+// @Description Gateway-agnostic USSD webhook. Accepts the common field names used by USSD aggregators
+// @Description (sessionId/session_id, phoneNumber/phone_number/msisdn, serviceCode/service_code, text/input)
+// @Description as form or JSON. Replies in plain text prefixed with "CON " (expects more input) or "END ",
+// @Description or as JSON {"message","end"} when the request accepts application/json.
+// @Description Requests must carry the gateway token in X-Gateway-Token or the token query parameter.
+// @Tags ussd
+// @Accept x-www-form-urlencoded
+// @Accept json
+// @Produce plain
+// @Produce json
+// @Param sessionId formData string true "Gateway session ID"
+// @Param phoneNumber formData string true "Caller phone number"
+// @Param serviceCode formData string false "Dialled USSD code"
+// @Param text formData string false "Caller input, either the latest entry or all entries joined by *"
+// @Success 200 {string} string "CON or END screen"
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 403 {object} util.ErrorResponse
+// @Router /ussd [post]
+func (h *USSDHandler) HandleSession(w http.ResponseWriter, r *http.Request) {
+	fields, err := ussdFields(r)
+	if err != nil {
+		util.Error(w, domainErrors.ErrInvalidRequestBody, http.StatusBadRequest)
+		return
+	}
+
+	req := dto.USSDRequest{
+		SessionID:   firstField(fields, "sessionId", "session_id", "sessionid"),
+		Phone:       firstField(fields, "phoneNumber", "phone_number", "msisdn"),
+		ServiceCode: firstField(fields, "serviceCode", "service_code"),
+		Text:        firstField(fields, "text", "input", "ussd_string"),
+	}
+
+	if req.SessionID == "" || req.Phone == "" {
+		util.Error(w, "sessionId and phoneNumber are required", http.StatusBadRequest)
+		return
+	}
+
+	resp := h.app.USSDUseCase.Handle(r.Context(), req)
+
+	if strings.Contains(r.Header.Get("Accept"), "application/json") {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(ussdJSONResponse{Message: resp.Message, End: resp.End})
+		return
+	}
+
+	prefix := "CON "
+	if resp.End {
+		prefix = "END "
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(prefix + resp.Message))
+}
+
+// ussdFields reads the callback as JSON or as a form, whichever the gateway
+// sent.
+func ussdFields(r *http.Request) (map[string]string, error) {
+	fields := make(map[string]string)
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			return nil, err
+		}
+		for k, v := range body {
+			if s, ok := v.(string); ok {
+				fields[k] = s
+			}
+		}
+		return fields, nil
+	}
+
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+	for k := range r.Form {
+		fields[k] = r.Form.Get(k)
+	}
+	return fields, nil
+}
+
+func firstField(fields map[string]string, names ...string) string {
+	for _, name := range names {
+		if v := strings.TrimSpace(fields[name]); v != "" {
+			return v
+		}
+	}
+	return ""
+}
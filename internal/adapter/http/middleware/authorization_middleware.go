@@ -7,6 +7,7 @@ import (
 
 	"github.com/google/uuid"
 	httputil "github.com/risk-place-angola/backend-risk-place/internal/adapter/http/util"
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
 	"github.com/risk-place-angola/backend-risk-place/internal/domain/service"
 )
 
@@ -30,27 +31,27 @@ func (m *AuthorizationMiddleware) RequirePermission(resource, action string) fun
 			userIDStr, ok := r.Context().Value(httputil.UserIDCtxKey).(string)
 			if !ok || userIDStr == "" {
 				slog.Warn("authorization check failed: missing user ID in context")
-				httputil.Error(w, "forbidden", http.StatusForbidden)
+				httputil.Error(w, domainErrors.ErrForbidden, http.StatusForbidden)
 				return
 			}
 
 			userID, err := uuid.Parse(userIDStr)
 			if err != nil {
 				slog.Error("authorization check failed: invalid user ID", "error", err)
-				httputil.Error(w, "forbidden", http.StatusForbidden)
+				httputil.Error(w, domainErrors.ErrForbidden, http.StatusForbidden)
 				return
 			}
 
 			hasPermission, err := m.authzService.HasPermission(r.Context(), userID, resource, action)
 			if err != nil {
 				slog.Error("authorization check failed", "error", err, "user_id", userID, "resource", resource, "action", action)
-				httputil.Error(w, "forbidden", http.StatusForbidden)
+				httputil.Error(w, domainErrors.ErrForbidden, http.StatusForbidden)
 				return
 			}
 
 			if !hasPermission {
 				slog.Warn("permission denied", "user_id", userID, "resource", resource, "action", action)
-				httputil.Error(w, "forbidden: insufficient permissions", http.StatusForbidden)
+				httputil.Error(w, domainErrors.ErrForbidden, http.StatusForbidden)
 				return
 			}
 
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+
+	httputil "github.com/risk-place-angola/backend-risk-place/internal/adapter/http/util"
+)
+
+const gatewayTokenHeader = "X-Gateway-Token"
+
+// GatewayTokenMiddleware guards callbacks from telecom gateways that can only
+// be configured with a shared secret. The token is read from the
+// X-Gateway-Token header or, for gateways that cannot set headers, from the
+// token query parameter of the callback URL. An empty token rejects every
+// request rather than leaving the webhook open.
+type GatewayTokenMiddleware struct {
+	token string
+}
+
+func NewGatewayTokenMiddleware(token string) *GatewayTokenMiddleware {
+	return &GatewayTokenMiddleware{token: token}
+}
+
+func (m *GatewayTokenMiddleware) Validate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got := r.Header.Get(gatewayTokenHeader)
+		if got == "" {
+			got = r.URL.Query().Get("token")
+		}
+
+		if m.token == "" || subtle.ConstantTimeCompare([]byte(got), []byte(m.token)) != 1 {
+			slog.Warn("rejected gateway callback with invalid token", "path", r.URL.Path)
+			httputil.Error(w, "invalid gateway token", http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
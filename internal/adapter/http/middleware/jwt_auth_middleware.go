@@ -9,6 +9,7 @@ import (
 
 	httputil "github.com/risk-place-angola/backend-risk-place/internal/adapter/http/util"
 	"github.com/risk-place-angola/backend-risk-place/internal/config"
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
 
 	"github.com/golang-jwt/jwt/v5"
 )
@@ -30,7 +31,7 @@ func (m *AuthMiddleware) ValidateJWT(next http.Handler) http.Handler {
 		sub, err := m.ValidateJWTFromRequest(r)
 		if err != nil {
 			slog.Error("JWT validation failed", slog.Any("error", err))
-			httputil.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+			httputil.Error(w, fmt.Errorf("%w: %w", domainErrors.ErrUnauthorized, err), http.StatusUnauthorized)
 			return
 		}
 
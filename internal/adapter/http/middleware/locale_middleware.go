@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/risk-place-angola/backend-risk-place/internal/adapter/service"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
+)
+
+// LocaleMiddleware negotiates the language used for error messages and for
+// any SMS or email sent while handling the request. Accept-Language wins;
+// otherwise the caller's stored device language is used.
+type LocaleMiddleware struct {
+	translations         *service.TranslationService
+	authMiddleware       *AuthMiddleware
+	userRepo             repository.UserRepository
+	anonymousSessionRepo repository.AnonymousSessionRepository
+}
+
+func NewLocaleMiddleware(
+	translations *service.TranslationService,
+	authMiddleware *AuthMiddleware,
+	userRepo repository.UserRepository,
+	anonymousSessionRepo repository.AnonymousSessionRepository,
+) *LocaleMiddleware {
+	return &LocaleMiddleware{
+		translations:         translations,
+		authMiddleware:       authMiddleware,
+		userRepo:             userRepo,
+		anonymousSessionRepo: anonymousSessionRepo,
+	}
+}
+
+func (m *LocaleMiddleware) Negotiate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := service.WithLanguageResolver(r.Context(), func() service.Language {
+			return m.resolve(r)
+		})
+
+		w.Header().Add("Vary", "Accept-Language")
+
+		lw := &localeResponseWriter{ResponseWriter: w, ctx: ctx, translations: m.translations}
+		next.ServeHTTP(lw, r.WithContext(ctx))
+	})
+}
+
+func (m *LocaleMiddleware) resolve(r *http.Request) service.Language {
+	if lang, ok := m.translations.NegotiateLanguage(r.Header.Get("Accept-Language")); ok {
+		return lang
+	}
+
+	if language := m.deviceLanguage(r.Context(), r); language != "" {
+		return m.translations.ParseLanguage(language)
+	}
+
+	// Left unresolved so services that know the user can apply their own
+	// fallback before DefaultLanguage.
+	return ""
+}
+
+func (m *LocaleMiddleware) deviceLanguage(ctx context.Context, r *http.Request) string {
+	if r.Header.Get("Authorization") != "" {
+		sub, err := m.authMiddleware.ValidateJWTFromRequest(r)
+		if err != nil {
+			return ""
+		}
+
+		userID, err := uuid.Parse(sub)
+		if err != nil {
+			return ""
+		}
+
+		language, _, err := m.userRepo.GetUserLanguageAndPhone(ctx, userID)
+		if err != nil {
+			slog.Debug("failed to load user language", "user_id", userID, "error", err)
+			return ""
+		}
+		return language
+	}
+
+	deviceID := r.Header.Get("X-Device-Id")
+	if deviceID == "" {
+		deviceID = r.Header.Get("Device-Id")
+	}
+	if deviceID == "" {
+		return ""
+	}
+
+	session, err := m.anonymousSessionRepo.FindByDeviceID(ctx, deviceID)
+	if err != nil || session == nil {
+		return ""
+	}
+	return session.DeviceLanguage
+}
+
+type localeResponseWriter struct {
+	http.ResponseWriter
+	ctx          context.Context //nolint:containedctx // carries the lazily resolved request language
+	translations *service.TranslationService
+}
+
+func (lw *localeResponseWriter) LocalizeError(code string) (string, bool) {
+	lang := service.LanguageFromContext(lw.ctx)
+	lw.Header().Set("Content-Language", string(lang))
+	return lw.translations.ErrorMessage(code, lang)
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	httputil "github.com/risk-place-angola/backend-risk-place/internal/adapter/http/util"
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
 )
 
 type OptionalAuthMiddleware struct {
@@ -71,7 +72,7 @@ func (m *OptionalAuthMiddleware) RequireDeviceID(next http.Handler) http.Handler
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		identifier, _, err := m.ExtractIdentifier(r)
 		if err != nil {
-			httputil.Error(w, "device_id or authentication required", http.StatusUnauthorized)
+			httputil.Error(w, domainErrors.ErrIdentifierRequired, http.StatusUnauthorized)
 			return
 		}
 
@@ -6,6 +6,7 @@ import (
 	"runtime/debug"
 
 	httputil "github.com/risk-place-angola/backend-risk-place/internal/adapter/http/util"
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
 )
 
 func PanicRecovery(next http.Handler) http.Handler {
@@ -18,7 +19,7 @@ func PanicRecovery(next http.Handler) http.Handler {
 					"method", r.Method,
 					"path", r.URL.Path,
 				)
-				httputil.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				httputil.Error(w, domainErrors.ErrInternalServer, http.StatusInternalServerError)
 			}
 		}()
 		next.ServeHTTP(w, r)
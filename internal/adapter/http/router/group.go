@@ -50,10 +50,10 @@ type RouteGroups struct {
 
 func NewGroups(mux *http.ServeMux, mw MWSet) RouteGroups {
 	return RouteGroups{
-		Public:               NewRouteGroup(mux, mw.Logging),
-		OptionalAuth:         NewRouteGroup(mux, mw.Logging, mw.OptionalAuth),
-		ProtectedJWT:         NewRouteGroup(mux, mw.Logging, mw.JWT),
-		ProtectedAPIKey:      NewRouteGroup(mux, mw.Logging, mw.APIKey),
-		ProtectedAPIKeyLimit: NewRouteGroup(mux, mw.Logging, mw.APIKeyWithLimit),
+		Public:               NewRouteGroup(mux, mw.Logging, mw.Locale),
+		OptionalAuth:         NewRouteGroup(mux, mw.Logging, mw.Locale, mw.OptionalAuth),
+		ProtectedJWT:         NewRouteGroup(mux, mw.Logging, mw.Locale, mw.JWT),
+		ProtectedAPIKey:      NewRouteGroup(mux, mw.Logging, mw.Locale, mw.APIKey),
+		ProtectedAPIKeyLimit: NewRouteGroup(mux, mw.Logging, mw.Locale, mw.APIKeyWithLimit),
 	}
 }
@@ -11,6 +11,7 @@ import (
 // Pattern: Compose these middlewares in RouteGroups based on authentication needs.
 type MWSet struct {
 	Logging middleware.Middleware
+	Locale  middleware.Middleware
 
 	JWT          middleware.Middleware
 	OptionalAuth middleware.Middleware
@@ -18,18 +19,30 @@ type MWSet struct {
 	APIKey          middleware.Middleware
 	APIKeyWithLimit middleware.Middleware
 
+	TwilioSignature middleware.Middleware
+	GatewayToken    middleware.Middleware
+
 	RequirePermission func(resource, action string) middleware.Middleware
 }
 
 func NewMWSet(c *bootstrap.Container) MWSet {
 	return MWSet{
 		Logging: middleware.Logging,
+		Locale: func(next http.Handler) http.Handler {
+			return c.LocaleMiddleware.Negotiate(next)
+		},
 		JWT: func(next http.Handler) http.Handler {
 			return c.AuthMiddleware.ValidateJWT(next)
 		},
 		OptionalAuth: func(next http.Handler) http.Handler {
 			return c.OptionalAuthMiddleware.ValidateOptional(next)
 		},
+		TwilioSignature: func(next http.Handler) http.Handler {
+			return c.TwilioSignature.Validate(next)
+		},
+		GatewayToken: func(next http.Handler) http.Handler {
+			return c.GatewayToken.Validate(next)
+		},
 		RequirePermission: func(resource, action string) middleware.Middleware {
 			return func(next http.Handler) http.Handler {
 				return c.AuthorizationMiddleware.RequirePermission(resource, action)(next)
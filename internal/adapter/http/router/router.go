@@ -32,14 +32,36 @@ func SetupRoutes(container *bootstrap.Container) *http.ServeMux {
 	g.OptionalAuth.HandleFunc("GET /api/v1/risks/topics", container.RiskHandler.ListRiskTopics)
 	g.OptionalAuth.HandleFunc("GET /api/v1/risks/topics/{id}", container.RiskHandler.GetRiskTopic)
 
-	adminRiskTypeGroup := NewRouteGroup(mux, mw.Logging, mw.JWT, mw.RequirePermission("risk_type", "manage"))
+	adminRiskTypeGroup := NewRouteGroup(mux, mw.Logging, mw.Locale, mw.JWT, mw.RequirePermission("risk_type", "manage"))
 	adminRiskTypeGroup.HandleFunc("PUT /api/v1/risks/types/{id}/enabled", container.RiskHandler.UpdateRiskTypeIsEnabled)
 
+	adminIncidentWeightGroup := NewRouteGroup(mux, mw.Logging, mw.Locale, mw.JWT, mw.RequirePermission("incident_weight", "manage"))
+	adminIncidentWeightGroup.HandleFunc("GET /api/v1/admin/incident-weights", container.RiskHandler.ListIncidentWeightRules)
+	adminIncidentWeightGroup.HandleFunc("PUT /api/v1/admin/incident-weights", container.RiskHandler.SaveIncidentWeightRule)
+	adminIncidentWeightGroup.HandleFunc("DELETE /api/v1/admin/incident-weights/{id}", container.RiskHandler.DeleteIncidentWeightRule)
+
+	adminDangerZoneGroup := NewRouteGroup(mux, mw.Logging, mw.Locale, mw.JWT, mw.RequirePermission("danger_zone", "manage"))
+	adminDangerZoneGroup.HandleFunc("POST /api/v1/admin/danger-zones", container.DangerZoneHandler.CreateManualDangerZone)
+	adminDangerZoneGroup.HandleFunc("GET /api/v1/admin/danger-zones", container.DangerZoneHandler.ListManualDangerZones)
+	adminDangerZoneGroup.HandleFunc("PUT /api/v1/admin/danger-zones/{id}", container.DangerZoneHandler.UpdateManualDangerZone)
+	adminDangerZoneGroup.HandleFunc("DELETE /api/v1/admin/danger-zones/{id}", container.DangerZoneHandler.DeleteManualDangerZone)
+
+	adminNotificationGroup := NewRouteGroup(mux, mw.Logging, mw.Locale, mw.JWT, mw.RequirePermission("notification", "read"))
+	adminNotificationGroup.HandleFunc("GET /api/v1/admin/notifications/metrics", container.DeliveryMetricsHandler.GetMetrics)
+
+	smsWebhookGroup := NewRouteGroup(mux, mw.Logging, mw.TwilioSignature)
+	smsWebhookGroup.HandleFunc("POST /api/v1/sms/inbound", container.SMSInboundHandler.ReceiveSMS)
+
+	ussdWebhookGroup := NewRouteGroup(mux, mw.Logging, mw.GatewayToken)
+	ussdWebhookGroup.HandleFunc("POST /api/v1/ussd", container.USSDHandler.HandleSession)
+
 	g.ProtectedJWT.HandleFunc("GET /api/v1/users/me", container.UserHandler.Me)
 	g.ProtectedJWT.HandleFunc("PUT /api/v1/users/profile", container.UserHandler.UpdateProfile)
 	g.ProtectedJWT.HandleFunc("PUT /api/v1/users/me/device", container.NotificationHandler.UpdateDeviceInfo)
 	g.OptionalAuth.HandleFunc("PUT /api/v1/users/me/notifications/preferences", container.NotificationHandler.UpdateNotificationPreferences)
 	g.OptionalAuth.HandleFunc("GET /api/v1/users/me/notifications/preferences", container.NotificationHandler.GetNotificationPreferences)
+	g.Public.HandleFunc("GET /api/v1/notifications/email/unsubscribe", container.NotificationHandler.UnsubscribeEmailPage)
+	g.Public.HandleFunc("POST /api/v1/notifications/email/unsubscribe", container.NotificationHandler.UnsubscribeEmail)
 
 	g.ProtectedJWT.HandleFunc("GET /api/v1/users/me/emergency-contacts", container.EmergencyContactHandler.GetEmergencyContacts)
 	g.ProtectedJWT.HandleFunc("POST /api/v1/users/me/emergency-contacts", container.EmergencyContactHandler.CreateEmergencyContact)
@@ -49,6 +71,8 @@ func SetupRoutes(container *bootstrap.Container) *http.ServeMux {
 
 	g.OptionalAuth.HandleFunc("GET /api/v1/users/me/settings", container.SafetySettingsHandler.GetSettings)
 	g.OptionalAuth.HandleFunc("PUT /api/v1/users/me/settings", container.SafetySettingsHandler.UpdateSettings)
+	g.ProtectedJWT.HandleFunc("GET /api/v1/users/me/digest", container.DigestHandler.GetPreferences)
+	g.ProtectedJWT.HandleFunc("PUT /api/v1/users/me/digest", container.DigestHandler.UpdatePreferences)
 
 	g.OptionalAuth.HandleFunc("POST /api/v1/alerts", container.AlertHandler.CreateAlert)
 	g.OptionalAuth.HandleFunc("POST /api/v1/alerts/{id}/subscribe", container.MyAlertsHandler.SubscribeToAlert)
@@ -68,6 +92,11 @@ func SetupRoutes(container *bootstrap.Container) *http.ServeMux {
 	g.ProtectedJWT.HandleFunc("POST /api/v1/routes/navigate-home", container.SafeRouteHandler.NavigateToHome)
 	g.ProtectedJWT.HandleFunc("POST /api/v1/routes/navigate-work", container.SafeRouteHandler.NavigateToWork)
 
+	g.ProtectedJWT.HandleFunc("POST /api/v1/trips", container.TripHandler.StartTrip)
+	g.ProtectedJWT.HandleFunc("GET /api/v1/trips/active", container.TripHandler.GetActiveTrip)
+	g.ProtectedJWT.HandleFunc("POST /api/v1/trips/{id}/check-in", container.TripHandler.CheckInTrip)
+	g.ProtectedJWT.HandleFunc("POST /api/v1/trips/{id}/end", container.TripHandler.EndTrip)
+
 	g.OptionalAuth.HandleFunc("GET /api/v1/reports", container.ReportHandler.List)
 	g.ProtectedJWT.HandleFunc("POST /api/v1/reports", container.ReportHandler.Create)
 	g.OptionalAuth.HandleFunc("GET /api/v1/reports/nearby", container.ReportHandler.ListNearby)
@@ -84,6 +113,8 @@ func SetupRoutes(container *bootstrap.Container) *http.ServeMux {
 	g.OptionalAuth.HandleFunc("POST /api/v1/users/nearby", container.NearbyUsersHandler.GetNearbyUsers)
 
 	g.OptionalAuth.HandleFunc("POST /api/v1/danger-zones/nearby", container.DangerZoneHandler.GetDangerZonesNearby)
+	g.OptionalAuth.HandleFunc("POST /api/v1/danger-zones/trends", container.DangerZoneHandler.GetDangerZoneTrends)
+	g.OptionalAuth.HandleFunc("GET /api/v1/danger-zones/{grid_cell_id}", container.DangerZoneHandler.ExplainDangerZone)
 
 	mux.HandleFunc("/ws/alerts", container.WSHandler.HandleWebSocket)
 	mux.HandleFunc("/docs/", httpSwagger.WrapHandler)
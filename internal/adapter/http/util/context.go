@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/google/uuid"
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
 )
 
 type ContextKey string
@@ -27,13 +28,13 @@ func GetUserIDFromContext(ctx context.Context) (string, bool) {
 func ExtractAndValidateUserID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
 	userID, ok := r.Context().Value("user_id").(string)
 	if !ok || userID == "" {
-		Error(w, "unauthorized", http.StatusUnauthorized)
+		Error(w, domainErrors.ErrUnauthorized, http.StatusUnauthorized)
 		return uuid.Nil, false
 	}
 
 	uid, err := uuid.Parse(userID)
 	if err != nil {
-		Error(w, "invalid user ID", http.StatusBadRequest)
+		Error(w, domainErrors.ErrInvalidUserID, http.StatusBadRequest)
 		return uuid.Nil, false
 	}
 
@@ -43,13 +44,13 @@ func ExtractAndValidateUserID(w http.ResponseWriter, r *http.Request) (uuid.UUID
 func ExtractAndValidatePathID(w http.ResponseWriter, r *http.Request, paramName, entityName string) (uuid.UUID, bool) {
 	id := r.PathValue(paramName)
 	if id == "" {
-		Error(w, entityName+" ID is required", http.StatusBadRequest)
+		Error(w, domainErrors.IDRequired(entityName), http.StatusBadRequest)
 		return uuid.Nil, false
 	}
 
 	parsed, err := uuid.Parse(id)
 	if err != nil {
-		Error(w, "invalid "+entityName+" ID", http.StatusBadRequest)
+		Error(w, domainErrors.InvalidID(entityName), http.StatusBadRequest)
 		return uuid.Nil, false
 	}
 
@@ -136,7 +137,7 @@ func ExtractUserIdentifier(r *http.Request) (*UserIdentifier, bool) {
 func ExtractUserIdentifierOrError(w http.ResponseWriter, r *http.Request) (*UserIdentifier, bool) {
 	identifier, ok := ExtractUserIdentifier(r)
 	if !ok {
-		Error(w, "user ID or device ID required", http.StatusUnauthorized)
+		Error(w, domainErrors.ErrIdentifierRequired, http.StatusUnauthorized)
 		return nil, false
 	}
 	return identifier, true
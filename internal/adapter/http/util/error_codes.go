@@ -0,0 +1,35 @@
+package util
+
+import (
+	"net/http"
+
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
+)
+
+// codeForStatus is the error code for responses whose error carries no
+// code of its own.
+func codeForStatus(status int) domainErrors.Code {
+	switch status {
+	case http.StatusUnauthorized:
+		return domainErrors.CodeUnauthorized
+	case http.StatusForbidden:
+		return domainErrors.CodeForbidden
+	case http.StatusNotFound:
+		return domainErrors.CodeNotFound
+	case http.StatusMethodNotAllowed:
+		return domainErrors.CodeMethodNotAllowed
+	case http.StatusConflict:
+		return domainErrors.CodeConflict
+	case http.StatusGone:
+		return domainErrors.CodeGone
+	case http.StatusTooManyRequests:
+		return domainErrors.CodeTooManyRequests
+	case http.StatusServiceUnavailable:
+		return domainErrors.CodeUnavailable
+	}
+
+	if status >= http.StatusInternalServerError {
+		return domainErrors.CodeInternal
+	}
+	return domainErrors.CodeBadRequest
+}
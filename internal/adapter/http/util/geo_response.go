@@ -0,0 +1,433 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
+)
+
+// GeoFormat is the representation a client asked for: the regular JSON
+// body, or a file GIS tools, Google Earth and navigation apps can open.
+type GeoFormat string
+
+const (
+	GeoFormatJSON    GeoFormat = "json"
+	GeoFormatGeoJSON GeoFormat = "geojson"
+	GeoFormatGPX     GeoFormat = "gpx"
+	GeoFormatKML     GeoFormat = "kml"
+)
+
+const (
+	geoJSONMediaType = "application/geo+json"
+	gpxMediaType     = "application/gpx+xml"
+	kmlMediaType     = "application/vnd.google-earth.kml+xml"
+
+	gpxNamespace = "http://www.topografix.com/GPX/1/1"
+	// gpxPropertiesNamespace qualifies the <extensions> elements that carry
+	// feature properties, which GPX has no element for.
+	gpxPropertiesNamespace = "urn:risk-place:gpx:properties"
+	kmlNamespace           = "http://www.opengis.net/kml/2.2"
+	geoCreator             = "Risk Place Angola"
+)
+
+var ErrUnsupportedGeoFormat = fmt.Errorf("%w: format must be json, geojson, gpx or kml", domainErrors.ErrInvalidRequest)
+
+// NegotiateGeoFormat reads the format query parameter or, without it, the
+// Accept header. Clients that ask for none of the geo media types get JSON.
+func NegotiateGeoFormat(r *http.Request) (GeoFormat, error) {
+	if format := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("format"))); format != "" {
+		switch GeoFormat(format) {
+		case GeoFormatJSON, GeoFormatGeoJSON, GeoFormatGPX, GeoFormatKML:
+			return GeoFormat(format), nil
+		}
+		return "", ErrUnsupportedGeoFormat
+	}
+
+	type candidate struct {
+		format  GeoFormat
+		quality float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, rest, _ := strings.Cut(strings.TrimSpace(part), ";")
+		quality := 1.0
+		if q, found := strings.CutPrefix(strings.TrimSpace(rest), "q="); found {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if quality <= 0 {
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(mediaType)) {
+		case "application/json":
+			candidates = append(candidates, candidate{format: GeoFormatJSON, quality: quality})
+		case geoJSONMediaType:
+			candidates = append(candidates, candidate{format: GeoFormatGeoJSON, quality: quality})
+		case gpxMediaType:
+			candidates = append(candidates, candidate{format: GeoFormatGPX, quality: quality})
+		case kmlMediaType:
+			candidates = append(candidates, candidate{format: GeoFormatKML, quality: quality})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].quality > candidates[j].quality })
+	if len(candidates) > 0 {
+		return candidates[0].format, nil
+	}
+	return GeoFormatJSON, nil
+}
+
+type GeoGeometryType string
+
+const (
+	GeoGeometryPoint      GeoGeometryType = "Point"
+	GeoGeometryLineString GeoGeometryType = "LineString"
+	GeoGeometryPolygon    GeoGeometryType = "Polygon"
+)
+
+type GeoCoordinate struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// GeoFeature is one exported shape. Polygons hold their outer ring, open
+// or closed.
+type GeoFeature struct {
+	Name        string
+	Geometry    GeoGeometryType
+	Coordinates []GeoCoordinate
+	Properties  map[string]any
+}
+
+func NewGeoPoint(name string, lat, lon float64, properties map[string]any) GeoFeature {
+	return GeoFeature{
+		Name:        name,
+		Geometry:    GeoGeometryPoint,
+		Coordinates: []GeoCoordinate{{Latitude: lat, Longitude: lon}},
+		Properties:  properties,
+	}
+}
+
+// GeoProperties turns a response DTO into feature properties under its JSON
+// names, leaving out the fields the geometry already carries.
+func GeoProperties(v any, omit ...string) map[string]any {
+	properties := map[string]any{}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return properties
+	}
+	if err := json.Unmarshal(data, &properties); err != nil {
+		return map[string]any{}
+	}
+	for _, key := range omit {
+		delete(properties, key)
+	}
+	return properties
+}
+
+// GeoResponse writes features as a GeoJSON FeatureCollection, a GPX file
+// (points as waypoints, lines and polygons as tracks) or a KML document.
+func GeoResponse(w http.ResponseWriter, format GeoFormat, filename string, features []GeoFeature) {
+	var (
+		body      []byte
+		mediaType string
+		err       error
+	)
+	switch format {
+	case GeoFormatGeoJSON:
+		body, err = encodeGeoJSON(features)
+		mediaType = geoJSONMediaType
+	case GeoFormatGPX:
+		body, err = encodeGPX(filename, features)
+		mediaType = gpxMediaType
+	case GeoFormatKML:
+		body, err = encodeKML(filename, features)
+		mediaType = kmlMediaType
+	case GeoFormatJSON:
+		err = ErrUnsupportedGeoFormat
+	}
+	if err != nil {
+		http.Error(w, `{"data":"failed to encode response"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", mediaType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+"."+string(format)))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
+type geoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+type geoJSONFeature struct {
+	Type       string          `json:"type"`
+	Geometry   geoJSONGeometry `json:"geometry"`
+	Properties map[string]any  `json:"properties"`
+}
+
+type geoJSONGeometry struct {
+	Type        GeoGeometryType `json:"type"`
+	Coordinates any             `json:"coordinates"`
+}
+
+func encodeGeoJSON(features []GeoFeature) ([]byte, error) {
+	collection := geoJSONFeatureCollection{Type: "FeatureCollection", Features: make([]geoJSONFeature, 0, len(features))}
+	for _, f := range features {
+		properties := make(map[string]any, len(f.Properties)+1)
+		for k, v := range f.Properties {
+			properties[k] = v
+		}
+		if _, ok := properties["name"]; !ok && f.Name != "" {
+			properties["name"] = f.Name
+		}
+
+		// GeoJSON positions are longitude first.
+		positions := make([][2]float64, 0, len(f.Coordinates))
+		for _, c := range f.Coordinates {
+			positions = append(positions, [2]float64{c.Longitude, c.Latitude})
+		}
+
+		geometry := geoJSONGeometry{Type: f.Geometry}
+		switch f.Geometry {
+		case GeoGeometryPoint:
+			if len(positions) == 0 {
+				continue
+			}
+			geometry.Coordinates = positions[0]
+		case GeoGeometryLineString:
+			geometry.Coordinates = positions
+		case GeoGeometryPolygon:
+			geometry.Coordinates = [][][2]float64{closeRing(positions)}
+		}
+
+		collection.Features = append(collection.Features, geoJSONFeature{
+			Type:       "Feature",
+			Geometry:   geometry,
+			Properties: properties,
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(collection); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+type gpxDocument struct {
+	XMLName    xml.Name      `xml:"gpx"`
+	Xmlns      string        `xml:"xmlns,attr"`
+	XmlnsProps string        `xml:"xmlns:rp,attr"`
+	Version    string        `xml:"version,attr"`
+	Creator    string        `xml:"creator,attr"`
+	Name       string        `xml:"metadata>name"`
+	Waypoints  []gpxWaypoint `xml:"wpt"`
+	Tracks     []gpxTrack    `xml:"trk"`
+}
+
+type gpxWaypoint struct {
+	Latitude   float64        `xml:"lat,attr"`
+	Longitude  float64        `xml:"lon,attr"`
+	Name       string         `xml:"name,omitempty"`
+	Extensions *gpxExtensions `xml:"extensions,omitempty"`
+}
+
+type gpxTrack struct {
+	Name       string         `xml:"name,omitempty"`
+	Extensions *gpxExtensions `xml:"extensions,omitempty"`
+	Points     []gpxWaypoint  `xml:"trkseg>trkpt"`
+}
+
+type gpxExtensions struct {
+	Properties []gpxProperty `xml:"rp:property"`
+}
+
+type gpxProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:",chardata"`
+}
+
+func encodeGPX(name string, features []GeoFeature) ([]byte, error) {
+	doc := gpxDocument{
+		Xmlns:      gpxNamespace,
+		XmlnsProps: gpxPropertiesNamespace,
+		Version:    "1.1",
+		Creator:    geoCreator,
+		Name:       name,
+	}
+
+	for _, f := range features {
+		var extensions *gpxExtensions
+		if properties := xmlProperties(f.Properties); len(properties) > 0 {
+			extensions = &gpxExtensions{}
+			for _, p := range properties {
+				extensions.Properties = append(extensions.Properties, gpxProperty{Name: p.name, Value: p.value})
+			}
+		}
+
+		if f.Geometry == GeoGeometryPoint {
+			if len(f.Coordinates) == 0 {
+				continue
+			}
+			doc.Waypoints = append(doc.Waypoints, gpxWaypoint{
+				Latitude:   f.Coordinates[0].Latitude,
+				Longitude:  f.Coordinates[0].Longitude,
+				Name:       f.Name,
+				Extensions: extensions,
+			})
+			continue
+		}
+
+		coordinates := f.Coordinates
+		if f.Geometry == GeoGeometryPolygon {
+			coordinates = closeCoordinates(coordinates)
+		}
+		track := gpxTrack{Name: f.Name, Extensions: extensions, Points: make([]gpxWaypoint, 0, len(coordinates))}
+		for _, c := range coordinates {
+			track.Points = append(track.Points, gpxWaypoint{Latitude: c.Latitude, Longitude: c.Longitude})
+		}
+		doc.Tracks = append(doc.Tracks, track)
+	}
+
+	return encodeXML(doc)
+}
+
+type kmlDocument struct {
+	XMLName    xml.Name       `xml:"kml"`
+	Xmlns      string         `xml:"xmlns,attr"`
+	Name       string         `xml:"Document>name"`
+	Placemarks []kmlPlacemark `xml:"Document>Placemark"`
+}
+
+type kmlPlacemark struct {
+	Name         string           `xml:"name,omitempty"`
+	ExtendedData *kmlExtendedData `xml:"ExtendedData,omitempty"`
+	Point        *kmlCoordinates  `xml:"Point,omitempty"`
+	LineString   *kmlCoordinates  `xml:"LineString,omitempty"`
+	Polygon      *kmlPolygon      `xml:"Polygon,omitempty"`
+}
+
+type kmlExtendedData struct {
+	Data []kmlData `xml:"Data"`
+}
+
+type kmlData struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value"`
+}
+
+type kmlCoordinates struct {
+	Coordinates string `xml:"coordinates"`
+}
+
+type kmlPolygon struct {
+	OuterBoundary kmlCoordinates `xml:"outerBoundaryIs>LinearRing"`
+}
+
+func encodeKML(name string, features []GeoFeature) ([]byte, error) {
+	doc := kmlDocument{Xmlns: kmlNamespace, Name: name}
+
+	for _, f := range features {
+		placemark := kmlPlacemark{Name: f.Name}
+		if properties := xmlProperties(f.Properties); len(properties) > 0 {
+			placemark.ExtendedData = &kmlExtendedData{}
+			for _, p := range properties {
+				placemark.ExtendedData.Data = append(placemark.ExtendedData.Data, kmlData{Name: p.name, Value: p.value})
+			}
+		}
+
+		switch f.Geometry {
+		case GeoGeometryPoint:
+			if len(f.Coordinates) == 0 {
+				continue
+			}
+			placemark.Point = &kmlCoordinates{Coordinates: kmlCoordinateList(f.Coordinates[:1])}
+		case GeoGeometryLineString:
+			placemark.LineString = &kmlCoordinates{Coordinates: kmlCoordinateList(f.Coordinates)}
+		case GeoGeometryPolygon:
+			placemark.Polygon = &kmlPolygon{
+				OuterBoundary: kmlCoordinates{Coordinates: kmlCoordinateList(closeCoordinates(f.Coordinates))},
+			}
+		}
+		doc.Placemarks = append(doc.Placemarks, placemark)
+	}
+
+	return encodeXML(doc)
+}
+
+// kmlCoordinateList renders KML's "lon,lat lon,lat" tuples.
+func kmlCoordinateList(coordinates []GeoCoordinate) string {
+	tuples := make([]string, 0, len(coordinates))
+	for _, c := range coordinates {
+		tuples = append(tuples,
+			strconv.FormatFloat(c.Longitude, 'f', -1, 64)+","+strconv.FormatFloat(c.Latitude, 'f', -1, 64))
+	}
+	return strings.Join(tuples, " ")
+}
+
+func encodeXML(doc any) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&buf)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return nil, err
+	}
+	buf.WriteString("\n")
+	return buf.Bytes(), nil
+}
+
+type xmlProperty struct {
+	name  string
+	value string
+}
+
+// xmlProperties flattens properties to text, sorted by name: strings as
+// they are, anything else, nested objects included, as JSON.
+func xmlProperties(properties map[string]any) []xmlProperty {
+	result := make([]xmlProperty, 0, len(properties))
+	for name, value := range properties {
+		if value == nil {
+			continue
+		}
+		text, ok := value.(string)
+		if !ok {
+			data, err := json.Marshal(value)
+			if err != nil {
+				continue
+			}
+			text = string(data)
+		}
+		result = append(result, xmlProperty{name: name, value: text})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].name < result[j].name })
+	return result
+}
+
+func closeCoordinates(ring []GeoCoordinate) []GeoCoordinate {
+	if len(ring) == 0 || ring[0] == ring[len(ring)-1] {
+		return ring
+	}
+	return append(append(make([]GeoCoordinate, 0, len(ring)+1), ring...), ring[0])
+}
+
+func closeRing(ring [][2]float64) [][2]float64 {
+	if len(ring) == 0 || ring[0] == ring[len(ring)-1] {
+		return ring
+	}
+	return append(append(make([][2]float64, 0, len(ring)+1), ring...), ring[0])
+}
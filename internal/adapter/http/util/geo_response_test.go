@@ -0,0 +1,171 @@
+package util
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiateGeoFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		accept string
+		want   GeoFormat
+	}{
+		{name: "json by default", want: GeoFormatJSON},
+		{name: "browser accept", accept: "text/html,application/xhtml+xml,*/*;q=0.8", want: GeoFormatJSON},
+		{name: "query wins", query: "?format=GPX", accept: "application/geo+json", want: GeoFormatGPX},
+		{name: "accept geojson", accept: "application/geo+json", want: GeoFormatGeoJSON},
+		{name: "accept by quality", accept: "application/json;q=0.5, application/vnd.google-earth.kml+xml", want: GeoFormatKML},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/reports"+tt.query, nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+			format, err := NegotiateGeoFormat(r)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, format)
+		})
+	}
+
+	_, err := NegotiateGeoFormat(httptest.NewRequest(http.MethodGet, "/api/v1/reports?format=shp", nil))
+	assert.ErrorIs(t, err, domainErrors.ErrInvalidRequest)
+}
+
+func testGeoFeatures() []GeoFeature {
+	return []GeoFeature{
+		{
+			Name:     "Route 1 (safest)",
+			Geometry: GeoGeometryLineString,
+			Coordinates: []GeoCoordinate{
+				{Latitude: -8.8383, Longitude: 13.2344},
+				{Latitude: -8.8390, Longitude: 13.2444},
+			},
+			Properties: map[string]any{"safety_score": 87.5, "labels": []any{"safest"}},
+		},
+		NewGeoPoint("crime: assalto", -8.8385, 13.2400, map[string]any{"report_id": "r1"}),
+		{
+			Name:     "Zone",
+			Geometry: GeoGeometryPolygon,
+			Coordinates: []GeoCoordinate{
+				{Latitude: -8.84, Longitude: 13.23},
+				{Latitude: -8.84, Longitude: 13.24},
+				{Latitude: -8.83, Longitude: 13.24},
+			},
+			Properties: map[string]any{"risk_level": "high"},
+		},
+	}
+}
+
+func TestGeoResponse_GeoJSON(t *testing.T) {
+	rec := httptest.NewRecorder()
+	GeoResponse(rec, GeoFormatGeoJSON, "safe-route", testGeoFeatures())
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/geo+json", rec.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="safe-route.geojson"`, rec.Header().Get("Content-Disposition"))
+
+	var collection struct {
+		Type     string `json:"type"`
+		Features []struct {
+			Geometry struct {
+				Type        string          `json:"type"`
+				Coordinates json.RawMessage `json:"coordinates"`
+			} `json:"geometry"`
+			Properties map[string]any `json:"properties"`
+		} `json:"features"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &collection))
+	assert.Equal(t, "FeatureCollection", collection.Type)
+	assert.Len(t, collection.Features, 3)
+
+	assert.Equal(t, "LineString", collection.Features[0].Geometry.Type)
+	assert.JSONEq(t, `[[13.2344,-8.8383],[13.2444,-8.839]]`, string(collection.Features[0].Geometry.Coordinates), "longitude first")
+	assert.InDelta(t, 87.5, collection.Features[0].Properties["safety_score"], 0.001)
+	assert.Equal(t, "Route 1 (safest)", collection.Features[0].Properties["name"])
+
+	assert.JSONEq(t, `[13.24,-8.8385]`, string(collection.Features[1].Geometry.Coordinates))
+	assert.JSONEq(t, `[[[13.23,-8.84],[13.24,-8.84],[13.24,-8.83],[13.23,-8.84]]]`,
+		string(collection.Features[2].Geometry.Coordinates), "rings are closed")
+}
+
+func TestGeoResponse_GPX(t *testing.T) {
+	rec := httptest.NewRecorder()
+	GeoResponse(rec, GeoFormatGPX, "safe-route", testGeoFeatures())
+
+	assert.Equal(t, "application/gpx+xml", rec.Header().Get("Content-Type"))
+
+	var doc struct {
+		Waypoints []struct {
+			Lat  float64 `xml:"lat,attr"`
+			Name string  `xml:"name"`
+		} `xml:"wpt"`
+		Tracks []struct {
+			Name       string `xml:"name"`
+			Properties []struct {
+				Name  string `xml:"name,attr"`
+				Value string `xml:",chardata"`
+			} `xml:"extensions>property"`
+			Points []struct {
+				Lon float64 `xml:"lon,attr"`
+			} `xml:"trkseg>trkpt"`
+		} `xml:"trk"`
+	}
+	assert.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &doc))
+	assert.Len(t, doc.Waypoints, 1)
+	assert.Equal(t, "crime: assalto", doc.Waypoints[0].Name)
+	assert.Len(t, doc.Tracks, 2)
+	assert.Len(t, doc.Tracks[0].Points, 2)
+	assert.Len(t, doc.Tracks[1].Points, 4, "polygons become closed tracks")
+	assert.Len(t, doc.Tracks[0].Properties, 2)
+	assert.Equal(t, "labels", doc.Tracks[0].Properties[0].Name)
+	assert.Equal(t, `["safest"]`, doc.Tracks[0].Properties[0].Value)
+	assert.Equal(t, "87.5", doc.Tracks[0].Properties[1].Value)
+}
+
+func TestGeoResponse_KML(t *testing.T) {
+	rec := httptest.NewRecorder()
+	GeoResponse(rec, GeoFormatKML, "danger-zones", testGeoFeatures())
+
+	assert.Equal(t, "application/vnd.google-earth.kml+xml", rec.Header().Get("Content-Type"))
+
+	var doc struct {
+		Placemarks []struct {
+			Name string `xml:"name"`
+			Data []struct {
+				Name  string `xml:"name,attr"`
+				Value string `xml:"value"`
+			} `xml:"ExtendedData>Data"`
+			Point      string `xml:"Point>coordinates"`
+			LineString string `xml:"LineString>coordinates"`
+			Polygon    string `xml:"Polygon>outerBoundaryIs>LinearRing>coordinates"`
+		} `xml:"Document>Placemark"`
+	}
+	assert.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &doc))
+	assert.Len(t, doc.Placemarks, 3)
+	assert.Equal(t, "13.2344,-8.8383 13.2444,-8.839", doc.Placemarks[0].LineString)
+	assert.Equal(t, "13.24,-8.8385", doc.Placemarks[1].Point)
+	assert.Equal(t, "report_id", doc.Placemarks[1].Data[0].Name)
+	assert.Equal(t, "r1", doc.Placemarks[1].Data[0].Value)
+	assert.Equal(t, "13.23,-8.84 13.24,-8.84 13.24,-8.83 13.23,-8.84", doc.Placemarks[2].Polygon)
+}
+
+func TestGeoProperties_UsesJSONNames(t *testing.T) {
+	type point struct {
+		Latitude  float64 `json:"latitude"`
+		Longitude float64 `json:"longitude"`
+		RiskLevel string  `json:"risk_level"`
+		Hidden    bool    `json:"-"`
+	}
+
+	properties := GeoProperties(point{Latitude: 1, Longitude: 2, RiskLevel: "high", Hidden: true}, "latitude", "longitude")
+	assert.Equal(t, map[string]any{"risk_level": "high"}, properties)
+}
@@ -3,7 +3,10 @@ package util
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"net/http"
+
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
 )
 
 type ErrorResponse struct {
@@ -12,24 +15,72 @@ type ErrorResponse struct {
 		Message   interface{} `json:"message"`
 		Code      int         `json:"code"`
 		ErrorCode string      `json:"error_code,omitempty"`
+		Detail    interface{} `json:"detail,omitempty"`
 	} `json:"error"`
 }
 
+// ErrorLocalizer is implemented by response writers that know the language
+// negotiated for the request (see middleware.LocaleMiddleware).
+type ErrorLocalizer interface {
+	LocalizeError(code string) (string, bool)
+}
+
+// Error writes an error response. message may be a string or an error; the
+// stable error code comes from the domain error in its chain (see
+// domainErrors.CodeOf) or else from the HTTP status.
 func Error(w http.ResponseWriter, message interface{}, code int) {
 	ErrorWithCode(w, message, code, "")
 }
 
 func ErrorWithCode(w http.ResponseWriter, message interface{}, code int, errorCode string) {
+	// canonical is the text the localized message stands for; anything the
+	// caller added to it is kept as detail for developers.
+	canonical := ""
+	if err, ok := message.(error); ok {
+		message = err.Error()
+		var coded *domainErrors.CodedError
+		if errors.As(err, &coded) {
+			canonical = coded.Message
+			if errorCode == "" {
+				errorCode = string(coded.Code)
+			}
+		}
+	}
+
+	text, _ := message.(string)
+	statusCode := string(codeForStatus(code))
+	if errorCode == "" {
+		errorCode = statusCode
+	}
+
+	localized := message
+	var detail interface{}
+	if localizer, ok := w.(ErrorLocalizer); ok {
+		for _, candidate := range []string{errorCode, statusCode} {
+			msg, found := localizer.LocalizeError(candidate)
+			if !found {
+				continue
+			}
+			localized = msg
+			if msg != text && text != canonical {
+				detail = message
+			}
+			break
+		}
+	}
+
 	var payload = ErrorResponse{
 		Success: false,
 		Error: struct {
 			Message   interface{} `json:"message"`
 			Code      int         `json:"code"`
 			ErrorCode string      `json:"error_code,omitempty"`
+			Detail    interface{} `json:"detail,omitempty"`
 		}{
-			Message:   message,
+			Message:   localized,
 			Code:      code,
 			ErrorCode: errorCode,
+			Detail:    detail,
 		},
 	}
 
@@ -0,0 +1,85 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeLocalizer struct {
+	*httptest.ResponseRecorder
+	messages map[string]string
+}
+
+func (f *fakeLocalizer) LocalizeError(code string) (string, bool) {
+	msg, ok := f.messages[code]
+	return msg, ok
+}
+
+func decodeError(t *testing.T, rec *httptest.ResponseRecorder) ErrorResponse {
+	t.Helper()
+	var resp ErrorResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	return resp
+}
+
+func TestError_DomainErrorIsCodedAndLocalized(t *testing.T) {
+	w := &fakeLocalizer{
+		ResponseRecorder: httptest.NewRecorder(),
+		messages:         map[string]string{"NO_EMERGENCY_CONTACTS": "Não tem contactos de emergência configurados"},
+	}
+
+	Error(w, domainErrors.ErrNoEmergencyContacts, http.StatusBadRequest)
+
+	resp := decodeError(t, w.ResponseRecorder)
+	assert.Equal(t, "NO_EMERGENCY_CONTACTS", resp.Error.ErrorCode)
+	assert.Equal(t, "Não tem contactos de emergência configurados", resp.Error.Message)
+	assert.Nil(t, resp.Error.Detail)
+}
+
+func TestError_UnknownMessageFallsBackToStatusCode(t *testing.T) {
+	w := &fakeLocalizer{
+		ResponseRecorder: httptest.NewRecorder(),
+		messages:         map[string]string{"BAD_REQUEST": "Pedido inválido"},
+	}
+
+	Error(w, "vote_type must be upvote or downvote", http.StatusBadRequest)
+
+	resp := decodeError(t, w.ResponseRecorder)
+	assert.Equal(t, "BAD_REQUEST", resp.Error.ErrorCode)
+	assert.Equal(t, "Pedido inválido", resp.Error.Message)
+	assert.Equal(t, "vote_type must be upvote or downvote", resp.Error.Detail)
+}
+
+func TestError_WithoutLocalizerKeepsMessage(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	Error(rec, domainErrors.InvalidID("report"), http.StatusBadRequest)
+
+	resp := decodeError(t, rec)
+	assert.Equal(t, "INVALID_ID", resp.Error.ErrorCode)
+	assert.Equal(t, "invalid report ID", resp.Error.Message)
+}
+
+func TestError_CodeComesFromTheErrorNotItsText(t *testing.T) {
+	w := &fakeLocalizer{
+		ResponseRecorder: httptest.NewRecorder(),
+		messages:         map[string]string{"INVALID_REQUEST_BODY": "Corpo do pedido inválido"},
+	}
+
+	Error(w, fmt.Errorf("%w: unexpected EOF", domainErrors.ErrInvalidRequestBody), http.StatusBadRequest)
+
+	resp := decodeError(t, w.ResponseRecorder)
+	assert.Equal(t, "INVALID_REQUEST_BODY", resp.Error.ErrorCode)
+	assert.Equal(t, "Corpo do pedido inválido", resp.Error.Message)
+	assert.Equal(t, "invalid request body: unexpected EOF", resp.Error.Detail)
+
+	rec := httptest.NewRecorder()
+	Error(rec, "invalid request body", http.StatusBadRequest)
+	assert.Equal(t, "BAD_REQUEST", decodeError(t, rec).Error.ErrorCode, "plain text is never matched to a code")
+}
@@ -0,0 +1,41 @@
+package notifier
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreaker_OpensAfterThresholdAndProbes(t *testing.T) {
+	breaker := NewCircuitBreaker("fcm", 2, 10*time.Millisecond)
+
+	breaker.RecordFailure()
+	assert.NoError(t, breaker.Allow())
+
+	breaker.RecordFailure()
+	assert.ErrorIs(t, breaker.Allow(), ErrCircuitOpen)
+	assert.Equal(t, "open", breaker.State())
+
+	time.Sleep(15 * time.Millisecond)
+
+	// Only one probe goes through while half open.
+	assert.NoError(t, breaker.Allow())
+	assert.ErrorIs(t, breaker.Allow(), ErrCircuitOpen)
+
+	breaker.RecordSuccess()
+	assert.Equal(t, "closed", breaker.State())
+	assert.NoError(t, breaker.Allow())
+}
+
+func TestCircuitBreaker_FailedProbeReopens(t *testing.T) {
+	breaker := NewCircuitBreaker("twilio", 1, 10*time.Millisecond)
+
+	breaker.RecordFailure()
+	time.Sleep(15 * time.Millisecond)
+
+	assert.NoError(t, breaker.Allow())
+	breaker.RecordFailure()
+
+	assert.ErrorIs(t, breaker.Allow(), ErrCircuitOpen)
+}
@@ -0,0 +1,82 @@
+package notifier
+
+import "sync"
+
+type ProviderStats struct {
+	Sent           int64  `json:"sent"`
+	Failed         int64  `json:"failed"`
+	Retried        int64  `json:"retried"`
+	PrunedTokens   int64  `json:"pruned_tokens"`
+	CircuitRejects int64  `json:"circuit_rejects"`
+	CircuitState   string `json:"circuit_state"`
+}
+
+// DeliveryMetrics keeps in-process delivery counters per provider since the
+// server started.
+type DeliveryMetrics struct {
+	mu       sync.Mutex
+	stats    map[string]*ProviderStats
+	breakers map[string]*CircuitBreaker
+}
+
+func NewDeliveryMetrics() *DeliveryMetrics {
+	return &DeliveryMetrics{
+		stats:    make(map[string]*ProviderStats),
+		breakers: make(map[string]*CircuitBreaker),
+	}
+}
+
+func (m *DeliveryMetrics) trackBreaker(breaker *CircuitBreaker) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.breakers[breaker.Name()] = breaker
+}
+
+func (m *DeliveryMetrics) add(provider string, update func(*ProviderStats)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats, ok := m.stats[provider]
+	if !ok {
+		stats = &ProviderStats{}
+		m.stats[provider] = stats
+	}
+	update(stats)
+}
+
+func (m *DeliveryMetrics) Sent(provider string, n int) {
+	m.add(provider, func(s *ProviderStats) { s.Sent += int64(n) })
+}
+
+func (m *DeliveryMetrics) Failed(provider string, n int) {
+	m.add(provider, func(s *ProviderStats) { s.Failed += int64(n) })
+}
+
+func (m *DeliveryMetrics) Retried(provider string) {
+	m.add(provider, func(s *ProviderStats) { s.Retried++ })
+}
+
+func (m *DeliveryMetrics) Pruned(provider string, n int) {
+	m.add(provider, func(s *ProviderStats) { s.PrunedTokens += int64(n) })
+}
+
+func (m *DeliveryMetrics) CircuitRejected(provider string) {
+	m.add(provider, func(s *ProviderStats) { s.CircuitRejects++ })
+}
+
+func (m *DeliveryMetrics) Snapshot() map[string]ProviderStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := make(map[string]ProviderStats, len(m.stats))
+	for provider, stats := range m.stats {
+		snapshot[provider] = *stats
+	}
+	for provider, breaker := range m.breakers {
+		stats := snapshot[provider]
+		stats.CircuitState = breaker.State()
+		snapshot[provider] = stats
+	}
+
+	return snapshot
+}
@@ -2,12 +2,28 @@ package notifier
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"github.com/risk-place-angola/backend-risk-place/internal/application/port"
 	"github.com/risk-place-angola/backend-risk-place/internal/config"
+	"log/slog"
+	"mime"
+	"net"
 	"net/smtp"
+	"sort"
+	"strings"
+	"time"
 )
 
+// smtpBatchSize caps how many messages share one SMTP session; most relays
+// start throttling or dropping connections beyond this.
+const smtpBatchSize = 50
+
+// smtpMessageTimeout bounds the session handshake and each message, so a
+// relay that stops answering cannot hold the batch forever.
+const smtpMessageTimeout = 30 * time.Second
+
 type SmtpEmailService struct {
 	host     string
 	port     string
@@ -42,6 +58,137 @@ func (s *SmtpEmailService) SendHTMLEmail(ctx context.Context, to, subject, htmlB
 	return smtp.SendMail(addr, auth, s.from, []string{to}, []byte(msg))
 }
 
+func (s *SmtpEmailService) SendBatch(ctx context.Context, messages []port.EmailMessage) error {
+	var errs []error
+	sent := 0
+	for start := 0; start < len(messages); start += smtpBatchSize {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		end := min(start+smtpBatchSize, len(messages))
+		n, err := s.sendSession(ctx, messages[start:end])
+		sent += n
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	err := errors.Join(errs...)
+	if err != nil && sent > 0 {
+		slog.Warn("some emails of the batch were not sent", "sent", sent, "messages", len(messages), "error", err)
+		return nil
+	}
+	return err
+}
+
+// sendSession delivers a chunk of messages over a single SMTP connection
+// and returns how many went out.
+func (s *SmtpEmailService) sendSession(ctx context.Context, messages []port.EmailMessage) (int, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(s.host, s.port))
+	if err != nil {
+		return 0, fmt.Errorf("failed to connect to smtp server: %w", err)
+	}
+
+	if err := setSMTPDeadline(ctx, conn); err != nil {
+		_ = conn.Close()
+		return 0, fmt.Errorf("failed to set smtp deadline: %w", err)
+	}
+
+	client, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		_ = conn.Close()
+		return 0, fmt.Errorf("failed to start smtp session: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.host, MinVersion: tls.VersionTLS12}); err != nil {
+			return 0, fmt.Errorf("failed to start tls: %w", err)
+		}
+	}
+	if ok, _ := client.Extension("AUTH"); ok && s.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.username, s.password, s.host)); err != nil {
+			return 0, fmt.Errorf("failed to authenticate: %w", err)
+		}
+	}
+
+	var errs []error
+	sent := 0
+	for _, m := range messages {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			return sent, errors.Join(errs...)
+		}
+		if err := setSMTPDeadline(ctx, conn); err != nil {
+			errs = append(errs, fmt.Errorf("failed to set smtp deadline: %w", err))
+			return sent, errors.Join(errs...)
+		}
+		if err := s.deliver(client, m); err != nil {
+			errs = append(errs, fmt.Errorf("failed to send email to %s: %w", m.To, err))
+			if resetErr := client.Reset(); resetErr != nil {
+				errs = append(errs, resetErr)
+				return sent, errors.Join(errs...)
+			}
+			continue
+		}
+		sent++
+	}
+
+	if err := client.Quit(); err != nil {
+		errs = append(errs, err)
+	}
+	return sent, errors.Join(errs...)
+}
+
+// setSMTPDeadline gives the next exchange smtpMessageTimeout, or less when
+// ctx ends sooner.
+func setSMTPDeadline(ctx context.Context, conn net.Conn) error {
+	deadline := time.Now().Add(smtpMessageTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	return conn.SetDeadline(deadline)
+}
+
+func (s *SmtpEmailService) deliver(client *smtp.Client, m port.EmailMessage) error {
+	if err := client.Mail(s.from); err != nil {
+		return err
+	}
+	if err := client.Rcpt(m.To); err != nil {
+		return err
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write([]byte(buildHTMLMessageWithHeaders(s.from, m))); err != nil {
+		_ = w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+func buildHTMLMessageWithHeaders(from string, m port.EmailMessage) string {
+	keys := make([]string, 0, len(m.Headers))
+	for k := range m.Headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var extra strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&extra, "%s: %s\r\n", k, m.Headers[k])
+	}
+
+	return fmt.Sprintf(
+		"From: %s\r\nTo: %s\r\nSubject: %s\r\n%sMIME-Version: 1.0\r\nContent-Type: text/html; charset=\"utf-8\"\r\n\r\n%s",
+		from, m.To, mime.QEncoding.Encode("utf-8", m.Subject), extra.String(), m.HTMLBody,
+	)
+}
+
 func buildPlainMessage(from, to, subject, body string) string {
 	return fmt.Sprintf(
 		"From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=\"utf-8\"\r\n\r\n%s",
@@ -2,14 +2,19 @@ package notifier
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 
 	"firebase.google.com/go/v4/messaging"
+	"github.com/risk-place-angola/backend-risk-place/internal/application/port"
 )
 
 const MaxBatchSize = 500
 const ThrottleMs = 400
 
+// apnsCollapseIDMaxLen is the limit APNs puts on apns-collapse-id.
+const apnsCollapseIDMaxLen = 64
+
 type FCMNotifier struct {
 	messageClient *messaging.Client
 }
@@ -28,7 +33,9 @@ func (f *FCMNotifier) NotifyPush(ctx context.Context, deviceToken string, title
 			Title: title,
 			Body:  message,
 		},
-		Data: data,
+		Data:    data,
+		Android: androidConfig(data),
+		APNS:    apnsConfig(data),
 	}
 
 	_, err := f.messageClient.Send(ctx, msg)
@@ -40,8 +47,20 @@ func (f *FCMNotifier) NotifyPush(ctx context.Context, deviceToken string, title
 	return nil
 }
 
-// NotifyPushMulti sends push notifications to multiple device tokens via FCM
+// MulticastError lists the tokens FCM rejected during a multicast send.
+type MulticastError struct {
+	Failures map[string]error
+}
+
+func (e *MulticastError) Error() string {
+	return fmt.Sprintf("fcm rejected %d tokens", len(e.Failures))
+}
+
+// NotifyPushMulti sends push notifications to multiple device tokens via FCM.
+// Tokens rejected by FCM are reported through a *MulticastError.
 func (f *FCMNotifier) NotifyPushMulti(ctx context.Context, deviceTokens []string, title string, message string, data map[string]string) error {
+	failures := make(map[string]error)
+
 	for i := 0; i < len(deviceTokens); i += MaxBatchSize {
 		end := i + MaxBatchSize
 		if end > len(deviceTokens) {
@@ -55,17 +74,58 @@ func (f *FCMNotifier) NotifyPushMulti(ctx context.Context, deviceTokens []string
 				Title: title,
 				Body:  message,
 			},
-			Data: data,
+			Data:    data,
+			Android: androidConfig(data),
+			APNS:    apnsConfig(data),
 		}
 
 		res, err := f.messageClient.SendEachForMulticast(ctx, msg)
 		if err != nil {
 			slog.Error("Error sending FCM multicast push notification", "error", err)
-			return err
+			for _, token := range batch {
+				failures[token] = err
+			}
+			continue
 		}
 
 		slog.Info("FCM multicast push notification sent", "successCount", res.SuccessCount, "failureCount", res.FailureCount)
+
+		for idx, resp := range res.Responses {
+			if !resp.Success && resp.Error != nil {
+				failures[batch[idx]] = resp.Error
+			}
+		}
+	}
+
+	if len(failures) > 0 {
+		return &MulticastError{Failures: failures}
 	}
 
 	return nil
 }
+
+// androidConfig makes pushes sharing a group key replace each other, both
+// while queued in FCM and once shown in the tray.
+func androidConfig(data map[string]string) *messaging.AndroidConfig {
+	group := data[port.PushDataGroupKey]
+	if group == "" {
+		return nil
+	}
+	return &messaging.AndroidConfig{
+		CollapseKey:  group,
+		Notification: &messaging.AndroidNotification{Tag: group},
+	}
+}
+
+func apnsConfig(data map[string]string) *messaging.APNSConfig {
+	group := data[port.PushDataGroupKey]
+	if group == "" {
+		return nil
+	}
+	if len(group) > apnsCollapseIDMaxLen {
+		group = group[:apnsCollapseIDMaxLen]
+	}
+	return &messaging.APNSConfig{
+		Headers: map[string]string{"apns-collapse-id": group},
+	}
+}
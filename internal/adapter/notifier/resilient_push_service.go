@@ -0,0 +1,243 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strings"
+	"time"
+
+	"firebase.google.com/go/v4/messaging"
+	"github.com/risk-place-angola/backend-risk-place/internal/application/port"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
+)
+
+const (
+	providerFCM = "fcm"
+
+	pushMaxAttempts      = 3
+	pushBaseDelay        = 500 * time.Millisecond
+	pushMaxDelay         = 5 * time.Second
+	pushBreakerThreshold = 5
+	pushBreakerTimeout   = 30 * time.Second
+)
+
+type deliveryFailure int
+
+const (
+	failurePermanent deliveryFailure = iota
+	failureTransient
+	failureInvalidToken
+)
+
+// ResilientPushNotifier wraps a NotifierPushService with retries for
+// transient FCM errors, a circuit breaker, delivery metrics and clearing of
+// tokens FCM reports as no longer registered or malformed. Only transient
+// errors count against the breaker: FCM answering that a token or message is
+// invalid shows the provider is up.
+type ResilientPushNotifier struct {
+	next    port.NotifierPushService
+	tokens  repository.DeviceTokenRepository
+	breaker *CircuitBreaker
+	metrics *DeliveryMetrics
+	policy  RetryPolicy
+}
+
+func NewResilientPushNotifier(next port.NotifierPushService, tokens repository.DeviceTokenRepository, metrics *DeliveryMetrics) *ResilientPushNotifier {
+	breaker := NewCircuitBreaker(providerFCM, pushBreakerThreshold, pushBreakerTimeout)
+	metrics.trackBreaker(breaker)
+
+	return &ResilientPushNotifier{
+		next:    next,
+		tokens:  tokens,
+		breaker: breaker,
+		metrics: metrics,
+		policy: RetryPolicy{
+			MaxAttempts: pushMaxAttempts,
+			BaseDelay:   pushBaseDelay,
+			MaxDelay:    pushMaxDelay,
+		},
+	}
+}
+
+func (n *ResilientPushNotifier) NotifyPush(ctx context.Context, deviceToken string, title string, message string, data map[string]string) error {
+	for attempt := 1; ; attempt++ {
+		if err := n.breaker.Allow(); err != nil {
+			n.metrics.CircuitRejected(providerFCM)
+			n.metrics.Failed(providerFCM, 1)
+			return err
+		}
+
+		err := n.next.NotifyPush(ctx, deviceToken, title, message, data)
+		if err == nil {
+			n.breaker.RecordSuccess()
+			n.metrics.Sent(providerFCM, 1)
+			return nil
+		}
+
+		switch classifyPushError(err) {
+		case failureInvalidToken:
+			n.breaker.RecordSuccess()
+			n.metrics.Failed(providerFCM, 1)
+			n.prune(ctx, []string{deviceToken})
+			return err
+		case failureTransient:
+			n.breaker.RecordFailure()
+			if attempt >= n.policy.MaxAttempts {
+				n.metrics.Failed(providerFCM, 1)
+				return fmt.Errorf("push delivery failed after %d attempts: %w", attempt, err)
+			}
+		case failurePermanent:
+			n.breaker.RecordSuccess()
+			n.metrics.Failed(providerFCM, 1)
+			return err
+		}
+
+		n.metrics.Retried(providerFCM)
+		if sleepErr := sleepContext(ctx, n.policy.delay(attempt)); sleepErr != nil {
+			n.metrics.Failed(providerFCM, 1)
+			return err
+		}
+	}
+}
+
+// NotifyPushMulti retries only the tokens that failed transiently. Tokens
+// rejected as invalid are cleared; as long as some tokens got the push, they
+// and other permanent rejections are not reported as an error, since
+// retrying the broadcast would not help. When no token got it, the last
+// rejection is returned.
+func (n *ResilientPushNotifier) NotifyPushMulti(ctx context.Context, deviceTokens []string, title string, message string, data map[string]string) error {
+	pending := deviceTokens
+	delivered := 0
+
+	for attempt := 1; len(pending) > 0; attempt++ {
+		if err := n.breaker.Allow(); err != nil {
+			n.metrics.CircuitRejected(providerFCM)
+			n.metrics.Failed(providerFCM, len(pending))
+			return err
+		}
+
+		err := n.next.NotifyPushMulti(ctx, pending, title, message, data)
+		if err == nil {
+			n.breaker.RecordSuccess()
+			n.metrics.Sent(providerFCM, len(pending))
+			return nil
+		}
+
+		retry, invalid, sent, lastErr := n.partitionFailures(pending, err)
+		delivered += sent
+
+		n.prune(ctx, invalid)
+		n.metrics.Failed(providerFCM, len(invalid))
+
+		if len(retry) == 0 {
+			n.breaker.RecordSuccess()
+			if delivered == 0 {
+				return fmt.Errorf("push delivery failed for all %d tokens: %w", len(deviceTokens), lastErr)
+			}
+			return nil
+		}
+
+		n.breaker.RecordFailure()
+		if attempt >= n.policy.MaxAttempts {
+			n.metrics.Failed(providerFCM, len(retry))
+			return fmt.Errorf("push delivery failed for %d tokens after %d attempts: %w", len(retry), attempt, lastErr)
+		}
+
+		n.metrics.Retried(providerFCM)
+		if sleepErr := sleepContext(ctx, n.policy.delay(attempt)); sleepErr != nil {
+			n.metrics.Failed(providerFCM, len(retry))
+			return lastErr
+		}
+		pending = retry
+	}
+
+	return nil
+}
+
+// partitionFailures splits the failed tokens into those to retry and those
+// to clear, and returns how many got the push along with the last error of a
+// failed token, preferring a transient one.
+//
+//nolint:nonamedreturns // two token lists are clearer named
+func (n *ResilientPushNotifier) partitionFailures(pending []string, err error) (retry, invalid []string, sent int, lastErr error) {
+	failures := make(map[string]error, len(pending))
+	var multicastErr *MulticastError
+	if errors.As(err, &multicastErr) {
+		failures = multicastErr.Failures
+	} else {
+		for _, token := range pending {
+			failures[token] = err
+		}
+	}
+
+	dropped := 0
+	var lastTransient, lastRejection error
+	for _, token := range pending {
+		tokenErr, failed := failures[token]
+		if !failed {
+			sent++
+			continue
+		}
+
+		switch classifyPushError(tokenErr) {
+		case failureInvalidToken:
+			invalid = append(invalid, token)
+			lastRejection = tokenErr
+		case failureTransient:
+			retry = append(retry, token)
+			lastTransient = tokenErr
+		case failurePermanent:
+			dropped++
+			lastRejection = tokenErr
+		}
+	}
+
+	n.metrics.Sent(providerFCM, sent)
+	n.metrics.Failed(providerFCM, dropped)
+
+	if lastTransient != nil {
+		return retry, invalid, sent, lastTransient
+	}
+	return retry, invalid, sent, lastRejection
+}
+
+func (n *ResilientPushNotifier) prune(ctx context.Context, tokens []string) {
+	if len(tokens) == 0 || n.tokens == nil {
+		return
+	}
+
+	cleared, err := n.tokens.ClearFCMTokens(ctx, tokens)
+	if err != nil {
+		slog.Error("failed to clear invalid fcm tokens", "count", len(tokens), "error", err)
+		return
+	}
+
+	n.metrics.Pruned(providerFCM, len(tokens))
+	slog.Info("cleared invalid fcm tokens", "tokens", len(tokens), "rows", cleared)
+}
+
+// classifyPushError sorts an FCM error into how the token should be handled.
+// INVALID_ARGUMENT only prunes the token when FCM names the registration
+// token as the bad field; the same code also covers message-level problems
+// such as an oversized payload, which a batch error assigns to every token.
+func classifyPushError(err error) deliveryFailure {
+	var netErr net.Error
+	switch {
+	case messaging.IsUnregistered(err), messaging.IsSenderIDMismatch(err):
+		return failureInvalidToken
+	case messaging.IsInvalidArgument(err):
+		if strings.Contains(strings.ToLower(err.Error()), "registration token") {
+			return failureInvalidToken
+		}
+		return failurePermanent
+	case messaging.IsUnavailable(err), messaging.IsInternal(err), messaging.IsQuotaExceeded(err):
+		return failureTransient
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr):
+		return failureTransient
+	default:
+		return failurePermanent
+	}
+}
@@ -0,0 +1,219 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	firebase "firebase.google.com/go/v4"
+	"firebase.google.com/go/v4/messaging"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/option"
+)
+
+// fcmError returns the error the FCM client reports for a rejection with
+// the given HTTP status and FCM error code.
+func fcmError(t *testing.T, status int, code string) error {
+	t.Helper()
+	return fcmErrorMessage(t, status, code, "rejected")
+}
+
+// fcmErrorMessage is fcmError with the message FCM sends alongside the code.
+func fcmErrorMessage(t *testing.T, status int, code, message string) error {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		fmt.Fprintf(w, `{"error":{"status":%q,"message":%q,"details":[{"@type":"type.googleapis.com/google.firebase.fcm.v1.FcmError","errorCode":%q}]}}`, code, message, code)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	app, err := firebase.NewApp(ctx, &firebase.Config{ProjectID: "test"}, option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	assert.NoError(t, err)
+	client, err := app.Messaging(ctx)
+	assert.NoError(t, err)
+
+	_, err = client.Send(ctx, &messaging.Message{Token: "token"})
+	assert.Error(t, err)
+	return err
+}
+
+type fakeTokenRepo struct {
+	cleared []string
+	owners  map[string]string
+}
+
+func (r *fakeTokenRepo) ClearFCMTokens(_ context.Context, tokens []string) (int64, error) {
+	r.cleared = append(r.cleared, tokens...)
+	return int64(len(tokens)), nil
+}
+
+func (r *fakeTokenRepo) ListTokenOwners(context.Context, []string) (map[string]string, error) {
+	return r.owners, nil
+}
+
+// scriptedPush fails each token listed in failures with its error, for the
+// first failuresBeforeSuccess tries or for good when that is zero.
+type scriptedPush struct {
+	failures              map[string]error
+	failuresBeforeSuccess int
+	calls                 map[string]int
+}
+
+func (p *scriptedPush) fail(token string) error {
+	if p.calls == nil {
+		p.calls = make(map[string]int)
+	}
+	p.calls[token]++
+	err, ok := p.failures[token]
+	if !ok || (p.failuresBeforeSuccess > 0 && p.calls[token] > p.failuresBeforeSuccess) {
+		return nil
+	}
+	return err
+}
+
+func (p *scriptedPush) NotifyPush(_ context.Context, token string, _ string, _ string, _ map[string]string) error {
+	return p.fail(token)
+}
+
+func (p *scriptedPush) NotifyPushMulti(_ context.Context, tokens []string, _ string, _ string, _ map[string]string) error {
+	failures := make(map[string]error)
+	for _, token := range tokens {
+		if err := p.fail(token); err != nil {
+			failures[token] = err
+		}
+	}
+	if len(failures) > 0 {
+		return &MulticastError{Failures: failures}
+	}
+	return nil
+}
+
+func newTestResilientPush(next *scriptedPush, tokens *fakeTokenRepo) *ResilientPushNotifier {
+	n := NewResilientPushNotifier(next, tokens, NewDeliveryMetrics())
+	n.breaker = NewCircuitBreaker(providerFCM, 1, time.Hour)
+	n.policy = RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+	return n
+}
+
+func TestResilientPush_BreakerOutcomeDuringProbe(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantState string
+		wantPrune bool
+	}{
+		{name: "transient reopens", err: context.DeadlineExceeded, wantState: "open"},
+		{name: "permanent closes", err: fcmError(t, http.StatusUnauthorized, "THIRD_PARTY_AUTH_ERROR"), wantState: "closed"},
+		{name: "unregistered closes and prunes", err: fcmError(t, http.StatusNotFound, "UNREGISTERED"), wantState: "closed", wantPrune: true},
+		{name: "malformed token closes and prunes", err: fcmErrorMessage(t, http.StatusBadRequest, "INVALID_ARGUMENT", "The registration token is not a valid FCM registration token"), wantState: "closed", wantPrune: true},
+		{name: "malformed message closes and keeps the token", err: fcmErrorMessage(t, http.StatusBadRequest, "INVALID_ARGUMENT", "Android message is too big"), wantState: "closed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens := &fakeTokenRepo{}
+			n := newTestResilientPush(&scriptedPush{failures: map[string]error{"a": tt.err}}, tokens)
+
+			// Open the breaker and let its timeout elapse, so the next send
+			// is the half-open probe.
+			n.breaker.RecordFailure()
+			n.breaker.openTimeout = 0
+
+			assert.Error(t, n.NotifyPush(context.Background(), "a", "t", "b", nil))
+			assert.Equal(t, tt.wantState, n.breaker.State())
+			if tt.wantState == "closed" {
+				assert.NoError(t, n.breaker.Allow(), "the probe must not stay in flight")
+			}
+			if tt.wantPrune {
+				assert.Equal(t, []string{"a"}, tokens.cleared)
+			} else {
+				assert.Empty(t, tokens.cleared)
+			}
+		})
+	}
+}
+
+func TestResilientPush_MultiPartialFailure(t *testing.T) {
+	unregistered := fcmError(t, http.StatusNotFound, "UNREGISTERED")
+	permanent := fcmError(t, http.StatusUnauthorized, "THIRD_PARTY_AUTH_ERROR")
+
+	tests := []struct {
+		name        string
+		tokens      []string
+		failures    map[string]error
+		transientOK bool
+		wantErr     bool
+		wantCleared []string
+		// wantFailures is the breaker's count of consecutive failures.
+		wantFailures int
+	}{
+		{
+			name:        "invalid and permanent alongside a delivered token",
+			tokens:      []string{"ok", "gone", "denied"},
+			failures:    map[string]error{"gone": unregistered, "denied": permanent},
+			wantCleared: []string{"gone"},
+		},
+		{
+			name:        "every token invalid or permanent",
+			tokens:      []string{"gone", "denied"},
+			failures:    map[string]error{"gone": unregistered, "denied": permanent},
+			wantErr:     true,
+			wantCleared: []string{"gone"},
+		},
+		{
+			name:        "transient token delivered on retry",
+			tokens:      []string{"ok", "slow"},
+			failures:    map[string]error{"slow": context.DeadlineExceeded},
+			transientOK: true,
+		},
+		{
+			name:         "transient token exhausts retries",
+			tokens:       []string{"ok", "slow"},
+			failures:     map[string]error{"slow": context.DeadlineExceeded},
+			wantErr:      true,
+			wantFailures: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &scriptedPush{failures: tt.failures}
+			if tt.transientOK {
+				next.failuresBeforeSuccess = 1
+			}
+			tokens := &fakeTokenRepo{}
+			n := newTestResilientPush(next, tokens)
+			n.breaker = NewCircuitBreaker(providerFCM, 5, time.Hour)
+
+			err := n.NotifyPushMulti(context.Background(), tt.tokens, "t", "b", nil)
+
+			assert.Equal(t, tt.wantErr, err != nil, "error: %v", err)
+			assert.ElementsMatch(t, tt.wantCleared, tokens.cleared)
+			assert.Equal(t, "closed", n.breaker.State())
+			assert.Equal(t, tt.wantFailures, n.breaker.failures)
+			for _, token := range tt.tokens {
+				if _, failed := tt.failures[token]; !failed {
+					assert.Equal(t, 1, next.calls[token], "delivered tokens are not retried")
+				}
+			}
+		})
+	}
+}
+
+func TestResilientPush_BatchInvalidArgumentKeepsTokens(t *testing.T) {
+	tooBig := fcmErrorMessage(t, http.StatusBadRequest, "INVALID_ARGUMENT", "Android message is too big")
+	tokens := &fakeTokenRepo{}
+	next := &scriptedPush{failures: map[string]error{"a": tooBig, "b": tooBig, "c": tooBig}}
+	n := newTestResilientPush(next, tokens)
+
+	err := n.NotifyPushMulti(context.Background(), []string{"a", "b", "c"}, "t", "b", nil)
+
+	assert.Error(t, err)
+	assert.Empty(t, tokens.cleared, "a message-level rejection must not clear any token")
+	for _, token := range []string{"a", "b", "c"} {
+		assert.Equal(t, 1, next.calls[token], "a permanent rejection is not retried")
+	}
+}
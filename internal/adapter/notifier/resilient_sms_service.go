@@ -0,0 +1,101 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/risk-place-angola/backend-risk-place/internal/application/port"
+	twilioclient "github.com/twilio/twilio-go/client"
+)
+
+const (
+	providerTwilio = "twilio"
+
+	smsMaxAttempts      = 3
+	smsBaseDelay        = time.Second
+	smsMaxDelay         = 8 * time.Second
+	smsBreakerThreshold = 5
+	smsBreakerTimeout   = time.Minute
+)
+
+// ResilientSMSNotifier wraps a NotifierSMSService with retries for transient
+// Twilio errors, a circuit breaker and delivery metrics. Invalid or
+// unsubscribed numbers fail straight away.
+type ResilientSMSNotifier struct {
+	next    port.NotifierSMSService
+	breaker *CircuitBreaker
+	metrics *DeliveryMetrics
+	policy  RetryPolicy
+}
+
+func NewResilientSMSNotifier(next port.NotifierSMSService, metrics *DeliveryMetrics) *ResilientSMSNotifier {
+	breaker := NewCircuitBreaker(providerTwilio, smsBreakerThreshold, smsBreakerTimeout)
+	metrics.trackBreaker(breaker)
+
+	return &ResilientSMSNotifier{
+		next:    next,
+		breaker: breaker,
+		metrics: metrics,
+		policy: RetryPolicy{
+			MaxAttempts: smsMaxAttempts,
+			BaseDelay:   smsBaseDelay,
+			MaxDelay:    smsMaxDelay,
+		},
+	}
+}
+
+func (n *ResilientSMSNotifier) NotifySMS(ctx context.Context, phone string, message string) error {
+	for attempt := 1; ; attempt++ {
+		if err := n.breaker.Allow(); err != nil {
+			n.metrics.CircuitRejected(providerTwilio)
+			n.metrics.Failed(providerTwilio, 1)
+			return err
+		}
+
+		err := n.next.NotifySMS(ctx, phone, message)
+		if err == nil {
+			n.breaker.RecordSuccess()
+			n.metrics.Sent(providerTwilio, 1)
+			return nil
+		}
+
+		if classifySMSError(err) != failureTransient {
+			n.breaker.RecordSuccess()
+			n.metrics.Failed(providerTwilio, 1)
+			return err
+		}
+
+		n.breaker.RecordFailure()
+		if attempt >= n.policy.MaxAttempts {
+			n.metrics.Failed(providerTwilio, 1)
+			return fmt.Errorf("sms delivery failed after %d attempts: %w", attempt, err)
+		}
+
+		n.metrics.Retried(providerTwilio)
+		if sleepErr := sleepContext(ctx, n.policy.delay(attempt)); sleepErr != nil {
+			n.metrics.Failed(providerTwilio, 1)
+			return err
+		}
+	}
+}
+
+func classifySMSError(err error) deliveryFailure {
+	var restErr *twilioclient.TwilioRestError
+	if errors.As(err, &restErr) {
+		if restErr.Status == http.StatusTooManyRequests || restErr.Status >= http.StatusInternalServerError {
+			return failureTransient
+		}
+		return failurePermanent
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) {
+		return failureTransient
+	}
+
+	return failurePermanent
+}
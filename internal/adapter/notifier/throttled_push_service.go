@@ -0,0 +1,344 @@
+package notifier
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"maps"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/risk-place-angola/backend-risk-place/internal/application/port"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
+)
+
+const (
+	pushDedupWindow    = 15 * time.Minute
+	pushUpdateInterval = 2 * time.Minute
+	pushHourlyCap      = 10
+	pushCapWindow      = time.Hour
+
+	pushDedupKeyPrefix = "push:dedup:"
+	pushRateKeyPrefix  = "push:rate:"
+
+	// pushDataCollapsed tells the app the push replaces an earlier one about
+	// the same incident or area.
+	pushDataCollapsed = "collapsed"
+)
+
+type pushDecision int
+
+const (
+	pushDrop pushDecision = iota
+	pushNew
+	pushUpdate
+)
+
+// pushSlot is the throttle's decision for one recipient. A new push holds
+// Entry in the recipient's hourly window, and an update replaced the group's
+// previous send time PrevSent, so a push that fails can give both back.
+type pushSlot struct {
+	Decision pushDecision
+	Entry    string
+	PrevSent int64
+}
+
+// ThrottleStore is the shared state behind ThrottledPushNotifier, so every
+// API instance sees the same windows and counters.
+type ThrottleStore interface {
+	// Reserve decides, for each recipient key and atomically with respect to
+	// concurrent pushes, whether a push about group is new, an update of the
+	// group's notification or dropped, and records it.
+	Reserve(ctx context.Context, recipients []string, group string, now time.Time) ([]pushSlot, error)
+	// Release undoes what Reserve recorded for pushes that were not sent.
+	Release(ctx context.Context, recipients []string, group string, slots []pushSlot) error
+}
+
+// ThrottledPushNotifier sits in front of the push provider so a busy incident
+// does not turn into a push per report. Pushes sharing a
+// port.PushDataGroupKey within the dedup window collapse into one
+// notification that is updated in place at most every pushUpdateInterval, and
+// each recipient gets at most pushHourlyCap new notifications per hour.
+// Critical pushes always go through. State is kept per user, or per device
+// for anonymous sessions, so every device of a user shares one budget.
+type ThrottledPushNotifier struct {
+	next   port.NotifierPushService
+	store  ThrottleStore
+	owners repository.DeviceTokenRepository
+	now    func() time.Time
+}
+
+func NewThrottledPushNotifier(next port.NotifierPushService, store ThrottleStore, owners repository.DeviceTokenRepository) *ThrottledPushNotifier {
+	return &ThrottledPushNotifier{
+		next:   next,
+		store:  store,
+		owners: owners,
+		now:    time.Now,
+	}
+}
+
+func (n *ThrottledPushNotifier) NotifyPush(ctx context.Context, deviceToken string, title string, message string, data map[string]string) error {
+	return n.send(ctx, []string{deviceToken}, data, func(tokens []string, data map[string]string) error {
+		return n.next.NotifyPush(ctx, tokens[0], title, message, data)
+	})
+}
+
+func (n *ThrottledPushNotifier) NotifyPushMulti(ctx context.Context, deviceTokens []string, title string, message string, data map[string]string) error {
+	return n.send(ctx, deviceTokens, data, func(tokens []string, data map[string]string) error {
+		return n.next.NotifyPushMulti(ctx, tokens, title, message, data)
+	})
+}
+
+// send reserves a slot per recipient, pushes the new and the updated
+// notifications, and gives the slots of a push that failed back. The store
+// failing never blocks a push.
+func (n *ThrottledPushNotifier) send(ctx context.Context, deviceTokens []string, data map[string]string, push func([]string, map[string]string) error) error {
+	if len(deviceTokens) == 0 {
+		return nil
+	}
+	if data[port.PushDataSeverity] == port.PushSeverityCritical {
+		return push(deviceTokens, data)
+	}
+
+	group := data[port.PushDataGroupKey]
+	recipients, tokensOf := n.recipients(ctx, deviceTokens)
+
+	slots, err := n.store.Reserve(ctx, recipients, group, n.now())
+	if err != nil {
+		slog.Warn("failed to reserve push throttle slots", "error", err)
+		return push(deviceTokens, data)
+	}
+
+	var fresh, updates batch
+	dropped := 0
+	for i, recipient := range recipients {
+		switch slots[i].Decision {
+		case pushNew:
+			fresh.add(recipient, tokensOf[recipient], slots[i])
+		case pushUpdate:
+			updates.add(recipient, tokensOf[recipient], slots[i])
+		case pushDrop:
+			dropped++
+		}
+	}
+
+	if dropped > 0 {
+		slog.Debug("throttled push notifications",
+			"dropped", dropped,
+			"group", group)
+	}
+
+	var firstErr error
+	for _, b := range []struct {
+		batch
+		data map[string]string
+	}{{fresh, data}, {updates, withCollapsed(data)}} {
+		if len(b.tokens) == 0 {
+			continue
+		}
+		if err := push(b.tokens, b.data); err != nil {
+			if releaseErr := n.store.Release(ctx, b.recipients, group, b.slots); releaseErr != nil {
+				slog.Warn("failed to release push throttle slots", "error", releaseErr)
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+// recipients groups the tokens by owner, so the devices of one user share a
+// slot. Tokens whose owner is unknown count on their own.
+//
+//nolint:nonamedreturns // the recipients and their tokens are returned together
+func (n *ThrottledPushNotifier) recipients(ctx context.Context, deviceTokens []string) (recipients []string, tokensOf map[string][]string) {
+	owners, err := n.owners.ListTokenOwners(ctx, deviceTokens)
+	if err != nil {
+		slog.Warn("failed to look up push token owners", "error", err)
+	}
+
+	tokensOf = make(map[string][]string, len(deviceTokens))
+	for _, token := range deviceTokens {
+		recipient, ok := owners[token]
+		if !ok {
+			recipient = "token:" + tokenKey(token)
+		}
+		if _, seen := tokensOf[recipient]; !seen {
+			recipients = append(recipients, recipient)
+		}
+		tokensOf[recipient] = append(tokensOf[recipient], token)
+	}
+	return recipients, tokensOf
+}
+
+// batch collects the recipients sent the same push, with their slots.
+type batch struct {
+	recipients []string
+	tokens     []string
+	slots      []pushSlot
+}
+
+func (b *batch) add(recipient string, tokens []string, slot pushSlot) {
+	b.recipients = append(b.recipients, recipient)
+	b.tokens = append(b.tokens, tokens...)
+	b.slots = append(b.slots, slot)
+}
+
+// tokenKey keeps FCM tokens, which are long and sensitive, out of Redis keys.
+func tokenKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:12])
+}
+
+func withCollapsed(data map[string]string) map[string]string {
+	out := make(map[string]string, len(data)+1)
+	maps.Copy(out, data)
+	out[pushDataCollapsed] = "true"
+	return out
+}
+
+// ScriptRunner runs Lua scripts atomically on the shared store.
+type ScriptRunner interface {
+	RunScript(ctx context.Context, script string, keys []string, args ...any) (any, error)
+}
+
+// reservePushScript decides every recipient's push in one atomic step, so
+// concurrent sends cannot both take the last slot of the hourly cap. KEYS
+// holds each recipient's rate window and group dedup keys; the dedup key is
+// ignored when the push has no group.
+const reservePushScript = `
+local now_s = tonumber(ARGV[1])
+local now_ms = tonumber(ARGV[2])
+local window_start = tonumber(ARGV[3])
+local cap = tonumber(ARGV[4])
+local update_interval = tonumber(ARGV[5])
+local dedup_ttl = tonumber(ARGV[6])
+local cap_ttl = tonumber(ARGV[7])
+local has_group = ARGV[8] == "1"
+local entry = ARGV[9]
+
+local result = {}
+for i = 1, #KEYS, 2 do
+  local rate_key, dedup_key = KEYS[i], KEYS[i + 1]
+  local decision, prev = 0, 0
+  local last = has_group and redis.call("GET", dedup_key)
+  if last then
+    prev = tonumber(last)
+    if now_s - prev >= update_interval then
+      decision = 2
+      redis.call("SET", dedup_key, now_s, "EX", dedup_ttl)
+    end
+  else
+    redis.call("ZREMRANGEBYSCORE", rate_key, "-inf", window_start)
+    if redis.call("ZCARD", rate_key) < cap then
+      decision = 1
+      redis.call("ZADD", rate_key, now_ms, entry)
+      redis.call("PEXPIRE", rate_key, cap_ttl)
+      if has_group then
+        redis.call("SET", dedup_key, now_s, "EX", dedup_ttl)
+      end
+    end
+  end
+  result[#result + 1] = decision
+  result[#result + 1] = prev
+end
+return result
+`
+
+// releasePushScript undoes reservePushScript for the recipients in KEYS:
+// ARGV holds the dedup TTL and the group flag, then each recipient's
+// decision, rate window entry and previous send time.
+const releasePushScript = `
+local dedup_ttl = tonumber(ARGV[1])
+local has_group = ARGV[2] == "1"
+
+for i = 1, #KEYS, 2 do
+  local rate_key, dedup_key = KEYS[i], KEYS[i + 1]
+  local arg = 3 + (i - 1) / 2 * 3
+  local decision, entry, prev = tonumber(ARGV[arg]), ARGV[arg + 1], tonumber(ARGV[arg + 2])
+  if decision == 1 then
+    redis.call("ZREM", rate_key, entry)
+    if has_group then
+      redis.call("DEL", dedup_key)
+    end
+  elseif decision == 2 then
+    redis.call("SET", dedup_key, prev, "EX", dedup_ttl)
+  end
+end
+return 0
+`
+
+// RedisThrottleStore keeps the throttle state in Redis.
+type RedisThrottleStore struct {
+	scripts ScriptRunner
+}
+
+func NewRedisThrottleStore(scripts ScriptRunner) *RedisThrottleStore {
+	return &RedisThrottleStore{scripts: scripts}
+}
+
+func (s *RedisThrottleStore) Reserve(ctx context.Context, recipients []string, group string, now time.Time) ([]pushSlot, error) {
+	entry := uuid.NewString()
+	res, err := s.scripts.RunScript(ctx, reservePushScript, throttleKeys(recipients, group),
+		now.Unix(),
+		now.UnixMilli(),
+		now.Add(-pushCapWindow).UnixMilli(),
+		pushHourlyCap,
+		int64(pushUpdateInterval/time.Second),
+		int64(pushDedupWindow/time.Second),
+		pushCapWindow.Milliseconds(),
+		groupFlag(group),
+		entry,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run push throttle script: %w", err)
+	}
+
+	values, ok := res.([]any)
+	if !ok || len(values) != 2*len(recipients) {
+		return nil, fmt.Errorf("unexpected push throttle result %v", res)
+	}
+
+	slots := make([]pushSlot, len(recipients))
+	for i := range recipients {
+		decision, decisionOK := values[2*i].(int64)
+		prev, prevOK := values[2*i+1].(int64)
+		if !decisionOK || !prevOK {
+			return nil, fmt.Errorf("unexpected push throttle result %v", res)
+		}
+		slots[i] = pushSlot{Decision: pushDecision(decision), Entry: entry, PrevSent: prev}
+	}
+	return slots, nil
+}
+
+func (s *RedisThrottleStore) Release(ctx context.Context, recipients []string, group string, slots []pushSlot) error {
+	args := make([]any, 0, 2+3*len(slots))
+	args = append(args, int64(pushDedupWindow/time.Second), groupFlag(group))
+	for _, slot := range slots {
+		args = append(args, int(slot.Decision), slot.Entry, slot.PrevSent)
+	}
+
+	if _, err := s.scripts.RunScript(ctx, releasePushScript, throttleKeys(recipients, group), args...); err != nil {
+		return fmt.Errorf("failed to run push release script: %w", err)
+	}
+	return nil
+}
+
+// throttleKeys lists each recipient's rate window and dedup keys in pairs.
+func throttleKeys(recipients []string, group string) []string {
+	keys := make([]string, 0, 2*len(recipients))
+	for _, recipient := range recipients {
+		keys = append(keys, pushRateKeyPrefix+recipient, pushDedupKeyPrefix+recipient+":"+group)
+	}
+	return keys
+}
+
+func groupFlag(group string) string {
+	if group == "" {
+		return "0"
+	}
+	return "1"
+}
@@ -0,0 +1,200 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/risk-place-angola/backend-risk-place/internal/application/port"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryThrottleStore applies the same rules as the Redis scripts.
+type memoryThrottleStore struct {
+	sent    map[string]int64
+	windows map[string]map[string]time.Time
+	entries int
+}
+
+func newMemoryThrottleStore() *memoryThrottleStore {
+	return &memoryThrottleStore{
+		sent:    make(map[string]int64),
+		windows: make(map[string]map[string]time.Time),
+	}
+}
+
+func (s *memoryThrottleStore) Reserve(_ context.Context, recipients []string, group string, now time.Time) ([]pushSlot, error) {
+	slots := make([]pushSlot, len(recipients))
+	for i, recipient := range recipients {
+		dedupKey := recipient + ":" + group
+		if prev, ok := s.sent[dedupKey]; ok && group != "" {
+			slots[i].PrevSent = prev
+			if now.Unix()-prev >= int64(pushUpdateInterval/time.Second) {
+				slots[i].Decision = pushUpdate
+				s.sent[dedupKey] = now.Unix()
+			}
+			continue
+		}
+
+		window := s.windows[recipient]
+		if window == nil {
+			window = make(map[string]time.Time)
+			s.windows[recipient] = window
+		}
+		for entry, at := range window {
+			if !at.After(now.Add(-pushCapWindow)) {
+				delete(window, entry)
+			}
+		}
+		if len(window) >= pushHourlyCap {
+			continue
+		}
+
+		s.entries++
+		slots[i] = pushSlot{Decision: pushNew, Entry: strconv.Itoa(s.entries)}
+		window[slots[i].Entry] = now
+		if group != "" {
+			s.sent[dedupKey] = now.Unix()
+		}
+	}
+	return slots, nil
+}
+
+func (s *memoryThrottleStore) Release(_ context.Context, recipients []string, group string, slots []pushSlot) error {
+	for i, recipient := range recipients {
+		dedupKey := recipient + ":" + group
+		switch slots[i].Decision {
+		case pushNew:
+			delete(s.windows[recipient], slots[i].Entry)
+			delete(s.sent, dedupKey)
+		case pushUpdate:
+			s.sent[dedupKey] = slots[i].PrevSent
+		case pushDrop:
+		}
+	}
+	return nil
+}
+
+type recordingPush struct {
+	sent   []map[string]string
+	tokens []string
+	err    error
+}
+
+func (r *recordingPush) NotifyPush(_ context.Context, token string, _ string, _ string, data map[string]string) error {
+	return r.NotifyPushMulti(context.Background(), []string{token}, "", "", data)
+}
+
+func (r *recordingPush) NotifyPushMulti(_ context.Context, tokens []string, _ string, _ string, data map[string]string) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.sent = append(r.sent, data)
+	r.tokens = append(r.tokens, tokens...)
+	return nil
+}
+
+func newTestThrottle(now *time.Time, owners map[string]string) (*ThrottledPushNotifier, *recordingPush) {
+	next := &recordingPush{}
+	n := NewThrottledPushNotifier(next, newMemoryThrottleStore(), &fakeTokenRepo{owners: owners})
+	n.now = func() time.Time { return *now }
+	return n, next
+}
+
+func TestThrottledPush_CollapsesSameGroup(t *testing.T) {
+	now := time.Date(2025, 3, 1, 18, 0, 0, 0, time.UTC)
+	n, next := newTestThrottle(&now, nil)
+	ctx := context.Background()
+	data := map[string]string{port.PushDataGroupKey: "report:robbery:-8.84:13.23"}
+
+	assert.NoError(t, n.NotifyPush(ctx, "token", "t", "m", data))
+
+	now = now.Add(30 * time.Second)
+	assert.NoError(t, n.NotifyPush(ctx, "token", "t", "m", data))
+	assert.Len(t, next.sent, 1, "a second report right away is dropped")
+
+	now = now.Add(pushUpdateInterval)
+	assert.NoError(t, n.NotifyPush(ctx, "token", "t", "m", data))
+	assert.Len(t, next.sent, 2)
+	assert.Equal(t, "true", next.sent[1][pushDataCollapsed], "later reports update the same notification")
+}
+
+func TestThrottledPush_HourlyCapSparesCritical(t *testing.T) {
+	now := time.Date(2025, 3, 1, 18, 0, 0, 0, time.UTC)
+	n, next := newTestThrottle(&now, nil)
+	ctx := context.Background()
+
+	for i := range pushHourlyCap + 2 {
+		now = now.Add(time.Minute)
+		data := map[string]string{port.PushDataGroupKey: "alert:" + strconv.Itoa(i)}
+		assert.NoError(t, n.NotifyPush(ctx, "token", "t", "m", data))
+	}
+	assert.Len(t, next.sent, pushHourlyCap)
+
+	critical := map[string]string{port.PushDataSeverity: port.PushSeverityCritical}
+	assert.NoError(t, n.NotifyPush(ctx, "token", "t", "m", critical))
+	assert.Len(t, next.sent, pushHourlyCap+1)
+
+	now = now.Add(pushCapWindow)
+	assert.NoError(t, n.NotifyPush(ctx, "token", "t", "m", map[string]string{}))
+	assert.Len(t, next.sent, pushHourlyCap+2, "the cap slides with the hour")
+}
+
+func TestThrottledPushMulti_CapIsPerUser(t *testing.T) {
+	now := time.Date(2025, 3, 1, 18, 0, 0, 0, time.UTC)
+	n, next := newTestThrottle(&now, map[string]string{
+		"phone":  "user:1",
+		"tablet": "user:1",
+		"quiet":  "device:2",
+	})
+	ctx := context.Background()
+
+	for i := range pushHourlyCap {
+		now = now.Add(time.Minute)
+		device := "phone"
+		if i%2 == 1 {
+			device = "tablet"
+		}
+		assert.NoError(t, n.NotifyPush(ctx, device, "t", "m", map[string]string{"n": strconv.Itoa(i)}))
+	}
+	assert.Len(t, next.sent, pushHourlyCap, "both devices draw on one budget")
+
+	next.tokens = nil
+	assert.NoError(t, n.NotifyPushMulti(ctx, []string{"phone", "tablet", "quiet"}, "t", "m", map[string]string{}))
+	assert.Equal(t, []string{"quiet"}, next.tokens)
+}
+
+func TestThrottledPushMulti_DevicesOfOneUserShareASlot(t *testing.T) {
+	now := time.Date(2025, 3, 1, 18, 0, 0, 0, time.UTC)
+	n, next := newTestThrottle(&now, map[string]string{"phone": "user:1", "tablet": "user:1"})
+	ctx := context.Background()
+
+	assert.NoError(t, n.NotifyPushMulti(ctx, []string{"phone", "tablet"}, "t", "m", map[string]string{}))
+	assert.ElementsMatch(t, []string{"phone", "tablet"}, next.tokens, "every device of the user gets the push")
+
+	store, ok := n.store.(*memoryThrottleStore)
+	assert.True(t, ok)
+	assert.Len(t, store.windows["user:1"], 1, "and it counts once against the cap")
+}
+
+func TestThrottledPush_FailedSendGivesTheSlotBack(t *testing.T) {
+	now := time.Date(2025, 3, 1, 18, 0, 0, 0, time.UTC)
+	n, next := newTestThrottle(&now, nil)
+	ctx := context.Background()
+	data := map[string]string{port.PushDataGroupKey: "alert:1"}
+
+	next.err = errors.New("fcm unavailable")
+	assert.Error(t, n.NotifyPush(ctx, "token", "t", "m", data))
+
+	next.err = nil
+	now = now.Add(time.Second)
+	assert.NoError(t, n.NotifyPush(ctx, "token", "t", "m", data))
+	assert.Len(t, next.sent, 1, "the retry is not mistaken for a duplicate")
+	assert.Empty(t, next.sent[0][pushDataCollapsed])
+
+	store, ok := n.store.(*memoryThrottleStore)
+	assert.True(t, ok)
+	assert.Len(t, store.windows["token:"+tokenKey("token")], 1, "the failed send used none of the budget")
+}
@@ -26,7 +26,7 @@ func (a alertRepoPG) Create(ctx context.Context, alert *model.Alert) error {
 
 	radiusMeters := int32(alert.RadiusMeters) // #nosec G115
 
-	return a.q.CreateAlert(ctx,
+	return queriesFor(ctx, a.q).CreateAlert(ctx,
 		sqlc.CreateAlertParams{
 			ID:           alert.ID,
 			CreatedBy:    uuidPtrToNullUUID(alert.CreatedBy),
@@ -46,7 +46,7 @@ func (a alertRepoPG) Create(ctx context.Context, alert *model.Alert) error {
 }
 
 func (a alertRepoPG) CreateAlertNotification(ctx context.Context, alertID uuid.UUID, userID string) error {
-	return a.q.CreateAlertNotification(ctx,
+	return queriesFor(ctx, a.q).CreateAlertNotification(ctx,
 		sqlc.CreateAlertNotificationParams{
 			ReferenceID: alertID,
 			UserID:      uuid.MustParse(userID),
@@ -94,6 +94,29 @@ func (a alertRepoPG) GetSubscribedAlerts(ctx context.Context, userID uuid.UUID)
 	return alerts, nil
 }
 
+func (a alertRepoPG) ListActiveNear(ctx context.Context, lat, lon, radiusMeters float64, limit int) ([]*model.Alert, error) {
+	if limit > math.MaxInt32 || limit < 0 {
+		return nil, fmt.Errorf("limit out of range: must be between 0 and %d", math.MaxInt32)
+	}
+
+	rows, err := a.q.ListActiveAlertsNear(ctx, sqlc.ListActiveAlertsNearParams{
+		Latitude:     lat,
+		Longitude:    lon,
+		RadiusMeters: radiusMeters,
+		MaxResults:   int32(limit), // #nosec G115
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	alerts := make([]*model.Alert, 0, len(rows))
+	for _, row := range rows {
+		alerts = append(alerts, a.listActiveAlertsNearRowToModel(row))
+	}
+
+	return alerts, nil
+}
+
 func (a alertRepoPG) Update(ctx context.Context, alert *model.Alert) error {
 	if alert.RadiusMeters > math.MaxInt32 || alert.RadiusMeters < math.MinInt32 {
 		return fmt.Errorf("radius meters out of range: must be between %d and %d", math.MinInt32, math.MaxInt32)
@@ -344,6 +367,34 @@ func (a alertRepoPG) getSubscribedAlertsRowToModel(row sqlc.GetSubscribedAlertsR
 	)
 }
 
+func (a alertRepoPG) listActiveAlertsNearRowToModel(row sqlc.ListActiveAlertsNearRow) *model.Alert {
+	return a.convertToAlert(
+		row.ID,
+		row.CreatedBy,
+		row.AnonymousSessionID,
+		row.DeviceID,
+		row.RiskTypeID,
+		row.RiskTopicID,
+		row.Message,
+		row.Latitude,
+		row.Longitude,
+		row.Province,
+		row.Municipality,
+		row.Neighborhood,
+		row.Address,
+		row.RadiusMeters,
+		row.Severity,
+		row.Status,
+		row.CreatedAt,
+		row.ExpiresAt,
+		row.ResolvedAt,
+		row.RiskTypeName,
+		row.RiskTypeIconPath,
+		row.RiskTopicName,
+		row.RiskTopicIconPath,
+	)
+}
+
 func NewAlertRepoPG(db *sql.DB) repository.AlertRepository {
 	return &alertRepoPG{
 		q: sqlc.New(db),
@@ -6,7 +6,10 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/google/uuid"
+
 	"github.com/risk-place-angola/backend-risk-place/internal/adapter/repository/postgres/sqlc"
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
 	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
 )
 
@@ -83,7 +86,7 @@ func (r *anonymousSessionRepoPG) FindByDeviceID(ctx context.Context, deviceID st
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("anonymous session not found")
+			return nil, domainErrors.ErrSessionNotFound
 		}
 		return nil, fmt.Errorf("failed to find anonymous session: %w", err)
 	}
@@ -135,7 +138,7 @@ func (r *anonymousSessionRepoPG) Update(ctx context.Context, session *model.Anon
 	}
 
 	if rows == 0 {
-		return fmt.Errorf("anonymous session not found")
+		return domainErrors.ErrSessionNotFound
 	}
 
 	return nil
@@ -162,7 +165,7 @@ func (r *anonymousSessionRepoPG) UpdateLocation(ctx context.Context, deviceID st
 	}
 
 	if rows == 0 {
-		return fmt.Errorf("anonymous session not found")
+		return domainErrors.ErrSessionNotFound
 	}
 
 	return nil
@@ -188,7 +191,7 @@ func (r *anonymousSessionRepoPG) UpdateFCMToken(ctx context.Context, deviceID st
 	}
 
 	if rows == 0 {
-		return fmt.Errorf("anonymous session not found")
+		return domainErrors.ErrSessionNotFound
 	}
 
 	return nil
@@ -257,7 +260,7 @@ func (r *anonymousSessionRepoPG) Delete(ctx context.Context, deviceID string) er
 	}
 
 	if rows == 0 {
-		return fmt.Errorf("anonymous session not found")
+		return domainErrors.ErrSessionNotFound
 	}
 
 	return nil
@@ -293,7 +296,7 @@ func (r *anonymousSessionRepoPG) TouchLastSeen(ctx context.Context, deviceID str
 	}
 
 	if rows == 0 {
-		return fmt.Errorf("anonymous session not found")
+		return domainErrors.ErrSessionNotFound
 	}
 
 	return nil
@@ -326,7 +329,7 @@ func (r *anonymousSessionRepoPG) UpdateNotificationPreferences(ctx context.Conte
 	}
 
 	if rows == 0 {
-		return fmt.Errorf("anonymous session not found")
+		return domainErrors.ErrSessionNotFound
 	}
 
 	return nil
@@ -342,19 +345,21 @@ func (r *anonymousSessionRepoPG) GetNotificationPreferences(ctx context.Context,
 	err = r.db.QueryRowContext(ctx, query, deviceID).Scan(&pushEnabled, &smsEnabled)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return false, false, fmt.Errorf("anonymous session not found")
+			return false, false, domainErrors.ErrSessionNotFound
 		}
 		return false, false, fmt.Errorf("failed to get notification preferences: %w", err)
 	}
 	return
 }
 
-func (r *anonymousSessionRepoPG) GetFCMTokensForAlertNotification(ctx context.Context, lat, lon, radiusMeters float64, severityLevel string) ([]model.DeviceToken, error) {
+func (r *anonymousSessionRepoPG) GetFCMTokensForAlertNotification(ctx context.Context, lat, lon, radiusMeters float64, severityLevel string, riskTypeID, riskTopicID uuid.UUID) ([]model.DeviceToken, error) {
 	rows, err := r.q.ListAnonymousTokensForAlertNotification(ctx, sqlc.ListAnonymousTokensForAlertNotificationParams{
 		Latitude:      lat,
 		Longitude:     lon,
 		RadiusMeters:  radiusMeters,
 		SeverityLevel: severityLevel,
+		RiskTypeID:    riskTypeID,
+		RiskTopicID:   riskTopicID,
 	})
 	if err != nil {
 		return nil, err
@@ -373,12 +378,14 @@ func (r *anonymousSessionRepoPG) GetFCMTokensForAlertNotification(ctx context.Co
 	return tokens, nil
 }
 
-func (r *anonymousSessionRepoPG) GetFCMTokensForReportNotification(ctx context.Context, lat, lon, radiusMeters float64, isVerified bool) ([]model.DeviceToken, error) {
+func (r *anonymousSessionRepoPG) GetFCMTokensForReportNotification(ctx context.Context, lat, lon, radiusMeters float64, isVerified bool, riskTypeID, riskTopicID uuid.UUID) ([]model.DeviceToken, error) {
 	rows, err := r.q.ListAnonymousTokensForReportNotification(ctx, sqlc.ListAnonymousTokensForReportNotificationParams{
 		Latitude:     lat,
 		Longitude:    lon,
 		RadiusMeters: radiusMeters,
 		IsVerified:   isVerified,
+		RiskTypeID:   riskTypeID,
+		RiskTopicID:  riskTopicID,
 	})
 	if err != nil {
 		return nil, err
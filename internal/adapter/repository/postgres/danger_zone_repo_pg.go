@@ -3,20 +3,18 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"math"
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
 	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
 	"github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
 )
 
-const (
-	earthRadiusKmDangerZone = 6371.0
-	gridSizeKmDefault       = 0.5
-	minIncidentsDefault     = 5
-	daysBackDefault         = 30
-)
-
 type DangerZoneRepoPG struct {
 	db *sql.DB
 }
@@ -25,46 +23,24 @@ func NewDangerZoneRepoPG(db *sql.DB) repository.DangerZoneRepository {
 	return &DangerZoneRepoPG{db: db}
 }
 
-func (r *DangerZoneRepoPG) CalculateDangerZones(ctx context.Context, gridSizeKm float64, minIncidents int, daysBack int) ([]repository.DangerZoneCalculationResult, error) {
+func (r *DangerZoneRepoPG) ListIncidents(ctx context.Context, since time.Time, bounds *model.BoundingBox) (_ []model.DangerZoneIncident, err error) {
 	query := `
-		WITH incident_grid AS (
-			SELECT 
-				FLOOR(latitude / $1) * $1 AS grid_lat,
-				FLOOR(longitude / $1) * $1 AS grid_lon,
-				COUNT(*) AS incident_count,
-				SUM(
-					CASE 
-						WHEN created_at > NOW() - INTERVAL '7 days' THEN 3.0
-						WHEN created_at > NOW() - INTERVAL '14 days' THEN 2.0
-						WHEN created_at > NOW() - INTERVAL '30 days' THEN 1.5
-						ELSE 1.0
-					END *
-					CASE 
-						WHEN status = 'verified' THEN 2.0
-						ELSE 1.0
-					END
-				) AS risk_score
-			FROM reports
-			WHERE 
-				created_at > NOW() - INTERVAL '1 day' * $3
-				AND status IN ('verified', 'pending')
-			GROUP BY grid_lat, grid_lon
-			HAVING COUNT(*) >= $2
-		)
-		SELECT 
-			CONCAT(grid_lat::text, ',', grid_lon::text) AS grid_cell_id,
-			grid_lat + ($1 / 2) AS cell_center_lat,
-			grid_lon + ($1 / 2) AS cell_center_lon,
-			incident_count,
-			LEAST(risk_score, 10.0) AS risk_score
-		FROM incident_grid
-		ORDER BY risk_score DESC
-		LIMIT 1000
+		SELECT r.id, rt.name, COALESCE(tp.name, ''), r.latitude, r.longitude, r.status = 'verified', r.is_private, r.created_at
+		FROM reports r
+		JOIN risk_types rt ON r.risk_type_id = rt.id
+		LEFT JOIN risk_topics tp ON r.risk_topic_id = tp.id
+		WHERE r.created_at > $1
+			AND r.status IN ('verified', 'pending')
 	`
+	args := []any{since}
+	if bounds != nil {
+		query += ` AND r.latitude BETWEEN $2 AND $3 AND r.longitude BETWEEN $4 AND $5`
+		args = append(args, bounds.MinLat, bounds.MaxLat, bounds.MinLon, bounds.MaxLon)
+	}
 
-	rows, err := r.db.QueryContext(ctx, query, gridSizeKm, minIncidents, daysBack)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to calculate danger zones: %w", err)
+		return nil, fmt.Errorf("failed to list danger zone incidents: %w", err)
 	}
 	defer func() {
 		if closeErr := rows.Close(); closeErr != nil {
@@ -72,75 +48,281 @@ func (r *DangerZoneRepoPG) CalculateDangerZones(ctx context.Context, gridSizeKm
 		}
 	}()
 
-	var results []repository.DangerZoneCalculationResult
+	var incidents []model.DangerZoneIncident
 	for rows.Next() {
-		var result repository.DangerZoneCalculationResult
-		err := rows.Scan(
-			&result.GridCellID,
-			&result.CellLat,
-			&result.CellLon,
-			&result.IncidentCount,
-			&result.RiskScore,
+		var incident model.DangerZoneIncident
+		if err := rows.Scan(
+			&incident.ReportID,
+			&incident.RiskType,
+			&incident.RiskTopic,
+			&incident.Latitude,
+			&incident.Longitude,
+			&incident.Verified,
+			&incident.IsPrivate,
+			&incident.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan danger zone incident: %w", err)
+		}
+		incidents = append(incidents, incident)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating danger zone incidents: %w", err)
+	}
+
+	return incidents, nil
+}
+
+func (r *DangerZoneRepoPG) ListRegions(ctx context.Context) (_ []*model.DangerZoneRegion, err error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, name, min_lat, min_lon, max_lat, max_lon, priority, days_back, levels
+		FROM danger_zone_regions
+		ORDER BY priority DESC, name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list danger zone regions: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			err = closeErr
+		}
+	}()
+
+	var regions []*model.DangerZoneRegion
+	for rows.Next() {
+		var (
+			region                         model.DangerZoneRegion
+			minLat, minLon, maxLat, maxLon sql.NullFloat64
+			levels                         []byte
 		)
-		if err != nil {
-			continue
+		if err := rows.Scan(
+			&region.ID,
+			&region.Name,
+			&minLat, &minLon, &maxLat, &maxLon,
+			&region.Priority,
+			&region.DaysBack,
+			&levels,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan danger zone region: %w", err)
 		}
-		results = append(results, result)
+
+		if err := json.Unmarshal(levels, &region.Levels); err != nil {
+			return nil, fmt.Errorf("invalid levels for danger zone region %s: %w", region.Name, err)
+		}
+		if minLat.Valid {
+			region.Bounds = &model.BoundingBox{
+				MinLat: minLat.Float64,
+				MinLon: minLon.Float64,
+				MaxLat: maxLat.Float64,
+				MaxLon: maxLon.Float64,
+			}
+		}
+		region.SortLevels()
+
+		regions = append(regions, &region)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating danger zone rows: %w", err)
+		return nil, fmt.Errorf("error iterating danger zone regions: %w", err)
+	}
+
+	return regions, nil
+}
+
+func (r *DangerZoneRepoPG) SaveSnapshots(ctx context.Context, snapshots []model.DangerZoneSnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	var (
+		runIDs, cells, levels []string
+		precisions, counts    []int64
+		lats, lons, scores    []float64
+		calculatedAt          []time.Time
+	)
+	for _, s := range snapshots {
+		runIDs = append(runIDs, s.RunID.String())
+		cells = append(cells, s.GridCellID)
+		precisions = append(precisions, int64(s.Precision))
+		lats = append(lats, s.CellLat)
+		lons = append(lons, s.CellLon)
+		counts = append(counts, int64(s.IncidentCount))
+		scores = append(scores, s.RiskScore)
+		levels = append(levels, s.RiskLevel)
+		calculatedAt = append(calculatedAt, s.CalculatedAt)
+	}
+
+	// One statement per run: a run stores up to a few thousand zones.
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO danger_zone_snapshots (
+			run_id, grid_cell_id, precision, cell_lat, cell_lon,
+			incident_count, risk_score, risk_level, calculated_at
+		)
+		SELECT * FROM unnest(
+			$1::uuid[], $2::text[], $3::smallint[], $4::float8[], $5::float8[],
+			$6::int[], $7::float8[], $8::text[], $9::timestamptz[]
+		)
+	`,
+		pq.Array(runIDs),
+		pq.Array(cells),
+		pq.Array(precisions),
+		pq.Array(lats),
+		pq.Array(lons),
+		pq.Array(counts),
+		pq.Array(scores),
+		pq.Array(levels),
+		pq.Array(formatTimes(calculatedAt)),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save danger zone snapshots: %w", err)
+	}
+	return nil
+}
+
+func (r *DangerZoneRepoPG) DeleteSnapshotsBefore(ctx context.Context, before time.Time) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM danger_zone_snapshots WHERE calculated_at < $1`, before); err != nil {
+		return fmt.Errorf("failed to delete old danger zone snapshots: %w", err)
+	}
+	return nil
+}
+
+func (r *DangerZoneRepoPG) SaveExplanations(
+	ctx context.Context,
+	runID uuid.UUID,
+	calculatedAt time.Time,
+	explanations []*model.DangerZoneExplanation,
+) error {
+	if len(explanations) == 0 {
+		return nil
+	}
+
+	var (
+		cells, data []string
+		precisions  []int64
+	)
+	for _, explanation := range explanations {
+		encoded, err := json.Marshal(explanation)
+		if err != nil {
+			return fmt.Errorf("failed to marshal danger zone explanation %s: %w", explanation.GridCellID, err)
+		}
+		cells = append(cells, explanation.GridCellID)
+		precisions = append(precisions, int64(explanation.Precision))
+		data = append(data, string(encoded))
 	}
 
-	return results, nil
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO danger_zone_explanations (run_id, grid_cell_id, precision, explanation, calculated_at)
+		SELECT $1, cell, precision, explanation, $5
+		FROM unnest($2::text[], $3::smallint[], $4::jsonb[]) AS e(cell, precision, explanation)
+	`,
+		runID,
+		pq.Array(cells),
+		pq.Array(precisions),
+		pq.Array(data),
+		calculatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save danger zone explanations: %w", err)
+	}
+	return nil
 }
 
-func (r *DangerZoneRepoPG) GetNearbyDangerZones(ctx context.Context, lat, lon, radiusKm float64) ([]*model.DangerZone, error) {
-	gridSize := gridSizeKmDefault
-	latMin := lat - (radiusKm / kmPerDegreeLat)
-	latMax := lat + (radiusKm / kmPerDegreeLat)
-	lonMin := lon - (radiusKm / (kmPerDegreeLat * math.Cos(lat*math.Pi/degreesInCircle)))
-	lonMax := lon + (radiusKm / (kmPerDegreeLat * math.Cos(lat*math.Pi/degreesInCircle)))
+func (r *DangerZoneRepoPG) GetExplanation(ctx context.Context, gridCellID string, at time.Time) (*model.DangerZoneExplanation, error) {
+	var data []byte
+	err := r.db.QueryRowContext(ctx, `
+		SELECT explanation
+		FROM danger_zone_explanations
+		WHERE grid_cell_id = $1
+			AND run_id = (
+				SELECT run_id FROM danger_zone_explanations
+				WHERE calculated_at <= $2
+				ORDER BY calculated_at DESC
+				LIMIT 1
+			)
+	`, gridCellID, at).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domainErrors.ErrDangerZoneNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get danger zone explanation: %w", err)
+	}
 
+	var explanation model.DangerZoneExplanation
+	if err := json.Unmarshal(data, &explanation); err != nil {
+		return nil, fmt.Errorf("invalid danger zone explanation %s: %w", gridCellID, err)
+	}
+	return &explanation, nil
+}
+
+func (r *DangerZoneRepoPG) DeleteExplanationsBefore(ctx context.Context, before time.Time) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM danger_zone_explanations WHERE calculated_at < $1`, before); err != nil {
+		return fmt.Errorf("failed to delete old danger zone explanations: %w", err)
+	}
+	return nil
+}
+
+func (r *DangerZoneRepoPG) ListDailyScores(
+	ctx context.Context,
+	filter repository.DangerZoneSnapshotFilter,
+) (_ []model.DangerZoneDailyScore, err error) {
+	var limit any // NULL keeps every cell
+	if filter.Limit > 0 {
+		limit = filter.Limit
+	}
+
+	where := `calculated_at >= $1`
+	args := []any{filter.Since, limit}
+	if filter.GridCellID != "" {
+		where += ` AND grid_cell_id = $3`
+		args = append(args, filter.GridCellID)
+	} else if filter.Bounds != nil {
+		where += ` AND precision = $3 AND cell_lat BETWEEN $4 AND $5 AND cell_lon BETWEEN $6 AND $7`
+		args = append(args, filter.Precision, filter.Bounds.MinLat, filter.Bounds.MaxLat, filter.Bounds.MinLon, filter.Bounds.MaxLon)
+	}
+
+	// A day's average divides by every run of that day, not just the runs
+	// that made the cell a zone. The cells kept are the ones whose average
+	// rose most over the last week, as BuildDangerZoneTrends ranks them.
 	query := `
-		WITH incident_grid AS (
-			SELECT 
-				FLOOR(latitude / $1) * $1 AS grid_lat,
-				FLOOR(longitude / $1) * $1 AS grid_lon,
-				COUNT(*) AS incident_count,
-				SUM(
-					CASE 
-						WHEN created_at > NOW() - INTERVAL '7 days' THEN 3.0
-						WHEN created_at > NOW() - INTERVAL '14 days' THEN 2.0
-						WHEN created_at > NOW() - INTERVAL '30 days' THEN 1.5
-						ELSE 1.0
-					END *
-					CASE 
-						WHEN status = 'verified' THEN 2.0
-						ELSE 1.0
-					END
-				) AS risk_score
-			FROM reports
-			WHERE 
-				created_at > NOW() - INTERVAL '30 days'
-				AND status IN ('verified', 'pending')
-				AND latitude BETWEEN $2 AND $3
-				AND longitude BETWEEN $4 AND $5
-			GROUP BY grid_lat, grid_lon
-			HAVING COUNT(*) >= $6
+		WITH runs AS (
+			SELECT (calculated_at AT TIME ZONE 'Africa/Luanda')::date AS day, COUNT(DISTINCT run_id) AS run_count
+			FROM danger_zone_snapshots
+			WHERE calculated_at >= $1
+			GROUP BY day
+		),
+		daily AS (
+			SELECT grid_cell_id, precision, cell_lat, cell_lon,
+				(calculated_at AT TIME ZONE 'Africa/Luanda')::date AS day,
+				SUM(risk_score) AS total_score, MAX(risk_score) AS max_score, MAX(incident_count) AS max_incidents
+			FROM danger_zone_snapshots
+			WHERE ` + where + `
+			GROUP BY grid_cell_id, precision, cell_lat, cell_lon, day
+		),
+		scored AS (
+			SELECT d.*, d.total_score / r.run_count AS average_score
+			FROM daily d
+			JOIN runs r ON r.day = d.day
+		),
+		cells AS (
+			SELECT grid_cell_id
+			FROM scored
+			GROUP BY grid_cell_id
+			ORDER BY SUM(CASE
+				WHEN day > (now() AT TIME ZONE 'Africa/Luanda')::date - 7 THEN average_score
+				WHEN day > (now() AT TIME ZONE 'Africa/Luanda')::date - 14 THEN -average_score
+				ELSE 0
+			END) DESC, grid_cell_id
+			LIMIT $2
 		)
-		SELECT 
-			CONCAT(grid_lat::text, ',', grid_lon::text) AS grid_cell_id,
-			grid_lat + ($1 / 2) AS cell_center_lat,
-			grid_lon + ($1 / 2) AS cell_center_lon,
-			incident_count,
-			LEAST(risk_score, 10.0) AS risk_score
-		FROM incident_grid
+		SELECT grid_cell_id, precision, cell_lat, cell_lon, day, average_score, max_score, max_incidents
+		FROM scored
+		WHERE grid_cell_id IN (SELECT grid_cell_id FROM cells)
+		ORDER BY grid_cell_id, day
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, gridSize, latMin, latMax, lonMin, lonMax, minIncidentsDefault)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get nearby danger zones: %w", err)
+		return nil, fmt.Errorf("failed to list danger zone daily scores: %w", err)
 	}
 	defer func() {
 		if closeErr := rows.Close(); closeErr != nil {
@@ -148,29 +330,39 @@ func (r *DangerZoneRepoPG) GetNearbyDangerZones(ctx context.Context, lat, lon, r
 		}
 	}()
 
-	var zones []*model.DangerZone
+	var scores []model.DangerZoneDailyScore
 	for rows.Next() {
-		var gridCellID string
-		var cellLat, cellLon float64
-		var incidentCount int
-		var riskScore float64
-
-		err := rows.Scan(&gridCellID, &cellLat, &cellLon, &incidentCount, &riskScore)
-		if err != nil {
-			continue
+		var (
+			score model.DangerZoneDailyScore
+			day   time.Time
+		)
+		if err := rows.Scan(
+			&score.GridCellID,
+			&score.Precision,
+			&score.CellLat,
+			&score.CellLon,
+			&day,
+			&score.AverageRiskScore,
+			&score.MaxRiskScore,
+			&score.MaxIncidentCount,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan danger zone daily score: %w", err)
 		}
-
-		zone := model.NewDangerZone(cellLat, cellLon, gridCellID)
-		zone.IncidentCount = incidentCount
-		zone.RiskScore = riskScore
-		zone.CalculateRiskLevel()
-
-		zones = append(zones, zone)
+		score.Day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, model.LocalTimezone())
+		scores = append(scores, score)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating nearby danger zones: %w", err)
+		return nil, fmt.Errorf("error iterating danger zone daily scores: %w", err)
 	}
 
-	return zones, nil
+	return scores, nil
+}
+
+func formatTimes(times []time.Time) []string {
+	formatted := make([]string, len(times))
+	for i, t := range times {
+		formatted[i] = t.Format(time.RFC3339Nano)
+	}
+	return formatted
 }
@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
+)
+
+type deviceTokenRepoPG struct {
+	db *sql.DB
+}
+
+func NewDeviceTokenRepository(db *sql.DB) repository.DeviceTokenRepository {
+	return &deviceTokenRepoPG{db: db}
+}
+
+func (r *deviceTokenRepoPG) ClearFCMTokens(ctx context.Context, tokens []string) (int64, error) {
+	if len(tokens) == 0 {
+		return 0, nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var cleared int64
+	for _, query := range []string{
+		`UPDATE users SET device_fcm_token = NULL WHERE device_fcm_token = ANY($1::text[])`,
+		`UPDATE anonymous_sessions SET device_fcm_token = NULL WHERE device_fcm_token = ANY($1::text[])`,
+	} {
+		res, err := tx.ExecContext(ctx, query, pq.Array(tokens))
+		if err != nil {
+			return 0, fmt.Errorf("failed to clear fcm tokens: %w", err)
+		}
+		if n, err := res.RowsAffected(); err == nil {
+			cleared += n
+		}
+	}
+
+	// Notifications held for quiet hours would only fail again at release time.
+	if _, err := tx.ExecContext(ctx, `DELETE FROM held_notifications WHERE fcm_token = ANY($1::text[])`, pq.Array(tokens)); err != nil {
+		return 0, fmt.Errorf("failed to drop held notifications for cleared tokens: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit cleared fcm tokens: %w", err)
+	}
+
+	return cleared, nil
+}
+
+func (r *deviceTokenRepoPG) ListTokenOwners(ctx context.Context, tokens []string) (map[string]string, error) {
+	if len(tokens) == 0 {
+		return map[string]string{}, nil
+	}
+
+	query := `
+		SELECT t.token, COALESCE(
+		  (SELECT 'user:' || u.id::text FROM users u
+		   WHERE u.device_fcm_token = t.token AND u.deleted_at IS NULL
+		   LIMIT 1),
+		  (SELECT 'user:' || m.user_id::text FROM anonymous_sessions a
+		   JOIN device_user_mappings m ON m.device_id = a.device_id AND m.is_active = true
+		   WHERE a.device_fcm_token = t.token
+		   LIMIT 1),
+		  (SELECT 'device:' || a.device_id FROM anonymous_sessions a
+		   WHERE a.device_fcm_token = t.token
+		   LIMIT 1)
+		) AS owner
+		FROM UNNEST($1::text[]) AS t(token)
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(tokens))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list token owners: %w", err)
+	}
+	defer rows.Close()
+
+	owners := make(map[string]string, len(tokens))
+	for rows.Next() {
+		var token string
+		var owner sql.NullString
+		if err := rows.Scan(&token, &owner); err != nil {
+			return nil, fmt.Errorf("failed to scan token owner: %w", err)
+		}
+		if owner.Valid {
+			owners[token] = owner.String
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating token owners: %w", err)
+	}
+
+	return owners, nil
+}
@@ -0,0 +1,204 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
+)
+
+// digestSendGrace lets a digest go out slightly before a full period has
+// elapsed so a digest sent late in the send hour last time is not slipped
+// to the following day.
+const digestSendGrace = time.Hour
+
+type digestRepoPG struct {
+	db *sql.DB
+}
+
+func NewDigestRepository(db *sql.DB) repository.DigestRepository {
+	return &digestRepoPG{db: db}
+}
+
+func (r *digestRepoPG) GetSubscription(ctx context.Context, userID uuid.UUID) (*model.DigestSubscription, error) {
+	query := `
+		SELECT user_id, frequency, push_enabled, email_enabled, last_sent_at,
+		       known_danger_zones, created_at, updated_at
+		FROM digest_subscriptions
+		WHERE user_id = $1
+	`
+
+	sub, err := scanDigestSubscription(r.db.QueryRowContext(ctx, query, userID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil //nolint:nilnil // no subscription yet is not an error
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get digest subscription: %w", err)
+	}
+
+	return sub, nil
+}
+
+func (r *digestRepoPG) UpsertSubscription(ctx context.Context, subscription *model.DigestSubscription) error {
+	query := `
+		INSERT INTO digest_subscriptions (
+			user_id, frequency, push_enabled, email_enabled, last_sent_at,
+			known_danger_zones, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (user_id) DO UPDATE SET
+			frequency = EXCLUDED.frequency,
+			push_enabled = EXCLUDED.push_enabled,
+			email_enabled = EXCLUDED.email_enabled,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	var lastSentAt sql.NullTime
+	if subscription.LastSentAt != nil {
+		lastSentAt = sql.NullTime{Time: *subscription.LastSentAt, Valid: true}
+	}
+
+	_, err := r.db.ExecContext(ctx, query,
+		subscription.UserID,
+		string(subscription.Frequency),
+		subscription.PushEnabled,
+		subscription.EmailEnabled,
+		lastSentAt,
+		pq.Array(subscription.KnownDangerZones),
+		subscription.CreatedAt,
+		subscription.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert digest subscription: %w", err)
+	}
+
+	return nil
+}
+
+func (r *digestRepoPG) ListDueSubscriptions(ctx context.Context, now time.Time, limit int) ([]*model.DigestSubscription, error) {
+	query := `
+		SELECT user_id, frequency, push_enabled, email_enabled, last_sent_at,
+		       known_danger_zones, created_at, updated_at
+		FROM digest_subscriptions
+		WHERE (frequency = 'daily' AND (last_sent_at IS NULL OR last_sent_at <= $1))
+		   OR (frequency = 'weekly' AND (last_sent_at IS NULL OR last_sent_at <= $2))
+		ORDER BY last_sent_at NULLS FIRST
+		LIMIT $3
+	`
+
+	dailyCutoff := now.Add(-(model.DigestPeriod(model.DigestFrequencyDaily) - digestSendGrace))
+	weeklyCutoff := now.Add(-(model.DigestPeriod(model.DigestFrequencyWeekly) - digestSendGrace))
+
+	rows, err := r.db.QueryContext(ctx, query, dailyCutoff, weeklyCutoff, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due digest subscriptions: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			err = closeErr
+		}
+	}()
+
+	var subscriptions []*model.DigestSubscription
+	for rows.Next() {
+		sub, scanErr := scanDigestSubscription(rows)
+		if scanErr != nil {
+			return nil, fmt.Errorf("failed to scan digest subscription: %w", scanErr)
+		}
+		subscriptions = append(subscriptions, sub)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating digest subscriptions: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+func (r *digestRepoPG) MarkSent(ctx context.Context, userID uuid.UUID, sentAt time.Time, knownDangerZones []string) error {
+	query := `
+		UPDATE digest_subscriptions
+		SET last_sent_at = $2, known_danger_zones = $3
+		WHERE user_id = $1
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, userID, sentAt, pq.Array(knownDangerZones)); err != nil {
+		return fmt.Errorf("failed to mark digest as sent: %w", err)
+	}
+
+	return nil
+}
+
+func (r *digestRepoPG) CountVerifiedReportsNear(ctx context.Context, lat, lon, radiusMeters float64, since time.Time) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM reports
+		WHERE status = 'verified'
+		  AND is_private = false
+		  AND COALESCE(updated_at, created_at) >= $4
+		  AND ll_to_earth(latitude, longitude) <@ earth_box(ll_to_earth($1, $2), $3)
+		  AND earth_distance(ll_to_earth(latitude, longitude), ll_to_earth($1, $2)) <= $3
+	`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, lat, lon, radiusMeters, since).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count verified reports: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *digestRepoPG) CountActiveAlertsNear(ctx context.Context, lat, lon, radiusMeters float64) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM alerts
+		WHERE status = 'active'
+		  AND (expires_at IS NULL OR expires_at > NOW())
+		  AND ll_to_earth(latitude, longitude) <@ earth_box(ll_to_earth($1, $2), $3)
+		  AND earth_distance(ll_to_earth(latitude, longitude), ll_to_earth($1, $2)) <= $3
+	`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, lat, lon, radiusMeters).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count active alerts: %w", err)
+	}
+
+	return count, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanDigestSubscription(row rowScanner) (*model.DigestSubscription, error) {
+	var sub model.DigestSubscription
+	var frequency string
+	var lastSentAt sql.NullTime
+	var knownZones pq.StringArray
+
+	if err := row.Scan(
+		&sub.UserID,
+		&frequency,
+		&sub.PushEnabled,
+		&sub.EmailEnabled,
+		&lastSentAt,
+		&knownZones,
+		&sub.CreatedAt,
+		&sub.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	sub.Frequency = model.DigestFrequency(frequency)
+	sub.KnownDangerZones = []string(knownZones)
+	if lastSentAt.Valid {
+		sub.LastSentAt = &lastSentAt.Time
+	}
+
+	return &sub, nil
+}
@@ -0,0 +1,173 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
+)
+
+type emailNotificationRepoPG struct {
+	db *sql.DB
+}
+
+func NewEmailNotificationRepository(db *sql.DB) repository.EmailNotificationRepository {
+	return &emailNotificationRepoPG{db: db}
+}
+
+func (r *emailNotificationRepoPG) IsEnabled(ctx context.Context, userID uuid.UUID) (bool, error) {
+	query := `SELECT email_notification_enabled FROM users WHERE id = $1 AND deleted_at IS NULL`
+
+	var enabled bool
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(&enabled)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, domainErrors.ErrUserNotFound
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get email notification preference: %w", err)
+	}
+
+	return enabled, nil
+}
+
+func (r *emailNotificationRepoPG) SetEnabled(ctx context.Context, userID uuid.UUID, enabled bool) error {
+	query := `
+		UPDATE users
+		SET email_notification_enabled = $2, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	res, err := r.db.ExecContext(ctx, query, userID, enabled)
+	if err != nil {
+		return fmt.Errorf("failed to update email notification preference: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return domainErrors.ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *emailNotificationRepoPG) ListRecipientsByUserIDs(ctx context.Context, userIDs []uuid.UUID) ([]model.EmailRecipient, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		SELECT id, email, name, COALESCE(device_language, 'pt')
+		FROM users
+		WHERE id = ANY($1::uuid[])
+		  AND email_notification_enabled = true
+		  AND deleted_at IS NULL
+		  AND email <> ''
+	`
+
+	return r.queryRecipients(ctx, query, pq.Array(uuidStrings(userIDs)))
+}
+
+func (r *emailNotificationRepoPG) ListRecipientsNear(ctx context.Context, userIDs []uuid.UUID, distancesMeters []int, lat, lon, radiusMeters float64, filter model.EmailAlertFilter) ([]model.EmailRecipient, error) {
+	// distance is the closest of the user's live location and their home and
+	// work addresses. Candidates are narrowed to users with a live location or
+	// an address inside the alert's bounding box first, so the home and work
+	// GiST indexes are used instead of measuring every email user.
+	query := `
+		WITH live AS (
+			SELECT user_id, MIN(distance_meters) AS distance_meters
+			FROM UNNEST($1::uuid[], $10::INT[]) AS l(user_id, distance_meters)
+			GROUP BY user_id
+		),
+		candidates AS (
+			SELECT id, email, name, COALESCE(device_language, 'pt') AS language,
+			       LEAST(
+			         (SELECT l.distance_meters::DOUBLE PRECISION FROM live l WHERE l.user_id = users.id),
+			         CASE WHEN home_address_lat IS NOT NULL AND home_address_lon IS NOT NULL
+			              THEN earth_distance(ll_to_earth(home_address_lat, home_address_lon), ll_to_earth($2, $3)) END,
+			         CASE WHEN work_address_lat IS NOT NULL AND work_address_lon IS NOT NULL
+			              THEN earth_distance(ll_to_earth(work_address_lat, work_address_lon), ll_to_earth($2, $3)) END
+			       ) AS distance
+			FROM users
+			WHERE email_notification_enabled = true
+			  AND deleted_at IS NULL
+			  AND email <> ''
+			  AND (
+			    id = ANY($1::uuid[])
+			    OR (home_address_lat IS NOT NULL AND home_address_lon IS NOT NULL
+			        AND ll_to_earth(home_address_lat, home_address_lon) <@ earth_box(ll_to_earth($2, $3), $4::DOUBLE PRECISION))
+			    OR (work_address_lat IS NOT NULL AND work_address_lon IS NOT NULL
+			        AND ll_to_earth(work_address_lat, work_address_lon) <@ earth_box(ll_to_earth($2, $3), $4::DOUBLE PRECISION))
+			  )
+		)
+		SELECT c.id, c.email, c.name, c.language
+		FROM candidates c
+		LEFT JOIN user_safety_settings s ON s.user_id = c.id
+		WHERE c.distance <= $4::DOUBLE PRECISION
+		  AND (s.id IS NULL OR s.notifications_enabled = true)
+		  AND (
+		    s.id IS NULL OR
+		    CASE WHEN $5::BOOLEAN
+		      THEN 'all' = ANY(s.notification_report_types)
+		           OR ($7::BOOLEAN AND 'verified' = ANY(s.notification_report_types))
+		      ELSE $6::TEXT = ANY(s.notification_alert_types)
+		           OR 'all' = ANY(s.notification_alert_types)
+		    END
+		  )
+		  AND (
+		    s.id IS NULL OR
+		    c.distance <= COALESCE((
+		      SELECT CASE WHEN p.enabled
+		        THEN COALESCE(p.radius_meters, CASE WHEN $5::BOOLEAN THEN s.notification_report_radius_mins ELSE s.notification_alert_radius_mins END)
+		        ELSE -1 END
+		      FROM notification_risk_preferences p
+		      WHERE p.settings_id = s.id
+		        AND p.risk_type_id = $8::UUID
+		        AND (p.risk_topic_id IS NULL OR p.risk_topic_id = $9::UUID)
+		      ORDER BY p.risk_topic_id IS NULL
+		      LIMIT 1
+		    ), CASE WHEN $5::BOOLEAN THEN s.notification_report_radius_mins ELSE s.notification_alert_radius_mins END)
+		  )
+	`
+
+	return r.queryRecipients(ctx, query, pq.Array(uuidStrings(userIDs)), lat, lon, radiusMeters,
+		filter.Report, filter.Severity, filter.IsVerified, filter.RiskTypeID, filter.RiskTopicID, pq.Array(int32Distances(distancesMeters)))
+}
+
+func (r *emailNotificationRepoPG) queryRecipients(ctx context.Context, query string, args ...any) ([]model.EmailRecipient, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list email recipients: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			err = closeErr
+		}
+	}()
+
+	var recipients []model.EmailRecipient
+	for rows.Next() {
+		var rec model.EmailRecipient
+		if err := rows.Scan(&rec.UserID, &rec.Email, &rec.Name, &rec.Language); err != nil {
+			return nil, fmt.Errorf("failed to scan email recipient: %w", err)
+		}
+		recipients = append(recipients, rec)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating email recipients: %w", err)
+	}
+
+	return recipients, nil
+}
+
+func uuidStrings(ids []uuid.UUID) []string {
+	out := make([]string, len(ids))
+	for i, id := range ids {
+		out[i] = id.String()
+	}
+	return out
+}
@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
+)
+
+type heldNotificationRepoPG struct {
+	db *sql.DB
+}
+
+func NewHeldNotificationRepository(db *sql.DB) repository.HeldNotificationRepository {
+	return &heldNotificationRepoPG{db: db}
+}
+
+func (r *heldNotificationRepoPG) Hold(ctx context.Context, notifications []*model.HeldNotification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+		INSERT INTO held_notifications (
+			id, user_id, device_id, fcm_token, language, event_key,
+			risk_type, reference_id, release_at, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT DO NOTHING
+	`
+
+	for _, n := range notifications {
+		_, err := tx.ExecContext(ctx, query,
+			n.ID,
+			uuidPtrToNullUUID(n.UserID),
+			nullStringPtr(n.DeviceID),
+			n.FCMToken,
+			n.Language,
+			n.EventKey,
+			sql.NullString{String: n.RiskType, Valid: n.RiskType != ""},
+			sql.NullString{String: n.ReferenceID, Valid: n.ReferenceID != ""},
+			n.ReleaseAt,
+			n.CreatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to hold notification: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ListDue returns every due notification of up to limit devices, so a
+// device's digest is never split across two batches.
+func (r *heldNotificationRepoPG) ListDue(ctx context.Context, before time.Time, limit int) ([]*model.HeldNotification, error) {
+	query := `
+		WITH due_tokens AS (
+			SELECT DISTINCT fcm_token
+			FROM held_notifications
+			WHERE release_at <= $1
+			ORDER BY fcm_token
+			LIMIT $2
+		)
+		SELECT h.id, h.user_id, h.device_id, h.fcm_token, h.language, h.event_key,
+		       COALESCE(h.risk_type, ''), COALESCE(h.reference_id, ''), h.release_at, h.created_at
+		FROM held_notifications h
+		JOIN due_tokens d ON d.fcm_token = h.fcm_token
+		WHERE h.release_at <= $1
+		ORDER BY h.fcm_token, h.created_at
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due held notifications: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			err = closeErr
+		}
+	}()
+
+	var notifications []*model.HeldNotification
+	for rows.Next() {
+		var n model.HeldNotification
+		var userID uuid.NullUUID
+		var deviceID sql.NullString
+
+		if err := rows.Scan(
+			&n.ID,
+			&userID,
+			&deviceID,
+			&n.FCMToken,
+			&n.Language,
+			&n.EventKey,
+			&n.RiskType,
+			&n.ReferenceID,
+			&n.ReleaseAt,
+			&n.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan held notification: %w", err)
+		}
+
+		n.UserID = nullUUIDToPtr(userID)
+		if deviceID.Valid {
+			n.DeviceID = &deviceID.String
+		}
+
+		notifications = append(notifications, &n)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating held notifications: %w", err)
+	}
+
+	return notifications, nil
+}
+
+func (r *heldNotificationRepoPG) DeleteByIDs(ctx context.Context, ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	_, err := r.db.ExecContext(ctx, `DELETE FROM held_notifications WHERE id = ANY($1::uuid[])`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to delete held notifications: %w", err)
+	}
+
+	return nil
+}
+
+func nullStringPtr(s *string) sql.NullString {
+	if s == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *s, Valid: true}
+}
@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
+)
+
+type IncidentWeightRuleRepoPG struct {
+	db *sql.DB
+}
+
+func NewIncidentWeightRuleRepoPG(db *sql.DB) repository.IncidentWeightRuleRepository {
+	return &IncidentWeightRuleRepoPG{db: db}
+}
+
+func (r *IncidentWeightRuleRepoPG) List(ctx context.Context) (_ []*model.IncidentWeightRule, err error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT w.id, w.risk_type_id, w.risk_topic_id, rt.name, COALESCE(tp.name, ''),
+			w.severity, w.half_life_days, w.proximity_falloff_meters, w.updated_at
+		FROM incident_weight_rules w
+		JOIN risk_types rt ON rt.id = w.risk_type_id
+		LEFT JOIN risk_topics tp ON tp.id = w.risk_topic_id
+		ORDER BY rt.name, tp.name NULLS FIRST
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list incident weight rules: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			err = closeErr
+		}
+	}()
+
+	var rules []*model.IncidentWeightRule
+	for rows.Next() {
+		var rule model.IncidentWeightRule
+		var topicID uuid.NullUUID
+		if err := rows.Scan(
+			&rule.ID,
+			&rule.RiskTypeID,
+			&topicID,
+			&rule.RiskType,
+			&rule.RiskTopic,
+			&rule.Severity,
+			&rule.HalfLifeDays,
+			&rule.ProximityFalloffMeters,
+			&rule.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan incident weight rule: %w", err)
+		}
+		if topicID.Valid {
+			rule.RiskTopicID = &topicID.UUID
+		}
+		rules = append(rules, &rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating incident weight rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+func (r *IncidentWeightRuleRepoPG) Save(ctx context.Context, rule *model.IncidentWeightRule) error {
+	// Each kind of rule has its own partial unique index to conflict on.
+	conflict := `(risk_type_id) WHERE risk_topic_id IS NULL`
+	if rule.RiskTopicID != nil {
+		conflict = `(risk_topic_id) WHERE risk_topic_id IS NOT NULL`
+	}
+
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO incident_weight_rules (
+			risk_type_id, risk_topic_id, severity, half_life_days, proximity_falloff_meters, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT `+conflict+` DO UPDATE
+		SET risk_type_id = EXCLUDED.risk_type_id,
+			severity = EXCLUDED.severity,
+			half_life_days = EXCLUDED.half_life_days,
+			proximity_falloff_meters = EXCLUDED.proximity_falloff_meters,
+			updated_at = EXCLUDED.updated_at
+		RETURNING id
+	`,
+		rule.RiskTypeID,
+		rule.RiskTopicID,
+		rule.Severity,
+		rule.HalfLifeDays,
+		rule.ProximityFalloffMeters,
+		rule.UpdatedAt,
+	).Scan(&rule.ID)
+	if err != nil {
+		return fmt.Errorf("failed to save incident weight rule: %w", err)
+	}
+	return nil
+}
+
+func (r *IncidentWeightRuleRepoPG) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM incident_weight_rules WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete incident weight rule: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check affected rows: %w", err)
+	}
+	if affected == 0 {
+		return domainErrors.ErrWeightRuleNotFound
+	}
+	return nil
+}
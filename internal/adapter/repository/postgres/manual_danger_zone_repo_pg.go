@@ -0,0 +1,195 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
+)
+
+const manualDangerZoneColumns = `
+	id, polygon, risk_level, reason, source, valid_from, valid_until, created_by, created_at, updated_at
+`
+
+type ManualDangerZoneRepoPG struct {
+	db *sql.DB
+}
+
+func NewManualDangerZoneRepoPG(db *sql.DB) repository.ManualDangerZoneRepository {
+	return &ManualDangerZoneRepoPG{db: db}
+}
+
+func (r *ManualDangerZoneRepoPG) Create(ctx context.Context, zone *model.ManualDangerZone) error {
+	polygon, err := json.Marshal(zone.Polygon)
+	if err != nil {
+		return fmt.Errorf("failed to encode danger zone polygon: %w", err)
+	}
+	bounds := zone.Bounds()
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO manual_danger_zones (
+			id, polygon, min_lat, min_lon, max_lat, max_lon, risk_level, reason, source,
+			valid_from, valid_until, created_by, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	`,
+		zone.ID,
+		polygon,
+		bounds.MinLat, bounds.MinLon, bounds.MaxLat, bounds.MaxLon,
+		zone.RiskLevel,
+		zone.Reason,
+		zone.Source,
+		zone.ValidFrom,
+		zone.ValidUntil,
+		uuid.NullUUID{UUID: zone.CreatedBy, Valid: zone.CreatedBy != uuid.Nil},
+		zone.CreatedAt,
+		zone.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create manual danger zone: %w", err)
+	}
+	return nil
+}
+
+func (r *ManualDangerZoneRepoPG) Update(ctx context.Context, zone *model.ManualDangerZone) error {
+	polygon, err := json.Marshal(zone.Polygon)
+	if err != nil {
+		return fmt.Errorf("failed to encode danger zone polygon: %w", err)
+	}
+	bounds := zone.Bounds()
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE manual_danger_zones
+		SET polygon = $2, min_lat = $3, min_lon = $4, max_lat = $5, max_lon = $6,
+			risk_level = $7, reason = $8, source = $9, valid_from = $10, valid_until = $11,
+			updated_at = $12
+		WHERE id = $1
+	`,
+		zone.ID,
+		polygon,
+		bounds.MinLat, bounds.MinLon, bounds.MaxLat, bounds.MaxLon,
+		zone.RiskLevel,
+		zone.Reason,
+		zone.Source,
+		zone.ValidFrom,
+		zone.ValidUntil,
+		zone.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update manual danger zone: %w", err)
+	}
+	return expectManualZoneRow(result)
+}
+
+func (r *ManualDangerZoneRepoPG) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM manual_danger_zones WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete manual danger zone: %w", err)
+	}
+	return expectManualZoneRow(result)
+}
+
+func (r *ManualDangerZoneRepoPG) FindByID(ctx context.Context, id uuid.UUID) (*model.ManualDangerZone, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+manualDangerZoneColumns+` FROM manual_danger_zones WHERE id = $1`, id)
+
+	zone, err := scanManualDangerZone(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domainErrors.ErrDangerZoneNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find manual danger zone: %w", err)
+	}
+	return zone, nil
+}
+
+func (r *ManualDangerZoneRepoPG) List(ctx context.Context) ([]*model.ManualDangerZone, error) {
+	return r.query(ctx, `SELECT `+manualDangerZoneColumns+` FROM manual_danger_zones ORDER BY created_at DESC`)
+}
+
+func (r *ManualDangerZoneRepoPG) ListUnexpired(ctx context.Context, at time.Time) ([]*model.ManualDangerZone, error) {
+	return r.query(ctx, `
+		SELECT `+manualDangerZoneColumns+`
+		FROM manual_danger_zones
+		WHERE valid_until IS NULL OR valid_until > $1
+		ORDER BY created_at DESC
+	`, at)
+}
+
+func (r *ManualDangerZoneRepoPG) query(ctx context.Context, query string, args ...any) (_ []*model.ManualDangerZone, err error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list manual danger zones: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			err = closeErr
+		}
+	}()
+
+	var zones []*model.ManualDangerZone
+	for rows.Next() {
+		zone, err := scanManualDangerZone(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan manual danger zone: %w", err)
+		}
+		zones = append(zones, zone)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating manual danger zones: %w", err)
+	}
+
+	return zones, nil
+}
+
+func scanManualDangerZone(row rowScanner) (*model.ManualDangerZone, error) {
+	var (
+		zone       model.ManualDangerZone
+		polygon    []byte
+		validUntil sql.NullTime
+		createdBy  uuid.NullUUID
+	)
+	if err := row.Scan(
+		&zone.ID,
+		&polygon,
+		&zone.RiskLevel,
+		&zone.Reason,
+		&zone.Source,
+		&zone.ValidFrom,
+		&validUntil,
+		&createdBy,
+		&zone.CreatedAt,
+		&zone.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(polygon, &zone.Polygon); err != nil {
+		return nil, fmt.Errorf("invalid polygon for manual danger zone %s: %w", zone.ID, err)
+	}
+	if validUntil.Valid {
+		zone.ValidUntil = &validUntil.Time
+	}
+	zone.CreatedBy = createdBy.UUID
+
+	return &zone, nil
+}
+
+// expectManualZoneRow maps an update or delete that matched nothing to
+// ErrDangerZoneNotFound.
+func expectManualZoneRow(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check affected rows: %w", err)
+	}
+	if affected == 0 {
+		return domainErrors.ErrDangerZoneNotFound
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
+)
+
+type outboxRepoPG struct {
+	db *sql.DB
+}
+
+func NewOutboxRepository(db *sql.DB) repository.OutboxRepository {
+	return &outboxRepoPG{db: db}
+}
+
+func (r *outboxRepoPG) Enqueue(ctx context.Context, message *model.OutboxMessage) error {
+	query := `
+		INSERT INTO outbox_events (id, event_name, payload, attempts, next_attempt_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := executorFor(ctx, r.db).ExecContext(ctx, query,
+		message.ID,
+		message.EventName,
+		message.Payload,
+		message.Attempts,
+		message.NextAttemptAt,
+		message.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue outbox event: %w", err)
+	}
+
+	return nil
+}
+
+func (r *outboxRepoPG) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*model.OutboxMessage, error) {
+	query := `
+		UPDATE outbox_events
+		SET attempts = attempts + 1, next_attempt_at = $2
+		WHERE id IN (
+			SELECT id FROM outbox_events
+			WHERE next_attempt_at <= $1
+			ORDER BY created_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, event_name, payload, attempts, next_attempt_at, COALESCE(last_error, ''), delivered_handlers, created_at
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim outbox events: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			err = closeErr
+		}
+	}()
+
+	var messages []*model.OutboxMessage
+	for rows.Next() {
+		var m model.OutboxMessage
+		var deliveredHandlers pq.StringArray
+		if err := rows.Scan(
+			&m.ID,
+			&m.EventName,
+			&m.Payload,
+			&m.Attempts,
+			&m.NextAttemptAt,
+			&m.LastError,
+			&deliveredHandlers,
+			&m.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan outbox event: %w", err)
+		}
+		m.DeliveredHandlers = []string(deliveredHandlers)
+		messages = append(messages, &m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating outbox events: %w", err)
+	}
+
+	return messages, nil
+}
+
+func (r *outboxRepoPG) MarkDelivered(ctx context.Context, id uuid.UUID) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM outbox_events WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to mark outbox event delivered: %w", err)
+	}
+	return nil
+}
+
+func (r *outboxRepoPG) MarkFailed(ctx context.Context, id uuid.UUID, nextAttemptAt time.Time, lastError string, deliveredHandlers []string) error {
+	query := `UPDATE outbox_events SET next_attempt_at = $2, last_error = $3, delivered_handlers = $4 WHERE id = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, id, nextAttemptAt, lastError, pq.Array(deliveredHandlers)); err != nil {
+		return fmt.Errorf("failed to mark outbox event failed: %w", err)
+	}
+	return nil
+}
+
+func (r *outboxRepoPG) MoveToDeadLetter(ctx context.Context, message *model.OutboxMessage, lastError string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	insert := `
+		INSERT INTO outbox_dead_letters (id, event_name, payload, attempts, last_error, delivered_handlers, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (id) DO NOTHING
+	`
+	if _, err := tx.ExecContext(ctx, insert,
+		message.ID,
+		message.EventName,
+		message.Payload,
+		message.Attempts,
+		lastError,
+		pq.Array(message.DeliveredHandlers),
+		message.CreatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to insert dead letter: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM outbox_events WHERE id = $1`, message.ID); err != nil {
+		return fmt.Errorf("failed to remove dead outbox event: %w", err)
+	}
+
+	return tx.Commit()
+}
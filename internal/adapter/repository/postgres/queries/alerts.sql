@@ -59,6 +59,21 @@ LEFT JOIN risk_topics rtopic ON a.risk_topic_id = rtopic.id
 WHERE a.status = 'active' AND (a.expires_at IS NULL OR a.expires_at > NOW()) AND rt.is_enabled = TRUE
 ORDER BY a.created_at DESC;
 
+-- name: ListActiveAlertsNear :many
+SELECT 
+    a.*,
+    rt.name as risk_type_name,
+    rt.icon_path as risk_type_icon_path,
+    rtopic.name as risk_topic_name,
+    rtopic.icon_path as risk_topic_icon_path
+FROM alerts a
+LEFT JOIN risk_types rt ON a.risk_type_id = rt.id
+LEFT JOIN risk_topics rtopic ON a.risk_topic_id = rtopic.id
+WHERE a.status = 'active' AND (a.expires_at IS NULL OR a.expires_at > NOW()) AND rt.is_enabled = TRUE
+  AND earth_distance(ll_to_earth(a.latitude, a.longitude), ll_to_earth(sqlc.arg(latitude)::FLOAT8, sqlc.arg(longitude)::FLOAT8)) <= sqlc.arg(radius_meters)::FLOAT8
+ORDER BY a.created_at DESC
+LIMIT sqlc.arg(max_results);
+
 -- name: UpdateAlert :exec
 UPDATE alerts
 SET message = $2, severity = $3, radius_meters = $4
@@ -178,6 +178,29 @@ WHERE r.risk_type_id = $1
       earth_box(ll_to_earth($3, $4), $5)
 ORDER BY r.created_at DESC;
 
+-- name: ListVerifiedReportsNear :many
+SELECT
+    r.id, r.user_id, r.risk_type_id, r.risk_topic_id, r.description,
+    r.latitude, r.longitude, r.province, r.municipality, r.neighborhood,
+    r.address, r.image_url, r.status, r.reviewed_by, r.resolved_at,
+    r.verification_count, r.rejection_count, r.expires_at, r.is_private,
+    r.created_at, r.updated_at,
+    rt.name as risk_type_name,
+    rt.icon_path as risk_type_icon_path,
+    rtopic.name as risk_topic_name,
+    rtopic.icon_path as risk_topic_icon_path,
+    earth_distance(ll_to_earth(r.latitude, r.longitude), ll_to_earth(sqlc.arg(latitude)::FLOAT8, sqlc.arg(longitude)::FLOAT8))::FLOAT8 AS distance_meters
+FROM reports r
+LEFT JOIN risk_types rt ON r.risk_type_id = rt.id
+LEFT JOIN risk_topics rtopic ON r.risk_topic_id = rtopic.id
+WHERE r.status = 'verified' AND r.is_private = FALSE AND rt.is_enabled = TRUE
+  AND r.created_at >= sqlc.arg(since)::TIMESTAMPTZ
+  AND ll_to_earth(r.latitude, r.longitude) <@
+      earth_box(ll_to_earth(sqlc.arg(latitude)::FLOAT8, sqlc.arg(longitude)::FLOAT8), sqlc.arg(radius_meters)::FLOAT8)
+  AND earth_distance(ll_to_earth(r.latitude, r.longitude), ll_to_earth(sqlc.arg(latitude)::FLOAT8, sqlc.arg(longitude)::FLOAT8)) <= sqlc.arg(radius_meters)::FLOAT8
+ORDER BY distance_meters
+LIMIT sqlc.arg(max_results);
+
 -- name: ExpireOldReports :exec
 UPDATE reports
 SET status = 'rejected', updated_at = NOW()
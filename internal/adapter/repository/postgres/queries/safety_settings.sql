@@ -83,3 +83,12 @@ DO UPDATE SET
 -- name: DeleteSafetySettingsByAnonymousSessionID :exec
 DELETE FROM user_safety_settings 
 WHERE anonymous_session_id = $1 AND device_id = $2;
+
+-- name: ListNightModeSettings :many
+SELECT user_id, device_id, night_mode_start_time, night_mode_end_time
+FROM user_safety_settings
+WHERE night_mode_enabled = TRUE
+  AND (
+    user_id = ANY(sqlc.arg(user_ids)::uuid[]) OR
+    device_id = ANY(sqlc.arg(device_ids)::text[])
+  );
@@ -74,10 +74,10 @@ WHERE deleted_at IS NULL
 -- name: ListDeviceTokensForAlertNotification :many
 SELECT DISTINCT u.device_fcm_token, u.device_language, u.id as user_id
 FROM users u
+JOIN UNNEST(sqlc.arg(user_ids)::uuid[], sqlc.arg(distances_meters)::INT[]) AS r(user_id, distance_meters) ON r.user_id = u.id
 LEFT JOIN user_safety_settings s ON s.user_id = u.id
 WHERE u.deleted_at IS NULL
   AND u.device_fcm_token IS NOT NULL
-  AND u.id IN (SELECT UNNEST(sqlc.arg(user_ids)::uuid[]))
   AND (s.id IS NULL OR s.notifications_enabled = true)
   AND (
     s.id IS NULL OR
@@ -86,16 +86,24 @@ WHERE u.deleted_at IS NULL
   )
   AND (
     s.id IS NULL OR
-    s.notification_alert_radius_mins >= sqlc.arg(distance_meters)::INT
+    r.distance_meters <= COALESCE((
+      SELECT CASE WHEN p.enabled THEN COALESCE(p.radius_meters, s.notification_alert_radius_mins) ELSE -1 END
+      FROM notification_risk_preferences p
+      WHERE p.settings_id = s.id
+        AND p.risk_type_id = sqlc.arg(risk_type_id)::UUID
+        AND (p.risk_topic_id IS NULL OR p.risk_topic_id = sqlc.arg(risk_topic_id)::UUID)
+      ORDER BY p.risk_topic_id IS NULL
+      LIMIT 1
+    ), s.notification_alert_radius_mins)
   );
 
 -- name: ListDeviceTokensForReportNotification :many
 SELECT DISTINCT u.device_fcm_token, u.device_language, u.id as user_id
 FROM users u
+JOIN UNNEST(sqlc.arg(user_ids)::uuid[], sqlc.arg(distances_meters)::INT[]) AS r(user_id, distance_meters) ON r.user_id = u.id
 LEFT JOIN user_safety_settings s ON s.user_id = u.id
 WHERE u.deleted_at IS NULL
   AND u.device_fcm_token IS NOT NULL
-  AND u.id IN (SELECT UNNEST(sqlc.arg(user_ids)::uuid[]))
   AND (s.id IS NULL OR s.notifications_enabled = true)
   AND (
     s.id IS NULL OR
@@ -104,7 +112,15 @@ WHERE u.deleted_at IS NULL
   )
   AND (
     s.id IS NULL OR
-    s.notification_report_radius_mins >= sqlc.arg(distance_meters)::INT
+    r.distance_meters <= COALESCE((
+      SELECT CASE WHEN p.enabled THEN COALESCE(p.radius_meters, s.notification_report_radius_mins) ELSE -1 END
+      FROM notification_risk_preferences p
+      WHERE p.settings_id = s.id
+        AND p.risk_type_id = sqlc.arg(risk_type_id)::UUID
+        AND (p.risk_topic_id IS NULL OR p.risk_topic_id = sqlc.arg(risk_topic_id)::UUID)
+      ORDER BY p.risk_topic_id IS NULL
+      LIMIT 1
+    ), s.notification_report_radius_mins)
   );
 
 -- name: ListAnonymousTokensForAlertNotification :many
@@ -136,13 +152,21 @@ WHERE a.device_fcm_token IS NOT NULL
   )
   AND (
     s.id IS NULL OR
-    s.notification_alert_radius_mins >= CAST((
+    CAST((
       6371000 * acos(
         cos(radians(sqlc.arg(latitude)::DOUBLE PRECISION)) * cos(radians(a.latitude)) *
         cos(radians(a.longitude) - radians(sqlc.arg(longitude)::DOUBLE PRECISION)) +
         sin(radians(sqlc.arg(latitude)::DOUBLE PRECISION)) * sin(radians(a.latitude))
       )
-    ) AS INT)
+    ) AS INT) <= COALESCE((
+      SELECT CASE WHEN p.enabled THEN COALESCE(p.radius_meters, s.notification_alert_radius_mins) ELSE -1 END
+      FROM notification_risk_preferences p
+      WHERE p.settings_id = s.id
+        AND p.risk_type_id = sqlc.arg(risk_type_id)::UUID
+        AND (p.risk_topic_id IS NULL OR p.risk_topic_id = sqlc.arg(risk_topic_id)::UUID)
+      ORDER BY p.risk_topic_id IS NULL
+      LIMIT 1
+    ), s.notification_alert_radius_mins)
   );
 
 -- name: ListAnonymousTokensForReportNotification :many
@@ -174,13 +198,21 @@ WHERE a.device_fcm_token IS NOT NULL
   )
   AND (
     s.id IS NULL OR
-    s.notification_report_radius_mins >= CAST((
+    CAST((
       6371000 * acos(
         cos(radians(sqlc.arg(latitude)::DOUBLE PRECISION)) * cos(radians(a.latitude)) *
         cos(radians(a.longitude) - radians(sqlc.arg(longitude)::DOUBLE PRECISION)) +
         sin(radians(sqlc.arg(latitude)::DOUBLE PRECISION)) * sin(radians(a.latitude))
       )
-    ) AS INT)
+    ) AS INT) <= COALESCE((
+      SELECT CASE WHEN p.enabled THEN COALESCE(p.radius_meters, s.notification_report_radius_mins) ELSE -1 END
+      FROM notification_risk_preferences p
+      WHERE p.settings_id = s.id
+        AND p.risk_type_id = sqlc.arg(risk_type_id)::UUID
+        AND (p.risk_topic_id IS NULL OR p.risk_topic_id = sqlc.arg(risk_topic_id)::UUID)
+      ORDER BY p.risk_topic_id IS NULL
+      LIMIT 1
+    ), s.notification_report_radius_mins)
   );
 
 -- name: UpdateUserSavedLocations :exec
@@ -141,6 +141,13 @@ func listReportsByIDsRowToModel(r sqlc.ListReportsByIDsRow) *model.Report {
 		r.ResolvedAt, r.VerificationCount, r.RejectionCount, r.ExpiresAt, r.CreatedAt, r.UpdatedAt, r.IsPrivate)
 }
 
+func listVerifiedReportsNearRowToModel(r sqlc.ListVerifiedReportsNearRow) *model.Report {
+	return mapReportRow(r.ID, r.UserID, r.RiskTypeID, r.RiskTypeName, r.RiskTypeIconPath,
+		r.RiskTopicID, r.RiskTopicName, r.RiskTopicIconPath, r.Description, r.Latitude, r.Longitude,
+		r.Province, r.Municipality, r.Neighborhood, r.Address, r.ImageUrl, r.Status, r.ReviewedBy,
+		r.ResolvedAt, r.VerificationCount, r.RejectionCount, r.ExpiresAt, r.CreatedAt, r.UpdatedAt, r.IsPrivate)
+}
+
 func listReportsWithPaginationRowToModel(r sqlc.ListReportsWithPaginationRow) *model.Report {
 	return mapReportRow(r.ID, r.UserID, r.RiskTypeID, r.RiskTypeName, r.RiskTypeIconPath,
 		r.RiskTopicID, r.RiskTopicName, r.RiskTopicIconPath, r.Description, r.Latitude, r.Longitude,
@@ -150,7 +157,7 @@ func listReportsWithPaginationRowToModel(r sqlc.ListReportsWithPaginationRow) *m
 
 func (r *ReportPG) Create(ctx context.Context, m *model.Report) error {
 	// Creates o report no PostgreSQL e obtém o ID gerado
-	reportID, err := r.q.CreateReport(ctx, sqlc.CreateReportParams{
+	reportID, err := queriesFor(ctx, r.q).CreateReport(ctx, sqlc.CreateReportParams{
 		UserID:       m.UserID,
 		RiskTypeID:   m.RiskTypeID,
 		RiskTopicID:  uuid.NullUUID{UUID: m.RiskTopicID, Valid: m.RiskTopicID != uuid.Nil},
@@ -270,7 +277,7 @@ func (r *ReportPG) UpdateLocation(ctx context.Context, reportID uuid.UUID, param
 }
 
 func (r *ReportPG) CreateReportNotification(ctx context.Context, reportID uuid.UUID, userID uuid.UUID) error {
-	return r.q.CreateReportNotification(ctx, sqlc.CreateReportNotificationParams{
+	return queriesFor(ctx, r.q).CreateReportNotification(ctx, sqlc.CreateReportNotificationParams{
 		ReferenceID: reportID,
 		UserID:      userID,
 		Type:        "report",
@@ -417,6 +424,30 @@ func (r *ReportPG) FindByRadiusWithDistance(ctx context.Context, lat float64, lo
 	return result, nil
 }
 
+func (r *ReportPG) FindVerifiedNearSince(ctx context.Context, lat, lon, radiusMeters float64, since time.Time, limit int) ([]repository.ReportWithDistance, error) {
+	// #nosec G115 -- limit is a small caller-defined constant
+	items, err := r.q.ListVerifiedReportsNear(ctx, sqlc.ListVerifiedReportsNearParams{
+		Latitude:     lat,
+		Longitude:    lon,
+		Since:        since,
+		RadiusMeters: radiusMeters,
+		MaxResults:   int32(limit),
+	})
+	if err != nil {
+		slog.Error("failed to find verified reports near location", "error", err)
+		return nil, err
+	}
+
+	result := make([]repository.ReportWithDistance, 0, len(items))
+	for _, item := range items {
+		result = append(result, repository.ReportWithDistance{
+			Report:   listVerifiedReportsNearRowToModel(item),
+			Distance: item.DistanceMeters,
+		})
+	}
+	return result, nil
+}
+
 func mapSlice[T any, R any](in []T, fn func(T) *R) []*R {
 	out := make([]*R, 0, len(in))
 	for _, v := range in {
@@ -14,31 +14,9 @@ import (
 )
 
 const (
-	earthRadiusKm         = 6371.0
-	corridorWidthKm       = 2.0
-	avgSpeedKmh           = 40.0
-	highRiskIncidentTypes = "robbery,assault,armed_robbery"
-	gridCellSizeKm        = 0.5
-
-	// Time and distance constants
-	minutesPerHour           = 60.0
-	hoursPerDay              = 24.0
-	degreesInCircle          = 180.0
-	kmPerDegreeLat           = 111.0
-	maxIntermediatePoints    = 10
-	intermediatePointSpacing = 2.0
-	halfDivisor              = 2.0
-
-	// Temporal decay constants
-	recentIncidentDays   = 7
-	mediumIncidentDays   = 30
-	oldIncidentDays      = 60
-	proximityThresholdKm = 0.5
-
-	// Weight calculation constants
-	baseWeightDivisor = 10.0
-	maxWeightCap      = 10.0
-	minWaypointCount  = 2
+	hoursPerDay     = 24.0
+	degreesInCircle = 180.0
+	kmPerDegreeLat  = 111.0
 )
 
 type SafeRouteRepoPG struct {
@@ -53,31 +31,6 @@ func NewSafeRouteRepoPG(db *sql.DB) repository.SafeRouteRepository {
 	}
 }
 
-func (r *SafeRouteRepoPG) CalculateSafeRoute(ctx context.Context, params repository.RouteCalculationParams) (*model.SafeRoute, error) {
-	route := model.NewSafeRoute(params.OriginLat, params.OriginLon, params.DestinationLat, params.DestinationLon)
-
-	waypoints := r.generateWaypoints(params.OriginLat, params.OriginLon, params.DestinationLat, params.DestinationLon)
-	for i, wp := range waypoints {
-		route.AddWaypoint(wp.Latitude, wp.Longitude, i)
-	}
-
-	route.DistanceKm = r.calculateTotalDistance(waypoints)
-	route.EstimatedDuration = int((route.DistanceKm / avgSpeedKmh) * minutesPerHour)
-
-	incidents, err := r.GetIncidentsForRoute(ctx, route.Waypoints, corridorWidthKm)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get incidents for route: %w", err)
-	}
-
-	for _, incident := range incidents {
-		route.AddIncident(incident)
-	}
-
-	route.CalculateSafetyScore()
-
-	return route, nil
-}
-
 func (r *SafeRouteRepoPG) GetIncidentsForRoute(ctx context.Context, waypoints []model.Waypoint, corridorWidthKm float64) ([]model.IncidentNearRoute, error) {
 	if len(waypoints) == 0 {
 		return []model.IncidentNearRoute{}, nil
@@ -95,6 +48,7 @@ func (r *SafeRouteRepoPG) GetIncidentsForRoute(ctx context.Context, waypoints []
 			r.latitude,
 			r.longitude,
 			r.created_at,
+			r.is_private,
 			ST_Distance(
 				ST_MakePoint(r.longitude, r.latitude)::geography,
 				ST_MakeLine(ARRAY[` + lineString + `])::geography
@@ -139,6 +93,7 @@ func (r *SafeRouteRepoPG) GetIncidentsForRoute(ctx context.Context, waypoints []
 			&incident.Latitude,
 			&incident.Longitude,
 			&createdAt,
+			&incident.IsPrivate,
 			&incident.DistanceKm,
 		)
 		if err != nil {
@@ -149,7 +104,6 @@ func (r *SafeRouteRepoPG) GetIncidentsForRoute(ctx context.Context, waypoints []
 		incident.RiskTopic = riskTopic
 		incident.CreatedAt = createdAt
 		incident.DaysAgo = int(now.Sub(createdAt).Hours() / hoursPerDay)
-		incident.WeightFactor = r.calculateIncidentWeight(riskType, incident.DaysAgo, incident.DistanceKm)
 
 		if incident.DistanceKm <= corridorWidthKm {
 			incidents = append(incidents, incident)
@@ -159,22 +113,92 @@ func (r *SafeRouteRepoPG) GetIncidentsForRoute(ctx context.Context, waypoints []
 	return incidents, nil
 }
 
-func (r *SafeRouteRepoPG) GetIncidentsHeatmap(ctx context.Context, params repository.IncidentHeatmapParams) ([]repository.HeatmapPoint, error) {
+// ListIncidentsInArea returns the recent verified reports inside bounds,
+// newest first.
+func (r *SafeRouteRepoPG) ListIncidentsInArea(ctx context.Context, bounds model.BoundingBox) (_ []model.IncidentNearRoute, err error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			r.id,
+			rt.name,
+			rtopic.name,
+			r.latitude,
+			r.longitude,
+			r.created_at
+		FROM reports r
+		JOIN risk_types rt ON r.risk_type_id = rt.id
+		JOIN risk_topics rtopic ON r.risk_topic_id = rtopic.id
+		WHERE r.status = 'verified'
+			AND r.latitude BETWEEN $1 AND $2
+			AND r.longitude BETWEEN $3 AND $4
+			AND r.created_at > NOW() - INTERVAL '90 days'
+		ORDER BY r.created_at DESC
+		LIMIT 5000
+	`, bounds.MinLat, bounds.MaxLat, bounds.MinLon, bounds.MaxLon)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query incidents in area: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+
+	now := time.Now()
+	var incidents []model.IncidentNearRoute
+	for rows.Next() {
+		var incident model.IncidentNearRoute
+		if err := rows.Scan(
+			&incident.ReportID,
+			&incident.RiskType,
+			&incident.RiskTopic,
+			&incident.Latitude,
+			&incident.Longitude,
+			&incident.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan incident: %w", err)
+		}
+
+		incident.DaysAgo = int(now.Sub(incident.CreatedAt).Hours() / hoursPerDay)
+		incidents = append(incidents, incident)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating incidents in area: %w", err)
+	}
+
+	return incidents, nil
+}
+
+// ListHeatmapIncidents groups the verified public reports inside the
+// heatmap's bounds and period by spot, risk type and topic, busiest first. Each report
+// decays by the half-life of its topic's rule, else its type's, else the
+// default, as the weighting model picks them.
+func (r *SafeRouteRepoPG) ListHeatmapIncidents(ctx context.Context, params repository.IncidentHeatmapParams) (_ []model.HeatmapIncidentGroup, err error) {
 	query := `
-		SELECT 
+		SELECT
 			r.latitude,
 			r.longitude,
-			rt.name as risk_type,
-			COUNT(*) as report_count
+			rt.name,
+			COALESCE(rtopic.name, ''),
+			COUNT(*),
+			SUM(POWER(0.5, (GREATEST(EXTRACT(EPOCH FROM now() - r.created_at), 0) / 86400)::float8
+				/ COALESCE(topic_rule.half_life_days, type_rule.half_life_days, $5)))
 		FROM reports r
 		JOIN risk_types rt ON r.risk_type_id = rt.id
+		LEFT JOIN risk_topics rtopic ON r.risk_topic_id = rtopic.id
+		LEFT JOIN incident_weight_rules topic_rule ON topic_rule.risk_topic_id = r.risk_topic_id
+		LEFT JOIN incident_weight_rules type_rule ON type_rule.risk_type_id = r.risk_type_id
+			AND type_rule.risk_topic_id IS NULL
 		WHERE r.status = 'verified'
+			AND r.is_private = FALSE
 			AND r.latitude BETWEEN $1 AND $2
 			AND r.longitude BETWEEN $3 AND $4
 	`
 
-	args := []interface{}{params.SouthWestLat, params.NorthEastLat, params.SouthWestLon, params.NorthEastLon}
-	argIndex := 5
+	args := []interface{}{
+		params.SouthWestLat, params.NorthEastLat, params.SouthWestLon, params.NorthEastLon,
+		model.DefaultIncidentHalfLifeDays,
+	}
+	argIndex := 6
 
 	if params.StartDate != "" {
 		query += fmt.Sprintf(" AND r.created_at >= $%d", argIndex)
@@ -189,17 +213,19 @@ func (r *SafeRouteRepoPG) GetIncidentsHeatmap(ctx context.Context, params reposi
 	}
 
 	if params.RiskTypeID != "" {
-		query += fmt.Sprintf(" AND r.risk_type_id = $%d", argIndex)
 		riskTypeUUID, err := uuid.Parse(params.RiskTypeID)
 		if err == nil {
+			query += fmt.Sprintf(" AND r.risk_type_id = $%d", argIndex)
 			args = append(args, riskTypeUUID)
 		}
 	}
 
+	// The topics of one spot come back as separate groups, so the limit
+	// leaves room above the heatmap's 500 points.
 	query += `
-		GROUP BY r.latitude, r.longitude, rt.name
-		ORDER BY report_count DESC
-		LIMIT 500
+		GROUP BY r.latitude, r.longitude, rt.name, rtopic.name
+		ORDER BY COUNT(*) DESC
+		LIMIT 1000
 	`
 
 	rows, err := r.db.QueryContext(ctx, query, args...)
@@ -207,103 +233,31 @@ func (r *SafeRouteRepoPG) GetIncidentsHeatmap(ctx context.Context, params reposi
 		return nil, fmt.Errorf("failed to query heatmap: %w", err)
 	}
 	defer func() {
-		if closeErr := rows.Close(); closeErr != nil {
+		if closeErr := rows.Close(); closeErr != nil && err == nil {
 			err = closeErr
 		}
 	}()
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating rows: %w", err)
-	}
-
-	points := []repository.HeatmapPoint{}
-
+	var groups []model.HeatmapIncidentGroup
 	for rows.Next() {
-		var point repository.HeatmapPoint
-		var reportCount int
-
-		err := rows.Scan(
-			&point.Latitude,
-			&point.Longitude,
-			&point.IncidentType,
-			&reportCount,
-		)
-		if err != nil {
-			continue
+		var group model.HeatmapIncidentGroup
+		if err := rows.Scan(
+			&group.Latitude,
+			&group.Longitude,
+			&group.RiskType,
+			&group.RiskTopic,
+			&group.ReportCount,
+			&group.Decay,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan heatmap group: %w", err)
 		}
-
-		point.ReportCount = reportCount
-		point.Weight = r.calculateHeatmapWeight(point.IncidentType, reportCount)
-		points = append(points, point)
-	}
-
-	return points, nil
-}
-
-func (r *SafeRouteRepoPG) generateWaypoints(originLat, originLon, destLat, destLon float64) []model.Waypoint {
-	waypoints := []model.Waypoint{
-		{Latitude: originLat, Longitude: originLon, Sequence: 0},
-	}
-
-	distance := r.haversineDistance(originLat, originLon, destLat, destLon)
-	numIntermediatePoints := int(distance / intermediatePointSpacing)
-
-	if numIntermediatePoints > maxIntermediatePoints {
-		numIntermediatePoints = maxIntermediatePoints
-	}
-
-	if numIntermediatePoints > 0 {
-		for i := 1; i <= numIntermediatePoints; i++ {
-			ratio := float64(i) / float64(numIntermediatePoints+1)
-			lat := originLat + ratio*(destLat-originLat)
-			lon := originLon + ratio*(destLon-originLon)
-			waypoints = append(waypoints, model.Waypoint{
-				Latitude:  lat,
-				Longitude: lon,
-				Sequence:  i,
-			})
-		}
-	}
-
-	waypoints = append(waypoints, model.Waypoint{
-		Latitude:  destLat,
-		Longitude: destLon,
-		Sequence:  len(waypoints),
-	})
-
-	return waypoints
-}
-
-func (r *SafeRouteRepoPG) haversineDistance(lat1, lon1, lat2, lon2 float64) float64 {
-	lat1Rad := lat1 * math.Pi / degreesInCircle
-	lat2Rad := lat2 * math.Pi / degreesInCircle
-	deltaLat := (lat2 - lat1) * math.Pi / degreesInCircle
-	deltaLon := (lon2 - lon1) * math.Pi / degreesInCircle
-
-	a := math.Sin(deltaLat/halfDivisor)*math.Sin(deltaLat/halfDivisor) +
-		math.Cos(lat1Rad)*math.Cos(lat2Rad)*
-			math.Sin(deltaLon/halfDivisor)*math.Sin(deltaLon/halfDivisor)
-
-	c := halfDivisor * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
-
-	return earthRadiusKm * c
-}
-
-func (r *SafeRouteRepoPG) calculateTotalDistance(waypoints []model.Waypoint) float64 {
-	if len(waypoints) < minWaypointCount {
-		return 0
+		groups = append(groups, group)
 	}
-
-	totalDistance := 0.0
-	for i := range len(waypoints) - 1 {
-		distance := r.haversineDistance(
-			waypoints[i].Latitude, waypoints[i].Longitude,
-			waypoints[i+1].Latitude, waypoints[i+1].Longitude,
-		)
-		totalDistance += distance
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating heatmap groups: %w", err)
 	}
 
-	return totalDistance
+	return groups, nil
 }
 
 func (r *SafeRouteRepoPG) calculateBoundingBox(waypoints []model.Waypoint, bufferKm float64) (float64, float64, float64, float64) {
@@ -357,50 +311,3 @@ func (r *SafeRouteRepoPG) buildLineString(waypoints []model.Waypoint) string {
 
 	return lineString
 }
-
-func (r *SafeRouteRepoPG) calculateIncidentWeight(riskType string, daysAgo int, distanceKm float64) float64 {
-	weight := 1.0
-
-	switch {
-	case daysAgo <= recentIncidentDays:
-		weight *= 3.0
-	case daysAgo <= mediumIncidentDays:
-		weight *= 2.0
-	case daysAgo <= oldIncidentDays:
-		weight *= 1.5
-	default:
-		weight *= 1.0
-	}
-
-	if containsRiskType(riskType, highRiskIncidentTypes) {
-		weight *= 2.5
-	} else {
-		weight *= 1.0
-	}
-
-	if distanceKm <= proximityThresholdKm {
-		weight *= 2.0
-	} else if distanceKm <= 1.0 {
-		weight *= 1.5
-	}
-
-	return weight
-}
-
-func (r *SafeRouteRepoPG) calculateHeatmapWeight(incidentType string, reportCount int) float64 {
-	baseWeight := float64(reportCount) / baseWeightDivisor
-
-	if containsRiskType(incidentType, highRiskIncidentTypes) {
-		baseWeight *= 1.5
-	}
-
-	if baseWeight > maxWeightCap {
-		baseWeight = maxWeightCap
-	}
-
-	return baseWeight
-}
-
-func containsRiskType(riskType, types string) bool {
-	return len(riskType) > 0 && len(types) > 0
-}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,11 +15,12 @@ import (
 )
 
 type safetySettingsRepoPG struct {
-	q sqlc.Querier
+	db *sql.DB
+	q  sqlc.Querier
 }
 
 func NewSafetySettingsRepository(db *sql.DB) repository.SafetySettingsRepository {
-	return &safetySettingsRepoPG{q: sqlc.New(db)}
+	return &safetySettingsRepoPG{db: db, q: sqlc.New(db)}
 }
 
 func (r *safetySettingsRepoPG) GetByUserID(ctx context.Context, userID uuid.UUID) (*model.SafetySettings, error) {
@@ -30,11 +32,11 @@ func (r *safetySettingsRepoPG) GetByUserID(ctx context.Context, userID uuid.UUID
 		return nil, err
 	}
 
-	return r.toDomain(row), nil
+	return r.withRiskPreferences(ctx, r.toDomain(row))
 }
 
 func (r *safetySettingsRepoPG) Upsert(ctx context.Context, settings *model.SafetySettings) error {
-	return r.q.UpsertSafetySettings(ctx, sqlc.UpsertSafetySettingsParams{
+	err := r.q.UpsertSafetySettings(ctx, sqlc.UpsertSafetySettingsParams{
 		ID:     settings.ID,
 		UserID: uuidPtrToNullUUID(settings.UserID),
 		NotificationsEnabled: sql.NullBool{
@@ -112,6 +114,20 @@ func (r *safetySettingsRepoPG) Upsert(ctx context.Context, settings *model.Safet
 			Valid: true,
 		},
 	})
+	if err != nil {
+		return err
+	}
+
+	// The upsert keeps the stored id when the user already had settings.
+	var settingsID uuid.UUID
+	if err := r.db.QueryRowContext(ctx,
+		`SELECT id FROM user_safety_settings WHERE user_id = $1`,
+		uuidPtrToNullUUID(settings.UserID),
+	).Scan(&settingsID); err != nil {
+		return fmt.Errorf("failed to resolve safety settings id: %w", err)
+	}
+
+	return r.replaceRiskPreferences(ctx, settingsID, settings.RiskPreferences)
 }
 
 func (r *safetySettingsRepoPG) GetByDeviceID(ctx context.Context, deviceID string) (*model.SafetySettings, error) {
@@ -123,7 +139,7 @@ func (r *safetySettingsRepoPG) GetByDeviceID(ctx context.Context, deviceID strin
 		return nil, err
 	}
 
-	return r.toDomain(row), nil
+	return r.withRiskPreferences(ctx, r.toDomain(row))
 }
 
 func (r *safetySettingsRepoPG) UpsertAnonymous(ctx context.Context, settings *model.SafetySettings) error {
@@ -134,7 +150,7 @@ func (r *safetySettingsRepoPG) UpsertAnonymous(ctx context.Context, settings *mo
 		settingsID = existing.ID
 	}
 
-	return r.q.UpsertAnonymousSafetySettings(ctx, sqlc.UpsertAnonymousSafetySettingsParams{
+	err = r.q.UpsertAnonymousSafetySettings(ctx, sqlc.UpsertAnonymousSafetySettingsParams{
 		ID:                 settingsID,
 		AnonymousSessionID: uuidPtrToNullUUID(settings.AnonymousSessionID),
 		DeviceID:           sql.NullString{String: *settings.DeviceID, Valid: true},
@@ -213,6 +229,37 @@ func (r *safetySettingsRepoPG) UpsertAnonymous(ctx context.Context, settings *mo
 			Valid: true,
 		},
 	})
+	if err != nil {
+		return err
+	}
+
+	return r.replaceRiskPreferences(ctx, settingsID, settings.RiskPreferences)
+}
+
+func (r *safetySettingsRepoPG) ListNightModes(ctx context.Context, userIDs []uuid.UUID, deviceIDs []string) ([]*model.SafetySettings, error) {
+	if len(userIDs) == 0 && len(deviceIDs) == 0 {
+		return []*model.SafetySettings{}, nil
+	}
+
+	rows, err := r.q.ListNightModeSettings(ctx, sqlc.ListNightModeSettingsParams{
+		UserIds:   userIDs,
+		DeviceIds: deviceIDs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list night mode settings: %w", err)
+	}
+
+	settings := make([]*model.SafetySettings, 0, len(rows))
+	for _, row := range rows {
+		settings = append(settings, &model.SafetySettings{
+			UserID:             nullUUIDToPtr(row.UserID),
+			DeviceID:           nullStringToPtr(row.DeviceID),
+			NightModeEnabled:   true,
+			NightModeStartTime: row.NightModeStartTime.Time,
+			NightModeEndTime:   row.NightModeEndTime.Time,
+		})
+	}
+	return settings, nil
 }
 
 func (r *safetySettingsRepoPG) toDomain(row sqlc.UserSafetySetting) *model.SafetySettings {
@@ -243,3 +290,77 @@ func (r *safetySettingsRepoPG) toDomain(row sqlc.UserSafetySetting) *model.Safet
 		UpdatedAt:                    row.UpdatedAt.Time,
 	}
 }
+
+func (r *safetySettingsRepoPG) withRiskPreferences(ctx context.Context, settings *model.SafetySettings) (_ *model.SafetySettings, err error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT risk_type_id, risk_topic_id, enabled, radius_meters
+		FROM notification_risk_preferences
+		WHERE settings_id = $1
+		ORDER BY risk_type_id, risk_topic_id NULLS FIRST
+	`, settings.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list risk notification preferences: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			err = closeErr
+		}
+	}()
+
+	prefs := make([]model.RiskNotificationPreference, 0)
+	for rows.Next() {
+		var (
+			pref   model.RiskNotificationPreference
+			topic  uuid.NullUUID
+			radius sql.NullInt32
+		)
+		if err := rows.Scan(&pref.RiskTypeID, &topic, &pref.Enabled, &radius); err != nil {
+			return nil, fmt.Errorf("failed to scan risk notification preference: %w", err)
+		}
+		pref.RiskTopicID = nullUUIDToPtr(topic)
+		if radius.Valid {
+			meters := int(radius.Int32)
+			pref.RadiusMeters = &meters
+		}
+		prefs = append(prefs, pref)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate risk notification preferences: %w", err)
+	}
+
+	settings.RiskPreferences = prefs
+	return settings, nil
+}
+
+// replaceRiskPreferences stores prefs as the complete set for the settings,
+// dropping any preference that is no longer listed.
+func (r *safetySettingsRepoPG) replaceRiskPreferences(ctx context.Context, settingsID uuid.UUID, prefs []model.RiskNotificationPreference) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM notification_risk_preferences WHERE settings_id = $1`, settingsID); err != nil {
+		return fmt.Errorf("failed to clear risk notification preferences: %w", err)
+	}
+
+	for _, pref := range prefs {
+		var radius sql.NullInt32
+		if pref.RadiusMeters != nil {
+			radius = sql.NullInt32{Int32: safeIntToInt32(*pref.RadiusMeters), Valid: true}
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO notification_risk_preferences (settings_id, risk_type_id, risk_topic_id, enabled, radius_meters)
+			VALUES ($1, $2, $3, $4, $5)
+		`, settingsID, pref.RiskTypeID, uuidPtrToNullUUID(pref.RiskTopicID), pref.Enabled, radius); err != nil {
+			return fmt.Errorf("failed to save risk notification preference: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit risk notification preferences: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package seeds
+
+import (
+	"context"
+	"database/sql"
+)
+
+// SeedIncidentWeightRules gives violent crime more weight than the default
+// and lets fires fade faster than standing problems such as unlit streets.
+func SeedIncidentWeightRules(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, `
+	INSERT INTO incident_weight_rules (risk_type_id, severity, half_life_days, proximity_falloff_meters)
+	SELECT rt.id, w.severity, w.half_life_days, 1000
+	FROM (VALUES
+		('crime', 2.0, 14.0),
+		('violence', 2.5, 14.0),
+		('accident', 1.5, 7.0),
+		('fire', 1.5, 3.0),
+		('natural_disaster', 1.5, 7.0),
+		('public_safety', 1.5, 30.0)
+	) AS w(risk_type, severity, half_life_days)
+	JOIN risk_types rt ON rt.name = w.risk_type
+	ON CONFLICT DO NOTHING;
+
+	INSERT INTO incident_weight_rules (risk_type_id, risk_topic_id, severity, half_life_days, proximity_falloff_meters)
+	SELECT rt.id, tp.id, w.severity, w.half_life_days, 1000
+	FROM (VALUES
+		('crime', 'assalto_mao_armada', 3.0, 14.0),
+		('violence', 'tiroteio', 3.0, 7.0),
+		('violence', 'sequestro', 3.0, 14.0)
+	) AS w(risk_type, topic, severity, half_life_days)
+	JOIN risk_types rt ON rt.name = w.risk_type
+	JOIN risk_topics tp ON tp.risk_type_id = rt.id AND tp.name = w.topic
+	ON CONFLICT DO NOTHING;
+	`)
+	return err
+}
@@ -21,7 +21,10 @@ func SeedPermissions(ctx context.Context, db *sql.DB) error {
 		('report', 'resolve'),
 		('risk_type', 'read'),
 		('risk_type', 'update'),
-		('risk_type', 'manage')
+		('risk_type', 'manage'),
+		('notification', 'read'),
+		('danger_zone', 'manage'),
+		('incident_weight', 'manage')
 	ON CONFLICT (resource, action) DO NOTHING;
 	`)
 	return err
@@ -0,0 +1,62 @@
+package seeds
+
+import (
+	"context"
+	"database/sql"
+)
+
+// SeedRiskKeywords adds the words people commonly use in SMS reports.
+// Keywords are lower-case without accents, as the parser normalizes input.
+func SeedRiskKeywords(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, `
+	INSERT INTO risk_keywords (keyword, risk_type_id, risk_topic_id)
+	SELECT k.keyword, rt.id, tp.id
+	FROM (VALUES
+		('assalto', 'crime', 'assalto'),
+		('assaltaram', 'crime', 'assalto'),
+		('gatuno', 'crime', 'roubo'),
+		('gatunos', 'crime', 'roubo'),
+		('roubo', 'crime', 'roubo'),
+		('roubaram', 'crime', 'roubo'),
+		('furto', 'crime', 'furtos'),
+		('telemovel', 'crime', 'furto_telemovel'),
+		('robbery', 'crime', 'assalto'),
+		('theft', 'crime', 'furtos'),
+		('tiros', 'violence', 'tiroteio'),
+		('disparos', 'violence', 'tiroteio'),
+		('rapto', 'violence', 'sequestro'),
+		('porrada', 'violence', 'agressao_fisica'),
+		('briga', 'violence', 'agressao_fisica'),
+		('acidente', 'accident', 'acidente_transito'),
+		('atropelado', 'accident', 'atropelamento'),
+		('accident', 'accident', 'acidente_transito'),
+		('fogo', 'fire', 'incendio_residencial'),
+		('incendio', 'fire', 'incendio_residencial'),
+		('queimada', 'fire', 'incendio_florestal'),
+		('fire', 'fire', 'incendio_residencial'),
+		('cheia', 'natural_disaster', 'enchente'),
+		('chuva', 'natural_disaster', 'enchente'),
+		('alagado', 'natural_disaster', 'inundacao'),
+		('flood', 'natural_disaster', 'enchente'),
+		('desabamento', 'natural_disaster', 'deslizamento'),
+		('ravina', 'natural_disaster', 'deslizamento'),
+		('doente', 'health', 'emergencia_medica'),
+		('colera', 'health', 'surto_doenca'),
+		('malaria', 'health', 'doenca_infecciosa'),
+		('luz', 'infrastructure', 'queda_energia'),
+		('energia', 'infrastructure', 'queda_energia'),
+		('agua', 'infrastructure', 'queda_agua'),
+		('buraco', 'infrastructure', 'buraco_via'),
+		('lixo', 'environment', 'lixo_acumulado'),
+		('esgoto', 'environment', 'esgoto_aberto'),
+		('escuro', 'public_safety', 'rua_escura'),
+		('engarrafamento', 'traffic', 'congestionamento'),
+		('transito', 'traffic', 'congestionamento'),
+		('bloqueada', 'traffic', 'via_bloqueada')
+	) AS k(keyword, risk_type, topic)
+	JOIN risk_types rt ON rt.name = k.risk_type
+	LEFT JOIN risk_topics tp ON tp.risk_type_id = rt.id AND tp.name = k.topic
+	ON CONFLICT (keyword) DO NOTHING;
+	`)
+	return err
+}
@@ -13,6 +13,8 @@ func RunAll(ctx context.Context, db *sql.DB) error {
 		SeedRoles,
 		SeedRiskTypes,
 		SeedRiskTopics,
+		SeedRiskKeywords,
+		SeedIncidentWeightRules,
 		SeedEntities,
 		SeedPermissions,
 		SeedRolePermissions,
@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
+)
+
+type smsReportRepoPG struct {
+	db *sql.DB
+}
+
+func NewSMSReportRepository(db *sql.DB) repository.SMSReportRepository {
+	return &smsReportRepoPG{db: db}
+}
+
+func (r *smsReportRepoPG) Create(ctx context.Context, report *model.SMSReport) error {
+	query := `
+		INSERT INTO sms_reports (
+			id, reference_code, provider_message_id, phone, body,
+			user_id, report_id, status, rejection_reason, created_at
+		) VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $7, $8, NULLIF($9, ''), $10)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		report.ID,
+		report.ReferenceCode,
+		report.ProviderMessageID,
+		report.Phone,
+		report.Body,
+		uuidPtrToNullUUID(report.UserID),
+		uuidPtrToNullUUID(report.ReportID),
+		string(report.Status),
+		report.RejectionReason,
+		report.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create sms report: %w", err)
+	}
+
+	return nil
+}
+
+func (r *smsReportRepoPG) FindByProviderMessageID(ctx context.Context, providerMessageID string) (*model.SMSReport, error) {
+	query := `
+		SELECT id, reference_code, COALESCE(provider_message_id, ''), phone, body,
+		       user_id, report_id, status, COALESCE(rejection_reason, ''), created_at
+		FROM sms_reports
+		WHERE provider_message_id = $1
+	`
+
+	var report model.SMSReport
+	var userID, reportID uuid.NullUUID
+	var status string
+
+	err := r.db.QueryRowContext(ctx, query, providerMessageID).Scan(
+		&report.ID,
+		&report.ReferenceCode,
+		&report.ProviderMessageID,
+		&report.Phone,
+		&report.Body,
+		&userID,
+		&reportID,
+		&status,
+		&report.RejectionReason,
+		&report.CreatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil //nolint:nilnil // unseen message is not an error
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sms report: %w", err)
+	}
+
+	report.UserID = nullUUIDToPtr(userID)
+	report.ReportID = nullUUIDToPtr(reportID)
+	report.Status = model.SMSReportStatus(status)
+
+	return &report, nil
+}
+
+func (r *smsReportRepoPG) ListRiskKeywords(ctx context.Context) ([]model.RiskKeyword, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT keyword, risk_type_id, risk_topic_id FROM risk_keywords`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list risk keywords: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			err = closeErr
+		}
+	}()
+
+	var keywords []model.RiskKeyword
+	for rows.Next() {
+		var kw model.RiskKeyword
+		var topicID uuid.NullUUID
+		if err := rows.Scan(&kw.Keyword, &kw.RiskTypeID, &topicID); err != nil {
+			return nil, fmt.Errorf("failed to scan risk keyword: %w", err)
+		}
+		kw.RiskTopicID = nullUUIDToPtr(topicID)
+		keywords = append(keywords, kw)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating risk keywords: %w", err)
+	}
+
+	return keywords, nil
+}
+
+func (r *smsReportRepoPG) ListPlaces(ctx context.Context) ([]model.Place, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT name, kind, province, COALESCE(municipality, ''), latitude, longitude
+		FROM places
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list places: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			err = closeErr
+		}
+	}()
+
+	var places []model.Place
+	for rows.Next() {
+		var p model.Place
+		var kind string
+		if err := rows.Scan(&p.Name, &kind, &p.Province, &p.Municipality, &p.Latitude, &p.Longitude); err != nil {
+			return nil, fmt.Errorf("failed to scan place: %w", err)
+		}
+		p.Kind = model.PlaceKind(kind)
+		places = append(places, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating places: %w", err)
+	}
+
+	return places, nil
+}
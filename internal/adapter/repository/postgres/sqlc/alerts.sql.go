@@ -703,6 +703,107 @@ func (q *Queries) ListActiveAlerts(ctx context.Context) ([]ListActiveAlertsRow,
 	return items, nil
 }
 
+const listActiveAlertsNear = `-- name: ListActiveAlertsNear :many
+SELECT 
+    a.id, a.created_by, a.anonymous_session_id, a.device_id, a.risk_type_id, a.risk_topic_id, a.message, a.latitude, a.longitude, a.province, a.municipality, a.neighborhood, a.address, a.radius_meters, a.severity, a.status, a.created_at, a.expires_at, a.resolved_at,
+    rt.name as risk_type_name,
+    rt.icon_path as risk_type_icon_path,
+    rtopic.name as risk_topic_name,
+    rtopic.icon_path as risk_topic_icon_path
+FROM alerts a
+LEFT JOIN risk_types rt ON a.risk_type_id = rt.id
+LEFT JOIN risk_topics rtopic ON a.risk_topic_id = rtopic.id
+WHERE a.status = 'active' AND (a.expires_at IS NULL OR a.expires_at > NOW()) AND rt.is_enabled = TRUE
+  AND earth_distance(ll_to_earth(a.latitude, a.longitude), ll_to_earth($1::FLOAT8, $2::FLOAT8)) <= $3::FLOAT8
+ORDER BY a.created_at DESC
+LIMIT $4
+`
+
+type ListActiveAlertsNearParams struct {
+	Latitude     float64 `json:"latitude"`
+	Longitude    float64 `json:"longitude"`
+	RadiusMeters float64 `json:"radius_meters"`
+	MaxResults   int32   `json:"max_results"`
+}
+
+type ListActiveAlertsNearRow struct {
+	ID                 uuid.UUID      `json:"id"`
+	CreatedBy          uuid.NullUUID  `json:"created_by"`
+	AnonymousSessionID uuid.NullUUID  `json:"anonymous_session_id"`
+	DeviceID           sql.NullString `json:"device_id"`
+	RiskTypeID         uuid.UUID      `json:"risk_type_id"`
+	RiskTopicID        uuid.NullUUID  `json:"risk_topic_id"`
+	Message            string         `json:"message"`
+	Latitude           float64        `json:"latitude"`
+	Longitude          float64        `json:"longitude"`
+	Province           sql.NullString `json:"province"`
+	Municipality       sql.NullString `json:"municipality"`
+	Neighborhood       sql.NullString `json:"neighborhood"`
+	Address            sql.NullString `json:"address"`
+	RadiusMeters       int32          `json:"radius_meters"`
+	Severity           interface{}    `json:"severity"`
+	Status             interface{}    `json:"status"`
+	CreatedAt          sql.NullTime   `json:"created_at"`
+	ExpiresAt          sql.NullTime   `json:"expires_at"`
+	ResolvedAt         sql.NullTime   `json:"resolved_at"`
+	RiskTypeName       sql.NullString `json:"risk_type_name"`
+	RiskTypeIconPath   sql.NullString `json:"risk_type_icon_path"`
+	RiskTopicName      sql.NullString `json:"risk_topic_name"`
+	RiskTopicIconPath  sql.NullString `json:"risk_topic_icon_path"`
+}
+
+func (q *Queries) ListActiveAlertsNear(ctx context.Context, arg ListActiveAlertsNearParams) ([]ListActiveAlertsNearRow, error) {
+	rows, err := q.db.QueryContext(ctx, listActiveAlertsNear,
+		arg.Latitude,
+		arg.Longitude,
+		arg.RadiusMeters,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListActiveAlertsNearRow{}
+	for rows.Next() {
+		var i ListActiveAlertsNearRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedBy,
+			&i.AnonymousSessionID,
+			&i.DeviceID,
+			&i.RiskTypeID,
+			&i.RiskTopicID,
+			&i.Message,
+			&i.Latitude,
+			&i.Longitude,
+			&i.Province,
+			&i.Municipality,
+			&i.Neighborhood,
+			&i.Address,
+			&i.RadiusMeters,
+			&i.Severity,
+			&i.Status,
+			&i.CreatedAt,
+			&i.ExpiresAt,
+			&i.ResolvedAt,
+			&i.RiskTypeName,
+			&i.RiskTypeIconPath,
+			&i.RiskTopicName,
+			&i.RiskTopicIconPath,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const resolveAlert = `-- name: ResolveAlert :exec
 UPDATE alerts
 SET status = 'resolved', resolved_at = NOW()
@@ -124,6 +124,7 @@ type Querier interface {
 	IsUserSubscribed(ctx context.Context, arg IsUserSubscribedParams) (bool, error)
 	IsUserSubscribedToAlert(ctx context.Context, arg IsUserSubscribedToAlertParams) (bool, error)
 	ListActiveAlerts(ctx context.Context) ([]ListActiveAlertsRow, error)
+	ListActiveAlertsNear(ctx context.Context, arg ListActiveAlertsNearParams) ([]ListActiveAlertsNearRow, error)
 	ListActiveLocationSharingsByDeviceID(ctx context.Context, deviceID sql.NullString) ([]LocationSharing, error)
 	ListActiveLocationSharingsByUserID(ctx context.Context, userID uuid.NullUUID) ([]LocationSharing, error)
 	ListAllDeviceTokensExceptUser(ctx context.Context, id uuid.UUID) ([]ListAllDeviceTokensExceptUserRow, error)
@@ -135,6 +136,7 @@ type Querier interface {
 	ListDeviceTokensForReportNotification(ctx context.Context, arg ListDeviceTokensForReportNotificationParams) ([]ListDeviceTokensForReportNotificationRow, error)
 	ListEntities(ctx context.Context) ([]Entity, error)
 	ListNearbyUsers(ctx context.Context) ([]User, error)
+	ListNightModeSettings(ctx context.Context, arg ListNightModeSettingsParams) ([]ListNightModeSettingsRow, error)
 	ListPermissions(ctx context.Context) ([]Permission, error)
 	ListReportsByIDs(ctx context.Context, dollar_1 []uuid.UUID) ([]ListReportsByIDsRow, error)
 	ListReportsByStatus(ctx context.Context, status interface{}) ([]ListReportsByStatusRow, error)
@@ -145,6 +147,7 @@ type Querier interface {
 	ListRiskTypes(ctx context.Context) ([]RiskType, error)
 	ListRoles(ctx context.Context) ([]Role, error)
 	ListUserNotifications(ctx context.Context, arg ListUserNotificationsParams) ([]Notification, error)
+	ListVerifiedReportsNear(ctx context.Context, arg ListVerifiedReportsNearParams) ([]ListVerifiedReportsNearRow, error)
 	MarkAccountVerified(ctx context.Context, id uuid.UUID) error
 	MarkAlertSeen(ctx context.Context, arg MarkAlertSeenParams) error
 	// Marca uma sessão anônima como migrada
@@ -8,6 +8,7 @@ package sqlc
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/lib/pq"
@@ -778,6 +779,123 @@ func (q *Queries) ListReportsWithPagination(ctx context.Context, arg ListReports
 	return items, nil
 }
 
+const listVerifiedReportsNear = `-- name: ListVerifiedReportsNear :many
+SELECT
+    r.id, r.user_id, r.risk_type_id, r.risk_topic_id, r.description,
+    r.latitude, r.longitude, r.province, r.municipality, r.neighborhood,
+    r.address, r.image_url, r.status, r.reviewed_by, r.resolved_at,
+    r.verification_count, r.rejection_count, r.expires_at, r.is_private,
+    r.created_at, r.updated_at,
+    rt.name as risk_type_name,
+    rt.icon_path as risk_type_icon_path,
+    rtopic.name as risk_topic_name,
+    rtopic.icon_path as risk_topic_icon_path,
+    earth_distance(ll_to_earth(r.latitude, r.longitude), ll_to_earth($1::FLOAT8, $2::FLOAT8))::FLOAT8 AS distance_meters
+FROM reports r
+LEFT JOIN risk_types rt ON r.risk_type_id = rt.id
+LEFT JOIN risk_topics rtopic ON r.risk_topic_id = rtopic.id
+WHERE r.status = 'verified' AND r.is_private = FALSE AND rt.is_enabled = TRUE
+  AND r.created_at >= $3::TIMESTAMPTZ
+  AND ll_to_earth(r.latitude, r.longitude) <@
+      earth_box(ll_to_earth($1::FLOAT8, $2::FLOAT8), $4::FLOAT8)
+  AND earth_distance(ll_to_earth(r.latitude, r.longitude), ll_to_earth($1::FLOAT8, $2::FLOAT8)) <= $4::FLOAT8
+ORDER BY distance_meters
+LIMIT $5
+`
+
+type ListVerifiedReportsNearParams struct {
+	Latitude     float64   `json:"latitude"`
+	Longitude    float64   `json:"longitude"`
+	Since        time.Time `json:"since"`
+	RadiusMeters float64   `json:"radius_meters"`
+	MaxResults   int32     `json:"max_results"`
+}
+
+type ListVerifiedReportsNearRow struct {
+	ID                uuid.UUID      `json:"id"`
+	UserID            uuid.UUID      `json:"user_id"`
+	RiskTypeID        uuid.UUID      `json:"risk_type_id"`
+	RiskTopicID       uuid.NullUUID  `json:"risk_topic_id"`
+	Description       sql.NullString `json:"description"`
+	Latitude          float64        `json:"latitude"`
+	Longitude         float64        `json:"longitude"`
+	Province          sql.NullString `json:"province"`
+	Municipality      sql.NullString `json:"municipality"`
+	Neighborhood      sql.NullString `json:"neighborhood"`
+	Address           sql.NullString `json:"address"`
+	ImageUrl          sql.NullString `json:"image_url"`
+	Status            interface{}    `json:"status"`
+	ReviewedBy        uuid.NullUUID  `json:"reviewed_by"`
+	ResolvedAt        sql.NullTime   `json:"resolved_at"`
+	VerificationCount sql.NullInt32  `json:"verification_count"`
+	RejectionCount    sql.NullInt32  `json:"rejection_count"`
+	ExpiresAt         sql.NullTime   `json:"expires_at"`
+	IsPrivate         bool           `json:"is_private"`
+	CreatedAt         sql.NullTime   `json:"created_at"`
+	UpdatedAt         sql.NullTime   `json:"updated_at"`
+	RiskTypeName      sql.NullString `json:"risk_type_name"`
+	RiskTypeIconPath  sql.NullString `json:"risk_type_icon_path"`
+	RiskTopicName     sql.NullString `json:"risk_topic_name"`
+	RiskTopicIconPath sql.NullString `json:"risk_topic_icon_path"`
+	DistanceMeters    float64        `json:"distance_meters"`
+}
+
+func (q *Queries) ListVerifiedReportsNear(ctx context.Context, arg ListVerifiedReportsNearParams) ([]ListVerifiedReportsNearRow, error) {
+	rows, err := q.db.QueryContext(ctx, listVerifiedReportsNear,
+		arg.Latitude,
+		arg.Longitude,
+		arg.Since,
+		arg.RadiusMeters,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListVerifiedReportsNearRow{}
+	for rows.Next() {
+		var i ListVerifiedReportsNearRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.RiskTypeID,
+			&i.RiskTopicID,
+			&i.Description,
+			&i.Latitude,
+			&i.Longitude,
+			&i.Province,
+			&i.Municipality,
+			&i.Neighborhood,
+			&i.Address,
+			&i.ImageUrl,
+			&i.Status,
+			&i.ReviewedBy,
+			&i.ResolvedAt,
+			&i.VerificationCount,
+			&i.RejectionCount,
+			&i.ExpiresAt,
+			&i.IsPrivate,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.RiskTypeName,
+			&i.RiskTypeIconPath,
+			&i.RiskTopicName,
+			&i.RiskTopicIconPath,
+			&i.DistanceMeters,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const rejectReport = `-- name: RejectReport :exec
 UPDATE reports
 SET status = 'rejected',
@@ -105,6 +105,56 @@ func (q *Queries) GetSafetySettingsByUserID(ctx context.Context, userID uuid.Nul
 	return i, err
 }
 
+const listNightModeSettings = `-- name: ListNightModeSettings :many
+SELECT user_id, device_id, night_mode_start_time, night_mode_end_time
+FROM user_safety_settings
+WHERE night_mode_enabled = TRUE
+  AND (
+    user_id = ANY($1::uuid[]) OR
+    device_id = ANY($2::text[])
+  )
+`
+
+type ListNightModeSettingsParams struct {
+	UserIds   []uuid.UUID `json:"user_ids"`
+	DeviceIds []string    `json:"device_ids"`
+}
+
+type ListNightModeSettingsRow struct {
+	UserID             uuid.NullUUID  `json:"user_id"`
+	DeviceID           sql.NullString `json:"device_id"`
+	NightModeStartTime sql.NullTime   `json:"night_mode_start_time"`
+	NightModeEndTime   sql.NullTime   `json:"night_mode_end_time"`
+}
+
+func (q *Queries) ListNightModeSettings(ctx context.Context, arg ListNightModeSettingsParams) ([]ListNightModeSettingsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listNightModeSettings, pq.Array(arg.UserIds), pq.Array(arg.DeviceIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListNightModeSettingsRow{}
+	for rows.Next() {
+		var i ListNightModeSettingsRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.DeviceID,
+			&i.NightModeStartTime,
+			&i.NightModeEndTime,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const upsertAnonymousSafetySettings = `-- name: UpsertAnonymousSafetySettings :exec
 INSERT INTO user_safety_settings (
     id, user_id, anonymous_session_id, device_id,
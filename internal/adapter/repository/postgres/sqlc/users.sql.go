@@ -284,21 +284,31 @@ WHERE a.device_fcm_token IS NOT NULL
   )
   AND (
     s.id IS NULL OR
-    s.notification_alert_radius_mins >= CAST((
+    CAST((
       6371000 * acos(
         cos(radians($1::DOUBLE PRECISION)) * cos(radians(a.latitude)) *
         cos(radians(a.longitude) - radians($2::DOUBLE PRECISION)) +
         sin(radians($1::DOUBLE PRECISION)) * sin(radians(a.latitude))
       )
-    ) AS INT)
+    ) AS INT) <= COALESCE((
+      SELECT CASE WHEN p.enabled THEN COALESCE(p.radius_meters, s.notification_alert_radius_mins) ELSE -1 END
+      FROM notification_risk_preferences p
+      WHERE p.settings_id = s.id
+        AND p.risk_type_id = $5::UUID
+        AND (p.risk_topic_id IS NULL OR p.risk_topic_id = $6::UUID)
+      ORDER BY p.risk_topic_id IS NULL
+      LIMIT 1
+    ), s.notification_alert_radius_mins)
   )
 `
 
 type ListAnonymousTokensForAlertNotificationParams struct {
-	Latitude      float64 `json:"latitude"`
-	Longitude     float64 `json:"longitude"`
-	RadiusMeters  float64 `json:"radius_meters"`
-	SeverityLevel string  `json:"severity_level"`
+	Latitude      float64   `json:"latitude"`
+	Longitude     float64   `json:"longitude"`
+	RadiusMeters  float64   `json:"radius_meters"`
+	SeverityLevel string    `json:"severity_level"`
+	RiskTypeID    uuid.UUID `json:"risk_type_id"`
+	RiskTopicID   uuid.UUID `json:"risk_topic_id"`
 }
 
 type ListAnonymousTokensForAlertNotificationRow struct {
@@ -312,6 +322,8 @@ func (q *Queries) ListAnonymousTokensForAlertNotification(ctx context.Context, a
 		arg.Longitude,
 		arg.RadiusMeters,
 		arg.SeverityLevel,
+		arg.RiskTypeID,
+		arg.RiskTopicID,
 	)
 	if err != nil {
 		return nil, err
@@ -363,21 +375,31 @@ WHERE a.device_fcm_token IS NOT NULL
   )
   AND (
     s.id IS NULL OR
-    s.notification_report_radius_mins >= CAST((
+    CAST((
       6371000 * acos(
         cos(radians($1::DOUBLE PRECISION)) * cos(radians(a.latitude)) *
         cos(radians(a.longitude) - radians($2::DOUBLE PRECISION)) +
         sin(radians($1::DOUBLE PRECISION)) * sin(radians(a.latitude))
       )
-    ) AS INT)
+    ) AS INT) <= COALESCE((
+      SELECT CASE WHEN p.enabled THEN COALESCE(p.radius_meters, s.notification_report_radius_mins) ELSE -1 END
+      FROM notification_risk_preferences p
+      WHERE p.settings_id = s.id
+        AND p.risk_type_id = $5::UUID
+        AND (p.risk_topic_id IS NULL OR p.risk_topic_id = $6::UUID)
+      ORDER BY p.risk_topic_id IS NULL
+      LIMIT 1
+    ), s.notification_report_radius_mins)
   )
 `
 
 type ListAnonymousTokensForReportNotificationParams struct {
-	Latitude     float64 `json:"latitude"`
-	Longitude    float64 `json:"longitude"`
-	RadiusMeters float64 `json:"radius_meters"`
-	IsVerified   bool    `json:"is_verified"`
+	Latitude     float64   `json:"latitude"`
+	Longitude    float64   `json:"longitude"`
+	RadiusMeters float64   `json:"radius_meters"`
+	IsVerified   bool      `json:"is_verified"`
+	RiskTypeID   uuid.UUID `json:"risk_type_id"`
+	RiskTopicID  uuid.UUID `json:"risk_topic_id"`
 }
 
 type ListAnonymousTokensForReportNotificationRow struct {
@@ -391,6 +413,8 @@ func (q *Queries) ListAnonymousTokensForReportNotification(ctx context.Context,
 		arg.Longitude,
 		arg.RadiusMeters,
 		arg.IsVerified,
+		arg.RiskTypeID,
+		arg.RiskTopicID,
 	)
 	if err != nil {
 		return nil, err
@@ -452,26 +476,36 @@ func (q *Queries) ListDeviceTokensByUserIDs(ctx context.Context, dollar_1 []uuid
 const listDeviceTokensForAlertNotification = `-- name: ListDeviceTokensForAlertNotification :many
 SELECT DISTINCT u.device_fcm_token, u.device_language, u.id as user_id
 FROM users u
+JOIN UNNEST($1::uuid[], $2::INT[]) AS r(user_id, distance_meters) ON r.user_id = u.id
 LEFT JOIN user_safety_settings s ON s.user_id = u.id
 WHERE u.deleted_at IS NULL
   AND u.device_fcm_token IS NOT NULL
-  AND u.id IN (SELECT UNNEST($1::uuid[]))
   AND (s.id IS NULL OR s.notifications_enabled = true)
   AND (
     s.id IS NULL OR
-    $2::TEXT = ANY(s.notification_alert_types) OR
+    $3::TEXT = ANY(s.notification_alert_types) OR
     'all' = ANY(s.notification_alert_types)
   )
   AND (
     s.id IS NULL OR
-    s.notification_alert_radius_mins >= $3::INT
+    r.distance_meters <= COALESCE((
+      SELECT CASE WHEN p.enabled THEN COALESCE(p.radius_meters, s.notification_alert_radius_mins) ELSE -1 END
+      FROM notification_risk_preferences p
+      WHERE p.settings_id = s.id
+        AND p.risk_type_id = $4::UUID
+        AND (p.risk_topic_id IS NULL OR p.risk_topic_id = $5::UUID)
+      ORDER BY p.risk_topic_id IS NULL
+      LIMIT 1
+    ), s.notification_alert_radius_mins)
   )
 `
 
 type ListDeviceTokensForAlertNotificationParams struct {
-	UserIds        []uuid.UUID `json:"user_ids"`
-	SeverityLevel  string      `json:"severity_level"`
-	DistanceMeters int32       `json:"distance_meters"`
+	UserIds         []uuid.UUID `json:"user_ids"`
+	DistancesMeters []int32     `json:"distances_meters"`
+	SeverityLevel   string      `json:"severity_level"`
+	RiskTypeID      uuid.UUID   `json:"risk_type_id"`
+	RiskTopicID     uuid.UUID   `json:"risk_topic_id"`
 }
 
 type ListDeviceTokensForAlertNotificationRow struct {
@@ -481,7 +515,13 @@ type ListDeviceTokensForAlertNotificationRow struct {
 }
 
 func (q *Queries) ListDeviceTokensForAlertNotification(ctx context.Context, arg ListDeviceTokensForAlertNotificationParams) ([]ListDeviceTokensForAlertNotificationRow, error) {
-	rows, err := q.db.QueryContext(ctx, listDeviceTokensForAlertNotification, pq.Array(arg.UserIds), arg.SeverityLevel, arg.DistanceMeters)
+	rows, err := q.db.QueryContext(ctx, listDeviceTokensForAlertNotification,
+		pq.Array(arg.UserIds),
+		pq.Array(arg.DistancesMeters),
+		arg.SeverityLevel,
+		arg.RiskTypeID,
+		arg.RiskTopicID,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -506,26 +546,36 @@ func (q *Queries) ListDeviceTokensForAlertNotification(ctx context.Context, arg
 const listDeviceTokensForReportNotification = `-- name: ListDeviceTokensForReportNotification :many
 SELECT DISTINCT u.device_fcm_token, u.device_language, u.id as user_id
 FROM users u
+JOIN UNNEST($1::uuid[], $2::INT[]) AS r(user_id, distance_meters) ON r.user_id = u.id
 LEFT JOIN user_safety_settings s ON s.user_id = u.id
 WHERE u.deleted_at IS NULL
   AND u.device_fcm_token IS NOT NULL
-  AND u.id IN (SELECT UNNEST($1::uuid[]))
   AND (s.id IS NULL OR s.notifications_enabled = true)
   AND (
     s.id IS NULL OR
     'all' = ANY(s.notification_report_types) OR
-    ($2::BOOLEAN = true AND 'verified' = ANY(s.notification_report_types))
+    ($3::BOOLEAN = true AND 'verified' = ANY(s.notification_report_types))
   )
   AND (
     s.id IS NULL OR
-    s.notification_report_radius_mins >= $3::INT
+    r.distance_meters <= COALESCE((
+      SELECT CASE WHEN p.enabled THEN COALESCE(p.radius_meters, s.notification_report_radius_mins) ELSE -1 END
+      FROM notification_risk_preferences p
+      WHERE p.settings_id = s.id
+        AND p.risk_type_id = $4::UUID
+        AND (p.risk_topic_id IS NULL OR p.risk_topic_id = $5::UUID)
+      ORDER BY p.risk_topic_id IS NULL
+      LIMIT 1
+    ), s.notification_report_radius_mins)
   )
 `
 
 type ListDeviceTokensForReportNotificationParams struct {
-	UserIds        []uuid.UUID `json:"user_ids"`
-	IsVerified     bool        `json:"is_verified"`
-	DistanceMeters int32       `json:"distance_meters"`
+	UserIds         []uuid.UUID `json:"user_ids"`
+	DistancesMeters []int32     `json:"distances_meters"`
+	IsVerified      bool        `json:"is_verified"`
+	RiskTypeID      uuid.UUID   `json:"risk_type_id"`
+	RiskTopicID     uuid.UUID   `json:"risk_topic_id"`
 }
 
 type ListDeviceTokensForReportNotificationRow struct {
@@ -535,7 +585,13 @@ type ListDeviceTokensForReportNotificationRow struct {
 }
 
 func (q *Queries) ListDeviceTokensForReportNotification(ctx context.Context, arg ListDeviceTokensForReportNotificationParams) ([]ListDeviceTokensForReportNotificationRow, error) {
-	rows, err := q.db.QueryContext(ctx, listDeviceTokensForReportNotification, pq.Array(arg.UserIds), arg.IsVerified, arg.DistanceMeters)
+	rows, err := q.db.QueryContext(ctx, listDeviceTokensForReportNotification,
+		pq.Array(arg.UserIds),
+		pq.Array(arg.DistancesMeters),
+		arg.IsVerified,
+		arg.RiskTypeID,
+		arg.RiskTopicID,
+	)
 	if err != nil {
 		return nil, err
 	}
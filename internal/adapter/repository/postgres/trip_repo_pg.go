@@ -0,0 +1,251 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
+)
+
+const (
+	tripColumns = `
+	id, user_id, status, mode, route, started_at, expected_arrival_at, ended_at,
+	last_latitude, last_longitude, last_location_at, stop_latitude, stop_longitude, stopped_since,
+	check_in_reason, check_in_requested_at, quiet_until, escalated_at, version, created_at, updated_at
+`
+	pqUniqueViolation = "23505"
+)
+
+type TripRepoPG struct {
+	db *sql.DB
+}
+
+func NewTripRepoPG(db *sql.DB) repository.TripRepository {
+	return &TripRepoPG{db: db}
+}
+
+func (r *TripRepoPG) Create(ctx context.Context, trip *model.Trip) error {
+	route, err := json.Marshal(trip.Route)
+	if err != nil {
+		return fmt.Errorf("failed to encode trip route: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO trips (`+tripColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
+	`,
+		trip.ID,
+		trip.UserID,
+		trip.Status,
+		trip.Mode,
+		route,
+		trip.StartedAt,
+		trip.ExpectedArrivalAt,
+		trip.EndedAt,
+		trip.LastLatitude,
+		trip.LastLongitude,
+		trip.LastLocationAt,
+		trip.StopLatitude,
+		trip.StopLongitude,
+		trip.StoppedSince,
+		trip.CheckInReason,
+		trip.CheckInRequestedAt,
+		trip.QuietUntil,
+		trip.EscalatedAt,
+		trip.Version,
+		trip.CreatedAt,
+		trip.UpdatedAt,
+	)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == pqUniqueViolation {
+		return domainErrors.ErrTripAlreadyActive
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create trip: %w", err)
+	}
+	return nil
+}
+
+func (r *TripRepoPG) Update(ctx context.Context, trip *model.Trip) (bool, error) {
+	result, err := executorFor(ctx, r.db).ExecContext(ctx, `
+		UPDATE trips
+		SET status = $3, expected_arrival_at = $4, ended_at = $5,
+			last_latitude = $6, last_longitude = $7, last_location_at = $8,
+			stop_latitude = $9, stop_longitude = $10, stopped_since = $11,
+			check_in_reason = $12, check_in_requested_at = $13, quiet_until = $14, escalated_at = $15,
+			version = version + 1, updated_at = $16
+		WHERE id = $1 AND version = $2
+	`,
+		trip.ID,
+		trip.Version,
+		trip.Status,
+		trip.ExpectedArrivalAt,
+		trip.EndedAt,
+		trip.LastLatitude,
+		trip.LastLongitude,
+		trip.LastLocationAt,
+		trip.StopLatitude,
+		trip.StopLongitude,
+		trip.StoppedSince,
+		trip.CheckInReason,
+		trip.CheckInRequestedAt,
+		trip.QuietUntil,
+		trip.EscalatedAt,
+		trip.UpdatedAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to update trip: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check affected rows: %w", err)
+	}
+	if affected == 0 {
+		return false, nil
+	}
+	trip.Version++
+	return true, nil
+}
+
+func (r *TripRepoPG) FindByID(ctx context.Context, id uuid.UUID) (*model.Trip, error) {
+	return r.find(ctx, `SELECT `+tripColumns+` FROM trips WHERE id = $1`, id)
+}
+
+func (r *TripRepoPG) FindActiveByUserID(ctx context.Context, userID uuid.UUID) (*model.Trip, error) {
+	return r.find(ctx, `SELECT `+tripColumns+` FROM trips WHERE user_id = $1 AND status = 'active'`, userID)
+}
+
+func (r *TripRepoPG) ListActive(ctx context.Context) (_ []*model.Trip, err error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+tripColumns+` FROM trips WHERE status = 'active' ORDER BY started_at`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list active trips: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			err = closeErr
+		}
+	}()
+
+	var trips []*model.Trip
+	for rows.Next() {
+		trip, err := scanTrip(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan trip: %w", err)
+		}
+		trips = append(trips, trip)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating trips: %w", err)
+	}
+
+	return trips, nil
+}
+
+func (r *TripRepoPG) ListEscalationDeliveries(ctx context.Context, tripID uuid.UUID) (_ map[uuid.UUID]bool, err error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT contact_id FROM trip_escalation_deliveries WHERE trip_id = $1`, tripID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trip escalation deliveries: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			err = closeErr
+		}
+	}()
+
+	delivered := make(map[uuid.UUID]bool)
+	for rows.Next() {
+		var contactID uuid.UUID
+		if err := rows.Scan(&contactID); err != nil {
+			return nil, fmt.Errorf("failed to scan trip escalation delivery: %w", err)
+		}
+		delivered[contactID] = true
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating trip escalation deliveries: %w", err)
+	}
+
+	return delivered, nil
+}
+
+func (r *TripRepoPG) RecordEscalationDelivery(ctx context.Context, tripID, contactID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO trip_escalation_deliveries (trip_id, contact_id)
+		VALUES ($1, $2)
+		ON CONFLICT (trip_id, contact_id) DO NOTHING
+	`, tripID, contactID)
+	if err != nil {
+		return fmt.Errorf("failed to record trip escalation delivery: %w", err)
+	}
+	return nil
+}
+
+func (r *TripRepoPG) find(ctx context.Context, query string, arg any) (*model.Trip, error) {
+	trip, err := scanTrip(r.db.QueryRowContext(ctx, query, arg))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domainErrors.ErrTripNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find trip: %w", err)
+	}
+	return trip, nil
+}
+
+func scanTrip(row rowScanner) (*model.Trip, error) {
+	var (
+		trip               model.Trip
+		route              []byte
+		endedAt            sql.NullTime
+		checkInRequestedAt sql.NullTime
+		escalatedAt        sql.NullTime
+	)
+	if err := row.Scan(
+		&trip.ID,
+		&trip.UserID,
+		&trip.Status,
+		&trip.Mode,
+		&route,
+		&trip.StartedAt,
+		&trip.ExpectedArrivalAt,
+		&endedAt,
+		&trip.LastLatitude,
+		&trip.LastLongitude,
+		&trip.LastLocationAt,
+		&trip.StopLatitude,
+		&trip.StopLongitude,
+		&trip.StoppedSince,
+		&trip.CheckInReason,
+		&checkInRequestedAt,
+		&trip.QuietUntil,
+		&escalatedAt,
+		&trip.Version,
+		&trip.CreatedAt,
+		&trip.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(route, &trip.Route); err != nil {
+		return nil, fmt.Errorf("invalid route for trip %s: %w", trip.ID, err)
+	}
+	if endedAt.Valid {
+		trip.EndedAt = &endedAt.Time
+	}
+	if checkInRequestedAt.Valid {
+		trip.CheckInRequestedAt = &checkInRequestedAt.Time
+	}
+	if escalatedAt.Valid {
+		trip.EscalatedAt = &escalatedAt.Time
+	}
+
+	return &trip, nil
+}
@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/risk-place-angola/backend-risk-place/internal/adapter/repository/postgres/sqlc"
+	"github.com/risk-place-angola/backend-risk-place/internal/application/port"
+)
+
+type txContextKey struct{}
+
+// dbExecutor is satisfied by both *sql.DB and *sql.Tx.
+type dbExecutor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type txManagerPG struct {
+	db *sql.DB
+}
+
+func NewTxManager(db *sql.DB) port.TransactionManager {
+	return &txManagerPG{db: db}
+}
+
+// WithinTransaction runs fn with a transaction stored in its context.
+// Repositories that support it pick the transaction up from the context, so
+// their writes commit or roll back together. Nested calls reuse the outer
+// transaction.
+func (m *txManagerPG) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if txFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := fn(context.WithValue(ctx, txContextKey{}, tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func txFromContext(ctx context.Context) *sql.Tx {
+	tx, _ := ctx.Value(txContextKey{}).(*sql.Tx)
+	return tx
+}
+
+// executorFor returns the transaction carried by ctx, or db when there is none.
+func executorFor(ctx context.Context, db *sql.DB) dbExecutor {
+	if tx := txFromContext(ctx); tx != nil {
+		return tx
+	}
+	return db
+}
+
+// queriesFor binds sqlc queries to the transaction carried by ctx, if any.
+func queriesFor(ctx context.Context, q sqlc.Querier) sqlc.Querier {
+	tx := txFromContext(ctx)
+	if tx == nil {
+		return q
+	}
+	if queries, ok := q.(*sqlc.Queries); ok {
+		return queries.WithTx(tx)
+	}
+	return q
+}
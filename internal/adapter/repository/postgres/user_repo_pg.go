@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"math"
 	"time"
 
 	"github.com/google/uuid"
@@ -97,13 +98,30 @@ func (u *userRepoPG) FindByID(ctx context.Context, id uuid.UUID) (*model.User, e
 			Neighborhood: userRow.Neighborhood.String,
 			ZipCode:      userRow.ZipCode.String,
 		},
-		CreatedAt: userRow.CreatedAt.Time,
-		UpdatedAt: userRow.UpdatedAt.Time,
+		HomeAddress:    savedLocationFromRow(userRow.HomeAddressName, userRow.HomeAddressAddress, userRow.HomeAddressLat, userRow.HomeAddressLon),
+		WorkAddress:    savedLocationFromRow(userRow.WorkAddressName, userRow.WorkAddressAddress, userRow.WorkAddressLat, userRow.WorkAddressLon),
+		DeviceToken:    userRow.DeviceFcmToken.String,
+		DeviceLanguage: userRow.DeviceLanguage.String,
+		CreatedAt:      userRow.CreatedAt.Time,
+		UpdatedAt:      userRow.UpdatedAt.Time,
 	}
 
 	return user, nil
 }
 
+func savedLocationFromRow(name, address sql.NullString, lat, lon sql.NullFloat64) *model.SavedLocation {
+	if !lat.Valid || !lon.Valid {
+		return nil
+	}
+
+	return &model.SavedLocation{
+		Name:      name.String,
+		Address:   address.String,
+		Latitude:  lat.Float64,
+		Longitude: lon.Float64,
+	}
+}
+
 func (u *userRepoPG) FindAll(ctx context.Context) ([]*model.User, error) {
 	panic("implement me")
 }
@@ -250,17 +268,13 @@ func (u *userRepoPG) GetUserLanguageAndPhone(ctx context.Context, userID uuid.UU
 	return
 }
 
-func (u *userRepoPG) ListDeviceTokensForAlertNotification(ctx context.Context, userIDs []uuid.UUID, severityLevel string, distanceMeters int) ([]model.DeviceToken, error) {
-	//nolint:gosec // Safe conversion: distance capped at int32 max value
-	distanceInt32 := int32(distanceMeters)
-	if distanceMeters > 2147483647 { //nolint:mnd // int32 max value constant
-		distanceInt32 = 2147483647
-	}
-
+func (u *userRepoPG) ListDeviceTokensForAlertNotification(ctx context.Context, userIDs []uuid.UUID, distancesMeters []int, severityLevel string, riskTypeID, riskTopicID uuid.UUID) ([]model.DeviceToken, error) {
 	rows, err := u.q.ListDeviceTokensForAlertNotification(ctx, sqlc.ListDeviceTokensForAlertNotificationParams{
-		UserIds:        userIDs,
-		SeverityLevel:  severityLevel,
-		DistanceMeters: distanceInt32,
+		UserIds:         userIDs,
+		DistancesMeters: int32Distances(distancesMeters),
+		SeverityLevel:   severityLevel,
+		RiskTypeID:      riskTypeID,
+		RiskTopicID:     riskTopicID,
 	})
 	if err != nil {
 		return nil, err
@@ -280,17 +294,13 @@ func (u *userRepoPG) ListDeviceTokensForAlertNotification(ctx context.Context, u
 	return tokens, nil
 }
 
-func (u *userRepoPG) ListDeviceTokensForReportNotification(ctx context.Context, userIDs []uuid.UUID, isVerified bool, distanceMeters int) ([]model.DeviceToken, error) {
-	//nolint:gosec // Safe conversion: distance capped at int32 max value
-	distanceInt32 := int32(distanceMeters)
-	if distanceMeters > 2147483647 { //nolint:mnd // int32 max value constant
-		distanceInt32 = 2147483647
-	}
-
+func (u *userRepoPG) ListDeviceTokensForReportNotification(ctx context.Context, userIDs []uuid.UUID, distancesMeters []int, isVerified bool, riskTypeID, riskTopicID uuid.UUID) ([]model.DeviceToken, error) {
 	rows, err := u.q.ListDeviceTokensForReportNotification(ctx, sqlc.ListDeviceTokensForReportNotificationParams{
-		UserIds:        userIDs,
-		IsVerified:     isVerified,
-		DistanceMeters: distanceInt32,
+		UserIds:         userIDs,
+		DistancesMeters: int32Distances(distancesMeters),
+		IsVerified:      isVerified,
+		RiskTypeID:      riskTypeID,
+		RiskTopicID:     riskTopicID,
 	})
 	if err != nil {
 		return nil, err
@@ -310,6 +320,16 @@ func (u *userRepoPG) ListDeviceTokensForReportNotification(ctx context.Context,
 	return tokens, nil
 }
 
+// int32Distances converts distances for the INT[] query parameters, capping
+// them at the int32 maximum.
+func int32Distances(distances []int) []int32 {
+	out := make([]int32, len(distances))
+	for i, d := range distances {
+		out[i] = int32(min(d, math.MaxInt32)) //nolint:gosec // capped at the int32 maximum
+	}
+	return out
+}
+
 func NewUserRepoPG(db *sql.DB) repository.UserRepository {
 	return &userRepoPG{
 		q:  sqlc.New(db),
@@ -0,0 +1,297 @@
+package routing
+
+import (
+	"compress/bzip2"
+	"compress/gzip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+)
+
+const mphToKmh = 1.609344
+
+// OSMRoadGraph loads the road network from an OpenStreetMap XML extract,
+// plain or compressed with gzip or bzip2. PBF extracts can be converted
+// with `osmium cat angola-latest.osm.pbf -o angola.osm.bz2`.
+type OSMRoadGraph struct {
+	path string
+
+	mu    sync.RWMutex
+	graph *model.RoadGraph
+}
+
+func NewOSMRoadGraph(path string) *OSMRoadGraph {
+	return &OSMRoadGraph{path: path}
+}
+
+// RoadGraph returns the loaded network, or nil until loading finishes.
+func (g *OSMRoadGraph) RoadGraph() *model.RoadGraph {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.graph
+}
+
+// LoadInBackground reads the extract without holding up startup; a country
+// extract takes a few minutes.
+func (g *OSMRoadGraph) LoadInBackground() {
+	if g.path == "" {
+		slog.Info("no OSM extract configured, safe routes follow straight lines")
+		return
+	}
+
+	go func() {
+		start := time.Now()
+		graph, err := buildRoadGraph(func() (io.ReadCloser, error) { return openOSMExtract(g.path) })
+		if err != nil {
+			slog.Error("failed to load road network", "path", g.path, "error", err)
+			return
+		}
+
+		g.mu.Lock()
+		g.graph = graph
+		g.mu.Unlock()
+
+		slog.Info("road network loaded",
+			"path", g.path,
+			"nodes", graph.NodeCount(),
+			"edges", graph.EdgeCount(),
+			"duration", time.Since(start))
+	}()
+}
+
+func openOSMExtract(path string) (io.ReadCloser, error) {
+	if strings.HasSuffix(path, ".pbf") {
+		return nil, errors.New("PBF extracts are not supported, convert to .osm, .osm.gz or .osm.bz2")
+	}
+
+	file, err := os.Open(path) //nolint:gosec // the path comes from configuration
+	if err != nil {
+		return nil, fmt.Errorf("failed to open OSM extract: %w", err)
+	}
+
+	switch {
+	case strings.HasSuffix(path, ".gz"):
+		reader, err := gzip.NewReader(file)
+		if err != nil {
+			_ = file.Close()
+			return nil, fmt.Errorf("failed to read gzip OSM extract: %w", err)
+		}
+		return struct {
+			io.Reader
+			io.Closer
+		}{reader, file}, nil
+	case strings.HasSuffix(path, ".bz2"):
+		return struct {
+			io.Reader
+			io.Closer
+		}{bzip2.NewReader(file), file}, nil
+	default:
+		return file, nil
+	}
+}
+
+type osmWay struct {
+	refs        []int64
+	class       model.RoadClass
+	oneway      int
+	maxSpeedKmh float64
+}
+
+// buildRoadGraph reads the extract twice: first the roads, then only the
+// nodes they use, so the coordinates of buildings and the like are never
+// held in memory.
+func buildRoadGraph(open func() (io.ReadCloser, error)) (*model.RoadGraph, error) {
+	ways, err := readWithin(open, readOSMWays)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int64
+	for _, way := range ways {
+		ids = append(ids, way.refs...)
+	}
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+
+	builder := &model.RoadGraphBuilder{}
+	indexes, err := readWithin(open, func(r io.Reader) ([]int32, error) {
+		return readOSMNodes(r, ids, builder)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, way := range ways {
+		// A way can reference nodes cut off by the extract's border; it is
+		// split where they are missing.
+		var nodes []int32
+		for _, ref := range way.refs {
+			pos, _ := slices.BinarySearch(ids, ref)
+			if indexes[pos] < 0 {
+				builder.AddWay(nodes, way.class, way.oneway, way.maxSpeedKmh)
+				nodes = nodes[:0]
+				continue
+			}
+			nodes = append(nodes, indexes[pos])
+		}
+		builder.AddWay(nodes, way.class, way.oneway, way.maxSpeedKmh)
+	}
+
+	return builder.Build(), nil
+}
+
+func readWithin[T any](open func() (io.ReadCloser, error), read func(io.Reader) (T, error)) (T, error) {
+	var zero T
+	r, err := open()
+	if err != nil {
+		return zero, err
+	}
+	defer func() { _ = r.Close() }()
+
+	return read(r)
+}
+
+// readOSMWays collects the routable highways.
+func readOSMWays(r io.Reader) ([]osmWay, error) {
+	decoder := xml.NewDecoder(r)
+
+	var ways []osmWay
+	var refs []int64
+	var tags map[string]string
+	inWay := false
+	for {
+		token, err := decoder.RawToken()
+		if errors.Is(err, io.EOF) {
+			return ways, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse OSM ways: %w", err)
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			switch element.Name.Local {
+			case "way":
+				inWay, refs, tags = true, nil, make(map[string]string)
+			case "nd":
+				if inWay {
+					if ref, err := strconv.ParseInt(xmlAttr(element, "ref"), 10, 64); err == nil {
+						refs = append(refs, ref)
+					}
+				}
+			case "tag":
+				if inWay {
+					tags[xmlAttr(element, "k")] = xmlAttr(element, "v")
+				}
+			}
+		case xml.EndElement:
+			if element.Name.Local != "way" {
+				continue
+			}
+			inWay = false
+			if way, ok := roadWay(refs, tags); ok {
+				ways = append(ways, way)
+			}
+		}
+	}
+}
+
+func roadWay(refs []int64, tags map[string]string) (osmWay, bool) {
+	class, ok := model.RoadClassFromHighway(tags["highway"])
+	if !ok || len(refs) < 2 || tags["area"] == "yes" {
+		return osmWay{}, false
+	}
+	if access := tags["access"]; access == "no" || access == "private" {
+		return osmWay{}, false
+	}
+
+	way := osmWay{refs: refs, class: class, maxSpeedKmh: parseMaxSpeed(tags["maxspeed"])}
+	switch tags["oneway"] {
+	case "yes", "true", "1":
+		way.oneway = 1
+	case "-1", "reverse":
+		way.oneway = -1
+	case "no", "false", "0":
+	default:
+		if tags["junction"] == "roundabout" || class == model.RoadClassMotorway {
+			way.oneway = 1
+		}
+	}
+	return way, true
+}
+
+// parseMaxSpeed reads limits such as "60" or "40 mph"; others, like "walk",
+// count as unknown.
+func parseMaxSpeed(value string) float64 {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return 0
+	}
+	speed, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0
+	}
+	if len(fields) > 1 && fields[1] == "mph" {
+		speed *= mphToKmh
+	}
+	return speed
+}
+
+// readOSMNodes adds the nodes listed in ids, which must be sorted, to the
+// builder and returns their graph index by position in ids, or -1 for those
+// not in the extract.
+func readOSMNodes(r io.Reader, ids []int64, builder *model.RoadGraphBuilder) ([]int32, error) {
+	indexes := make([]int32, len(ids))
+	for i := range indexes {
+		indexes[i] = -1
+	}
+
+	decoder := xml.NewDecoder(r)
+	for {
+		token, err := decoder.RawToken()
+		if errors.Is(err, io.EOF) {
+			return indexes, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse OSM nodes: %w", err)
+		}
+
+		element, ok := token.(xml.StartElement)
+		if !ok || element.Name.Local != "node" {
+			continue
+		}
+		id, err := strconv.ParseInt(xmlAttr(element, "id"), 10, 64)
+		if err != nil {
+			continue
+		}
+		pos, found := slices.BinarySearch(ids, id)
+		if !found {
+			continue
+		}
+		lat, latErr := strconv.ParseFloat(xmlAttr(element, "lat"), 64)
+		lon, lonErr := strconv.ParseFloat(xmlAttr(element, "lon"), 64)
+		if latErr != nil || lonErr != nil {
+			continue
+		}
+		indexes[pos] = builder.AddNode(lat, lon)
+	}
+}
+
+func xmlAttr(element xml.StartElement, name string) string {
+	for _, attr := range element.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,72 @@
+package routing
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+	"github.com/stretchr/testify/assert"
+)
+
+const testExtract = `<?xml version="1.0" encoding="UTF-8"?>
+<osm version="0.6">
+  <node id="1" lat="-8.8300" lon="13.2300"/>
+  <node id="2" lat="-8.8300" lon="13.2350"/>
+  <node id="3" lat="-8.8300" lon="13.2400"/>
+  <node id="4" lat="-8.8350" lon="13.2400"/>
+  <node id="5" lat="-8.8400" lon="13.2400"/>
+  <way id="10">
+    <nd ref="1"/><nd ref="2"/><nd ref="3"/>
+    <tag k="highway" v="primary"/>
+    <tag k="oneway" v="yes"/>
+    <tag k="maxspeed" v="40"/>
+  </way>
+  <way id="11">
+    <nd ref="3"/><nd ref="4"/><nd ref="99"/><nd ref="5"/>
+    <tag k="highway" v="residential"/>
+  </way>
+  <way id="12">
+    <nd ref="1"/><nd ref="5"/>
+    <tag k="highway" v="service"/>
+    <tag k="access" v="private"/>
+  </way>
+  <way id="13">
+    <nd ref="1"/><nd ref="4"/>
+    <tag k="building" v="yes"/>
+  </way>
+</osm>`
+
+func TestBuildRoadGraph(t *testing.T) {
+	graph, err := buildRoadGraph(func() (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(testExtract)), nil
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// Node 5 is only reached through the missing node 99 and the private
+	// road, so it is left without edges.
+	assert.Equal(t, 5, graph.NodeCount())
+	assert.Equal(t, 6, graph.EdgeCount())
+
+	first, ok := graph.Nearest(-8.8301, 13.2301, 500, func(model.RoadEdge) bool { return true })
+	if assert.True(t, ok) {
+		edges := graph.Edges(first)
+		if assert.Len(t, edges, 1) {
+			assert.Equal(t, model.RoadClassPrimary, edges[0].Class)
+			assert.False(t, edges[0].AgainstOneway)
+			assert.InDelta(t, 40, edges[0].DrivingSpeedKmh(), 0.001)
+		}
+	}
+
+	_, ok = graph.Nearest(-8.8400, 13.2400, 100, func(model.RoadEdge) bool { return true })
+	assert.False(t, ok)
+}
+
+func TestParseMaxSpeed(t *testing.T) {
+	assert.InDelta(t, 60, parseMaxSpeed("60"), 0.001)
+	assert.InDelta(t, 40*mphToKmh, parseMaxSpeed("40 mph"), 0.001)
+	assert.Zero(t, parseMaxSpeed("walk"))
+	assert.Zero(t, parseMaxSpeed(""))
+}
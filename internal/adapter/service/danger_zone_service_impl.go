@@ -5,85 +5,359 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/google/uuid"
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
 	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
 	"github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
 	domainService "github.com/risk-place-angola/backend-risk-place/internal/domain/service"
 )
 
 const (
-	dangerZoneCacheKey       = "danger_zones"
+	dangerZoneGenerationKey  = model.DangerZoneCacheNamespace + ":generation"
 	dangerZoneCacheTTL       = 1 * time.Hour
-	dangerZoneGridSize       = 0.5
-	dangerZoneMinIncidents   = 5
-	dangerZoneDaysBack       = 30
 	dangerZoneRecalcInterval = 30 * time.Minute
 	dangerZoneRadiusMeters   = 500.0
-	metersToKm               = 1000.0
+	dangerZoneRegionsTTL     = 10 * time.Minute
+	dangerZoneSnapshotDays   = 90
+	// dangerZoneExplanationDays is shorter than the snapshot history because
+	// an explanation lists every report behind the zone.
+	dangerZoneExplanationDays = 30
+	// dangerZoneTrendMaxCells bounds the series returned for an area.
+	dangerZoneTrendMaxCells = 200
+	// manualDangerZonesTTL is short because other instances only see an
+	// admin's change when their copy expires.
+	manualDangerZonesTTL = 1 * time.Minute
+	// manualZoneRouteBufferMeters is how close a route has to pass to a
+	// manual zone to be scored for it.
+	manualZoneRouteBufferMeters = 50.0
+	// dangerZoneGenerationGrace is how long a replaced generation stays
+	// readable, for requests that looked it up just before the swap.
+	dangerZoneGenerationGrace = 1 * time.Minute
 )
 
 type DangerZoneServiceImpl struct {
-	repo  repository.DangerZoneRepository
-	cache domainService.CacheService
+	repo       repository.DangerZoneRepository
+	manualRepo repository.ManualDangerZoneRepository
+	weights    domainService.IncidentWeightingService
+	cache      domainService.CacheService
+
+	mu             sync.RWMutex
+	regions        []*model.DangerZoneRegion
+	regionsLoaded  time.Time
+	regionsRefresh time.Duration
+	manualZones    []*model.ManualDangerZone
+	manualLoaded   time.Time
 }
 
 func NewDangerZoneService(
 	repo repository.DangerZoneRepository,
+	manualRepo repository.ManualDangerZoneRepository,
+	weights domainService.IncidentWeightingService,
 	cache domainService.CacheService,
 ) domainService.DangerZoneService {
 	return &DangerZoneServiceImpl{
-		repo:  repo,
-		cache: cache,
+		repo:           repo,
+		manualRepo:     manualRepo,
+		weights:        weights,
+		cache:          cache,
+		regionsRefresh: dangerZoneRegionsTTL,
 	}
 }
 
+// CalculateDangerZones rebuilds every level of every region's grid as a new
+// cache generation, indexing each level under its own geo key, and makes it
+// current once it is complete.
 func (s *DangerZoneServiceImpl) CalculateDangerZones(ctx context.Context) error {
-	results, err := s.repo.CalculateDangerZones(ctx, dangerZoneGridSize, dangerZoneMinIncidents, dangerZoneDaysBack)
+	regions, err := s.reloadRegions(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	daysBack := 0
+	for _, region := range regions {
+		daysBack = max(daysBack, region.DaysBack)
+	}
+
+	incidents, err := s.repo.ListIncidents(ctx, now.AddDate(0, 0, -daysBack), nil)
 	if err != nil {
 		return fmt.Errorf("failed to calculate danger zones: %w", err)
 	}
 
-	for _, result := range results {
-		zone := model.NewDangerZone(result.CellLat, result.CellLon, result.GridCellID)
-		zone.IncidentCount = result.IncidentCount
-		zone.RiskScore = result.RiskScore
-		zone.CalculateRiskLevel()
+	byRegion := make(map[*model.DangerZoneRegion][]model.DangerZoneIncident, len(regions))
+	for _, incident := range incidents {
+		region := model.DangerZoneRegionAt(regions, incident.Latitude, incident.Longitude)
+		if incident.CreatedAt.Before(now.AddDate(0, 0, -region.DaysBack)) {
+			continue
+		}
+		byRegion[region] = append(byRegion[region], incident)
+	}
 
-		data, err := json.Marshal(zone)
-		if err != nil {
-			slog.Debug("failed to marshal danger zone", "error", err, "grid_cell_id", result.GridCellID)
+	weighting := s.weights.Weighting(ctx)
+	runID := uuid.New()
+	generation := &model.DangerZoneGeneration{
+		ID:                runID.String(),
+		StartedAt:         now,
+		IncidentCount:     len(incidents),
+		ZonesPerPrecision: make(map[int]int),
+	}
+
+	var snapshots []model.DangerZoneSnapshot
+	var explanations []*model.DangerZoneExplanation
+	var cacheErr error
+	expiring := make(map[string]bool)
+	for region, regionIncidents := range byRegion {
+		for _, level := range region.Levels {
+			cells := domainService.GroupIncidentsByCell(regionIncidents, level.Precision)
+			zones := domainService.BuildDangerZones(regionIncidents, level, weighting, now)
+			generation.ZonesPerPrecision[level.Precision] += len(zones)
+			generation.ZoneCount += len(zones)
+
+			for _, zone := range zones {
+				snapshots = append(snapshots, model.NewDangerZoneSnapshot(runID, zone))
+				explanations = append(explanations, domainService.ExplainDangerZone(zone, cells[zone.GridCellID], weighting, now))
+				if cacheErr == nil {
+					cacheErr = s.cacheZone(ctx, generation, zone, expiring)
+				}
+			}
+		}
+	}
+
+	s.saveSnapshots(ctx, snapshots, now)
+	s.saveExplanations(ctx, runID, explanations, now)
+
+	if cacheErr == nil {
+		cacheErr = s.publishGeneration(ctx, generation)
+	}
+	if cacheErr != nil {
+		// Readers stay on the previous generation; drop what was written of
+		// this one.
+		s.deleteGeneration(ctx, generation)
+		return fmt.Errorf("failed to cache danger zones: %w", cacheErr)
+	}
+
+	return nil
+}
+
+// saveSnapshots keeps the run for trend analysis. Losing a run only leaves a
+// gap in the history, so failures are logged rather than returned.
+func (s *DangerZoneServiceImpl) saveSnapshots(ctx context.Context, snapshots []model.DangerZoneSnapshot, now time.Time) {
+	if err := s.repo.SaveSnapshots(ctx, snapshots); err != nil {
+		slog.Warn("failed to save danger zone snapshots", "error", err, "count", len(snapshots))
+	}
+	if err := s.repo.DeleteSnapshotsBefore(ctx, now.AddDate(0, 0, -dangerZoneSnapshotDays)); err != nil {
+		slog.Warn("failed to prune danger zone snapshots", "error", err)
+	}
+}
+
+// saveExplanations keeps what the run saw for each zone, so an explanation
+// shows what the calculation used rather than what the reports say later.
+// Like snapshots, a lost run is logged rather than returned.
+func (s *DangerZoneServiceImpl) saveExplanations(ctx context.Context, runID uuid.UUID, explanations []*model.DangerZoneExplanation, now time.Time) {
+	if err := s.repo.SaveExplanations(ctx, runID, now, explanations); err != nil {
+		slog.Warn("failed to save danger zone explanations", "error", err, "count", len(explanations))
+	}
+	if err := s.repo.DeleteExplanationsBefore(ctx, now.AddDate(0, 0, -dangerZoneExplanationDays)); err != nil {
+		slog.Warn("failed to prune danger zone explanations", "error", err)
+	}
+}
+
+// GetDangerZoneTrends returns the daily history of the matching cells with
+// their week-over-week trend.
+func (s *DangerZoneServiceImpl) GetDangerZoneTrends(
+	ctx context.Context,
+	filter repository.DangerZoneSnapshotFilter,
+) ([]model.DangerZoneTrendSeries, error) {
+	filter.Limit = dangerZoneTrendMaxCells
+	scores, err := s.repo.ListDailyScores(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get danger zone trends: %w", err)
+	}
+
+	return model.BuildDangerZoneTrends(scores, time.Now()), nil
+}
+
+// cacheZone writes the zone into the generation. Each key gets its expiry
+// when first written, listed in expiring, so a build that dies before it
+// is published does not leave keys behind for good.
+func (s *DangerZoneServiceImpl) cacheZone(
+	ctx context.Context,
+	generation *model.DangerZoneGeneration,
+	zone *model.DangerZone,
+	expiring map[string]bool,
+) error {
+	zoneData, err := json.Marshal(zone)
+	if err != nil {
+		return fmt.Errorf("failed to marshal danger zone %s: %w", zone.GridCellID, err)
+	}
+
+	zonesKey := generation.ZonesKey()
+	if err := s.cache.HSet(ctx, zonesKey, zone.GridCellID, string(zoneData)); err != nil {
+		return err
+	}
+	geoKey := generation.GeoKey(zone.Precision)
+	if err := s.cache.GeoAdd(ctx, geoKey, zone.CellLon, zone.CellLat, zone.GridCellID); err != nil {
+		return err
+	}
+
+	for _, key := range []string{zonesKey, geoKey} {
+		if expiring[key] {
 			continue
 		}
+		if err := s.cache.Expire(ctx, key, dangerZoneCacheTTL+dangerZoneGenerationGrace); err != nil {
+			return err
+		}
+		expiring[key] = true
+	}
+	return nil
+}
 
-		key := fmt.Sprintf("%s:%s", dangerZoneCacheKey, result.GridCellID)
-		if err := s.cache.Set(ctx, key, string(data), dangerZoneCacheTTL); err != nil {
-			slog.Debug("failed to cache danger zone", "error", err, "grid_cell_id", result.GridCellID)
+// publishGeneration makes a fully written generation current. Pointing
+// readers at it is a single write; the previous generation expires after a
+// grace period rather than at once, so requests already reading it finish.
+func (s *DangerZoneServiceImpl) publishGeneration(ctx context.Context, generation *model.DangerZoneGeneration) error {
+	for _, key := range generation.Keys() {
+		if err := s.cache.Expire(ctx, key, dangerZoneCacheTTL+dangerZoneGenerationGrace); err != nil {
+			return err
 		}
+	}
+
+	previous := s.currentGeneration(ctx)
+	if previous != nil {
+		generation.PreviousID = previous.ID
+	}
+	generation.CompletedAt = time.Now()
 
-		if err := s.cache.GeoAdd(ctx, dangerZoneCacheKey, result.CellLon, result.CellLat, result.GridCellID); err != nil {
-			slog.Debug("failed to add danger zone to geospatial index", "error", err, "grid_cell_id", result.GridCellID)
+	data, err := json.Marshal(generation)
+	if err != nil {
+		return fmt.Errorf("failed to marshal danger zone generation: %w", err)
+	}
+	if err := s.cache.Set(ctx, dangerZoneGenerationKey, string(data), dangerZoneCacheTTL); err != nil {
+		return err
+	}
+
+	if previous != nil && previous.ID != generation.ID {
+		for _, key := range previous.Keys() {
+			if err := s.cache.Expire(ctx, key, dangerZoneGenerationGrace); err != nil {
+				slog.Debug("failed to expire previous danger zone generation", "error", err, "key", key)
+			}
 		}
 	}
+	if err := s.deleteLegacyKeys(ctx); err != nil {
+		slog.Debug("failed to delete legacy danger zone keys", "error", err)
+	}
 
+	slog.Info("published danger zone generation",
+		"generation", generation.ID,
+		"previous_generation", generation.PreviousID,
+		"zones", generation.ZoneCount,
+		"incidents", generation.IncidentCount,
+		"duration", generation.CompletedAt.Sub(generation.StartedAt))
 	return nil
 }
 
-func (s *DangerZoneServiceImpl) GetDangerZonesNearby(ctx context.Context, lat, lon, radiusMeters float64) ([]*model.DangerZone, error) {
-	geoResults, err := s.cache.GeoSearchWithDistance(ctx, dangerZoneCacheKey, lon, lat, radiusMeters)
+func (s *DangerZoneServiceImpl) deleteGeneration(ctx context.Context, generation *model.DangerZoneGeneration) {
+	for _, key := range generation.Keys() {
+		if err := s.cache.Delete(ctx, key); err != nil {
+			slog.Debug("failed to delete danger zone generation key", "error", err, "key", key)
+		}
+	}
+}
+
+// currentGeneration returns the generation readers should use, or nil when
+// none has been published or the last one expired.
+func (s *DangerZoneServiceImpl) currentGeneration(ctx context.Context) *model.DangerZoneGeneration {
+	data, err := s.cache.Get(ctx, dangerZoneGenerationKey)
+	if err != nil || data == "" {
+		return nil
+	}
+
+	var generation model.DangerZoneGeneration
+	if err := json.Unmarshal([]byte(data), &generation); err != nil {
+		slog.Warn("ignoring invalid danger zone generation", "error", err)
+		return nil
+	}
+	return &generation
+}
+
+func (s *DangerZoneServiceImpl) GetDangerZonesNearby(
+	ctx context.Context,
+	lat, lon, radiusMeters float64,
+	zoom int,
+	at time.Time,
+) ([]*model.DangerZone, error) {
+	zones, err := s.zonesNearby(ctx, lat, lon, radiusMeters, zoom)
 	if err != nil {
-		slog.Debug("cache miss for danger zones, querying database", "error", err)
-		return s.repo.GetNearbyDangerZones(ctx, lat, lon, radiusMeters/metersToKm)
+		return nil, err
 	}
 
-	if len(geoResults) == 0 {
-		return s.repo.GetNearbyDangerZones(ctx, lat, lon, radiusMeters/metersToKm)
+	for i, zone := range zones {
+		zones[i] = zone.AtTime(at)
+	}
+	return append(s.manualZonesNearby(ctx, lat, lon, radiusMeters, at), zones...), nil
+}
+
+func (s *DangerZoneServiceImpl) manualZonesNearby(ctx context.Context, lat, lon, radiusMeters float64, at time.Time) []*model.DangerZone {
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	var zones []*model.DangerZone
+	for _, manual := range s.currentManualZones(ctx) {
+		if manual.IsActive(at) && manual.DistanceMeters(lat, lon) <= radiusMeters {
+			zones = append(zones, manual.ToDangerZone())
+		}
+	}
+	return zones
+}
+
+func (s *DangerZoneServiceImpl) ManualZonesAlongRoute(
+	ctx context.Context,
+	waypoints []model.Waypoint,
+	at time.Time,
+) []*model.ManualDangerZone {
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	var zones []*model.ManualDangerZone
+	for _, manual := range s.currentManualZones(ctx) {
+		if manual.IsActive(at) && manual.NearRoute(waypoints, manualZoneRouteBufferMeters) {
+			zones = append(zones, manual)
+		}
+	}
+	return zones
+}
+
+func (s *DangerZoneServiceImpl) zonesNearby(ctx context.Context, lat, lon, radiusMeters float64, zoom int) ([]*model.DangerZone, error) {
+	region := model.DangerZoneRegionAt(s.currentRegions(ctx), lat, lon)
+
+	precision := model.GeohashPrecisionForRadius(radiusMeters)
+	if zoom > 0 {
+		precision = model.GeohashPrecisionForZoom(zoom)
+	}
+	level := region.LevelFor(precision)
+
+	generation := s.currentGeneration(ctx)
+	if generation == nil {
+		slog.Debug("no danger zone generation cached, querying database")
+		return s.computeNearby(ctx, lat, lon, radiusMeters, region, level)
+	}
+
+	// The generation is complete, so no results means no zones here.
+	geoResults, err := s.cache.GeoSearchWithDistance(ctx, generation.GeoKey(level.Precision), lon, lat, radiusMeters)
+	if err != nil {
+		slog.Debug("cache miss for danger zones, querying database", "error", err)
+		return s.computeNearby(ctx, lat, lon, radiusMeters, region, level)
 	}
 
 	var zones []*model.DangerZone
 	for _, result := range geoResults {
-		key := fmt.Sprintf("%s:%s", dangerZoneCacheKey, result.Member)
-		data, err := s.cache.Get(ctx, key)
+		data, err := s.cache.HGet(ctx, generation.ZonesKey(), result.Member)
 		if err != nil {
 			continue
 		}
@@ -101,10 +375,36 @@ func (s *DangerZoneServiceImpl) GetDangerZonesNearby(ctx context.Context, lat, l
 	return zones, nil
 }
 
+// computeNearby builds the level's zones straight from the reports around the
+// point. The search box is widened by a cell so border cells are complete.
+func (s *DangerZoneServiceImpl) computeNearby(
+	ctx context.Context,
+	lat, lon, radiusMeters float64,
+	region *model.DangerZoneRegion,
+	level model.DangerZoneLevel,
+) ([]*model.DangerZone, error) {
+	cellWidth, cellHeight := model.GeohashCellSizeMeters(level.Precision)
+	bounds := model.BoundingBoxAround(lat, lon, radiusMeters+max(cellWidth, cellHeight))
+
+	now := time.Now()
+	incidents, err := s.repo.ListIncidents(ctx, now.AddDate(0, 0, -region.DaysBack), &bounds)
+	if err != nil {
+		return nil, err
+	}
+
+	var zones []*model.DangerZone
+	for _, zone := range domainService.BuildDangerZones(incidents, level, s.weights.Weighting(ctx), now) {
+		if model.DistanceMeters(lat, lon, zone.CellLat, zone.CellLon) <= radiusMeters {
+			zones = append(zones, zone)
+		}
+	}
+	return zones, nil
+}
+
 var ErrNoDangerZoneFound = fmt.Errorf("no danger zone found")
 
-func (s *DangerZoneServiceImpl) IsInDangerZone(ctx context.Context, lat, lon float64) (*model.DangerZone, error) {
-	zones, err := s.GetDangerZonesNearby(ctx, lat, lon, dangerZoneRadiusMeters)
+func (s *DangerZoneServiceImpl) IsInDangerZone(ctx context.Context, lat, lon float64, at time.Time) (*model.DangerZone, error) {
+	zones, err := s.GetDangerZonesNearby(ctx, lat, lon, dangerZoneRadiusMeters, 0, at)
 	if err != nil {
 		return nil, err
 	}
@@ -118,9 +418,155 @@ func (s *DangerZoneServiceImpl) IsInDangerZone(ctx context.Context, lat, lon flo
 	return nil, ErrNoDangerZoneFound
 }
 
+// ExplainDangerZone returns the explanation the calculation in effect at
+// the given time stored for the cell; a zero time means the latest one.
+func (s *DangerZoneServiceImpl) ExplainDangerZone(ctx context.Context, gridCellID string, at time.Time) (*model.DangerZoneExplanation, error) {
+	if strings.HasPrefix(gridCellID, model.ManualDangerZoneCellPrefix) {
+		// Manual zones are not built from reports; their reason and source
+		// come with them.
+		return nil, domainErrors.ErrDangerZoneNotFound
+	}
+
+	if _, err := model.DecodeGeohash(gridCellID); err != nil {
+		return nil, err
+	}
+
+	if at.IsZero() {
+		at = time.Now()
+	}
+	return s.repo.GetExplanation(ctx, gridCellID, at)
+}
+
+// InvalidateCache drops the current generation, so reads go to the database
+// until the next calculation, along with the keys of the layouts before
+// generations.
 func (s *DangerZoneServiceImpl) InvalidateCache(ctx context.Context) error {
-	if err := s.cache.Delete(ctx, dangerZoneCacheKey); err != nil {
+	generation := s.currentGeneration(ctx)
+
+	if err := s.cache.Delete(ctx, dangerZoneGenerationKey); err != nil {
+		return fmt.Errorf("failed to invalidate danger zone cache: %w", err)
+	}
+	if generation != nil {
+		s.deleteGeneration(ctx, generation)
+	}
+
+	if err := s.deleteLegacyKeys(ctx); err != nil {
 		return fmt.Errorf("failed to invalidate danger zone cache: %w", err)
 	}
 	return nil
 }
+
+// deleteLegacyKeys removes the geo sets of the layouts before generations:
+// the shared "danger_zones" set and the short-lived per precision
+// "danger_zones:p{n}" sets. Neither expires on its own.
+func (s *DangerZoneServiceImpl) deleteLegacyKeys(ctx context.Context) error {
+	keys := []string{model.DangerZoneCacheNamespace}
+	seen := make(map[int]bool)
+	for _, region := range s.currentRegions(ctx) {
+		for _, level := range region.Levels {
+			if !seen[level.Precision] {
+				seen[level.Precision] = true
+				keys = append(keys, legacyDangerZoneGeoKey(level.Precision))
+			}
+		}
+	}
+
+	for _, key := range keys {
+		if err := s.cache.Delete(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// currentRegions returns the configured regions, reloading them when they
+// are older than regionsRefresh. A failed reload keeps the last good set.
+func (s *DangerZoneServiceImpl) currentRegions(ctx context.Context) []*model.DangerZoneRegion {
+	s.mu.RLock()
+	regions, loaded := s.regions, s.regionsLoaded
+	s.mu.RUnlock()
+
+	if regions != nil && time.Since(loaded) < s.regionsRefresh {
+		return regions
+	}
+
+	fresh, err := s.reloadRegions(ctx)
+	if err != nil {
+		slog.Warn("failed to reload danger zone regions", "error", err)
+		if regions != nil {
+			return regions
+		}
+		return []*model.DangerZoneRegion{model.DefaultDangerZoneRegion()}
+	}
+	return fresh
+}
+
+// reloadRegions loads the regions and makes sure one of them covers
+// everywhere, so every incident and query maps to exactly one region.
+func (s *DangerZoneServiceImpl) reloadRegions(ctx context.Context) ([]*model.DangerZoneRegion, error) {
+	loaded, err := s.repo.ListRegions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load danger zone regions: %w", err)
+	}
+
+	regions := make([]*model.DangerZoneRegion, 0, len(loaded)+1)
+	hasFallback := false
+	for _, region := range loaded {
+		if err := region.Validate(); err != nil {
+			slog.Warn("skipping invalid danger zone region", "region", region.Name, "error", err)
+			continue
+		}
+		hasFallback = hasFallback || region.Bounds == nil
+		regions = append(regions, region)
+	}
+	if !hasFallback {
+		regions = append(regions, model.DefaultDangerZoneRegion())
+	}
+
+	s.mu.Lock()
+	s.regions = regions
+	s.regionsLoaded = time.Now()
+	s.mu.Unlock()
+
+	return regions, nil
+}
+
+// currentManualZones returns the manual zones that have not expired,
+// reloading them when they are older than manualDangerZonesTTL. A failed
+// reload keeps the last good set.
+func (s *DangerZoneServiceImpl) currentManualZones(ctx context.Context) []*model.ManualDangerZone {
+	s.mu.RLock()
+	zones, loaded := s.manualZones, s.manualLoaded
+	s.mu.RUnlock()
+
+	if !loaded.IsZero() && time.Since(loaded) < manualDangerZonesTTL {
+		return zones
+	}
+
+	if err := s.RefreshManualZones(ctx); err != nil {
+		slog.Warn("failed to reload manual danger zones", "error", err)
+		return zones
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.manualZones
+}
+
+func (s *DangerZoneServiceImpl) RefreshManualZones(ctx context.Context) error {
+	zones, err := s.manualRepo.ListUnexpired(ctx, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to load manual danger zones: %w", err)
+	}
+
+	s.mu.Lock()
+	s.manualZones = zones
+	s.manualLoaded = time.Now()
+	s.mu.Unlock()
+
+	return nil
+}
+
+func legacyDangerZoneGeoKey(precision int) string {
+	return fmt.Sprintf("%s:p%d", model.DangerZoneCacheNamespace, precision)
+}
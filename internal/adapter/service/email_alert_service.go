@@ -0,0 +1,206 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"log/slog"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/risk-place-angola/backend-risk-place/internal/application/port"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+)
+
+const (
+	emailLayoutTemplate  = "layout.html"
+	emailDefaultTemplate = "default.html"
+	emailUnsubscribePath = "/api/v1/notifications/email/unsubscribe"
+)
+
+//go:embed templates/email
+var embeddedEmailTemplates embed.FS
+
+// EmailAlert is one event to be emailed to many recipients. Each recipient
+// gets the copy in their own language.
+type EmailAlert struct {
+	EventKey   string
+	RiskType   string
+	Params     Params
+	DetailsURL string
+	Data       map[string]string
+}
+
+type emailTemplateData struct {
+	Lang             Language
+	Name             string
+	Title            string
+	Body             string
+	DetailsURL       string
+	DetailsLabel     string
+	Footer           string
+	UnsubscribeURL   string
+	UnsubscribeLabel string
+	Data             map[string]string
+}
+
+// EmailAlertService renders alert emails from HTML templates and hands them
+// to the email transport in batches.
+type EmailAlertService struct {
+	emailService       port.EmailService
+	translationService *TranslationService
+	signer             port.UnsubscribeTokenSigner
+	baseURL            string
+	templates          map[string]*template.Template
+}
+
+func NewEmailAlertService(
+	emailService port.EmailService,
+	translationService *TranslationService,
+	signer port.UnsubscribeTokenSigner,
+	baseURL string,
+) (*EmailAlertService, error) {
+	sub, err := fs.Sub(embeddedEmailTemplates, "templates/email")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open embedded email templates: %w", err)
+	}
+	return newEmailAlertService(emailService, translationService, signer, baseURL, sub)
+}
+
+func newEmailAlertService(
+	emailService port.EmailService,
+	translationService *TranslationService,
+	signer port.UnsubscribeTokenSigner,
+	baseURL string,
+	fsys fs.FS,
+) (*EmailAlertService, error) {
+	templates, err := parseEmailTemplates(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EmailAlertService{
+		emailService:       emailService,
+		translationService: translationService,
+		signer:             signer,
+		baseURL:            strings.TrimRight(baseURL, "/"),
+		templates:          templates,
+	}, nil
+}
+
+// parseEmailTemplates pairs every content template with the shared layout.
+func parseEmailTemplates(fsys fs.FS) (map[string]*template.Template, error) {
+	layout, err := template.ParseFS(fsys, emailLayoutTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse email layout: %w", err)
+	}
+
+	templates := make(map[string]*template.Template)
+	err = fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || path.Ext(p) != ".html" || p == emailLayoutTemplate {
+			return err
+		}
+
+		base, err := layout.Clone()
+		if err != nil {
+			return err
+		}
+		tmpl, err := base.ParseFS(fsys, p)
+		if err != nil {
+			return fmt.Errorf("failed to parse email template %s: %w", p, err)
+		}
+		templates[p] = tmpl
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := templates[emailDefaultTemplate]; !ok {
+		return nil, fmt.Errorf("missing %s email template", emailDefaultTemplate)
+	}
+
+	return templates, nil
+}
+
+// NotifyRecipients renders the alert for each recipient and sends the lot
+// through a single batched delivery.
+func (s *EmailAlertService) NotifyRecipients(ctx context.Context, recipients []model.EmailRecipient, alert EmailAlert) error {
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	messages := make([]port.EmailMessage, 0, len(recipients))
+	for _, r := range recipients {
+		msg, err := s.Render(r, alert)
+		if err != nil {
+			slog.Error("failed to render alert email", "user_id", r.UserID, "event_key", alert.EventKey, "error", err)
+			continue
+		}
+		messages = append(messages, msg)
+	}
+
+	if err := s.emailService.SendBatch(ctx, messages); err != nil {
+		return fmt.Errorf("failed to send alert emails: %w", err)
+	}
+
+	slog.Info("alert emails sent", "event_key", alert.EventKey, "recipients", len(messages))
+	return nil
+}
+
+func (s *EmailAlertService) Render(r model.EmailRecipient, alert EmailAlert) (port.EmailMessage, error) {
+	lang := s.translationService.ParseLanguage(r.Language)
+	msg := s.translationService.Render(alert.EventKey, lang, alert.RiskType, alert.Params)
+	unsubscribeURL := s.UnsubscribeURL(r)
+
+	data := emailTemplateData{
+		Lang:             lang,
+		Name:             r.Name,
+		Title:            msg.Title,
+		Body:             msg.Body,
+		DetailsURL:       alert.DetailsURL,
+		DetailsLabel:     s.translationService.GetMessage("email_view_details", lang, "").Body,
+		Footer:           s.translationService.GetMessage("email_footer", lang, "").Body,
+		UnsubscribeURL:   unsubscribeURL,
+		UnsubscribeLabel: s.translationService.GetMessage("email_unsubscribe", lang, "").Body,
+		Data:             alert.Data,
+	}
+
+	var buf bytes.Buffer
+	if err := s.template(alert.EventKey, lang).ExecuteTemplate(&buf, "layout", data); err != nil {
+		return port.EmailMessage{}, err
+	}
+
+	return port.EmailMessage{
+		To:       r.Email,
+		Subject:  msg.Title,
+		HTMLBody: buf.String(),
+		Headers: map[string]string{
+			"List-Unsubscribe":      "<" + unsubscribeURL + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	}, nil
+}
+
+// UnsubscribeURL is the one-click link that turns email alerts off without
+// logging in.
+func (s *EmailAlertService) UnsubscribeURL(r model.EmailRecipient) string {
+	return s.baseURL + emailUnsubscribePath + "?token=" + url.QueryEscape(s.signer.Sign(r.UserID))
+}
+
+func (s *EmailAlertService) template(eventKey string, lang Language) *template.Template {
+	for _, name := range []string{
+		path.Join(string(lang), eventKey+".html"),
+		path.Join(string(DefaultLanguage), eventKey+".html"),
+		eventKey + ".html",
+	} {
+		if tmpl, ok := s.templates[name]; ok {
+			return tmpl
+		}
+	}
+	return s.templates[emailDefaultTemplate]
+}
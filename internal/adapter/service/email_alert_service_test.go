@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/uuid"
+	"github.com/risk-place-angola/backend-risk-place/internal/application/port"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingEmailService struct {
+	port.EmailService
+	batches [][]port.EmailMessage
+}
+
+func (r *recordingEmailService) SendBatch(_ context.Context, messages []port.EmailMessage) error {
+	r.batches = append(r.batches, messages)
+	return nil
+}
+
+func TestEmailAlertService_TemplateFallbacks(t *testing.T) {
+	ts, err := NewTranslationService("")
+	assert.NoError(t, err)
+
+	fsys := fstest.MapFS{
+		"layout.html":           {Data: []byte(`{{define "layout"}}[{{.Lang}}]{{template "content" .}}|{{.UnsubscribeURL}}{{end}}`)},
+		"default.html":          {Data: []byte(`{{define "content"}}default:{{.Title}}{{end}}`)},
+		"alert_created.html":    {Data: []byte(`{{define "content"}}alert:{{.Body}}{{end}}`)},
+		"fr/alert_created.html": {Data: []byte(`{{define "content"}}alerte:{{.Body}}{{end}}`)},
+	}
+
+	sender := &recordingEmailService{}
+	svc, err := newEmailAlertService(sender, ts, NewUnsubscribeTokenService("secret"), "https://api.example.com/", fsys)
+	assert.NoError(t, err)
+
+	recipients := []model.EmailRecipient{
+		{UserID: uuid.New(), Email: "fr@example.com", Language: "fr"},
+		{UserID: uuid.New(), Email: "en@example.com", Language: "en"},
+	}
+	assert.NoError(t, svc.NotifyRecipients(context.Background(), recipients, EmailAlert{EventKey: "alert_created"}))
+	assert.Len(t, sender.batches, 1)
+
+	batch := sender.batches[0]
+	assert.Contains(t, batch[0].HTMLBody, "[fr]alerte:")
+	assert.Contains(t, batch[1].HTMLBody, "[en]alert:")
+	assert.Contains(t, batch[1].HTMLBody, "https://api.example.com/api/v1/notifications/email/unsubscribe?token=")
+	assert.Equal(t, "List-Unsubscribe=One-Click", batch[1].Headers["List-Unsubscribe-Post"])
+
+	// Event keys without their own template use the default one.
+	msg, err := svc.Render(recipients[1], EmailAlert{EventKey: "report_verified"})
+	assert.NoError(t, err)
+	assert.Contains(t, msg.HTMLBody, "default:"+ts.GetMessage("report_verified", LanguageEnglish, "").Title)
+
+	// The shipped templates must parse as well.
+	_, err = NewEmailAlertService(sender, ts, NewUnsubscribeTokenService("secret"), "")
+	assert.NoError(t, err)
+}
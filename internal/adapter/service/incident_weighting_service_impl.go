@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
+	domainService "github.com/risk-place-angola/backend-risk-place/internal/domain/service"
+)
+
+// incidentWeightingTTL is short because other instances only see an admin's
+// change when their copy expires.
+const incidentWeightingTTL = 1 * time.Minute
+
+type IncidentWeightingServiceImpl struct {
+	repo repository.IncidentWeightRuleRepository
+
+	mu        sync.RWMutex
+	weighting *model.IncidentWeighting
+	loaded    time.Time
+}
+
+func NewIncidentWeightingService(repo repository.IncidentWeightRuleRepository) domainService.IncidentWeightingService {
+	return &IncidentWeightingServiceImpl{
+		repo:      repo,
+		weighting: model.NewIncidentWeighting(nil),
+	}
+}
+
+func (s *IncidentWeightingServiceImpl) Weighting(ctx context.Context) *model.IncidentWeighting {
+	s.mu.RLock()
+	weighting, loaded := s.weighting, s.loaded
+	s.mu.RUnlock()
+
+	if !loaded.IsZero() && time.Since(loaded) < incidentWeightingTTL {
+		return weighting
+	}
+
+	if err := s.Refresh(ctx); err != nil {
+		slog.Warn("failed to reload incident weight rules", "error", err)
+		return weighting
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.weighting
+}
+
+func (s *IncidentWeightingServiceImpl) Refresh(ctx context.Context) error {
+	rules, err := s.repo.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load incident weight rules: %w", err)
+	}
+
+	s.mu.Lock()
+	s.weighting = model.NewIncidentWeighting(rules)
+	s.loaded = time.Now()
+	s.mu.Unlock()
+
+	return nil
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// fullLocales are the catalogs that carry every message; the others fall
+// back to Portuguese.
+var fullLocales = []string{"en", "fr", "pt"}
+
+// catalogKeyCalls are the TranslationService methods that take a catalog
+// key, with the position of the key argument.
+var catalogKeyCalls = map[string]int{
+	"Render":      0,
+	"GetMessage":  0,
+	"Lookup":      0,
+	"Localize":    1,
+	"LocalizeFor": 1,
+}
+
+func loadCatalogMessages(t *testing.T, locale string) map[string]json.RawMessage {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("locales", locale+".json"))
+	assert.NoError(t, err)
+
+	var file map[string]json.RawMessage
+	assert.NoError(t, json.Unmarshal(data, &file))
+
+	allowed := map[string]bool{"locale": true, "name": true, "aliases": true, "fallback": true, "plural_rule": true, "messages": true}
+	for key := range file {
+		assert.True(t, allowed[key], "%s.json: %q is outside \"messages\" and is never loaded", locale, key)
+	}
+
+	var messages map[string]json.RawMessage
+	assert.NoError(t, json.Unmarshal(file["messages"], &messages))
+	return messages
+}
+
+// collectCatalogKeys returns the literal keys the code looks up and the
+// literal prefixes of keys built at runtime, such as "trip_check_in_"+reason.
+//
+//nolint:nonamedreturns // keys and prefixes are returned together
+func collectCatalogKeys(t *testing.T, root string) (keys, prefixes map[string]string) {
+	t.Helper()
+	keys, prefixes = map[string]string{}, map[string]string{}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return err
+		}
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return err
+		}
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			index, ok := catalogKeyCalls[sel.Sel.Name]
+			if !ok || len(call.Args) <= index {
+				return true
+			}
+
+			where := fset.Position(call.Pos()).String()
+			switch arg := call.Args[index].(type) {
+			case *ast.BasicLit:
+				if key, err := strconv.Unquote(arg.Value); err == nil && arg.Kind == token.STRING {
+					keys[key] = where
+				}
+			case *ast.BinaryExpr:
+				if lit, ok := arg.X.(*ast.BasicLit); ok && arg.Op == token.ADD && lit.Kind == token.STRING {
+					if prefix, err := strconv.Unquote(lit.Value); err == nil {
+						prefixes[prefix] = where
+					}
+				}
+			}
+			return true
+		})
+		return nil
+	})
+	assert.NoError(t, err)
+	return keys, prefixes
+}
+
+func TestLocaleCatalogs_ResolveEveryKeyUsedByTheCode(t *testing.T) {
+	keys, prefixes := collectCatalogKeys(t, filepath.Join("..", "..", ".."))
+	assert.NotEmpty(t, keys)
+
+	// Error codes are looked up as "error_" plus the lowercased code.
+	for _, code := range collectCodes(t, filepath.Join("..", "..", "domain", "errors", "codes.go")) {
+		keys["error_"+strings.ToLower(code)] = "domain error code " + code
+	}
+
+	// Danger zone risk levels are shown through "risk_level_" plus the level.
+	for score := 0.0; score <= 10; score++ {
+		level := model.DangerZoneRiskLevel(score)
+		keys["risk_level_"+level] = "danger zone risk level " + level
+	}
+
+	for _, locale := range fullLocales {
+		messages := loadCatalogMessages(t, locale)
+
+		for key, where := range keys {
+			_, ok := messages[key]
+			assert.True(t, ok, "%s.json: messages has no %q (used at %s)", locale, key, where)
+		}
+
+		for prefix, where := range prefixes {
+			found := false
+			for key := range messages {
+				if strings.HasPrefix(key, prefix) {
+					found = true
+					break
+				}
+			}
+			assert.True(t, found, "%s.json: messages has no key starting with %q (used at %s)", locale, prefix, where)
+		}
+	}
+}
+
+// collectCodes reads the string values of the Code constants.
+func collectCodes(t *testing.T, path string) []string {
+	t.Helper()
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+	assert.NoError(t, err)
+
+	var codes []string
+	ast.Inspect(file, func(n ast.Node) bool {
+		spec, ok := n.(*ast.ValueSpec)
+		if !ok {
+			return true
+		}
+		if ident, ok := spec.Type.(*ast.Ident); !ok || ident.Name != "Code" {
+			return true
+		}
+		for _, value := range spec.Values {
+			if lit, ok := value.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				if code, err := strconv.Unquote(lit.Value); err == nil {
+					codes = append(codes, code)
+				}
+			}
+		}
+		return true
+	})
+	return codes
+}
@@ -2,10 +2,12 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 
 	"github.com/google/uuid"
 	"github.com/risk-place-angola/backend-risk-place/internal/application/port"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
 	domainrepository "github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
 )
 
@@ -15,6 +17,8 @@ type NotificationService struct {
 	smsService           port.NotifierSMSService
 	userRepo             domainrepository.UserRepository
 	anonymousSessionRepo domainrepository.AnonymousSessionRepository
+	emailRepo            domainrepository.EmailNotificationRepository
+	emailAlerts          *EmailAlertService
 }
 
 func NewNotificationService(
@@ -23,6 +27,8 @@ func NewNotificationService(
 	smsService port.NotifierSMSService,
 	userRepo domainrepository.UserRepository,
 	anonymousSessionRepo domainrepository.AnonymousSessionRepository,
+	emailRepo domainrepository.EmailNotificationRepository,
+	emailAlerts *EmailAlertService,
 ) *NotificationService {
 	return &NotificationService{
 		translationService:   translationService,
@@ -30,6 +36,8 @@ func NewNotificationService(
 		smsService:           smsService,
 		userRepo:             userRepo,
 		anonymousSessionRepo: anonymousSessionRepo,
+		emailRepo:            emailRepo,
+		emailAlerts:          emailAlerts,
 	}
 }
 
@@ -65,6 +73,8 @@ func (s *NotificationService) SendNotificationWithFallback(ctx context.Context,
 		if err == nil && user != nil {
 			fcmToken = user.DeviceToken
 		}
+
+		s.sendEmail(ctx, []uuid.UUID{uid}, riskType, eventKey, data)
 	} else if deviceID != "" {
 		pushEnabled, smsEnabled, err = s.anonymousSessionRepo.GetNotificationPreferences(ctx, deviceID)
 		if err != nil {
@@ -129,6 +139,8 @@ func (s *NotificationService) SendNotificationToMultiple(ctx context.Context, us
 
 	tokens = append(tokens, deviceTokens...)
 
+	s.sendEmail(ctx, userIDs, riskType, eventKey, data)
+
 	//nolint:nestif // complex notification logic requires nested conditions
 	if len(tokens) > 0 {
 		err := s.pushService.NotifyPushMulti(ctx, tokens, msg.Title, msg.Body, data)
@@ -147,3 +159,42 @@ func (s *NotificationService) SendNotificationToMultiple(ctx context.Context, us
 
 	return nil
 }
+
+// SendEmailNear emails opted-in users among userIDs, distancesMeters[i] away
+// from the event, plus those whose home or work address lies within
+// radiusMeters of it, honouring the same notification settings as push.
+// Email does not honour quiet hours: it is read later and never wakes
+// anyone up.
+func (s *NotificationService) SendEmailNear(ctx context.Context, userIDs []uuid.UUID, distancesMeters []int, lat, lon, radiusMeters float64, filter model.EmailAlertFilter, riskType, eventKey string, data map[string]string) error {
+	recipients, err := s.emailRepo.ListRecipientsNear(ctx, userIDs, distancesMeters, lat, lon, radiusMeters, filter)
+	if err != nil {
+		return fmt.Errorf("failed to list email recipients: %w", err)
+	}
+
+	alert := EmailAlert{
+		EventKey:   eventKey,
+		RiskType:   riskType,
+		DetailsURL: fmt.Sprintf("https://maps.google.com/?q=%.6f,%.6f", lat, lon),
+		Data:       data,
+	}
+	return s.emailAlerts.NotifyRecipients(ctx, recipients, alert)
+}
+
+// sendEmail is an additional channel rather than a fallback: users who opt
+// in get the email whether or not push went through.
+func (s *NotificationService) sendEmail(ctx context.Context, userIDs []uuid.UUID, riskType, eventKey string, data map[string]string) {
+	if len(userIDs) == 0 {
+		return
+	}
+
+	recipients, err := s.emailRepo.ListRecipientsByUserIDs(ctx, userIDs)
+	if err != nil {
+		slog.Error("failed to list email recipients", "event_key", eventKey, "error", err)
+		return
+	}
+
+	alert := EmailAlert{EventKey: eventKey, RiskType: riskType, Data: data}
+	if err := s.emailAlerts.NotifyRecipients(ctx, recipients, alert); err != nil {
+		slog.Error("email notification failed", "event_key", eventKey, "error", err)
+	}
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/risk-place-angola/backend-risk-place/internal/application/port"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/event"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+	domainrepository "github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
+)
+
+// OutboxDispatcher writes events to the Postgres outbox instead of firing the
+// handlers directly. The OutboxRelay later delivers them to the handlers
+// registered on the in-memory dispatcher.
+type OutboxDispatcher struct {
+	repo     domainrepository.OutboxRepository
+	handlers *event.EventDispatcher
+}
+
+func NewOutboxDispatcher(repo domainrepository.OutboxRepository, handlers *event.EventDispatcher) port.EventOutbox {
+	return &OutboxDispatcher{
+		repo:     repo,
+		handlers: handlers,
+	}
+}
+
+func (d *OutboxDispatcher) Register(eventName, handlerName string, handler event.EventHandler) {
+	d.handlers.Register(eventName, handlerName, handler)
+}
+
+// Dispatch stores the event outside of any transaction. If the outbox cannot
+// be written the event is handed to the in-memory dispatcher so it is not
+// dropped.
+func (d *OutboxDispatcher) Dispatch(ev event.Event) {
+	if err := d.Publish(context.Background(), ev); err != nil {
+		slog.Error("failed to write event to outbox, dispatching in memory", "event", ev.Name(), "error", err)
+		d.handlers.Dispatch(ev)
+	}
+}
+
+func (d *OutboxDispatcher) Publish(ctx context.Context, ev event.Event) error {
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return fmt.Errorf("failed to encode event %s: %w", ev.Name(), err)
+	}
+
+	return d.repo.Enqueue(ctx, model.NewOutboxMessage(ev.Name(), payload))
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/event"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+	domainrepository "github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
+)
+
+const (
+	outboxRelayBatchSize = 50
+	// outboxRelayDeliveryTimeout bounds the handlers of one message.
+	outboxRelayDeliveryTimeout = 2 * time.Minute
+	// outboxRelayLease outlasts the delivery timeout so no other instance
+	// claims a message whose handlers are still running.
+	outboxRelayLease       = outboxRelayDeliveryTimeout + 3*time.Minute
+	outboxRelayMaxAttempts = 10
+	outboxRelayBaseBackoff = 5 * time.Second
+	outboxRelayMaxBackoff  = 30 * time.Minute
+)
+
+// OutboxRelay delivers outbox events to the registered handlers with
+// at-least-once semantics: a message is only removed after every handler
+// handled it, failed handlers are retried with exponential backoff while the
+// ones that succeeded are skipped, and the message is moved to the
+// dead-letter table once it runs out of attempts.
+type OutboxRelay struct {
+	repo     domainrepository.OutboxRepository
+	handlers *event.EventDispatcher
+}
+
+func NewOutboxRelay(repo domainrepository.OutboxRepository, handlers *event.EventDispatcher) *OutboxRelay {
+	return &OutboxRelay{
+		repo:     repo,
+		handlers: handlers,
+	}
+}
+
+func (r *OutboxRelay) RelayPending(ctx context.Context) error {
+	messages, err := r.repo.ClaimDue(ctx, time.Now(), outboxRelayLease, outboxRelayBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to claim outbox events: %w", err)
+	}
+
+	var wg sync.WaitGroup
+	for _, msg := range messages {
+		wg.Add(1)
+		go func(msg *model.OutboxMessage) {
+			defer wg.Done()
+			r.relay(ctx, msg)
+		}(msg)
+	}
+	wg.Wait()
+
+	return nil
+}
+
+func (r *OutboxRelay) relay(ctx context.Context, msg *model.OutboxMessage) {
+	deliveryCtx, cancel := context.WithTimeout(ctx, outboxRelayDeliveryTimeout)
+	delivered, deliveryErr := r.deliver(deliveryCtx, msg)
+	cancel()
+	if deliveryErr == nil {
+		if err := r.repo.MarkDelivered(ctx, msg.ID); err != nil {
+			slog.Error("failed to mark outbox event delivered", "id", msg.ID, "event", msg.EventName, "error", err)
+		}
+		return
+	}
+
+	msg.DeliveredHandlers = delivered
+	if msg.Attempts >= outboxRelayMaxAttempts {
+		slog.Error("outbox event moved to dead letters", "id", msg.ID, "event", msg.EventName, "attempts", msg.Attempts, "error", deliveryErr)
+		if err := r.repo.MoveToDeadLetter(ctx, msg, deliveryErr.Error()); err != nil {
+			slog.Error("failed to dead-letter outbox event", "id", msg.ID, "error", err)
+		}
+		return
+	}
+
+	nextAttempt := time.Now().Add(outboxBackoff(msg.Attempts))
+	slog.Warn("outbox event delivery failed, will retry", "id", msg.ID, "event", msg.EventName, "attempts", msg.Attempts, "next_attempt_at", nextAttempt, "error", deliveryErr)
+	if err := r.repo.MarkFailed(ctx, msg.ID, nextAttempt, deliveryErr.Error(), delivered); err != nil {
+		slog.Error("failed to record outbox delivery failure", "id", msg.ID, "error", err)
+	}
+}
+
+func (r *OutboxRelay) deliver(ctx context.Context, msg *model.OutboxMessage) ([]string, error) {
+	ev, err := event.Decode(msg.EventName, msg.Payload)
+	if err != nil {
+		return msg.DeliveredHandlers, err
+	}
+	return r.handlers.Deliver(ctx, ev, msg.DeliveredHandlers)
+}
+
+func outboxBackoff(attempts int) time.Duration {
+	backoff := time.Duration(float64(outboxRelayBaseBackoff) * math.Pow(2, float64(attempts-1)))
+	if backoff <= 0 || backoff > outboxRelayMaxBackoff {
+		return outboxRelayMaxBackoff
+	}
+	return backoff
+}
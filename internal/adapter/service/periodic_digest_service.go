@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/risk-place-angola/backend-risk-place/internal/application/port"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+	domainrepository "github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
+	domainService "github.com/risk-place-angola/backend-risk-place/internal/domain/service"
+)
+
+const (
+	periodicDigestBatchSize    = 500
+	periodicDigestRadiusMeters = 1000.0
+	// Digests go out once the local clock reaches this hour so users get
+	// them in the morning rather than whenever they happened to opt in.
+	periodicDigestSendHour = 8
+)
+
+type digestPlace struct {
+	place     model.DigestPlace
+	latitude  float64
+	longitude float64
+}
+
+// PeriodicDigestService builds the opt-in daily/weekly safety digests around
+// a user's home, work and current location and delivers them by push and/or
+// email.
+type PeriodicDigestService struct {
+	digestRepo         domainrepository.DigestRepository
+	userRepo           domainrepository.UserRepository
+	locationRepo       domainrepository.UserLocationRepository
+	dangerZoneService  domainService.DangerZoneService
+	pushService        port.NotifierPushService
+	emailService       port.EmailService
+	translationService *TranslationService
+}
+
+func NewPeriodicDigestService(
+	digestRepo domainrepository.DigestRepository,
+	userRepo domainrepository.UserRepository,
+	locationRepo domainrepository.UserLocationRepository,
+	dangerZoneService domainService.DangerZoneService,
+	pushService port.NotifierPushService,
+	emailService port.EmailService,
+	translationService *TranslationService,
+) *PeriodicDigestService {
+	return &PeriodicDigestService{
+		digestRepo:         digestRepo,
+		userRepo:           userRepo,
+		locationRepo:       locationRepo,
+		dangerZoneService:  dangerZoneService,
+		pushService:        pushService,
+		emailService:       emailService,
+		translationService: translationService,
+	}
+}
+
+// SendDueDigests sends batches of digests during the send hour until every
+// due subscription is served. It stops early when a whole batch fails, as
+// those subscriptions stay due and would be listed again.
+func (s *PeriodicDigestService) SendDueDigests(ctx context.Context) error {
+	now := time.Now()
+	if now.In(model.LocalTimezone()).Hour() != periodicDigestSendHour {
+		return nil
+	}
+
+	for ctx.Err() == nil {
+		subscriptions, err := s.digestRepo.ListDueSubscriptions(ctx, now, periodicDigestBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to list due digest subscriptions: %w", err)
+		}
+
+		sent := 0
+		for _, sub := range subscriptions {
+			if err := s.sendDigest(ctx, sub, now); err != nil {
+				slog.Error("failed to send periodic digest", "user_id", sub.UserID, "error", err)
+				continue
+			}
+			sent++
+		}
+
+		if len(subscriptions) > 0 {
+			slog.Info("periodic digests sent", "due", len(subscriptions), "sent", sent)
+		}
+
+		if len(subscriptions) < periodicDigestBatchSize || sent == 0 {
+			return nil
+		}
+	}
+
+	return ctx.Err()
+}
+
+func (s *PeriodicDigestService) sendDigest(ctx context.Context, sub *model.DigestSubscription, now time.Time) error {
+	user, err := s.userRepo.FindByID(ctx, sub.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to load user: %w", err)
+	}
+
+	digest, err := s.BuildDigest(ctx, sub, user, now)
+	if err != nil {
+		return err
+	}
+
+	lang := s.translationService.ParseLanguage(user.DeviceLanguage)
+	title, summary := s.renderSummary(digest, lang)
+
+	if sub.PushEnabled {
+		s.deliverPush(ctx, sub.UserID, digest, title, summary)
+	}
+
+	if sub.EmailEnabled && user.Email != "" {
+		html := s.renderEmail(digest, lang, title, summary)
+		if err := s.emailService.SendHTMLEmail(ctx, user.Email, title, html); err != nil {
+			slog.Error("failed to email periodic digest", "user_id", sub.UserID, "error", err)
+		}
+	}
+
+	return s.digestRepo.MarkSent(ctx, sub.UserID, now, digest.DangerZoneCells())
+}
+
+// BuildDigest summarises verified reports, active alerts and danger zone
+// changes around each of the user's places since the previous digest.
+func (s *PeriodicDigestService) BuildDigest(ctx context.Context, sub *model.DigestSubscription, user *model.User, now time.Time) (*model.Digest, error) {
+	since := sub.Since(now)
+	known := make(map[string]struct{}, len(sub.KnownDangerZones))
+	for _, cell := range sub.KnownDangerZones {
+		known[cell] = struct{}{}
+	}
+
+	digest := &model.Digest{
+		UserID:      sub.UserID,
+		Frequency:   sub.Frequency,
+		GeneratedAt: now,
+	}
+
+	for _, p := range s.placesFor(ctx, user, since) {
+		reports, err := s.digestRepo.CountVerifiedReportsNear(ctx, p.latitude, p.longitude, periodicDigestRadiusMeters, since)
+		if err != nil {
+			return nil, err
+		}
+
+		alerts, err := s.digestRepo.CountActiveAlertsNear(ctx, p.latitude, p.longitude, periodicDigestRadiusMeters)
+		if err != nil {
+			return nil, err
+		}
+
+		zones, err := s.dangerZoneService.GetDangerZonesNearby(ctx, p.latitude, p.longitude, periodicDigestRadiusMeters, 0, time.Time{})
+		if err != nil {
+			slog.Warn("failed to load danger zones for digest", "user_id", sub.UserID, "place", p.place, "error", err)
+		}
+
+		area := model.DigestAreaActivity{
+			Place:           p.place,
+			VerifiedReports: reports,
+			ActiveAlerts:    alerts,
+			DangerZones:     make([]string, 0),
+		}
+		for _, zone := range zones {
+			if zone.RiskLevel != "high" && zone.RiskLevel != "critical" {
+				continue
+			}
+			area.DangerZones = append(area.DangerZones, zone.GridCellID)
+			if _, ok := known[zone.GridCellID]; !ok {
+				area.NewDangerZoneCount++
+			}
+		}
+
+		digest.Areas = append(digest.Areas, area)
+	}
+
+	current := digest.DangerZoneCells()
+	for cell := range known {
+		if !slices.Contains(current, cell) {
+			digest.ClearedZoneCount++
+		}
+	}
+
+	return digest, nil
+}
+
+func (s *PeriodicDigestService) placesFor(ctx context.Context, user *model.User, since time.Time) []digestPlace {
+	places := make([]digestPlace, 0, 3) //nolint:mnd // home, work and current location
+
+	if user.HomeAddress != nil {
+		places = append(places, digestPlace{model.DigestPlaceHome, user.HomeAddress.Latitude, user.HomeAddress.Longitude})
+	}
+	if user.WorkAddress != nil {
+		places = append(places, digestPlace{model.DigestPlaceWork, user.WorkAddress.Latitude, user.WorkAddress.Longitude})
+	}
+
+	// The live location table is pruned aggressively, so a missing row simply
+	// means the user has not been seen recently.
+	loc, err := s.locationRepo.FindByUserID(ctx, user.ID)
+	if err == nil && loc != nil && loc.LastUpdate.After(since) {
+		places = append(places, digestPlace{model.DigestPlaceCurrent, loc.Latitude, loc.Longitude})
+	}
+
+	return places
+}
+
+func (s *PeriodicDigestService) deliverPush(ctx context.Context, userID uuid.UUID, digest *model.Digest, title, summary string) {
+	tokens, err := s.userRepo.ListDeviceTokensByUserIDs(ctx, []uuid.UUID{userID})
+	if err != nil {
+		slog.Error("failed to load device tokens for digest", "user_id", userID, "error", err)
+		return
+	}
+	if len(tokens) == 0 {
+		return
+	}
+
+	data := map[string]string{
+		"type":             "periodic_digest",
+		"frequency":        string(digest.Frequency),
+		"verified_reports": fmt.Sprintf("%d", digest.TotalVerifiedReports()),
+		"active_alerts":    fmt.Sprintf("%d", digest.TotalActiveAlerts()),
+		"new_danger_zones": fmt.Sprintf("%d", digest.TotalNewDangerZones()),
+	}
+
+	if err := s.pushService.NotifyPushMulti(ctx, tokens, title, summary, data); err != nil {
+		slog.Error("failed to push periodic digest", "user_id", userID, "error", err)
+	}
+}
+
+//nolint:nonamedreturns // title and body are both strings
+func (s *PeriodicDigestService) renderSummary(digest *model.Digest, lang Language) (title, body string) {
+	key := "digest_daily"
+	if digest.Frequency == model.DigestFrequencyWeekly {
+		key = "digest_weekly"
+	}
+	if !digest.HasActivity() {
+		title = s.translationService.GetMessage(key, lang, "").Title
+		return title, s.translationService.GetMessage("digest_no_activity", lang, "").Body
+	}
+
+	msg := s.translationService.Render(key, lang, "", Params{
+		"reports":   digest.TotalVerifiedReports(),
+		"alerts":    digest.TotalActiveAlerts(),
+		"new_zones": digest.TotalNewDangerZones(),
+	})
+	return msg.Title, msg.Body
+}
+
+func (s *PeriodicDigestService) renderEmail(digest *model.Digest, lang Language, title, summary string) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "<h2>%s</h2>\n<p>%s</p>\n", title, summary)
+
+	if len(digest.Areas) > 0 {
+		b.WriteString("<ul>\n")
+		for _, area := range digest.Areas {
+			label := s.translationService.GetMessage("digest_place_"+string(area.Place), lang, "").Title
+			line := s.translationService.Render("digest_area", lang, "", Params{
+				"place":     label,
+				"reports":   area.VerifiedReports,
+				"alerts":    area.ActiveAlerts,
+				"zones":     len(area.DangerZones),
+				"new_zones": area.NewDangerZoneCount,
+			}).Body
+			fmt.Fprintf(&b, "<li>%s</li>\n", line)
+		}
+		b.WriteString("</ul>\n")
+	}
+
+	if digest.ClearedZoneCount > 0 {
+		cleared := s.translationService.Render("digest_zones_cleared", lang, "", Params{"count": digest.ClearedZoneCount})
+		fmt.Fprintf(&b, "<p>%s</p>\n", cleared.Body)
+	}
+
+	return b.String()
+}
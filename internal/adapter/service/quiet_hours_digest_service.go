@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/risk-place-angola/backend-risk-place/internal/application/port"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+	domainrepository "github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
+)
+
+const (
+	quietHoursDigestEventKey = "quiet_hours_digest"
+	// quietHoursDigestBatchSize is the number of devices digested per batch.
+	quietHoursDigestBatchSize = 1000
+)
+
+// QuietHoursDigestService delivers notifications held during night mode as a
+// single morning digest per device.
+type QuietHoursDigestService struct {
+	heldRepo           domainrepository.HeldNotificationRepository
+	pushService        port.NotifierPushService
+	translationService *TranslationService
+}
+
+func NewQuietHoursDigestService(
+	heldRepo domainrepository.HeldNotificationRepository,
+	pushService port.NotifierPushService,
+	translationService *TranslationService,
+) *QuietHoursDigestService {
+	return &QuietHoursDigestService{
+		heldRepo:           heldRepo,
+		pushService:        pushService,
+		translationService: translationService,
+	}
+}
+
+// SendDueDigests sends batches of digests until no held notification is due.
+func (s *QuietHoursDigestService) SendDueDigests(ctx context.Context) error {
+	now := time.Now()
+	for ctx.Err() == nil {
+		devices, err := s.sendBatch(ctx, now)
+		if err != nil {
+			return err
+		}
+		if devices < quietHoursDigestBatchSize {
+			return nil
+		}
+	}
+	return ctx.Err()
+}
+
+func (s *QuietHoursDigestService) sendBatch(ctx context.Context, now time.Time) (int, error) {
+	due, err := s.heldRepo.ListDue(ctx, now, quietHoursDigestBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list held notifications: %w", err)
+	}
+
+	if len(due) == 0 {
+		return 0, nil
+	}
+
+	byToken := make(map[string][]*model.HeldNotification)
+	order := make([]string, 0)
+	for _, n := range due {
+		if _, ok := byToken[n.FCMToken]; !ok {
+			order = append(order, n.FCMToken)
+		}
+		byToken[n.FCMToken] = append(byToken[n.FCMToken], n)
+	}
+
+	delivered := make([]uuid.UUID, 0, len(due))
+	for _, token := range order {
+		held := byToken[token]
+
+		lang := s.translationService.ParseLanguage(held[0].Language)
+		msg := s.translationService.Render(quietHoursDigestEventKey, lang, "", Params{"count": len(held)})
+
+		data := map[string]string{
+			"type":  quietHoursDigestEventKey,
+			"count": fmt.Sprintf("%d", len(held)),
+		}
+
+		if err := s.pushService.NotifyPush(ctx, token, msg.Title, msg.Body, data); err != nil {
+			slog.Error("failed to send quiet hours digest", "error", err, "count", len(held))
+		}
+
+		// Drop the held rows even when delivery fails so a dead token does not
+		// keep the digest alive forever.
+		for _, n := range held {
+			delivered = append(delivered, n.ID)
+		}
+	}
+
+	if err := s.heldRepo.DeleteByIDs(ctx, delivered); err != nil {
+		return 0, fmt.Errorf("failed to delete delivered held notifications: %w", err)
+	}
+
+	slog.Info("quiet hours digests sent", "devices", len(order), "notifications", len(delivered))
+
+	return len(order), nil
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"sync"
+)
+
+type languageContextKey struct{}
+
+// requestLanguage defers resolution until something is actually localized,
+// because the device language fallback costs a database lookup.
+type requestLanguage struct {
+	once    sync.Once
+	resolve func() Language
+	lang    Language
+}
+
+// WithLanguageResolver attaches the request's language to ctx. resolve runs at
+// most once, on first use.
+func WithLanguageResolver(ctx context.Context, resolve func() Language) context.Context {
+	return context.WithValue(ctx, languageContextKey{}, &requestLanguage{resolve: resolve})
+}
+
+func WithLanguage(ctx context.Context, lang Language) context.Context {
+	return WithLanguageResolver(ctx, func() Language { return lang })
+}
+
+// LanguageFromContext returns the language negotiated for the request, or
+// DefaultLanguage outside of one.
+func LanguageFromContext(ctx context.Context) Language {
+	if lang, ok := RequestLanguage(ctx); ok {
+		return lang
+	}
+	return DefaultLanguage
+}
+
+// RequestLanguage reports the negotiated language only when ctx carries one.
+func RequestLanguage(ctx context.Context) (Language, bool) {
+	rl, ok := ctx.Value(languageContextKey{}).(*requestLanguage)
+	if !ok {
+		return "", false
+	}
+
+	rl.once.Do(func() {
+		rl.lang = rl.resolve()
+	})
+
+	return rl.lang, rl.lang != ""
+}
@@ -1,313 +1,251 @@
 package service
 
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
 type Language string
 
 const (
 	LanguagePortuguese Language = "pt"
 	LanguageEnglish    Language = "en"
+	LanguageFrench     Language = "fr"
+	LanguageUmbundu    Language = "umb"
+	LanguageKimbundu   Language = "kmb"
+	LanguageKikongo    Language = "kg"
+
+	// DefaultLanguage terminates every fallback chain, so it must ship a
+	// complete catalog.
+	DefaultLanguage = LanguagePortuguese
+)
+
+const (
+	pluralRuleOne     = "one"      // singular only for 1 (en, umb, kmb, kg)
+	pluralRuleZeroOne = "zero_one" // singular for 0 and 1 (pt, fr)
+
+	pluralCountParam = "count"
 )
 
+// Catalogs shipped with the binary. Files in the optional external directory
+// are layered on top so copy can be fixed without a redeploy.
+//
+//go:embed locales/*.json
+var embeddedLocales embed.FS
+
 type NotificationMessage struct {
 	Title string
 	Body  string
 }
 
+// Params are the named values substituted into {placeholders}. The "count"
+// entry also selects the plural form.
+type Params map[string]any
+
+type catalogFile struct {
+	Locale     string                  `json:"locale"`
+	Name       string                  `json:"name"`
+	Aliases    []string                `json:"aliases"`
+	Fallback   []string                `json:"fallback"`
+	PluralRule string                  `json:"plural_rule"`
+	Messages   map[string]catalogEntry `json:"messages"`
+}
+
+type catalogEntry struct {
+	Title pluralText `json:"title"`
+	Body  pluralText `json:"body"`
+}
+
+// pluralText is either a plain string or an object with "one" and "other"
+// forms.
+type pluralText struct {
+	One   string `json:"one"`
+	Other string `json:"other"`
+}
+
+func (p *pluralText) UnmarshalJSON(data []byte) error {
+	var plain string
+	if err := json.Unmarshal(data, &plain); err == nil {
+		p.One, p.Other = plain, plain
+		return nil
+	}
+
+	type forms pluralText
+	var f forms
+	if err := json.Unmarshal(data, &f); err != nil {
+		return fmt.Errorf("message must be a string or an object with one/other forms: %w", err)
+	}
+	if f.One == "" {
+		f.One = f.Other
+	}
+	*p = pluralText(f)
+	return nil
+}
+
+func (p pluralText) form(rule string, count int, hasCount bool) string {
+	if !hasCount {
+		return p.Other
+	}
+	singular := count == 1
+	if rule == pluralRuleZeroOne {
+		singular = count == 0 || count == 1
+	}
+	if singular {
+		return p.One
+	}
+	return p.Other
+}
+
+type locale struct {
+	code       Language
+	name       string
+	fallback   []Language
+	pluralRule string
+	messages   map[string]catalogEntry
+}
+
 type TranslationService struct {
-	messages map[string]map[Language]NotificationMessage
+	locales map[Language]*locale
+	aliases map[string]Language
 }
 
-func NewTranslationService() *TranslationService {
+// NewTranslationService loads the embedded catalogs and then any *.json
+// catalogs found in externalDir, which may add locales or override
+// individual messages. An empty externalDir uses the embedded catalogs only.
+func NewTranslationService(externalDir string) (*TranslationService, error) {
 	ts := &TranslationService{
-		messages: make(map[string]map[Language]NotificationMessage),
+		locales: make(map[Language]*locale),
+		aliases: make(map[string]Language),
+	}
+
+	embedded, err := fs.Sub(embeddedLocales, "locales")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open embedded locales: %w", err)
+	}
+	if err := ts.loadCatalogs(embedded); err != nil {
+		return nil, fmt.Errorf("failed to load embedded locales: %w", err)
+	}
+
+	if externalDir != "" {
+		if err := ts.loadCatalogs(os.DirFS(externalDir)); err != nil {
+			return nil, fmt.Errorf("failed to load locales from %s: %w", externalDir, err)
+		}
 	}
-	ts.loadMessages()
-	return ts
+
+	if err := ts.validate(); err != nil {
+		return nil, err
+	}
+
+	return ts, nil
 }
 
-func (ts *TranslationService) loadMessages() {
-	ts.messages["alert_created"] = map[Language]NotificationMessage{
-		LanguagePortuguese: {
-			Title: "🚨 Alerta de Segurança",
-			Body:  "Um novo alerta foi criado na sua área",
-		},
-		LanguageEnglish: {
-			Title: "🚨 Safety Alert",
-			Body:  "A new alert has been created in your area",
-		},
-	}
-
-	ts.messages["alert_created_crime"] = map[Language]NotificationMessage{
-		LanguagePortuguese: {
-			Title: "🚨 Alerta de Crime",
-			Body:  "Actividade criminosa reportada próxima de si",
-		},
-		LanguageEnglish: {
-			Title: "🚨 Crime Alert",
-			Body:  "Criminal activity reported near you",
-		},
-	}
-
-	ts.messages["alert_created_accident"] = map[Language]NotificationMessage{
-		LanguagePortuguese: {
-			Title: "🚨 Alerta de Acidente",
-			Body:  "Acidente reportado na sua área",
-		},
-		LanguageEnglish: {
-			Title: "🚨 Accident Alert",
-			Body:  "Accident reported in your area",
-		},
-	}
-
-	ts.messages["alert_created_fire"] = map[Language]NotificationMessage{
-		LanguagePortuguese: {
-			Title: "🔥 Alerta de Incêndio",
-			Body:  "Incêndio reportado próximo de si",
-		},
-		LanguageEnglish: {
-			Title: "🔥 Fire Alert",
-			Body:  "Fire reported near you",
-		},
-	}
-
-	ts.messages["alert_created_natural_disaster"] = map[Language]NotificationMessage{
-		LanguagePortuguese: {
-			Title: "⚠️ Alerta de Desastre Natural",
-			Body:  "Desastre natural na sua região",
-		},
-		LanguageEnglish: {
-			Title: "⚠️ Natural Disaster Alert",
-			Body:  "Natural disaster in your region",
-		},
-	}
-
-	ts.messages["alert_created_violence"] = map[Language]NotificationMessage{
-		LanguagePortuguese: {
-			Title: "⚠️ Alerta de Violência",
-			Body:  "Incidente violento reportado na área",
-		},
-		LanguageEnglish: {
-			Title: "⚠️ Violence Alert",
-			Body:  "Violent incident reported in the area",
-		},
-	}
-
-	ts.messages["alert_created_health"] = map[Language]NotificationMessage{
-		LanguagePortuguese: {
-			Title: "🏥 Alerta de Emergência Médica",
-			Body:  "Emergência médica na sua área",
-		},
-		LanguageEnglish: {
-			Title: "🏥 Medical Emergency Alert",
-			Body:  "Medical emergency in your area",
-		},
-	}
-
-	ts.messages["alert_created_infrastructure"] = map[Language]NotificationMessage{
-		LanguagePortuguese: {
-			Title: "⚠️ Alerta de Infraestrutura",
-			Body:  "Problema de infraestrutura na área",
-		},
-		LanguageEnglish: {
-			Title: "⚠️ Infrastructure Alert",
-			Body:  "Infrastructure issue in the area",
-		},
-	}
-
-	ts.messages["alert_created_environment"] = map[Language]NotificationMessage{
-		LanguagePortuguese: {
-			Title: "🌍 Alerta Ambiental",
-			Body:  "Risco ambiental detectado",
-		},
-		LanguageEnglish: {
-			Title: "🌍 Environmental Alert",
-			Body:  "Environmental hazard detected",
-		},
-	}
-
-	ts.messages["alert_created_public_safety"] = map[Language]NotificationMessage{
-		LanguagePortuguese: {
-			Title: "👮 Alerta de Segurança Pública",
-			Body:  "Situação de segurança na sua área",
-		},
-		LanguageEnglish: {
-			Title: "👮 Public Safety Alert",
-			Body:  "Safety situation in your area",
-		},
-	}
-
-	ts.messages["alert_created_traffic"] = map[Language]NotificationMessage{
-		LanguagePortuguese: {
-			Title: "🚦 Alerta de Trânsito",
-			Body:  "Problema de trânsito na área",
-		},
-		LanguageEnglish: {
-			Title: "🚦 Traffic Alert",
-			Body:  "Traffic issue in the area",
-		},
-	}
-
-	ts.messages["alert_created_urban_issue"] = map[Language]NotificationMessage{
-		LanguagePortuguese: {
-			Title: "🏙️ Alerta Urbano",
-			Body:  "Problema urbano reportado",
-		},
-		LanguageEnglish: {
-			Title: "🏙️ Urban Alert",
-			Body:  "Urban issue reported",
-		},
-	}
-
-	ts.messages["report_created"] = map[Language]NotificationMessage{
-		LanguagePortuguese: {
-			Title: "📍 Novo Relato",
-			Body:  "Novo relato de risco na sua área",
-		},
-		LanguageEnglish: {
-			Title: "📍 New Report",
-			Body:  "New risk report in your area",
-		},
-	}
-
-	ts.messages["report_verified"] = map[Language]NotificationMessage{
-		LanguagePortuguese: {
-			Title: "✅ Relato Verificado",
-			Body:  "Seu relato foi verificado pelas autoridades",
-		},
-		LanguageEnglish: {
-			Title: "✅ Report Verified",
-			Body:  "Your report has been verified by authorities",
-		},
-	}
-
-	ts.messages["report_resolved"] = map[Language]NotificationMessage{
-		LanguagePortuguese: {
-			Title: "✅ Relato Resolvido",
-			Body:  "O relato na sua área foi resolvido",
-		},
-		LanguageEnglish: {
-			Title: "✅ Report Resolved",
-			Body:  "The report in your area has been resolved",
-		},
-	}
-
-	ts.messages["verification_code_sms"] = map[Language]NotificationMessage{
-		LanguagePortuguese: {
-			Title: "Seu código de verificação Risk Place",
-			Body:  "Válido por 10 minutos",
-		},
-		LanguageEnglish: {
-			Title: "Your Risk Place verification code",
-			Body:  "Valid for 10 minutes",
-		},
-	}
-
-	ts.messages["verification_code_email"] = map[Language]NotificationMessage{
-		LanguagePortuguese: {
-			Title: "Verificação de Conta - Risk Place Angola",
-			Body:  "Seu código de verificação",
-		},
-		LanguageEnglish: {
-			Title: "Account Verification - Risk Place Angola",
-			Body:  "Your verification code",
-		},
-	}
-
-	ts.messages["verification_code_wait"] = map[Language]NotificationMessage{
-		LanguagePortuguese: {
-			Title: "Aguarde",
-			Body:  "Código já enviado, por favor aguarde",
-		},
-		LanguageEnglish: {
-			Title: "Please Wait",
-			Body:  "Code already sent, please wait",
-		},
-	}
-
-	ts.messages["verification_locked"] = map[Language]NotificationMessage{
-		LanguagePortuguese: {
-			Title: "Conta Bloqueada",
-			Body:  "Muitas tentativas incorretas. Aguarde 15 minutos",
-		},
-		LanguageEnglish: {
-			Title: "Account Locked",
-			Body:  "Too many incorrect attempts. Wait 15 minutes",
-		},
-	}
-
-	ts.messages["verification_resend_cooldown"] = map[Language]NotificationMessage{
-		LanguagePortuguese: {
-			Title: "Aguarde",
-			Body:  "Aguarde 60 segundos antes de reenviar",
-		},
-		LanguageEnglish: {
-			Title: "Please Wait",
-			Body:  "Wait 60 seconds before resending",
-		},
-	}
-
-	ts.messages["password_reset_sms"] = map[Language]NotificationMessage{
-		LanguagePortuguese: {
-			Title: "Seu código de redefinição de senha Risk Place",
-			Body:  "Válido por 10 minutos",
-		},
-		LanguageEnglish: {
-			Title: "Your Risk Place password reset code",
-			Body:  "Valid for 10 minutes",
-		},
-	}
-
-	ts.messages["password_reset_email"] = map[Language]NotificationMessage{
-		LanguagePortuguese: {
-			Title: "Redefinição de Senha - Risk Place Angola",
-			Body:  "Seu código de redefinição de senha",
-		},
-		LanguageEnglish: {
-			Title: "Password Reset - Risk Place Angola",
-			Body:  "Your password reset code",
-		},
-	}
-
-	ts.messages["code_expired"] = map[Language]NotificationMessage{
-		LanguagePortuguese: {
-			Title: "Código Expirado",
-			Body:  "O código expirou após 10 minutos. Solicite um novo código",
-		},
-		LanguageEnglish: {
-			Title: "Code Expired",
-			Body:  "The code expired after 10 minutes. Request a new code",
-		},
-	}
-
-	ts.messages["invalid_code"] = map[Language]NotificationMessage{
-		LanguagePortuguese: {
-			Title: "Código Inválido",
-			Body:  "O código informado está incorreto. Tente novamente",
-		},
-		LanguageEnglish: {
-			Title: "Invalid Code",
-			Body:  "The code entered is incorrect. Try again",
-		},
+func (ts *TranslationService) loadCatalogs(fsys fs.FS) error {
+	files, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
+
+	for _, name := range files {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", name, err)
+		}
+
+		var file catalogFile
+		if err := json.Unmarshal(data, &file); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+		if file.Locale == "" {
+			file.Locale = strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+		}
+
+		ts.merge(file)
 	}
+
+	return nil
 }
 
-func (ts *TranslationService) GetMessage(key string, lang Language, riskType string) NotificationMessage {
-	if riskType != "" {
-		specificKey := key + "_" + riskType
-		if msgs, exists := ts.messages[specificKey]; exists {
-			if msg, ok := msgs[lang]; ok {
-				return msg
-			}
+func (ts *TranslationService) merge(file catalogFile) {
+	code := Language(normalizeLanguageTag(file.Locale))
+
+	loc, ok := ts.locales[code]
+	if !ok {
+		loc = &locale{code: code, pluralRule: pluralRuleOne, messages: make(map[string]catalogEntry)}
+		ts.locales[code] = loc
+	}
+
+	if file.Name != "" {
+		loc.name = file.Name
+	}
+	if file.PluralRule != "" {
+		loc.pluralRule = file.PluralRule
+	}
+	if len(file.Fallback) > 0 {
+		loc.fallback = loc.fallback[:0]
+		for _, fb := range file.Fallback {
+			loc.fallback = append(loc.fallback, Language(normalizeLanguageTag(fb)))
 		}
 	}
+	for _, alias := range file.Aliases {
+		ts.aliases[normalizeLanguageTag(alias)] = code
+	}
+	for key, entry := range file.Messages {
+		loc.messages[key] = entry
+	}
+}
+
+func (ts *TranslationService) validate() error {
+	if _, ok := ts.locales[DefaultLanguage]; !ok {
+		return fmt.Errorf("default locale %q has no catalog", DefaultLanguage)
+	}
 
-	if msgs, exists := ts.messages[key]; exists {
-		if msg, ok := msgs[lang]; ok {
-			return msg
+	var errs []error
+	for code, loc := range ts.locales {
+		if loc.pluralRule != pluralRuleOne && loc.pluralRule != pluralRuleZeroOne {
+			errs = append(errs, fmt.Errorf("locale %q: unknown plural rule %q", code, loc.pluralRule))
 		}
-		if msg, ok := msgs[LanguagePortuguese]; ok {
-			return msg
+		for _, fb := range loc.fallback {
+			if _, ok := ts.locales[fb]; !ok {
+				errs = append(errs, fmt.Errorf("locale %q: unknown fallback locale %q", code, fb))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Languages returns every locale that ships its own copy, sorted by code.
+func (ts *TranslationService) Languages() []Language {
+	langs := make([]Language, 0, len(ts.locales))
+	for code, loc := range ts.locales {
+		if loc.hasCopy() {
+			langs = append(langs, code)
 		}
 	}
+	sort.Slice(langs, func(i, j int) bool { return langs[i] < langs[j] })
+	return langs
+}
+
+func (ts *TranslationService) GetMessage(key string, lang Language, riskType string) NotificationMessage {
+	return ts.Render(key, lang, riskType, nil)
+}
+
+// Render looks the message up along the language's fallback chain, preferring
+// the risk type specific variant in each locale, then fills in placeholders.
+func (ts *TranslationService) Render(key string, lang Language, riskType string, params Params) NotificationMessage {
+	if msg, ok := ts.Lookup(key, lang, riskType, params); ok {
+		return msg
+	}
 
 	return NotificationMessage{
 		Title: "Risk Place",
@@ -315,13 +253,176 @@ func (ts *TranslationService) GetMessage(key string, lang Language, riskType str
 	}
 }
 
+// Lookup is Render without the generic default, for callers that have a
+// better fallback of their own.
+func (ts *TranslationService) Lookup(key string, lang Language, riskType string, params Params) (NotificationMessage, bool) {
+	for _, loc := range ts.chain(lang) {
+		entry, ok := loc.lookup(key, riskType)
+		if !ok {
+			continue
+		}
+
+		count, hasCount := countParam(params)
+		replacer := placeholderReplacer(params)
+
+		return NotificationMessage{
+			Title: replacer.Replace(entry.Title.form(loc.pluralRule, count, hasCount)),
+			Body:  replacer.Replace(entry.Body.form(loc.pluralRule, count, hasCount)),
+		}, true
+	}
+
+	return NotificationMessage{}, false
+}
+
+// Localize renders the body of a catalog message in the language negotiated
+// for the request carried by ctx.
+func (ts *TranslationService) Localize(ctx context.Context, key string, params map[string]any) string {
+	return ts.Render(key, LanguageFromContext(ctx), "", params).Body
+}
+
+// LocalizeFor renders the body of a catalog message in a stored device
+// language such as "pt_AO".
+func (ts *TranslationService) LocalizeFor(language, key string, params map[string]any) string {
+	return ts.Render(key, ts.ParseLanguage(language), "", params).Body
+}
+
+// ErrorMessage returns the localized text for a stable error code.
+func (ts *TranslationService) ErrorMessage(code string, lang Language) (string, bool) {
+	msg, ok := ts.Lookup("error_"+strings.ToLower(code), lang, "", nil)
+	return msg.Body, ok
+}
+
+// ParseLanguage maps a device or header language tag onto a configured
+// locale, e.g. "pt_AO", "pt-BR" and "Portuguese" all resolve to pt.
+// Unknown tags resolve to DefaultLanguage.
 func (ts *TranslationService) ParseLanguage(lang string) Language {
-	switch lang {
-	case "en", "EN", "english", "English":
-		return LanguageEnglish
-	case "pt", "PT", "portuguese", "Portuguese":
-		return LanguagePortuguese
+	if resolved, ok := ts.match(lang); ok {
+		return resolved
+	}
+	return DefaultLanguage
+}
+
+// NegotiateLanguage picks the best configured locale from an Accept-Language
+// header, honouring q-values. It reports false when nothing matches.
+func (ts *TranslationService) NegotiateLanguage(header string) (Language, bool) {
+	type candidate struct {
+		tag     string
+		quality float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, rest, _ := strings.Cut(strings.TrimSpace(part), ";")
+		quality := 1.0
+		if q, found := strings.CutPrefix(strings.TrimSpace(rest), "q="); found {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if tag == "" || tag == "*" || quality <= 0 {
+			continue
+		}
+		candidates = append(candidates, candidate{tag: tag, quality: quality})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].quality > candidates[j].quality })
+
+	for _, c := range candidates {
+		if lang, ok := ts.match(c.tag); ok {
+			return lang, true
+		}
+	}
+
+	return "", false
+}
+
+func (ts *TranslationService) match(lang string) (Language, bool) {
+	tag := normalizeLanguageTag(lang)
+	if resolved, ok := ts.resolve(tag); ok {
+		return resolved, true
+	}
+
+	if base, _, found := strings.Cut(tag, "-"); found {
+		return ts.resolve(base)
+	}
+
+	return "", false
+}
+
+// resolve only matches locales with copy of their own. A registered locale
+// without messages would render Portuguese under another name, so clients
+// asking for it are left to negotiate their next preference instead.
+func (ts *TranslationService) resolve(tag string) (Language, bool) {
+	code := Language(tag)
+	if _, ok := ts.locales[code]; !ok {
+		code = ts.aliases[tag]
+	}
+	if loc, ok := ts.locales[code]; ok && loc.hasCopy() {
+		return code, true
+	}
+	return "", false
+}
+
+func (ts *TranslationService) chain(lang Language) []*locale {
+	seen := make(map[Language]bool)
+	var chain []*locale
+
+	var walk func(code Language)
+	walk = func(code Language) {
+		loc, ok := ts.locales[code]
+		if !ok || seen[code] {
+			return
+		}
+		seen[code] = true
+		chain = append(chain, loc)
+		for _, fb := range loc.fallback {
+			walk(fb)
+		}
+	}
+
+	walk(lang)
+	walk(DefaultLanguage)
+
+	return chain
+}
+
+func (l *locale) hasCopy() bool {
+	return len(l.messages) > 0
+}
+
+func (l *locale) lookup(key, riskType string) (catalogEntry, bool) {
+	if riskType != "" {
+		if entry, ok := l.messages[key+"_"+riskType]; ok {
+			return entry, true
+		}
+	}
+	entry, ok := l.messages[key]
+	return entry, ok
+}
+
+func countParam(params Params) (int, bool) {
+	switch v := params[pluralCountParam].(type) {
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	case float64:
+		return int(v), true
 	default:
-		return LanguagePortuguese
+		return 0, false
 	}
 }
+
+func placeholderReplacer(params Params) *strings.Replacer {
+	pairs := make([]string, 0, len(params)*2) //nolint:mnd // placeholder and value
+	for name, value := range params {
+		pairs = append(pairs, "{"+name+"}", fmt.Sprint(value))
+	}
+	return strings.NewReplacer(pairs...)
+}
+
+func normalizeLanguageTag(tag string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(tag)), "_", "-")
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranslationService_RenderPluralsAndFallbacks(t *testing.T) {
+	ts, err := NewTranslationService("")
+	assert.NoError(t, err)
+
+	one := ts.Render("quiet_hours_digest", LanguageEnglish, "", Params{"count": 1})
+	many := ts.Render("quiet_hours_digest", LanguageEnglish, "", Params{"count": 3})
+	assert.Equal(t, "1 notification in your area during night mode", one.Body)
+	assert.Equal(t, "3 notifications in your area during night mode", many.Body)
+
+	// Portuguese and French treat zero as singular.
+	zero := ts.Render("digest_zones_cleared", LanguageFrench, "", Params{"count": 0})
+	assert.Equal(t, "0 zone de danger n'est plus active.", zero.Body)
+
+	// Locales without their own copy fall back to Portuguese.
+	assert.Equal(t,
+		ts.GetMessage("alert_created", LanguagePortuguese, "fire"),
+		ts.GetMessage("alert_created", LanguageUmbundu, "fire"))
+
+	// The generic message is used when no risk type variant exists.
+	assert.Equal(t,
+		ts.GetMessage("alert_created", LanguageEnglish, ""),
+		ts.GetMessage("alert_created", LanguageEnglish, "unknown_type"))
+}
+
+func TestTranslationService_ParseLanguage(t *testing.T) {
+	ts, err := NewTranslationService("")
+	assert.NoError(t, err)
+
+	assert.Equal(t, LanguagePortuguese, ts.ParseLanguage("pt_AO"))
+	assert.Equal(t, LanguageEnglish, ts.ParseLanguage("en-US"))
+	assert.Equal(t, LanguageFrench, ts.ParseLanguage("French"))
+	// Registered locales without their own copy are not advertised.
+	assert.Equal(t, DefaultLanguage, ts.ParseLanguage("kmb"))
+	assert.Equal(t, DefaultLanguage, ts.ParseLanguage("xx"))
+	assert.Equal(t, DefaultLanguage, ts.ParseLanguage(""))
+}
+
+func TestTranslationService_NegotiateLanguage(t *testing.T) {
+	ts, err := NewTranslationService("")
+	assert.NoError(t, err)
+
+	lang, ok := ts.NegotiateLanguage("de-DE, fr-FR;q=0.8, en;q=0.9")
+	assert.True(t, ok)
+	assert.Equal(t, LanguageEnglish, lang)
+
+	lang, ok = ts.NegotiateLanguage("pt-AO")
+	assert.True(t, ok)
+	assert.Equal(t, LanguagePortuguese, lang)
+
+	lang, ok = ts.NegotiateLanguage("umb, en;q=0.5")
+	assert.True(t, ok)
+	assert.Equal(t, LanguageEnglish, lang)
+	assert.NotContains(t, ts.Languages(), LanguageUmbundu)
+
+	_, ok = ts.NegotiateLanguage("de, *;q=0.5")
+	assert.False(t, ok)
+
+	msg, ok := ts.ErrorMessage("NO_EMERGENCY_CONTACTS", LanguageFrench)
+	assert.True(t, ok)
+	assert.Equal(t, "Aucun contact d'urgence configuré", msg)
+}
+
+func TestTranslationService_ExternalCatalogOverrides(t *testing.T) {
+	dir := t.TempDir()
+	catalog := `{
+		"locale": "umb",
+		"messages": {"report_verified": {"title": "Override", "body": "Override body"}}
+	}`
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "umb.json"), []byte(catalog), 0o600))
+
+	ts, err := NewTranslationService(dir)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "Override", ts.GetMessage("report_verified", LanguageUmbundu, "").Title)
+	// Once it has copy the locale is negotiable.
+	assert.Equal(t, LanguageUmbundu, ts.ParseLanguage("umbundu"))
+	// Keys the override does not touch keep falling back.
+	assert.Equal(t,
+		ts.GetMessage("report_resolved", LanguagePortuguese, ""),
+		ts.GetMessage("report_resolved", LanguageUmbundu, ""))
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/risk-place-angola/backend-risk-place/internal/application/port"
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
+)
+
+// unsubscribePurpose keeps these signatures from being valid for any other
+// HMAC issued with the same secret.
+const unsubscribePurpose = "email-unsubscribe:"
+
+// UnsubscribeTokenService signs email unsubscribe links. Tokens carry the
+// user ID and an HMAC over it; they do not expire because mail clients keep
+// old messages around and the link must keep working.
+type UnsubscribeTokenService struct {
+	secret []byte
+}
+
+func NewUnsubscribeTokenService(secret string) port.UnsubscribeTokenSigner {
+	return &UnsubscribeTokenService{secret: []byte(secret)}
+}
+
+func (s *UnsubscribeTokenService) Sign(userID uuid.UUID) string {
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString(userID[:]) + "." + enc.EncodeToString(s.mac(userID))
+}
+
+func (s *UnsubscribeTokenService) Verify(token string) (uuid.UUID, error) {
+	enc := base64.RawURLEncoding
+
+	idPart, macPart, ok := strings.Cut(token, ".")
+	if !ok {
+		return uuid.Nil, domainErrors.ErrInvalidUnsubscribeToken
+	}
+
+	rawID, err := enc.DecodeString(idPart)
+	if err != nil {
+		return uuid.Nil, domainErrors.ErrInvalidUnsubscribeToken
+	}
+	userID, err := uuid.FromBytes(rawID)
+	if err != nil {
+		return uuid.Nil, domainErrors.ErrInvalidUnsubscribeToken
+	}
+
+	sig, err := enc.DecodeString(macPart)
+	if err != nil || !hmac.Equal(sig, s.mac(userID)) {
+		return uuid.Nil, domainErrors.ErrInvalidUnsubscribeToken
+	}
+
+	return userID, nil
+}
+
+func (s *UnsubscribeTokenService) mac(userID uuid.UUID) []byte {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write([]byte(unsubscribePurpose + userID.String()))
+	return h.Sum(nil)
+}
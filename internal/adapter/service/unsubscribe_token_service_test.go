@@ -0,0 +1,30 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnsubscribeTokenService_SignVerify(t *testing.T) {
+	signer := NewUnsubscribeTokenService("secret")
+	userID := uuid.New()
+
+	got, err := signer.Verify(signer.Sign(userID))
+	assert.NoError(t, err)
+	assert.Equal(t, userID, got)
+
+	// A token for another user cannot be forged by swapping the ID part.
+	other := signer.Sign(uuid.New())
+	forged := signer.Sign(userID)[:22] + other[22:]
+	_, err = signer.Verify(forged)
+	assert.ErrorIs(t, err, domainErrors.ErrInvalidUnsubscribeToken)
+
+	_, err = NewUnsubscribeTokenService("another-secret").Verify(signer.Sign(userID))
+	assert.ErrorIs(t, err, domainErrors.ErrInvalidUnsubscribeToken)
+
+	_, err = signer.Verify("garbage")
+	assert.ErrorIs(t, err, domainErrors.ErrInvalidUnsubscribeToken)
+}
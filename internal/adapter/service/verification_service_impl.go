@@ -182,7 +182,13 @@ func (s *verificationServiceImpl) incrementAttempts(ctx context.Context, attempt
 	return s.cache.Set(ctx, attemptsKey, fmt.Sprintf("%d", attempts), verificationCodeTTL)
 }
 
+// getUserLanguage prefers the language negotiated for the current request so
+// the code arrives in the same language as the API responses.
 func (s *verificationServiceImpl) getUserLanguage(ctx context.Context, userID uuid.UUID) Language {
+	if lang, ok := RequestLanguage(ctx); ok {
+		return lang
+	}
+
 	language, _, err := s.userRepo.GetUserLanguageAndPhone(ctx, userID)
 	if err != nil {
 		slog.Warn("Failed to get user language, using default", "user_id", userID, "error", err)
@@ -306,7 +306,7 @@ func (h *Hub) handleIncomingMessage(ctx context.Context, c *Client, raw []byte)
 	}
 }
 
-func (h *Hub) BroadcastAlert(ctx context.Context, alertID string, message string, lat, lon, radius float64, severity string) {
+func (h *Hub) BroadcastAlert(ctx context.Context, alertID string, message string, lat, lon, radius float64, severity string, riskTypeID, riskTopicID uuid.UUID) {
 	userIDs, err := h.locationStore.FindUsersInRadius(ctx, lat, lon, radius)
 	if err != nil {
 		slog.Error("error finding nearby users", "error", err)
@@ -345,7 +345,7 @@ func (h *Hub) BroadcastAlert(ctx context.Context, alertID string, message string
 
 			isHighRiskTime := h.settingsChecker.IsInHighRiskTime(ctx, userUUID, deviceID)
 			if !isHighRiskTime {
-				if !h.settingsChecker.CanReceiveAlerts(ctx, userUUID, deviceID, severity, int(distanceMeters)) {
+				if !h.settingsChecker.CanReceiveAlerts(ctx, userUUID, deviceID, severity, riskTypeID, riskTopicID, int(distanceMeters)) {
 					continue
 				}
 			} else {
@@ -370,7 +370,7 @@ func (h *Hub) BroadcastAlert(ctx context.Context, alertID string, message string
 	slog.Info("alert broadcast completed", "alert_id", alertID, "notified_users", notifiedCount, "potential_users", len(userIDs))
 }
 
-func (h *Hub) BroadcastReport(ctx context.Context, reportID, message string, lat, lon, radius float64, isVerified bool) {
+func (h *Hub) BroadcastReport(ctx context.Context, reportID, message string, lat, lon, radius float64, isVerified bool, riskTypeID, riskTopicID uuid.UUID) {
 	userIDs, err := h.locationStore.FindUsersInRadius(ctx, lat, lon, radius)
 	if err != nil {
 		slog.Error("error finding nearby users for report", "error", err)
@@ -407,7 +407,7 @@ func (h *Hub) BroadcastReport(ctx context.Context, reportID, message string, lat
 
 			distanceMeters := h.calculateDistance(lat, lon, client.lastLat, client.lastLon)
 
-			if !h.settingsChecker.CanReceiveReports(ctx, userUUID, deviceID, isVerified, int(distanceMeters)) {
+			if !h.settingsChecker.CanReceiveReports(ctx, userUUID, deviceID, isVerified, riskTypeID, riskTopicID, int(distanceMeters)) {
 				continue
 			}
 
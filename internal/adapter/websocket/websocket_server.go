@@ -8,6 +8,7 @@ import (
 	"github.com/gorilla/websocket"
 	"github.com/risk-place-angola/backend-risk-place/internal/adapter/http/middleware"
 	"github.com/risk-place-angola/backend-risk-place/internal/adapter/http/util"
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
 )
 
 type WSHandler struct {
@@ -42,7 +43,7 @@ func (h *WSHandler) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 	identifier, isAuthenticated, err := h.OptionalMiddleware.ExtractIdentifier(r)
 	if err != nil {
 		slog.Error("failed to extract identifier", slog.Any("error", err))
-		util.Error(w, "unauthorized: JWT or X-Device-ID header required", http.StatusUnauthorized)
+		util.Error(w, domainErrors.ErrCredentialsRequired, http.StatusUnauthorized)
 		return
 	}
 
@@ -4,6 +4,8 @@ import (
 	"github.com/risk-place-angola/backend-risk-place/internal/application/port"
 	"github.com/risk-place-angola/backend-risk-place/internal/application/usecase/alert"
 	"github.com/risk-place-angola/backend-risk-place/internal/application/usecase/dangerzone"
+	"github.com/risk-place-angola/backend-risk-place/internal/application/usecase/digest"
+	"github.com/risk-place-angola/backend-risk-place/internal/application/usecase/emailnotification"
 	"github.com/risk-place-angola/backend-risk-place/internal/application/usecase/emergencycontact"
 	"github.com/risk-place-angola/backend-risk-place/internal/application/usecase/locationsharing"
 	"github.com/risk-place-angola/backend-risk-place/internal/application/usecase/myalerts"
@@ -11,7 +13,10 @@ import (
 	"github.com/risk-place-angola/backend-risk-place/internal/application/usecase/risk"
 	"github.com/risk-place-angola/backend-risk-place/internal/application/usecase/saferoute"
 	"github.com/risk-place-angola/backend-risk-place/internal/application/usecase/safetysettings"
+	"github.com/risk-place-angola/backend-risk-place/internal/application/usecase/smsreport"
+	"github.com/risk-place-angola/backend-risk-place/internal/application/usecase/trip"
 	"github.com/risk-place-angola/backend-risk-place/internal/application/usecase/user"
+	"github.com/risk-place-angola/backend-risk-place/internal/application/usecase/ussd"
 	"github.com/risk-place-angola/backend-risk-place/internal/config"
 	domainrepository "github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
 	domainService "github.com/risk-place-angola/backend-risk-place/internal/domain/service"
@@ -22,13 +27,20 @@ type Application struct {
 	AlertUseCase              *alert.AlertUseCase
 	ReportUseCase             *report.ReportUseCase
 	RiskUseCase               *risk.RiskUseCase
+	IncidentWeightUseCase     *risk.IncidentWeightUseCase
 	LocationSharingUseCase    *locationsharing.LocationSharingUseCase
 	SafeRouteUseCase          *saferoute.SafeRouteUseCase
+	TripUseCase               *trip.TripUseCase
 	EmergencyContactUseCase   *emergencycontact.EmergencyContactUseCase
 	EmergencyAlertUseCase     *emergencycontact.EmergencyAlertUseCase
 	MyAlertsUseCase           *myalerts.MyAlertsUseCase
 	SafetySettingsUseCase     *safetysettings.SafetySettingsUseCase
 	DangerZoneUseCase         *dangerzone.DangerZoneUseCase
+	ManualDangerZoneUseCase   *dangerzone.ManualDangerZoneUseCase
+	DigestUseCase             *digest.DigestUseCase
+	EmailNotificationUseCase  *emailnotification.EmailNotificationUseCase
+	SMSReportUseCase          *smsreport.SMSReportUseCase
+	USSDUseCase               *ussd.USSDUseCase
 	ReportVerificationService domainService.ReportVerificationService
 }
 
@@ -44,20 +56,51 @@ func NewUserApplication(
 	safeRouteRepo domainrepository.SafeRouteRepository,
 	emergencyContactRepo domainrepository.EmergencyContactRepository,
 	safetySettingsRepo domainrepository.SafetySettingsRepository,
+	digestRepo domainrepository.DigestRepository,
+	emailNotificationRepo domainrepository.EmailNotificationRepository,
+	smsReportRepo domainrepository.SMSReportRepository,
+	manualDangerZoneRepo domainrepository.ManualDangerZoneRepository,
+	incidentWeightRuleRepo domainrepository.IncidentWeightRuleRepository,
+	tripRepo domainrepository.TripRepository,
 
 	token port.TokenGenerator,
 	hasher port.PasswordHasher,
 	emailService port.EmailService,
 	smsNotifier port.NotifierSMSService,
+	localizer port.Localizer,
+	unsubscribeSigner port.UnsubscribeTokenSigner,
+	ussdSessions port.KVCache,
 	config *config.Config,
 	locationStore port.LocationStore,
 	geoService port.GeolocationService,
-	eventDispatcher port.EventDispatcher,
+	eventDispatcher port.EventOutbox,
+	txManager port.TransactionManager,
 	migrationService domainService.AnonymousMigrationService,
 	verificationService domainService.VerificationService,
 	storageService port.StorageService,
 	dangerZoneService domainService.DangerZoneService,
+	routePlanner *domainService.SafeRoutePlanner,
+	incidentWeighting domainService.IncidentWeightingService,
+	tripMonitor *domainService.TripMonitorService,
 ) *Application {
+	reportUseCase := report.NewReportUseCase(
+		reportRepo,
+		eventDispatcher,
+		geoService,
+		riskTypeRepo,
+		riskTopicRepo,
+		safetySettingsRepo,
+		locationStore,
+		txManager,
+	)
+
+	emergencyAlertUseCase := emergencycontact.NewEmergencyAlertUseCase(
+		emergencyContactRepo,
+		userRepo,
+		smsNotifier,
+		localizer,
+	)
+
 	return &Application{
 		UserUseCase: user.NewUserUseCase(
 			userRepo,
@@ -75,20 +118,19 @@ func NewUserApplication(
 			alertRepo,
 			riskTypeRepo,
 			eventDispatcher,
+			txManager,
 		),
-		ReportUseCase: report.NewReportUseCase(
-			reportRepo,
-			eventDispatcher,
-			geoService,
+		ReportUseCase: reportUseCase,
+		RiskUseCase: risk.NewRiskUseCase(
 			riskTypeRepo,
 			riskTopicRepo,
-			safetySettingsRepo,
-			locationStore,
+			storageService,
 		),
-		RiskUseCase: risk.NewRiskUseCase(
+		IncidentWeightUseCase: risk.NewIncidentWeightUseCase(
+			incidentWeightRuleRepo,
 			riskTypeRepo,
 			riskTopicRepo,
-			storageService,
+			incidentWeighting,
 		),
 		LocationSharingUseCase: locationsharing.NewLocationSharingUseCase(
 			locationSharingRepo,
@@ -100,15 +142,19 @@ func NewUserApplication(
 		SafeRouteUseCase: saferoute.NewSafeRouteUseCase(
 			safeRouteRepo,
 			userRepo,
+			dangerZoneService,
+			routePlanner,
+			incidentWeighting,
 		),
-		EmergencyContactUseCase: emergencycontact.NewEmergencyContactUseCase(
+		TripUseCase: trip.NewTripUseCase(
+			tripRepo,
 			emergencyContactRepo,
+			tripMonitor,
 		),
-		EmergencyAlertUseCase: emergencycontact.NewEmergencyAlertUseCase(
+		EmergencyContactUseCase: emergencycontact.NewEmergencyContactUseCase(
 			emergencyContactRepo,
-			userRepo,
-			smsNotifier,
 		),
+		EmergencyAlertUseCase: emergencyAlertUseCase,
 		MyAlertsUseCase: myalerts.NewMyAlertsUseCase(
 			alertRepo,
 			riskTypeRepo,
@@ -120,5 +166,37 @@ func NewUserApplication(
 		DangerZoneUseCase: dangerzone.NewDangerZoneUseCase(
 			dangerZoneService,
 		),
+		ManualDangerZoneUseCase: dangerzone.NewManualDangerZoneUseCase(
+			manualDangerZoneRepo,
+			dangerZoneService,
+		),
+		DigestUseCase: digest.NewDigestUseCase(
+			digestRepo,
+		),
+		EmailNotificationUseCase: emailnotification.NewEmailNotificationUseCase(
+			emailNotificationRepo,
+			unsubscribeSigner,
+			localizer,
+		),
+		SMSReportUseCase: smsreport.NewSMSReportUseCase(
+			reportUseCase,
+			smsReportRepo,
+			riskTypeRepo,
+			riskTopicRepo,
+			userRepo,
+			smsNotifier,
+			localizer,
+		),
+		USSDUseCase: ussd.NewUSSDUseCase(
+			ussdSessions,
+			reportUseCase,
+			emergencyAlertUseCase,
+			smsReportRepo,
+			alertRepo,
+			riskTypeRepo,
+			riskTopicRepo,
+			userRepo,
+			localizer,
+		),
 	}
 }
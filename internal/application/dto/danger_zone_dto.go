@@ -1,23 +1,177 @@
 package dto
 
+import (
+	"time"
+
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+)
+
 type DangerZoneDTO struct {
 	ID           string  `json:"id"`
 	Latitude     float64 `json:"latitude"`
 	Longitude    float64 `json:"longitude"`
 	GridCellID   string  `json:"grid_cell_id"`
+	Precision    int     `json:"precision"`
 	IncidentCount int     `json:"incident_count"`
 	RiskScore    float64 `json:"risk_score"`
 	RiskLevel    string  `json:"risk_level"`
+	// Riskiest is the slot of the week when the zone is most dangerous.
+	Riskiest     *DangerZoneTimeSlotDTO  `json:"riskiest,omitempty"`
+	TimeProfile  []DangerZoneTimeSlotDTO `json:"time_profile,omitempty"`
+	// Manual is set when the zone was drawn by an authority rather than
+	// computed from reports.
+	Manual       *ManualDangerZoneDTO `json:"manual,omitempty"`
 	CalculatedAt string  `json:"calculated_at"`
 }
 
+type DangerZoneTimeSlotDTO struct {
+	DayType       string  `json:"day_type"`
+	StartHour     int     `json:"start_hour"`
+	EndHour       int     `json:"end_hour"`
+	IncidentCount int     `json:"incident_count"`
+	RiskScore     float64 `json:"risk_score"`
+	RiskLevel     string  `json:"risk_level"`
+}
+
 type GetDangerZonesRequest struct {
 	Latitude     float64 `json:"latitude" validate:"required,latitude"`
 	Longitude    float64 `json:"longitude" validate:"required,longitude"`
 	RadiusMeters float64 `json:"radius_meters" validate:"required,min=100,max=10000"`
+	// Zoom is the map zoom level; when set it picks the grid resolution
+	// instead of the radius.
+	Zoom int `json:"zoom,omitempty" validate:"omitempty,min=0,max=22"`
+	// At scores the zones for the time slot it falls in (RFC3339); the
+	// weekly score is returned when it is omitted.
+	At *time.Time `json:"at,omitempty"`
 }
 
 type GetDangerZonesResponse struct {
 	Zones      []DangerZoneDTO `json:"zones"`
 	TotalCount int             `json:"total_count"`
 }
+
+type DangerZoneExplanationDTO struct {
+	GridCellID    string                      `json:"grid_cell_id"`
+	Precision     int                         `json:"precision"`
+	Latitude      float64                     `json:"latitude"`
+	Longitude     float64                     `json:"longitude"`
+	IncidentCount int                         `json:"incident_count"`
+	RawScore      float64                     `json:"raw_score"`
+	RiskScore     float64                     `json:"risk_score"`
+	MaxRiskScore  float64                     `json:"max_risk_score"`
+	RiskLevel     string                      `json:"risk_level"`
+	Breakdown     []DangerZoneRiskTypeDTO     `json:"breakdown"`
+	Contributions []DangerZoneContributionDTO `json:"contributions"`
+	CalculatedAt  string                      `json:"calculated_at"`
+}
+
+type DangerZoneRiskTypeDTO struct {
+	RiskType      string  `json:"risk_type"`
+	IncidentCount int     `json:"incident_count"`
+	Weight        float64 `json:"weight"`
+}
+
+// DangerZoneContributionDTO is one report's part in a zone's score. Private
+// reports are redacted: no report ID and only the day they were made.
+type DangerZoneContributionDTO struct {
+	ReportID           string  `json:"report_id,omitempty"`
+	RiskType           string  `json:"risk_type"`
+	RiskTopic          string  `json:"risk_topic,omitempty"`
+	Verified           bool    `json:"verified"`
+	Redacted           bool    `json:"redacted"`
+	CreatedAt          string  `json:"created_at"`
+	SeverityWeight     float64 `json:"severity_weight"`
+	RecencyWeight      float64 `json:"recency_weight"`
+	VerificationWeight float64 `json:"verification_weight"`
+	Weight             float64 `json:"weight"`
+}
+
+// GetDangerZoneTrendsRequest selects one cell by grid_cell_id, or every cell
+// of a precision inside the bounding box.
+type GetDangerZoneTrendsRequest struct {
+	GridCellID   string  `json:"grid_cell_id,omitempty"`
+	NorthEastLat float64 `json:"north_east_lat,omitempty" validate:"omitempty,latitude"`
+	NorthEastLon float64 `json:"north_east_lon,omitempty" validate:"omitempty,longitude"`
+	SouthWestLat float64 `json:"south_west_lat,omitempty" validate:"omitempty,latitude"`
+	SouthWestLon float64 `json:"south_west_lon,omitempty" validate:"omitempty,longitude"`
+	// Precision of the cells in the bounding box; defaults to 6 (~1.2 km).
+	Precision int `json:"precision,omitempty" validate:"omitempty,min=1,max=12"`
+	// Days of history to return, 14 to 90; defaults to 28.
+	Days int `json:"days,omitempty" validate:"omitempty,min=14,max=90"`
+}
+
+type DangerZoneDailyScoreDTO struct {
+	Date             string  `json:"date"`
+	AverageRiskScore float64 `json:"average_risk_score"`
+	MaxRiskScore     float64 `json:"max_risk_score"`
+	MaxIncidentCount int     `json:"max_incident_count"`
+}
+
+type DangerZoneTrendDTO struct {
+	GridCellID        string                    `json:"grid_cell_id"`
+	Precision         int                       `json:"precision"`
+	Latitude          float64                   `json:"latitude"`
+	Longitude         float64                   `json:"longitude"`
+	Trend             string                    `json:"trend"`
+	CurrentWeekScore  float64                   `json:"current_week_score"`
+	PreviousWeekScore float64                   `json:"previous_week_score"`
+	WeekOverWeek      float64                   `json:"week_over_week_change"`
+	Days              []DangerZoneDailyScoreDTO `json:"days"`
+}
+
+type GetDangerZoneTrendsResponse struct {
+	Series     []DangerZoneTrendDTO `json:"series"`
+	TotalCount int                  `json:"total_count"`
+}
+
+type GeoPointDTO struct {
+	Latitude  float64 `json:"latitude"  validate:"latitude"`
+	Longitude float64 `json:"longitude" validate:"longitude"`
+}
+
+type ManualDangerZoneRequest struct {
+	Polygon   []GeoPointDTO `json:"polygon"    validate:"required,min=3,max=500,dive"`
+	RiskLevel string        `json:"risk_level" validate:"required,oneof=low medium high critical"`
+	Reason    string        `json:"reason"     validate:"required,max=500"`
+	Source    string        `json:"source"     validate:"required,max=500"`
+	// ValidFrom defaults to now; a nil ValidUntil keeps the zone until it is
+	// deleted.
+	ValidFrom  *time.Time `json:"valid_from,omitempty"`
+	ValidUntil *time.Time `json:"valid_until,omitempty"`
+}
+
+type ManualDangerZoneDTO struct {
+	ID         string        `json:"id"`
+	Polygon    []GeoPointDTO `json:"polygon"`
+	RiskLevel  string        `json:"risk_level"`
+	Reason     string        `json:"reason"`
+	Source     string        `json:"source"`
+	ValidFrom  time.Time     `json:"valid_from"`
+	ValidUntil *time.Time    `json:"valid_until,omitempty"`
+	CreatedAt  time.Time     `json:"created_at"`
+	UpdatedAt  time.Time     `json:"updated_at"`
+}
+
+type ListManualDangerZonesResponse struct {
+	Zones      []ManualDangerZoneDTO `json:"zones"`
+	TotalCount int                   `json:"total_count"`
+}
+
+func ToManualDangerZoneDTO(zone *model.ManualDangerZone) ManualDangerZoneDTO {
+	polygon := make([]GeoPointDTO, 0, len(zone.Polygon))
+	for _, p := range zone.Polygon {
+		polygon = append(polygon, GeoPointDTO{Latitude: p.Latitude, Longitude: p.Longitude})
+	}
+
+	return ManualDangerZoneDTO{
+		ID:         zone.ID.String(),
+		Polygon:    polygon,
+		RiskLevel:  zone.RiskLevel,
+		Reason:     zone.Reason,
+		Source:     zone.Source,
+		ValidFrom:  zone.ValidFrom,
+		ValidUntil: zone.ValidUntil,
+		CreatedAt:  zone.CreatedAt,
+		UpdatedAt:  zone.UpdatedAt,
+	}
+}
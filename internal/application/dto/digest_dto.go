@@ -0,0 +1,16 @@
+package dto
+
+import "time"
+
+type DigestPreferencesResponse struct {
+	Frequency    string     `enums:"off,daily,weekly"   example:"daily" json:"frequency"`
+	PushEnabled  bool       `example:"true"             json:"push_enabled"`
+	EmailEnabled bool       `example:"false"            json:"email_enabled"`
+	LastSentAt   *time.Time `example:"2024-01-15T08:00:00Z" json:"last_sent_at,omitempty"`
+}
+
+type UpdateDigestPreferencesInput struct {
+	Frequency    *string `enums:"off,daily,weekly" json:"frequency,omitempty"`
+	PushEnabled  *bool   `json:"push_enabled,omitempty"`
+	EmailEnabled *bool   `json:"email_enabled,omitempty"`
+}
@@ -8,9 +8,21 @@ type UpdateDeviceInfoRequest struct {
 type NotificationPreferencesRequest struct {
 	PushEnabled bool `json:"push_enabled"`
 	SMSEnabled  bool `json:"sms_enabled"`
+	// EmailEnabled is left unchanged when omitted. Only registered users can
+	// turn it on.
+	EmailEnabled *bool `json:"email_enabled,omitempty"`
 }
 
 type NotificationPreferencesResponse struct {
-	PushEnabled bool `json:"push_enabled"`
-	SMSEnabled  bool `json:"sms_enabled"`
+	PushEnabled  bool `json:"push_enabled"`
+	SMSEnabled   bool `json:"sms_enabled"`
+	EmailEnabled bool `json:"email_enabled"`
+}
+
+// EmailUnsubscribePage is the localized text of the page an unsubscribe link
+// opens. Opening it changes nothing; the form on it does.
+type EmailUnsubscribePage struct {
+	Token    string
+	Question string
+	Button   string
 }
@@ -38,3 +38,31 @@ type RiskTypesListResponse struct {
 type RiskTopicsListResponse struct {
 	Data []RiskTopicResponse `json:"data"`
 }
+
+// IncidentWeightRuleRequest sets how heavily the incidents of a risk type, or
+// of one of its topics, count towards route, heatmap and danger zone scores
+type IncidentWeightRuleRequest struct {
+	RiskTypeID             uuid.UUID  `json:"risk_type_id"`
+	RiskTopicID            *uuid.UUID `json:"risk_topic_id,omitempty"`
+	Severity               float64    `json:"severity"`
+	HalfLifeDays           float64    `json:"half_life_days"`
+	ProximityFalloffMeters float64    `json:"proximity_falloff_meters"`
+}
+
+// IncidentWeightRuleResponse represents the response structure for an incident weight rule
+type IncidentWeightRuleResponse struct {
+	ID                     uuid.UUID  `json:"id"`
+	RiskTypeID             uuid.UUID  `json:"risk_type_id"`
+	RiskType               string     `json:"risk_type"`
+	RiskTopicID            *uuid.UUID `json:"risk_topic_id,omitempty"`
+	RiskTopic              string     `json:"risk_topic,omitempty"`
+	Severity               float64    `json:"severity"`
+	HalfLifeDays           float64    `json:"half_life_days"`
+	ProximityFalloffMeters float64    `json:"proximity_falloff_meters"`
+	UpdatedAt              time.Time  `json:"updated_at"`
+}
+
+// IncidentWeightRulesListResponse represents the response for listing incident weight rules
+type IncidentWeightRulesListResponse struct {
+	Data []IncidentWeightRuleResponse `json:"data"`
+}
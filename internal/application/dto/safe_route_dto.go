@@ -8,6 +8,13 @@ type SafeRouteRequest struct {
 	DestinationLat float64 `json:"destination_lat" validate:"required,latitude"`
 	DestinationLon float64 `json:"destination_lon" validate:"required,longitude"`
 	MaxRoutes      int     `json:"max_routes"      validate:"omitempty,min=1,max=3"`
+	// DepartureAt scores the route for the time of day of the trip (RFC3339).
+	DepartureAt *time.Time `json:"departure_at,omitempty"`
+	// SafetyWeight ranks the alternatives from 0, fastest first, to 1,
+	// safest first. It defaults to 0.5.
+	SafetyWeight *float64 `json:"safety_weight,omitempty" validate:"omitempty,min=0,max=1"`
+	// Mode is "walking", "driving" (the default) or "candongueiro".
+	Mode string `json:"mode,omitempty" validate:"omitempty,oneof=walking driving candongueiro"`
 }
 
 type WaypointDTO struct {
@@ -34,14 +41,25 @@ type SafeRouteResponse struct {
 	OriginLon         float64       `json:"origin_lon"`
 	DestinationLat    float64       `json:"destination_lat"`
 	DestinationLon    float64       `json:"destination_lon"`
+	Mode              string        `json:"mode"`
 	Waypoints         []WaypointDTO `json:"waypoints"`
 	DistanceKm        float64       `json:"distance_km"`
 	EstimatedDuration int           `json:"estimated_duration_minutes"`
 	SafetyScore       float64       `json:"safety_score"`
 	RiskLevel         string        `json:"risk_level"`
 	IncidentCount     int           `json:"incident_count"`
-	Incidents         []IncidentDTO `json:"incidents"`
-	CalculatedAt      time.Time     `json:"calculated_at"`
+	// Incidents lists the public reports along the route. Private reports
+	// count in IncidentCount and the score but are never listed.
+	Incidents []IncidentDTO `json:"incidents"`
+	// DangerZones are the manual danger zones the route crosses.
+	DangerZones []ManualDangerZoneDTO `json:"danger_zones"`
+	// Labels are "fastest", "safest" and "balanced", for the alternatives
+	// that are best at each.
+	Labels       []string  `json:"labels"`
+	CalculatedAt time.Time `json:"calculated_at"`
+	// Alternatives are the other routes found when max_routes is above one,
+	// ranked after this one.
+	Alternatives []SafeRouteResponse `json:"alternatives,omitempty"`
 }
 
 type HeatmapRequest struct {
@@ -12,6 +12,8 @@ type SafetySettingsResponse struct {
 	NotificationReportTypes      []string `example:"verified"      json:"notification_report_types"`
 	NotificationReportRadiusMins int      `example:"500"           json:"notification_report_radius_mins"`
 
+	RiskPreferences []RiskPreferenceResponse `json:"risk_preferences"`
+
 	LocationSharingEnabled bool `example:"false" json:"location_sharing_enabled"`
 	LocationHistoryEnabled bool `example:"true"  json:"location_history_enabled"`
 
@@ -40,6 +42,9 @@ type UpdateSafetySettingsInput struct {
 	NotificationReportTypes      *[]string `json:"notification_report_types,omitempty"`
 	NotificationReportRadiusMins *int      `json:"notification_report_radius_mins,omitempty"`
 
+	// RiskPreferences, when present, replaces every stored preference.
+	RiskPreferences *[]RiskPreferenceInput `json:"risk_preferences,omitempty"`
+
 	LocationSharingEnabled *bool `json:"location_sharing_enabled,omitempty"`
 	LocationHistoryEnabled *bool `json:"location_history_enabled,omitempty"`
 
@@ -57,3 +62,19 @@ type UpdateSafetySettingsInput struct {
 	NightModeStartTime *string `example:"22:00"                     json:"night_mode_start_time,omitempty"`
 	NightModeEndTime   *string `example:"06:00"                     json:"night_mode_end_time,omitempty"`
 }
+
+// RiskPreferenceResponse is an opt-in or opt-out for one risk type, or for
+// one topic of it when RiskTopicID is set.
+type RiskPreferenceResponse struct {
+	RiskTypeID   string  `example:"550e8400-e29b-41d4-a716-446655440002" json:"risk_type_id"`
+	RiskTopicID  *string `example:"550e8400-e29b-41d4-a716-446655440003" json:"risk_topic_id,omitempty"`
+	Enabled      bool    `example:"true"                                 json:"enabled"`
+	RadiusMeters *int    `example:"2000"                                 json:"radius_meters,omitempty"`
+}
+
+type RiskPreferenceInput struct {
+	RiskTypeID   string  `example:"550e8400-e29b-41d4-a716-446655440002" json:"risk_type_id"`
+	RiskTopicID  *string `example:"550e8400-e29b-41d4-a716-446655440003" json:"risk_topic_id,omitempty"`
+	Enabled      bool    `example:"true"                                 json:"enabled"`
+	RadiusMeters *int    `example:"2000"                                 json:"radius_meters,omitempty"`
+}
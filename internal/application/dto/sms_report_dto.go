@@ -0,0 +1,9 @@
+package dto
+
+// InboundSMS is a message received from the SMS provider's webhook.
+// MessageID is the provider's identifier, used to ignore retries.
+type InboundSMS struct {
+	From      string
+	Body      string
+	MessageID string
+}
@@ -0,0 +1,50 @@
+package dto
+
+import "time"
+
+// StartTripRequest starts following the user along a route, usually one of
+// the safe routes returned by /routes/safe.
+type StartTripRequest struct {
+	// Mode is "walking", "driving" (the default) or "candongueiro".
+	Mode      string        `json:"mode,omitempty" validate:"omitempty,oneof=walking driving candongueiro"`
+	Waypoints []WaypointDTO `json:"waypoints"      validate:"required,min=2"`
+	// EstimatedDuration sets when the user is expected to arrive.
+	EstimatedDuration int `json:"estimated_duration_minutes" validate:"required,min=1"`
+}
+
+// TripCheckInRequest answers a check-in, or raises the alarm unprompted
+// when OK is false.
+type TripCheckInRequest struct {
+	OK bool `json:"ok"`
+}
+
+// EndTripRequest ends a trip, as completed when the user arrived and as
+// cancelled otherwise.
+type EndTripRequest struct {
+	Arrived bool `json:"arrived"`
+}
+
+type TripCheckInDTO struct {
+	Reason      string    `json:"reason"`
+	RequestedAt time.Time `json:"requested_at"`
+	// Deadline is when the emergency contacts are alerted without an
+	// answer.
+	Deadline time.Time `json:"deadline"`
+}
+
+type TripResponse struct {
+	ID                string          `json:"id"`
+	Status            string          `json:"status"`
+	Mode              string          `json:"mode"`
+	Waypoints         []WaypointDTO   `json:"waypoints"`
+	StartedAt         time.Time       `json:"started_at"`
+	ExpectedArrivalAt time.Time       `json:"expected_arrival_at"`
+	EndedAt           *time.Time      `json:"ended_at,omitempty"`
+	LastLatitude      float64         `json:"last_latitude"`
+	LastLongitude     float64         `json:"last_longitude"`
+	LastLocationAt    time.Time       `json:"last_location_at"`
+	CheckIn           *TripCheckInDTO `json:"check_in,omitempty"`
+	EscalatedAt       *time.Time      `json:"escalated_at,omitempty"`
+	// EscalationReason is why the emergency contacts were alerted.
+	EscalationReason string `json:"escalation_reason,omitempty"`
+}
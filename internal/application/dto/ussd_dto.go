@@ -0,0 +1,17 @@
+package dto
+
+// USSDRequest is one step of a USSD session as relayed by the gateway.
+// Text is either the latest input or every input so far joined by "*";
+// only the part after the last "*" is used.
+type USSDRequest struct {
+	SessionID   string
+	Phone       string
+	ServiceCode string
+	Text        string
+}
+
+// USSDResponse is the screen shown to the user. End closes the session.
+type USSDResponse struct {
+	Message string
+	End     bool
+}
@@ -2,7 +2,21 @@ package port
 
 import "context"
 
+// EmailMessage is a single HTML email queued for batch delivery. Headers are
+// added verbatim, e.g. List-Unsubscribe.
+type EmailMessage struct {
+	To       string
+	Subject  string
+	HTMLBody string
+	Headers  map[string]string
+}
+
 type EmailService interface {
 	SendEmail(ctx context.Context, to string, subject string, body string) error
 	SendHTMLEmail(ctx context.Context, to string, subject string, htmlBody string) error
+	// SendBatch delivers many messages over as few SMTP sessions as possible.
+	// It returns an error only when no message went out, so a caller that
+	// retries does not email anyone twice; failures of individual messages
+	// alongside delivered ones are logged.
+	SendBatch(ctx context.Context, messages []EmailMessage) error
 }
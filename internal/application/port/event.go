@@ -1,8 +1,24 @@
 package port
 
-import "github.com/risk-place-angola/backend-risk-place/internal/domain/event"
+import (
+	"context"
+
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/event"
+)
 
 type EventDispatcher interface {
-	Register(eventName string, handler event.EventHandler)
+	Register(eventName, handlerName string, handler event.EventHandler)
 	Dispatch(event event.Event)
 }
+
+// EventOutbox is an EventDispatcher backed by durable storage. Publish writes
+// the event within the transaction carried by ctx, if any, so it is only
+// delivered once that transaction commits.
+type EventOutbox interface {
+	EventDispatcher
+	Publish(ctx context.Context, event event.Event) error
+}
+
+type TransactionManager interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
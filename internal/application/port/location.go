@@ -17,6 +17,7 @@ type Geolocation struct {
 type LocationStore interface {
 	UpdateUserLocation(ctx context.Context, userID string, lat float64, lon float64) error
 	FindUsersInRadius(ctx context.Context, lat float64, lon float64, radiusMeters float64) ([]string, error)
+	FindUsersInRadiusWithDistance(ctx context.Context, lat, lon float64, radiusMeters float64) ([]GeoResult, error)
 	RemoveReportLocation(ctx context.Context, reportID string) error
 	UpdateReportLocation(ctx context.Context, reportID string, lat, lon float64) error
 	FindReportsInRadius(ctx context.Context, lat, lon float64, radiusMeters float64) ([]string, error)
@@ -2,6 +2,17 @@ package port
 
 import "context"
 
+// Push data keys read by the push pipeline as well as the app.
+const (
+	// PushDataGroupKey groups pushes about the same incident or area so a
+	// newer one replaces the older on the device instead of stacking up.
+	PushDataGroupKey = "group_key"
+	// PushDataSeverity set to PushSeverityCritical exempts a push from
+	// deduplication and rate limiting.
+	PushDataSeverity     = "severity"
+	PushSeverityCritical = "critical"
+)
+
 type NotifierPushService interface {
 	NotifyPush(ctx context.Context, deviceToken string, title string, message string, data map[string]string) error
 	NotifyPushMulti(ctx context.Context, deviceTokens []string, title string, message string, data map[string]string) error
@@ -41,3 +52,12 @@ type NearbyUser struct {
 	Speed       float64 `json:"speed"`
 	Heading     float64 `json:"heading"`
 }
+
+// Localizer renders catalog messages in the language negotiated for the
+// request carried by ctx, so SMS and email bodies match the API responses.
+// LocalizeFor is for replies to someone other than the caller, in their
+// stored device language.
+type Localizer interface {
+	Localize(ctx context.Context, key string, params map[string]any) string
+	LocalizeFor(language, key string, params map[string]any) string
+}
@@ -16,3 +16,10 @@ type TokenGenerator interface {
 	GenerateEmailVerificationToken(userID string) (string, error)
 	ValidateEmailVerificationToken(tokenString string) (string, error)
 }
+
+// UnsubscribeTokenSigner issues the tokens embedded in email unsubscribe
+// links so they can be honoured without a login.
+type UnsubscribeTokenSigner interface {
+	Sign(userID uuid.UUID) string
+	Verify(token string) (uuid.UUID, error)
+}
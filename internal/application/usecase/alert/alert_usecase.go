@@ -18,7 +18,8 @@ type AlertUseCase struct {
 	geoService      port.GeolocationService
 	repo            repository.AlertRepository
 	riskTypesRepo   repository.RiskTypesRepository
-	eventDispatcher port.EventDispatcher
+	eventDispatcher port.EventOutbox
+	txManager       port.TransactionManager
 }
 
 func NewAlertUseCase(
@@ -26,7 +27,8 @@ func NewAlertUseCase(
 	geoService port.GeolocationService,
 	repo repository.AlertRepository,
 	riskTypesRepo repository.RiskTypesRepository,
-	eventDispatcher port.EventDispatcher,
+	eventDispatcher port.EventOutbox,
+	txManager port.TransactionManager,
 ) *AlertUseCase {
 	return &AlertUseCase{
 		locationStore:   locationStore,
@@ -34,6 +36,7 @@ func NewAlertUseCase(
 		repo:            repo,
 		riskTypesRepo:   riskTypesRepo,
 		eventDispatcher: eventDispatcher,
+		txManager:       txManager,
 	}
 }
 
@@ -70,38 +73,57 @@ func (uc *AlertUseCase) TriggerAlert(ctx context.Context, alert dto.Alert) error
 		alrt.RadiusMeters = riskType.DefaultRadiusMeters
 	}
 
-	err = uc.repo.Create(ctx, alrt)
+	nearby, err := uc.locationStore.FindUsersInRadiusWithDistance(ctx, alert.Latitude, alert.Longitude, alert.Radius)
 	if err != nil {
-		slog.Error("failed to create alert", "error", err)
+		slog.Error("failed to find users in radius", "error", err)
 		return err
 	}
 
-	userIDs, err := uc.locationStore.FindUsersInRadius(ctx, alert.Latitude, alert.Longitude, alert.Radius)
+	uuidUserIDs := make([]uuid.UUID, 0, len(nearby))
+	distances := make([]float64, 0, len(nearby))
+	for _, result := range nearby {
+		parsed, parseErr := uuid.Parse(result.Member)
+		if parseErr != nil {
+			slog.Warn("invalid user id in location store", "user_id", result.Member, "error", parseErr)
+			continue
+		}
+		uuidUserIDs = append(uuidUserIDs, parsed)
+		distances = append(distances, result.Distance)
+	}
+
+	// The alert and its AlertCreated event commit together, so the event is
+	// never delivered for an alert that was rolled back.
+	err = uc.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := uc.repo.Create(ctx, alrt); err != nil {
+			slog.Error("failed to create alert", "error", err)
+			return err
+		}
+
+		return uc.eventDispatcher.Publish(ctx, event.AlertCreatedEvent{
+			AlertID:       alrt.ID,
+			UserID:        uuidUserIDs,
+			UserDistances: distances,
+			Message:       alert.Message,
+			Latitude:      alert.Latitude,
+			Longitude:     alert.Longitude,
+			Radius:        alert.Radius,
+			RiskType:      riskType.Name,
+			RiskTypeID:    alrt.RiskTypeID,
+			RiskTopicID:   alrt.RiskTopicID,
+			Severity:      string(alrt.Severity),
+		})
+	})
 	if err != nil {
-		slog.Error("failed to find users in radius", "error", err)
 		return err
 	}
 
-	uuidUserIDs := make([]uuid.UUID, 0, len(userIDs))
-	for _, uid := range userIDs {
-		if err := uc.repo.CreateAlertNotification(ctx, alrt.ID, uid); err != nil {
+	for _, uid := range uuidUserIDs {
+		if err := uc.repo.CreateAlertNotification(ctx, alrt.ID, uid.String()); err != nil {
 			slog.Error("failed to create alert notification", "error", err, "user_id", uid)
 			continue
 		}
 		slog.Info("created alert notification", "alert_id", alrt.ID.String(), "user_id", uid)
-		uuidUserIDs = append(uuidUserIDs, uuid.MustParse(uid))
 	}
 
-	uc.eventDispatcher.Dispatch(event.AlertCreatedEvent{
-		AlertID:   alrt.ID,
-		UserID:    uuidUserIDs,
-		Message:   alert.Message,
-		Latitude:  alert.Latitude,
-		Longitude: alert.Longitude,
-		Radius:    alert.Radius,
-		RiskType:  riskType.Name,
-		Severity:  string(alrt.Severity),
-	})
-
 	return nil
 }
@@ -3,8 +3,12 @@ package dangerzone
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/risk-place-angola/backend-risk-place/internal/application/dto"
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
 	"github.com/risk-place-angola/backend-risk-place/internal/domain/service"
 )
 
@@ -19,7 +23,12 @@ func NewDangerZoneUseCase(dangerZoneService service.DangerZoneService) *DangerZo
 }
 
 func (uc *DangerZoneUseCase) GetDangerZonesNearby(ctx context.Context, req *dto.GetDangerZonesRequest) (*dto.GetDangerZonesResponse, error) {
-	zones, err := uc.dangerZoneService.GetDangerZonesNearby(ctx, req.Latitude, req.Longitude, req.RadiusMeters)
+	var at time.Time
+	if req.At != nil {
+		at = *req.At
+	}
+
+	zones, err := uc.dangerZoneService.GetDangerZonesNearby(ctx, req.Latitude, req.Longitude, req.RadiusMeters, req.Zoom, at)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get danger zones: %w", err)
 	}
@@ -30,17 +39,199 @@ func (uc *DangerZoneUseCase) GetDangerZonesNearby(ctx context.Context, req *dto.
 	}
 
 	for _, zone := range zones {
+		var riskiest *dto.DangerZoneTimeSlotDTO
+		if slot, ok := zone.Riskiest(); ok && slot.RiskScore > 0 {
+			slotDTO := toTimeSlotDTO(slot)
+			riskiest = &slotDTO
+		}
+
+		timeProfile := make([]dto.DangerZoneTimeSlotDTO, 0, len(zone.TimeProfile))
+		for _, slot := range zone.TimeProfile {
+			timeProfile = append(timeProfile, toTimeSlotDTO(slot))
+		}
+
+		var manual *dto.ManualDangerZoneDTO
+		if zone.Manual != nil {
+			manualDTO := dto.ToManualDangerZoneDTO(zone.Manual)
+			manual = &manualDTO
+		}
+
 		response.Zones = append(response.Zones, dto.DangerZoneDTO{
 			ID:           zone.ID.String(),
 			Latitude:     zone.CellLat,
 			Longitude:    zone.CellLon,
 			GridCellID:   zone.GridCellID,
+			Precision:    zone.Precision,
 			IncidentCount: zone.IncidentCount,
 			RiskScore:    zone.RiskScore,
 			RiskLevel:    zone.RiskLevel,
+			Riskiest:     riskiest,
+			TimeProfile:  timeProfile,
+			Manual:       manual,
 			CalculatedAt: zone.CalculatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		})
 	}
 
 	return response, nil
 }
+
+func (uc *DangerZoneUseCase) ExplainDangerZone(ctx context.Context, gridCellID string, at time.Time) (*dto.DangerZoneExplanationDTO, error) {
+	explanation, err := uc.dangerZoneService.ExplainDangerZone(ctx, gridCellID, at)
+	if err != nil {
+		return nil, fmt.Errorf("failed to explain danger zone: %w", err)
+	}
+
+	response := &dto.DangerZoneExplanationDTO{
+		GridCellID:    explanation.GridCellID,
+		Precision:     explanation.Precision,
+		Latitude:      explanation.CellLat,
+		Longitude:     explanation.CellLon,
+		IncidentCount: explanation.IncidentCount,
+		RawScore:      explanation.RawScore,
+		RiskScore:     explanation.RiskScore,
+		MaxRiskScore:  explanation.MaxRiskScore,
+		RiskLevel:     explanation.RiskLevel,
+		Breakdown:     make([]dto.DangerZoneRiskTypeDTO, 0, len(explanation.Breakdown)),
+		Contributions: make([]dto.DangerZoneContributionDTO, 0, len(explanation.Contributions)),
+		CalculatedAt:  explanation.CalculatedAt.Format(time.RFC3339),
+	}
+
+	for _, breakdown := range explanation.Breakdown {
+		response.Breakdown = append(response.Breakdown, dto.DangerZoneRiskTypeDTO{
+			RiskType:      breakdown.RiskType,
+			IncidentCount: breakdown.IncidentCount,
+			Weight:        breakdown.Weight,
+		})
+	}
+
+	for _, contribution := range explanation.Contributions {
+		contribution = contribution.Redacted()
+
+		reportID := ""
+		if !contribution.Private {
+			reportID = contribution.ReportID.String()
+		}
+
+		response.Contributions = append(response.Contributions, dto.DangerZoneContributionDTO{
+			ReportID:           reportID,
+			RiskType:           contribution.RiskType,
+			RiskTopic:          contribution.RiskTopic,
+			Verified:           contribution.Verified,
+			Redacted:           contribution.Private,
+			CreatedAt:          contribution.CreatedAt.Format(time.RFC3339),
+			SeverityWeight:     contribution.SeverityWeight,
+			RecencyWeight:      contribution.RecencyWeight,
+			VerificationWeight: contribution.VerificationWeight,
+			Weight:             contribution.Weight,
+		})
+	}
+
+	return response, nil
+}
+
+const (
+	defaultTrendDays      = 28
+	minTrendDays          = 14
+	maxTrendDays          = 90
+	defaultTrendPrecision = 6
+	// maxTrendBoxDegrees bounds each side of a trends bounding box, about
+	// 55 km, which covers the Luanda metropolitan area.
+	maxTrendBoxDegrees = 0.5
+)
+
+func (uc *DangerZoneUseCase) GetDangerZoneTrends(ctx context.Context, req *dto.GetDangerZoneTrendsRequest) (*dto.GetDangerZoneTrendsResponse, error) {
+	filter, err := toSnapshotFilter(req)
+	if err != nil {
+		return nil, err
+	}
+
+	trends, err := uc.dangerZoneService.GetDangerZoneTrends(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get danger zone trends: %w", err)
+	}
+
+	response := &dto.GetDangerZoneTrendsResponse{
+		Series:     make([]dto.DangerZoneTrendDTO, 0, len(trends)),
+		TotalCount: len(trends),
+	}
+
+	for _, trend := range trends {
+		days := make([]dto.DangerZoneDailyScoreDTO, 0, len(trend.Days))
+		for _, day := range trend.Days {
+			days = append(days, dto.DangerZoneDailyScoreDTO{
+				Date:             day.Day.Format(time.DateOnly),
+				AverageRiskScore: day.AverageRiskScore,
+				MaxRiskScore:     day.MaxRiskScore,
+				MaxIncidentCount: day.MaxIncidentCount,
+			})
+		}
+
+		response.Series = append(response.Series, dto.DangerZoneTrendDTO{
+			GridCellID:        trend.GridCellID,
+			Precision:         trend.Precision,
+			Latitude:          trend.CellLat,
+			Longitude:         trend.CellLon,
+			Trend:             string(trend.Trend),
+			CurrentWeekScore:  trend.Comparison.CurrentWeekScore,
+			PreviousWeekScore: trend.Comparison.PreviousWeekScore,
+			WeekOverWeek:      trend.Comparison.Change,
+			Days:              days,
+		})
+	}
+
+	return response, nil
+}
+
+func toSnapshotFilter(req *dto.GetDangerZoneTrendsRequest) (repository.DangerZoneSnapshotFilter, error) {
+	days := req.Days
+	if days == 0 {
+		days = defaultTrendDays
+	}
+	if days < minTrendDays || days > maxTrendDays {
+		return repository.DangerZoneSnapshotFilter{}, fmt.Errorf("%w: days must be between %d and %d", domainErrors.ErrInvalidRequest, minTrendDays, maxTrendDays)
+	}
+
+	filter := repository.DangerZoneSnapshotFilter{
+		Since: time.Now().AddDate(0, 0, -days),
+	}
+
+	if req.GridCellID != "" {
+		if _, err := model.DecodeGeohash(req.GridCellID); err != nil {
+			return repository.DangerZoneSnapshotFilter{}, err
+		}
+		filter.GridCellID = req.GridCellID
+		return filter, nil
+	}
+
+	bounds := model.BoundingBox{
+		MinLat: req.SouthWestLat,
+		MinLon: req.SouthWestLon,
+		MaxLat: req.NorthEastLat,
+		MaxLon: req.NorthEastLon,
+	}
+	if bounds.MinLat >= bounds.MaxLat || bounds.MinLon >= bounds.MaxLon {
+		return repository.DangerZoneSnapshotFilter{}, fmt.Errorf("%w: a grid cell ID or a bounding box is required", domainErrors.ErrInvalidRequest)
+	}
+	if bounds.MaxLat-bounds.MinLat > maxTrendBoxDegrees || bounds.MaxLon-bounds.MinLon > maxTrendBoxDegrees {
+		return repository.DangerZoneSnapshotFilter{}, fmt.Errorf("%w: the bounding box can span at most %.1f degrees each way", domainErrors.ErrInvalidRequest, maxTrendBoxDegrees)
+	}
+	filter.Bounds = &bounds
+
+	filter.Precision = req.Precision
+	if filter.Precision == 0 {
+		filter.Precision = defaultTrendPrecision
+	}
+
+	return filter, nil
+}
+
+func toTimeSlotDTO(slot model.DangerZoneTimeSlot) dto.DangerZoneTimeSlotDTO {
+	return dto.DangerZoneTimeSlotDTO{
+		DayType:       string(slot.DayType),
+		StartHour:     slot.StartHour,
+		EndHour:       slot.EndHour,
+		IncidentCount: slot.IncidentCount,
+		RiskScore:     slot.RiskScore,
+		RiskLevel:     slot.RiskLevel,
+	}
+}
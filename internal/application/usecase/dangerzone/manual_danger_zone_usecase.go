@@ -0,0 +1,134 @@
+package dangerzone
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/risk-place-angola/backend-risk-place/internal/application/dto"
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/service"
+)
+
+// ManualDangerZoneUseCase manages the danger zones authorities draw by hand.
+type ManualDangerZoneUseCase struct {
+	repo              repository.ManualDangerZoneRepository
+	dangerZoneService service.DangerZoneService
+}
+
+func NewManualDangerZoneUseCase(
+	repo repository.ManualDangerZoneRepository,
+	dangerZoneService service.DangerZoneService,
+) *ManualDangerZoneUseCase {
+	return &ManualDangerZoneUseCase{
+		repo:              repo,
+		dangerZoneService: dangerZoneService,
+	}
+}
+
+func (uc *ManualDangerZoneUseCase) Create(
+	ctx context.Context,
+	createdBy uuid.UUID,
+	req *dto.ManualDangerZoneRequest,
+) (*dto.ManualDangerZoneDTO, error) {
+	now := time.Now()
+	zone := &model.ManualDangerZone{
+		ID:        uuid.New(),
+		CreatedBy: createdBy,
+		CreatedAt: now,
+	}
+	if err := applyManualZoneRequest(zone, req, now); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.Create(ctx, zone); err != nil {
+		return nil, fmt.Errorf("failed to create manual danger zone: %w", err)
+	}
+	uc.refresh(ctx)
+
+	response := dto.ToManualDangerZoneDTO(zone)
+	return &response, nil
+}
+
+func (uc *ManualDangerZoneUseCase) Update(
+	ctx context.Context,
+	id uuid.UUID,
+	req *dto.ManualDangerZoneRequest,
+) (*dto.ManualDangerZoneDTO, error) {
+	zone, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find manual danger zone: %w", err)
+	}
+
+	if err := applyManualZoneRequest(zone, req, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.Update(ctx, zone); err != nil {
+		return nil, fmt.Errorf("failed to update manual danger zone: %w", err)
+	}
+	uc.refresh(ctx)
+
+	response := dto.ToManualDangerZoneDTO(zone)
+	return &response, nil
+}
+
+func (uc *ManualDangerZoneUseCase) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := uc.repo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete manual danger zone: %w", err)
+	}
+	uc.refresh(ctx)
+	return nil
+}
+
+func (uc *ManualDangerZoneUseCase) List(ctx context.Context) (*dto.ListManualDangerZonesResponse, error) {
+	zones, err := uc.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list manual danger zones: %w", err)
+	}
+
+	response := &dto.ListManualDangerZonesResponse{
+		Zones:      make([]dto.ManualDangerZoneDTO, 0, len(zones)),
+		TotalCount: len(zones),
+	}
+	for _, zone := range zones {
+		response.Zones = append(response.Zones, dto.ToManualDangerZoneDTO(zone))
+	}
+	return response, nil
+}
+
+// refresh makes the change visible on this instance right away; the others
+// pick it up when their copy expires.
+func (uc *ManualDangerZoneUseCase) refresh(ctx context.Context) {
+	if err := uc.dangerZoneService.RefreshManualZones(ctx); err != nil {
+		slog.Warn("failed to refresh manual danger zones", "error", err)
+	}
+}
+
+func applyManualZoneRequest(zone *model.ManualDangerZone, req *dto.ManualDangerZoneRequest, now time.Time) error {
+	polygon := make([]model.GeoPoint, 0, len(req.Polygon))
+	for _, p := range req.Polygon {
+		polygon = append(polygon, model.GeoPoint{Latitude: p.Latitude, Longitude: p.Longitude})
+	}
+
+	zone.Polygon = polygon
+	zone.RiskLevel = req.RiskLevel
+	zone.Reason = req.Reason
+	zone.Source = req.Source
+	if req.ValidFrom != nil {
+		zone.ValidFrom = *req.ValidFrom
+	} else if zone.ValidFrom.IsZero() {
+		zone.ValidFrom = now
+	}
+	zone.ValidUntil = req.ValidUntil
+	zone.UpdatedAt = now
+
+	if err := zone.Validate(); err != nil {
+		return fmt.Errorf("%w: %s", domainErrors.ErrInvalidRequest, err.Error())
+	}
+	return nil
+}
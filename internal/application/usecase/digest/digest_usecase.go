@@ -0,0 +1,79 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/risk-place-angola/backend-risk-place/internal/application/dto"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
+)
+
+type DigestUseCase struct {
+	repo repository.DigestRepository
+}
+
+func NewDigestUseCase(repo repository.DigestRepository) *DigestUseCase {
+	return &DigestUseCase{repo: repo}
+}
+
+func (uc *DigestUseCase) GetPreferences(ctx context.Context, userID uuid.UUID) (*dto.DigestPreferencesResponse, error) {
+	sub, err := uc.repo.GetSubscription(ctx, userID)
+	if err != nil {
+		slog.Error("Error fetching digest subscription", "user_id", userID, "error", err)
+		return nil, errors.New("failed to fetch digest preferences")
+	}
+
+	if sub == nil {
+		sub = model.NewDigestSubscription(userID)
+	}
+
+	return toResponse(sub), nil
+}
+
+func (uc *DigestUseCase) UpdatePreferences(ctx context.Context, userID uuid.UUID, input dto.UpdateDigestPreferencesInput) (*dto.DigestPreferencesResponse, error) {
+	sub, err := uc.repo.GetSubscription(ctx, userID)
+	if err != nil {
+		slog.Error("Error fetching digest subscription for update", "user_id", userID, "error", err)
+		return nil, errors.New("failed to fetch digest preferences")
+	}
+
+	if sub == nil {
+		sub = model.NewDigestSubscription(userID)
+	}
+
+	if input.Frequency != nil {
+		sub.Frequency = model.DigestFrequency(*input.Frequency)
+	}
+	if input.PushEnabled != nil {
+		sub.PushEnabled = *input.PushEnabled
+	}
+	if input.EmailEnabled != nil {
+		sub.EmailEnabled = *input.EmailEnabled
+	}
+
+	if err := sub.Validate(); err != nil {
+		return nil, err
+	}
+
+	sub.UpdatedAt = time.Now()
+
+	if err := uc.repo.UpsertSubscription(ctx, sub); err != nil {
+		slog.Error("Error updating digest subscription", "user_id", userID, "error", err)
+		return nil, errors.New("failed to update digest preferences")
+	}
+
+	return toResponse(sub), nil
+}
+
+func toResponse(sub *model.DigestSubscription) *dto.DigestPreferencesResponse {
+	return &dto.DigestPreferencesResponse{
+		Frequency:    string(sub.Frequency),
+		PushEnabled:  sub.PushEnabled,
+		EmailEnabled: sub.EmailEnabled,
+		LastSentAt:   sub.LastSentAt,
+	}
+}
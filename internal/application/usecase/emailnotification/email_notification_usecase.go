@@ -0,0 +1,85 @@
+package emailnotification
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/risk-place-angola/backend-risk-place/internal/application/dto"
+	"github.com/risk-place-angola/backend-risk-place/internal/application/port"
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
+)
+
+type EmailNotificationUseCase struct {
+	repo      repository.EmailNotificationRepository
+	signer    port.UnsubscribeTokenSigner
+	localizer port.Localizer
+}
+
+func NewEmailNotificationUseCase(repo repository.EmailNotificationRepository, signer port.UnsubscribeTokenSigner, localizer port.Localizer) *EmailNotificationUseCase {
+	return &EmailNotificationUseCase{repo: repo, signer: signer, localizer: localizer}
+}
+
+func (uc *EmailNotificationUseCase) IsEnabled(ctx context.Context, userID uuid.UUID) (bool, error) {
+	enabled, err := uc.repo.IsEnabled(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domainErrors.ErrUserNotFound) {
+			return false, err
+		}
+		slog.Error("Error fetching email notification preference", "user_id", userID, "error", err)
+		return false, errors.New("failed to get preferences")
+	}
+	return enabled, nil
+}
+
+func (uc *EmailNotificationUseCase) SetEnabled(ctx context.Context, userID uuid.UUID, enabled bool) error {
+	if err := uc.repo.SetEnabled(ctx, userID, enabled); err != nil {
+		if errors.Is(err, domainErrors.ErrUserNotFound) {
+			return err
+		}
+		slog.Error("Error updating email notification preference", "user_id", userID, "error", err)
+		return errors.New("failed to update preferences")
+	}
+	return nil
+}
+
+// UnsubscribePage checks an unsubscribe token and returns the confirmation
+// page for it without changing anything, so mail scanners and link
+// prefetchers that open the link do not unsubscribe the user.
+func (uc *EmailNotificationUseCase) UnsubscribePage(ctx context.Context, token string) (dto.EmailUnsubscribePage, error) {
+	if _, err := uc.signer.Verify(token); err != nil {
+		return dto.EmailUnsubscribePage{}, domainErrors.ErrInvalidUnsubscribeToken
+	}
+
+	return dto.EmailUnsubscribePage{
+		Token:    token,
+		Question: uc.localizer.Localize(ctx, "email_unsubscribe_confirm", nil),
+		Button:   uc.localizer.Localize(ctx, "email_unsubscribe", nil),
+	}, nil
+}
+
+// Unsubscribed returns the message shown once the user is unsubscribed.
+func (uc *EmailNotificationUseCase) Unsubscribed(ctx context.Context) string {
+	return uc.localizer.Localize(ctx, "email_unsubscribed", nil)
+}
+
+// Unsubscribe turns email alerts off for the user named by a signed
+// unsubscribe token. Unsubscribing twice is not an error.
+func (uc *EmailNotificationUseCase) Unsubscribe(ctx context.Context, token string) error {
+	userID, err := uc.signer.Verify(token)
+	if err != nil {
+		return domainErrors.ErrInvalidUnsubscribeToken
+	}
+
+	if err := uc.SetEnabled(ctx, userID, false); err != nil {
+		if errors.Is(err, domainErrors.ErrUserNotFound) {
+			return domainErrors.ErrInvalidUnsubscribeToken
+		}
+		return err
+	}
+
+	slog.Info("user unsubscribed from email alerts", "user_id", userID)
+	return nil
+}
@@ -10,6 +10,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/risk-place-angola/backend-risk-place/internal/application/dto"
 	"github.com/risk-place-angola/backend-risk-place/internal/application/port"
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
 	"github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
 )
 
@@ -17,17 +19,20 @@ type EmergencyAlertUseCase struct {
 	contactRepo repository.EmergencyContactRepository
 	userRepo    repository.UserRepository
 	smsNotifier port.NotifierSMSService
+	localizer   port.Localizer
 }
 
 func NewEmergencyAlertUseCase(
 	contactRepo repository.EmergencyContactRepository,
 	userRepo repository.UserRepository,
 	smsNotifier port.NotifierSMSService,
+	localizer port.Localizer,
 ) *EmergencyAlertUseCase {
 	return &EmergencyAlertUseCase{
 		contactRepo: contactRepo,
 		userRepo:    userRepo,
 		smsNotifier: smsNotifier,
+		localizer:   localizer,
 	}
 }
 
@@ -39,7 +44,7 @@ func (uc *EmergencyAlertUseCase) SendEmergencyAlert(ctx context.Context, userID
 	}
 
 	if user == nil {
-		return nil, errors.New("user not found")
+		return nil, domainErrors.ErrUserNotFound
 	}
 
 	contacts, err := uc.contactRepo.FindByUserID(ctx, userID)
@@ -49,31 +54,10 @@ func (uc *EmergencyAlertUseCase) SendEmergencyAlert(ctx context.Context, userID
 	}
 
 	if len(contacts) == 0 {
-		return nil, errors.New("no emergency contacts configured")
+		return nil, domainErrors.ErrNoEmergencyContacts
 	}
 
-	mapLink := fmt.Sprintf("https://maps.google.com/?q=%.6f,%.6f", input.Latitude, input.Longitude)
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
-
-	customMessage := input.Message
-	if customMessage == "" {
-		customMessage = "Preciso de ajuda urgente!"
-	}
-
-	smsMessage := fmt.Sprintf(
-		"🚨 ALERTA DE EMERGÊNCIA 🚨\n\n"+
-			"De: %s\n"+
-			"Telefone: %s\n"+
-			"Mensagem: %s\n"+
-			"Data/Hora: %s\n"+
-			"Localização: %s\n\n"+
-			"Por favor, verifique se está tudo bem!",
-		user.Name,
-		user.Phone,
-		customMessage,
-		timestamp,
-		mapLink,
-	)
+	smsMessage := uc.buildSMS(ctx, user, input)
 
 	notifiedContacts := make([]string, 0)
 	successCount := 0
@@ -101,14 +85,14 @@ func (uc *EmergencyAlertUseCase) SendEmergencyAlert(ctx context.Context, userID
 
 	if successCount == 0 {
 		slog.Error("Failed to notify any emergency contacts", "user_id", userID)
-		return nil, errors.New("failed to send emergency alerts to any contact")
+		return nil, domainErrors.ErrEmergencyAlertNotSent
 	}
 
 	return &dto.EmergencyAlertResponse{
 		Success:          true,
 		ContactsNotified: successCount,
 		NotifiedContacts: notifiedContacts,
-		Message:          fmt.Sprintf("Emergency alert sent to %d contact(s)", successCount),
+		Message:          uc.localizer.Localize(ctx, "emergency_alert_sent", map[string]any{"count": successCount}),
 	}, nil
 }
 
@@ -120,7 +104,7 @@ func (uc *EmergencyAlertUseCase) SendEmergencyAlertToAll(ctx context.Context, us
 	}
 
 	if user == nil {
-		return nil, errors.New("user not found")
+		return nil, domainErrors.ErrUserNotFound
 	}
 
 	contacts, err := uc.contactRepo.FindByUserID(ctx, userID)
@@ -130,31 +114,10 @@ func (uc *EmergencyAlertUseCase) SendEmergencyAlertToAll(ctx context.Context, us
 	}
 
 	if len(contacts) == 0 {
-		return nil, errors.New("no emergency contacts configured")
-	}
-
-	mapLink := fmt.Sprintf("https://maps.google.com/?q=%.6f,%.6f", input.Latitude, input.Longitude)
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
-
-	customMessage := input.Message
-	if customMessage == "" {
-		customMessage = "Preciso de ajuda urgente!"
+		return nil, domainErrors.ErrNoEmergencyContacts
 	}
 
-	smsMessage := fmt.Sprintf(
-		"🚨 ALERTA DE EMERGÊNCIA 🚨\n\n"+
-			"De: %s\n"+
-			"Telefone: %s\n"+
-			"Mensagem: %s\n"+
-			"Data/Hora: %s\n"+
-			"Localização: %s\n\n"+
-			"Por favor, verifique se está tudo bem!",
-		user.Name,
-		user.Phone,
-		customMessage,
-		timestamp,
-		mapLink,
-	)
+	smsMessage := uc.buildSMS(ctx, user, input)
 
 	notifiedContacts := make([]string, 0)
 	successCount := 0
@@ -180,13 +143,30 @@ func (uc *EmergencyAlertUseCase) SendEmergencyAlertToAll(ctx context.Context, us
 
 	if successCount == 0 {
 		slog.Error("Failed to notify any emergency contacts", "user_id", userID)
-		return nil, errors.New("failed to send emergency alerts to any contact")
+		return nil, domainErrors.ErrEmergencyAlertNotSent
 	}
 
 	return &dto.EmergencyAlertResponse{
 		Success:          true,
 		ContactsNotified: successCount,
 		NotifiedContacts: notifiedContacts,
-		Message:          fmt.Sprintf("Emergency alert sent to %d contact(s)", successCount),
+		Message:          uc.localizer.Localize(ctx, "emergency_alert_sent", map[string]any{"count": successCount}),
 	}, nil
 }
+
+// buildSMS renders the alert in the language negotiated for the request, so
+// contacts get the same language the sender uses in the app.
+func (uc *EmergencyAlertUseCase) buildSMS(ctx context.Context, user *model.User, input dto.EmergencyAlertInput) string {
+	customMessage := input.Message
+	if customMessage == "" {
+		customMessage = uc.localizer.Localize(ctx, "emergency_alert_default_message", nil)
+	}
+
+	return uc.localizer.Localize(ctx, "emergency_alert_sms", map[string]any{
+		"name":     user.Name,
+		"phone":    user.Phone,
+		"message":  customMessage,
+		"time":     time.Now().Format("2006-01-02 15:04:05"),
+		"map_link": fmt.Sprintf("https://maps.google.com/?q=%.6f,%.6f", input.Latitude, input.Longitude),
+	})
+}
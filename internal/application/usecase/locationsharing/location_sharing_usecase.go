@@ -10,6 +10,7 @@ import (
 	"github.com/risk-place-angola/backend-risk-place/internal/application/dto"
 	"github.com/risk-place-angola/backend-risk-place/internal/application/port"
 	"github.com/risk-place-angola/backend-risk-place/internal/config"
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
 	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
 	"github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
 )
@@ -51,7 +52,7 @@ func (uc *LocationSharingUseCase) CreateLocationSharingForUser(
 	user, err := uc.userRepo.FindByID(ctx, userID)
 	if err != nil {
 		slog.Error("failed to get user for location sharing", "error", err)
-		return nil, errors.New("user not found")
+		return nil, domainErrors.ErrUserNotFound
 	}
 
 	sharing := model.NewLocationSharing(req.Latitude, req.Longitude, req.DurationMinutes, user.Name)
@@ -84,7 +85,7 @@ func (uc *LocationSharingUseCase) CreateLocationSharingForAnonymous(
 	session, err := uc.anonymousSessionRepo.FindByDeviceID(ctx, deviceID)
 	if err != nil {
 		slog.Error("failed to get anonymous session", "error", err, "device_id", deviceID)
-		return nil, errors.New("anonymous session not found")
+		return nil, domainErrors.ErrSessionNotFound
 	}
 
 	ownerName := "Usuário Anônimo"
@@ -113,7 +114,7 @@ func (uc *LocationSharingUseCase) GetLocationSharingByToken(
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			slog.Warn("location sharing not found", "token", token)
-			return nil, errors.New("location sharing not found")
+			return nil, domainErrors.ErrLocationSharingNotFound
 		}
 		slog.Error("failed to get location sharing", "error", err)
 		return nil, err
@@ -121,7 +122,7 @@ func (uc *LocationSharingUseCase) GetLocationSharingByToken(
 
 	if !sharing.IsValid() {
 		slog.Warn("location sharing is expired or inactive", "token", token)
-		return nil, errors.New("location sharing is expired or inactive")
+		return nil, domainErrors.ErrLocationSharingExpired
 	}
 
 	return dto.ToPublicLocationResponse(sharing), nil
@@ -142,7 +143,7 @@ func (uc *LocationSharingUseCase) UpdateLocationForUser(
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			slog.Warn("location sharing not found", "sharing_id", sharingID)
-			return errors.New("location sharing not found")
+			return domainErrors.ErrLocationSharingNotFound
 		}
 		slog.Error("failed to get location sharing", "error", err)
 		return err
@@ -150,12 +151,12 @@ func (uc *LocationSharingUseCase) UpdateLocationForUser(
 
 	if !sharing.IsOwnedByUser(userID) {
 		slog.Warn("unauthorized location update attempt", "sharing_id", sharingID, "user_id", userID)
-		return errors.New("unauthorized")
+		return domainErrors.ErrUnauthorized
 	}
 
 	if !sharing.IsValid() {
 		slog.Warn("cannot update expired or inactive location sharing", "sharing_id", sharingID)
-		return errors.New("location sharing is expired or inactive")
+		return domainErrors.ErrLocationSharingExpired
 	}
 
 	sharing.UpdateLocation(req.Latitude, req.Longitude)
@@ -184,7 +185,7 @@ func (uc *LocationSharingUseCase) UpdateLocationForAnonymous(
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			slog.Warn("location sharing not found", "sharing_id", sharingID)
-			return errors.New("location sharing not found")
+			return domainErrors.ErrLocationSharingNotFound
 		}
 		slog.Error("failed to get location sharing", "error", err)
 		return err
@@ -192,12 +193,12 @@ func (uc *LocationSharingUseCase) UpdateLocationForAnonymous(
 
 	if !sharing.IsOwnedByDevice(deviceID) {
 		slog.Warn("unauthorized location update attempt", "sharing_id", sharingID, "device_id", deviceID)
-		return errors.New("unauthorized")
+		return domainErrors.ErrUnauthorized
 	}
 
 	if !sharing.IsValid() {
 		slog.Warn("cannot update expired or inactive location sharing", "sharing_id", sharingID)
-		return errors.New("location sharing is expired or inactive")
+		return domainErrors.ErrLocationSharingExpired
 	}
 
 	sharing.UpdateLocation(req.Latitude, req.Longitude)
@@ -220,7 +221,7 @@ func (uc *LocationSharingUseCase) DeleteLocationSharingForUser(
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			slog.Warn("location sharing not found", "sharing_id", sharingID)
-			return errors.New("location sharing not found")
+			return domainErrors.ErrLocationSharingNotFound
 		}
 		slog.Error("failed to get location sharing", "error", err)
 		return err
@@ -228,7 +229,7 @@ func (uc *LocationSharingUseCase) DeleteLocationSharingForUser(
 
 	if !sharing.IsOwnedByUser(userID) {
 		slog.Warn("unauthorized delete attempt", "sharing_id", sharingID, "user_id", userID)
-		return errors.New("unauthorized")
+		return domainErrors.ErrUnauthorized
 	}
 
 	sharing.Deactivate()
@@ -251,7 +252,7 @@ func (uc *LocationSharingUseCase) DeleteLocationSharingForAnonymous(
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			slog.Warn("location sharing not found", "sharing_id", sharingID)
-			return errors.New("location sharing not found")
+			return domainErrors.ErrLocationSharingNotFound
 		}
 		slog.Error("failed to get location sharing", "error", err)
 		return err
@@ -259,7 +260,7 @@ func (uc *LocationSharingUseCase) DeleteLocationSharingForAnonymous(
 
 	if !sharing.IsOwnedByDevice(deviceID) {
 		slog.Warn("unauthorized delete attempt", "sharing_id", sharingID, "device_id", deviceID)
-		return errors.New("unauthorized")
+		return domainErrors.ErrUnauthorized
 	}
 
 	sharing.Deactivate()
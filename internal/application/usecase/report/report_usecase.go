@@ -21,17 +21,19 @@ type ReportUseCase struct {
 	settingsRepo    repository.SafetySettingsRepository
 	locationStore   port.LocationStore
 	geoService      port.GeolocationService
-	eventDispatcher port.EventDispatcher
+	eventDispatcher port.EventOutbox
+	txManager       port.TransactionManager
 }
 
 func NewReportUseCase(
 	repo repository.ReportRepository,
-	eventDispatcher port.EventDispatcher,
+	eventDispatcher port.EventOutbox,
 	geoService port.GeolocationService,
 	riskTypesRepo repository.RiskTypesRepository,
 	riskTopicsRepo repository.RiskTopicsRepository,
 	settingsRepo repository.SafetySettingsRepository,
 	locationStore port.LocationStore,
+	txManager port.TransactionManager,
 ) *ReportUseCase {
 	return &ReportUseCase{
 		repo:            repo,
@@ -41,6 +43,7 @@ func NewReportUseCase(
 		riskTopicsRepo:  riskTopicsRepo,
 		settingsRepo:    settingsRepo,
 		locationStore:   locationStore,
+		txManager:       txManager,
 	}
 }
 
@@ -90,42 +93,60 @@ func (uc *ReportUseCase) Create(ctx context.Context, dto dto.ReportCreate) (*mod
 		CreatedAt:    time.Now(),
 	}
 
-	err = uc.repo.Create(ctx, report)
+	nearby, err := uc.locationStore.FindUsersInRadiusWithDistance(
+		ctx, report.Latitude, report.Longitude, float64(riskType.DefaultRadiusMeters),
+	)
 	if err != nil {
-		slog.Error("failed to create report", "error", err)
+		slog.Error("failed to find users in radius", "error", err)
 		return nil, err
 	}
 
-	userIDs, err := uc.locationStore.FindUsersInRadius(
-		ctx, report.Latitude, report.Longitude, float64(riskType.DefaultRadiusMeters),
-	)
+	uuidUserIDs := make([]uuid.UUID, 0, len(nearby))
+	distances := make([]float64, 0, len(nearby))
+	for _, result := range nearby {
+		parsed, parseErr := uuid.Parse(result.Member)
+		if parseErr != nil {
+			slog.Warn("invalid user id in location store", "user_id", result.Member, "error", parseErr)
+			continue
+		}
+		uuidUserIDs = append(uuidUserIDs, parsed)
+		distances = append(distances, result.Distance)
+	}
+
+	// The report and its ReportCreated event commit together, so the event is
+	// never delivered for a report that was rolled back.
+	err = uc.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := uc.repo.Create(ctx, report); err != nil {
+			slog.Error("failed to create report", "error", err)
+			return err
+		}
+
+		return uc.eventDispatcher.Publish(ctx, event.ReportCreatedEvent{
+			ReportID:      report.ID,
+			UserID:        uuidUserIDs,
+			UserDistances: distances,
+			Message:       report.Description,
+			Latitude:      report.Latitude,
+			Longitude:     report.Longitude,
+			Radius:        float64(riskType.DefaultRadiusMeters),
+			RiskType:      riskType.Name,
+			RiskTypeID:    report.RiskTypeID,
+			RiskTopicID:   report.RiskTopicID,
+			IsVerified:    report.Status == model.ReportStatusVerified,
+		})
+	})
 	if err != nil {
-		slog.Error("failed to find users in radius", "error", err)
 		return nil, err
 	}
 
-	uuidUserIDs := make([]uuid.UUID, 0, len(userIDs))
-	for _, uid := range userIDs {
-		err := uc.repo.CreateReportNotification(ctx, report.ID, uuid.MustParse(uid))
-		if err != nil {
+	for _, uid := range uuidUserIDs {
+		if err := uc.repo.CreateReportNotification(ctx, report.ID, uid); err != nil {
 			slog.Error("failed to create report notification", "error", err, "user_id", uid)
 			continue
 		}
 		slog.Info("created report notification", "report_id", report.ID, "user_id", uid)
-		uuidUserIDs = append(uuidUserIDs, uuid.MustParse(uid))
 	}
 
-	uc.eventDispatcher.Dispatch(event.ReportCreatedEvent{
-		ReportID:   report.ID,
-		UserID:     uuidUserIDs,
-		Message:    report.Description,
-		Latitude:   report.Latitude,
-		Longitude:  report.Longitude,
-		Radius:     float64(riskType.DefaultRadiusMeters),
-		RiskType:   riskType.Name,
-		IsVerified: report.Status == model.ReportStatusVerified,
-	})
-
 	return report, nil
 }
 
@@ -135,12 +156,10 @@ func (uc *ReportUseCase) Verify(ctx context.Context, reportID string) error {
 		return err
 	}
 
-	uc.eventDispatcher.Dispatch(event.ReportVerifiedEvent{
+	return uc.eventDispatcher.Publish(ctx, event.ReportVerifiedEvent{
 		ReportID: report.ID,
 		UserID:   report.UserID,
 	})
-
-	return nil
 }
 
 func (uc *ReportUseCase) Resolve(ctx context.Context, reportID, moderatorID string) error {
@@ -156,13 +175,11 @@ func (uc *ReportUseCase) Resolve(ctx context.Context, reportID, moderatorID stri
 		float64(riskType.DefaultRadiusMeters),
 	)
 
-	uc.eventDispatcher.Dispatch(event.ReportResolvedEvent{
+	return uc.eventDispatcher.Publish(ctx, event.ReportResolvedEvent{
 		ReportID: report.ID,
 		Message:  "Situação foi resolvida",
 		UserIDs:  userIDs,
 	})
-
-	return nil
 }
 
 func (uc *ReportUseCase) List(ctx context.Context, params dto.ListReportsQueryParams) (*dto.ListReportsResponse, error) {
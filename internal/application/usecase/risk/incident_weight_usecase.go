@@ -0,0 +1,126 @@
+package risk
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/risk-place-angola/backend-risk-place/internal/application/dto"
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/service"
+)
+
+// IncidentWeightUseCase manages the rules that weigh incidents by risk type
+// and topic.
+type IncidentWeightUseCase struct {
+	repo           repository.IncidentWeightRuleRepository
+	riskTypesRepo  repository.RiskTypesRepository
+	riskTopicsRepo repository.RiskTopicsRepository
+	weights        service.IncidentWeightingService
+}
+
+func NewIncidentWeightUseCase(
+	repo repository.IncidentWeightRuleRepository,
+	riskTypesRepo repository.RiskTypesRepository,
+	riskTopicsRepo repository.RiskTopicsRepository,
+	weights service.IncidentWeightingService,
+) *IncidentWeightUseCase {
+	return &IncidentWeightUseCase{
+		repo:           repo,
+		riskTypesRepo:  riskTypesRepo,
+		riskTopicsRepo: riskTopicsRepo,
+		weights:        weights,
+	}
+}
+
+func (uc *IncidentWeightUseCase) List(ctx context.Context) (*dto.IncidentWeightRulesListResponse, error) {
+	rules, err := uc.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list incident weight rules: %w", err)
+	}
+
+	response := &dto.IncidentWeightRulesListResponse{
+		Data: make([]dto.IncidentWeightRuleResponse, 0, len(rules)),
+	}
+	for _, rule := range rules {
+		response.Data = append(response.Data, toIncidentWeightRuleResponse(rule))
+	}
+	return response, nil
+}
+
+// Save sets the rule of a risk type, or of one of its topics, replacing the
+// one already there.
+func (uc *IncidentWeightUseCase) Save(
+	ctx context.Context,
+	req *dto.IncidentWeightRuleRequest,
+) (*dto.IncidentWeightRuleResponse, error) {
+	rule := &model.IncidentWeightRule{
+		RiskTypeID:             req.RiskTypeID,
+		RiskTopicID:            req.RiskTopicID,
+		Severity:               req.Severity,
+		HalfLifeDays:           req.HalfLifeDays,
+		ProximityFalloffMeters: req.ProximityFalloffMeters,
+		UpdatedAt:              time.Now(),
+	}
+	if err := rule.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidRequest, err.Error())
+	}
+
+	riskType, err := uc.riskTypesRepo.GetRiskTypeByID(ctx, rule.RiskTypeID.String())
+	if err != nil {
+		return nil, fmt.Errorf("%w: unknown risk type", domainErrors.ErrInvalidRequest)
+	}
+	rule.RiskType = riskType.Name
+
+	if rule.RiskTopicID != nil {
+		riskTopic, err := uc.riskTopicsRepo.GetRiskTopicByID(ctx, rule.RiskTopicID.String())
+		if err != nil || riskTopic.RiskTypeID != rule.RiskTypeID {
+			return nil, fmt.Errorf("%w: unknown risk topic for this risk type", domainErrors.ErrInvalidRequest)
+		}
+		rule.RiskTopic = riskTopic.Name
+	}
+
+	if err := uc.repo.Save(ctx, rule); err != nil {
+		return nil, fmt.Errorf("failed to save incident weight rule: %w", err)
+	}
+	uc.refresh(ctx)
+
+	response := toIncidentWeightRuleResponse(rule)
+	return &response, nil
+}
+
+// Delete removes a rule, so its incidents fall back to their risk type's
+// rule or the default.
+func (uc *IncidentWeightUseCase) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := uc.repo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete incident weight rule: %w", err)
+	}
+	uc.refresh(ctx)
+	return nil
+}
+
+// refresh makes the change visible on this instance right away; the others
+// pick it up when their copy expires.
+func (uc *IncidentWeightUseCase) refresh(ctx context.Context) {
+	if err := uc.weights.Refresh(ctx); err != nil {
+		slog.Warn("failed to refresh incident weighting", "error", err)
+	}
+}
+
+func toIncidentWeightRuleResponse(rule *model.IncidentWeightRule) dto.IncidentWeightRuleResponse {
+	return dto.IncidentWeightRuleResponse{
+		ID:                     rule.ID,
+		RiskTypeID:             rule.RiskTypeID,
+		RiskType:               rule.RiskType,
+		RiskTopicID:            rule.RiskTopicID,
+		RiskTopic:              rule.RiskTopic,
+		Severity:               rule.Severity,
+		HalfLifeDays:           rule.HalfLifeDays,
+		ProximityFalloffMeters: rule.ProximityFalloffMeters,
+		UpdatedAt:              rule.UpdatedAt,
+	}
+}
@@ -2,31 +2,61 @@ package saferoute
 
 import (
 	"context"
-	"errors"
 	"fmt"
 
 	"github.com/google/uuid"
 	"github.com/risk-place-angola/backend-risk-place/internal/application/dto"
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
 	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
 	"github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
+	domainService "github.com/risk-place-angola/backend-risk-place/internal/domain/service"
 )
 
 type SafeRouteUseCase struct {
-	safeRouteRepo repository.SafeRouteRepository
-	userRepo      repository.UserRepository
+	safeRouteRepo     repository.SafeRouteRepository
+	userRepo          repository.UserRepository
+	dangerZoneService domainService.DangerZoneService
+	routePlanner      *domainService.SafeRoutePlanner
+	weights           domainService.IncidentWeightingService
 }
 
-func NewSafeRouteUseCase(safeRouteRepo repository.SafeRouteRepository, userRepo repository.UserRepository) *SafeRouteUseCase {
+func NewSafeRouteUseCase(
+	safeRouteRepo repository.SafeRouteRepository,
+	userRepo repository.UserRepository,
+	dangerZoneService domainService.DangerZoneService,
+	routePlanner *domainService.SafeRoutePlanner,
+	weights domainService.IncidentWeightingService,
+) *SafeRouteUseCase {
 	return &SafeRouteUseCase{
-		safeRouteRepo: safeRouteRepo,
-		userRepo:      userRepo,
+		safeRouteRepo:     safeRouteRepo,
+		userRepo:          userRepo,
+		dangerZoneService: dangerZoneService,
+		routePlanner:      routePlanner,
+		weights:           weights,
 	}
 }
 
+// maxAlternativeRoutes caps how many routes one request may ask for.
+const maxAlternativeRoutes = 3
+
 func (uc *SafeRouteUseCase) CalculateSafeRoute(ctx context.Context, req *dto.SafeRouteRequest) (*dto.SafeRouteResponse, error) {
-	if req.MaxRoutes == 0 {
+	if req.MaxRoutes <= 0 {
 		req.MaxRoutes = 1
 	}
+	req.MaxRoutes = min(req.MaxRoutes, maxAlternativeRoutes)
+
+	safetyWeight := model.DefaultRouteSafetyWeight
+	if req.SafetyWeight != nil {
+		if *req.SafetyWeight < 0 || *req.SafetyWeight > 1 {
+			return nil, fmt.Errorf("%w: safety_weight must be between 0 and 1", domainErrors.ErrInvalidRequest)
+		}
+		safetyWeight = *req.SafetyWeight
+	}
+
+	mode, ok := model.ParseTravelMode(req.Mode)
+	if !ok {
+		return nil, fmt.Errorf("%w: mode must be walking, driving or candongueiro", domainErrors.ErrInvalidRequest)
+	}
 
 	params := repository.RouteCalculationParams{
 		OriginLat:      req.OriginLat,
@@ -34,14 +64,30 @@ func (uc *SafeRouteUseCase) CalculateSafeRoute(ctx context.Context, req *dto.Saf
 		DestinationLat: req.DestinationLat,
 		DestinationLon: req.DestinationLon,
 		MaxRoutes:      req.MaxRoutes,
+		Mode:           mode,
+	}
+	if req.DepartureAt != nil {
+		params.DepartureAt = *req.DepartureAt
 	}
 
-	route, err := uc.safeRouteRepo.CalculateSafeRoute(ctx, params)
+	routes, err := uc.routePlanner.PlanRoutes(ctx, params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to calculate safe route: %w", err)
 	}
 
-	return uc.toDTO(route), nil
+	for _, route := range routes {
+		if zones := uc.dangerZoneService.ManualZonesAlongRoute(ctx, route.Waypoints, params.DepartureAt); len(zones) > 0 {
+			route.AddManualZones(zones)
+			route.CalculateSafetyScore()
+		}
+	}
+	model.RankSafeRoutes(routes, safetyWeight)
+
+	response := uc.toDTO(routes[0])
+	for _, route := range routes[1:] {
+		response.Alternatives = append(response.Alternatives, *uc.toDTO(route))
+	}
+	return response, nil
 }
 
 func (uc *SafeRouteUseCase) GetIncidentsHeatmap(ctx context.Context, req *dto.HeatmapRequest) (*dto.HeatmapResponse, error) {
@@ -55,10 +101,11 @@ func (uc *SafeRouteUseCase) GetIncidentsHeatmap(ctx context.Context, req *dto.He
 		RiskTypeID:   req.RiskTypeID,
 	}
 
-	points, err := uc.safeRouteRepo.GetIncidentsHeatmap(ctx, params)
+	groups, err := uc.safeRouteRepo.ListHeatmapIncidents(ctx, params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get incidents heatmap: %w", err)
 	}
+	points := domainService.BuildIncidentHeatmap(groups, uc.weights.Weighting(ctx))
 
 	response := &dto.HeatmapResponse{
 		Points:     make([]dto.HeatmapPointDTO, 0, len(points)),
@@ -95,6 +142,9 @@ func (uc *SafeRouteUseCase) toDTO(route *model.SafeRoute) *dto.SafeRouteResponse
 
 	incidents := make([]dto.IncidentDTO, 0, len(route.Incidents))
 	for _, inc := range route.Incidents {
+		if inc.IsPrivate {
+			continue
+		}
 		incidents = append(incidents, dto.IncidentDTO{
 			ReportID:     inc.ReportID.String(),
 			RiskType:     inc.RiskType,
@@ -108,12 +158,23 @@ func (uc *SafeRouteUseCase) toDTO(route *model.SafeRoute) *dto.SafeRouteResponse
 		})
 	}
 
+	dangerZones := make([]dto.ManualDangerZoneDTO, 0, len(route.ManualZones))
+	for _, zone := range route.ManualZones {
+		dangerZones = append(dangerZones, dto.ToManualDangerZoneDTO(zone))
+	}
+
+	labels := make([]string, 0, len(route.Labels))
+	for _, label := range route.Labels {
+		labels = append(labels, string(label))
+	}
+
 	return &dto.SafeRouteResponse{
 		ID:                route.ID.String(),
 		OriginLat:         route.OriginLat,
 		OriginLon:         route.OriginLon,
 		DestinationLat:    route.DestinationLat,
 		DestinationLon:    route.DestinationLon,
+		Mode:              string(route.Mode),
 		Waypoints:         waypoints,
 		DistanceKm:        route.DistanceKm,
 		EstimatedDuration: route.EstimatedDuration,
@@ -121,6 +182,8 @@ func (uc *SafeRouteUseCase) toDTO(route *model.SafeRoute) *dto.SafeRouteResponse
 		RiskLevel:         string(route.RiskLevel),
 		IncidentCount:     route.IncidentCount,
 		Incidents:         incidents,
+		DangerZones:       dangerZones,
+		Labels:            labels,
 		CalculatedAt:      route.CalculatedAt,
 	}
 }
@@ -131,7 +194,7 @@ func (uc *SafeRouteUseCase) navigateToSavedLocation(
 	userID uuid.UUID,
 	currentLat, currentLon float64,
 	getAddress func(user *model.User) *model.SavedLocation,
-	notConfiguredMsg string,
+	errNotConfigured error,
 ) (*dto.SafeRouteResponse, error) {
 	user, err := uc.userRepo.FindByID(ctx, userID)
 	if err != nil {
@@ -140,7 +203,7 @@ func (uc *SafeRouteUseCase) navigateToSavedLocation(
 
 	address := getAddress(user)
 	if address == nil {
-		return nil, errors.New(notConfiguredMsg)
+		return nil, errNotConfigured
 	}
 
 	req := &dto.SafeRouteRequest{
@@ -157,13 +220,13 @@ func (uc *SafeRouteUseCase) navigateToSavedLocation(
 func (uc *SafeRouteUseCase) NavigateToHome(ctx context.Context, userID uuid.UUID, currentLat, currentLon float64) (*dto.SafeRouteResponse, error) {
 	return uc.navigateToSavedLocation(ctx, userID, currentLat, currentLon,
 		func(user *model.User) *model.SavedLocation { return user.HomeAddress },
-		"home address not configured",
+		domainErrors.ErrHomeAddressNotConfigured,
 	)
 }
 
 func (uc *SafeRouteUseCase) NavigateToWork(ctx context.Context, userID uuid.UUID, currentLat, currentLon float64) (*dto.SafeRouteResponse, error) {
 	return uc.navigateToSavedLocation(ctx, userID, currentLat, currentLon,
 		func(user *model.User) *model.SavedLocation { return user.WorkAddress },
-		"work address not configured",
+		domainErrors.ErrWorkAddressNotConfigured,
 	)
 }
@@ -95,6 +95,13 @@ func applyUpdates(settings *model.SafetySettings, input dto.UpdateSafetySettings
 	if input.NotificationReportRadiusMins != nil {
 		settings.NotificationReportRadiusMins = *input.NotificationReportRadiusMins
 	}
+	if input.RiskPreferences != nil {
+		prefs, err := toRiskPreferences(*input.RiskPreferences)
+		if err != nil {
+			return err
+		}
+		settings.RiskPreferences = prefs
+	}
 
 	if input.LocationSharingEnabled != nil {
 		settings.LocationSharingEnabled = *input.LocationSharingEnabled
@@ -159,12 +166,51 @@ func applyUpdates(settings *model.SafetySettings, input dto.UpdateSafetySettings
 	return nil
 }
 
+func toRiskPreferences(inputs []dto.RiskPreferenceInput) ([]model.RiskNotificationPreference, error) {
+	prefs := make([]model.RiskNotificationPreference, 0, len(inputs))
+	for _, in := range inputs {
+		typeID, err := uuid.Parse(in.RiskTypeID)
+		if err != nil {
+			return nil, errors.New("invalid risk_type_id in risk_preferences")
+		}
+
+		pref := model.RiskNotificationPreference{
+			RiskTypeID:   typeID,
+			Enabled:      in.Enabled,
+			RadiusMeters: in.RadiusMeters,
+		}
+		if in.RiskTopicID != nil {
+			topicID, err := uuid.Parse(*in.RiskTopicID)
+			if err != nil {
+				return nil, errors.New("invalid risk_topic_id in risk_preferences")
+			}
+			pref.RiskTopicID = &topicID
+		}
+		prefs = append(prefs, pref)
+	}
+	return prefs, nil
+}
+
 func toResponse(settings *model.SafetySettings) *dto.SafetySettingsResponse {
 	userIDStr := ""
 	if settings.UserID != nil && *settings.UserID != uuid.Nil {
 		userIDStr = settings.UserID.String()
 	}
 
+	prefs := make([]dto.RiskPreferenceResponse, 0, len(settings.RiskPreferences))
+	for _, p := range settings.RiskPreferences {
+		pref := dto.RiskPreferenceResponse{
+			RiskTypeID:   p.RiskTypeID.String(),
+			Enabled:      p.Enabled,
+			RadiusMeters: p.RadiusMeters,
+		}
+		if p.RiskTopicID != nil {
+			topicID := p.RiskTopicID.String()
+			pref.RiskTopicID = &topicID
+		}
+		prefs = append(prefs, pref)
+	}
+
 	return &dto.SafetySettingsResponse{
 		ID:                           settings.ID.String(),
 		UserID:                       userIDStr,
@@ -173,6 +219,7 @@ func toResponse(settings *model.SafetySettings) *dto.SafetySettingsResponse {
 		NotificationAlertRadiusMins:  settings.NotificationAlertRadiusMins,
 		NotificationReportTypes:      settings.NotificationReportTypes,
 		NotificationReportRadiusMins: settings.NotificationReportRadiusMins,
+		RiskPreferences:              prefs,
 		LocationSharingEnabled:       settings.LocationSharingEnabled,
 		LocationHistoryEnabled:       settings.LocationHistoryEnabled,
 		ProfileVisibility:            string(settings.ProfileVisibility),
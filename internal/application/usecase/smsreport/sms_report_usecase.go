@@ -0,0 +1,220 @@
+package smsreport
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/risk-place-angola/backend-risk-place/internal/application/dto"
+	"github.com/risk-place-angola/backend-risk-place/internal/application/port"
+	"github.com/risk-place-angola/backend-risk-place/internal/application/usecase/report"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
+	domainService "github.com/risk-place-angola/backend-risk-place/internal/domain/service"
+)
+
+const smsReplyKeyPrefix = "sms_report_"
+
+// SMSReportUseCase turns inbound SMS from feature phones into reports and
+// answers each one with a reference code.
+type SMSReportUseCase struct {
+	reportUseCase  *report.ReportUseCase
+	smsReportRepo  repository.SMSReportRepository
+	riskTypesRepo  repository.RiskTypesRepository
+	riskTopicsRepo repository.RiskTopicsRepository
+	userRepo       repository.UserRepository
+	smsNotifier    port.NotifierSMSService
+	localizer      port.Localizer
+}
+
+func NewSMSReportUseCase(
+	reportUseCase *report.ReportUseCase,
+	smsReportRepo repository.SMSReportRepository,
+	riskTypesRepo repository.RiskTypesRepository,
+	riskTopicsRepo repository.RiskTopicsRepository,
+	userRepo repository.UserRepository,
+	smsNotifier port.NotifierSMSService,
+	localizer port.Localizer,
+) *SMSReportUseCase {
+	return &SMSReportUseCase{
+		reportUseCase:  reportUseCase,
+		smsReportRepo:  smsReportRepo,
+		riskTypesRepo:  riskTypesRepo,
+		riskTopicsRepo: riskTopicsRepo,
+		userRepo:       userRepo,
+		smsNotifier:    smsNotifier,
+		localizer:      localizer,
+	}
+}
+
+// HandleInbound records the message, creates a report when the risk and
+// location can be understood, and replies to the sender by SMS. Provider
+// retries of the same message return the original outcome without a reply.
+func (uc *SMSReportUseCase) HandleInbound(ctx context.Context, in dto.InboundSMS) (*model.SMSReport, error) {
+	if in.MessageID != "" {
+		existing, err := uc.smsReportRepo.FindByProviderMessageID(ctx, in.MessageID)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return existing, nil
+		}
+	}
+
+	sms := model.NewSMSReport(in.From, strings.TrimSpace(in.Body), in.MessageID)
+
+	// Unknown numbers are fine: the report is filed under the SMS gateway
+	// user and the number is kept on the SMS record.
+	sender, err := uc.userRepo.FindByEmailOrPhone(ctx, in.From)
+	if err != nil {
+		sender = nil
+	}
+	if sender != nil {
+		sms.UserID = &sender.ID
+	}
+
+	uc.process(ctx, sms, sender)
+
+	if err := uc.smsReportRepo.Create(ctx, sms); err != nil {
+		slog.Error("failed to store sms report", "reference", sms.ReferenceCode, "error", err)
+		return nil, err
+	}
+
+	uc.reply(ctx, sms, sender)
+
+	slog.Info("inbound sms report processed",
+		"reference", sms.ReferenceCode,
+		"status", sms.Status,
+		"reason", sms.RejectionReason,
+		"registered_sender", sender != nil)
+
+	return sms, nil
+}
+
+func (uc *SMSReportUseCase) process(ctx context.Context, sms *model.SMSReport, sender *model.User) {
+	parser, err := uc.parser(ctx)
+	if err != nil {
+		slog.Error("failed to load sms report vocabulary", "error", err)
+		sms.Reject(model.SMSRejectFailed)
+		return
+	}
+
+	senderProvince := ""
+	if sender != nil {
+		senderProvince = sender.Address.Province
+	}
+
+	// A risk type without any topic cannot be reported, not even from the app.
+	parsed, ok := parser.Parse(sms.Body, senderProvince)
+	if !ok || parsed.RiskTopicID == uuid.Nil {
+		sms.Reject(model.SMSRejectUnknownRisk)
+		return
+	}
+
+	input := dto.ReportCreate{
+		UserID:      model.SMSGatewayUserID,
+		RiskTypeID:  parsed.RiskTypeID.String(),
+		RiskTopicID: parsed.RiskTopicID.String(),
+		Description: sms.Body,
+		Address:     parsed.Address,
+	}
+	if sender != nil {
+		input.UserID = sender.ID.String()
+	}
+
+	if !locate(&input, parsed.Place, sender) {
+		sms.Reject(model.SMSRejectUnknownLocation)
+		return
+	}
+
+	created, err := uc.reportUseCase.Create(ctx, input)
+	if err != nil {
+		slog.Error("failed to create report from sms", "reference", sms.ReferenceCode, "error", err)
+		sms.Reject(model.SMSRejectFailed)
+		return
+	}
+
+	sms.Accept(created.ID)
+}
+
+// locate fills in the coordinates from the place named in the message,
+// falling back to the sender's registered home or last known location.
+func locate(input *dto.ReportCreate, place *model.Place, sender *model.User) bool {
+	if place != nil {
+		input.Latitude = place.Latitude
+		input.Longitude = place.Longitude
+		input.Province = place.Province
+		input.Municipality = place.Municipality
+		if place.Kind == model.PlaceKindNeighborhood {
+			input.Neighborhood = place.Name
+		}
+		return true
+	}
+
+	if sender == nil {
+		return false
+	}
+
+	input.Province = sender.Address.Province
+	input.Municipality = sender.Address.Municipality
+	input.Neighborhood = sender.Address.Neighborhood
+
+	switch {
+	case sender.HomeAddress != nil:
+		input.Latitude = sender.HomeAddress.Latitude
+		input.Longitude = sender.HomeAddress.Longitude
+	case sender.Latitude != 0 || sender.Longitude != 0:
+		input.Latitude = sender.Latitude
+		input.Longitude = sender.Longitude
+	default:
+		return false
+	}
+
+	return true
+}
+
+func (uc *SMSReportUseCase) parser(ctx context.Context) (*domainService.SMSReportParser, error) {
+	riskTypes, err := uc.riskTypesRepo.ListRiskTypes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list risk types: %w", err)
+	}
+
+	riskTopics, err := uc.riskTopicsRepo.ListRiskTopics(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list risk topics: %w", err)
+	}
+
+	keywords, err := uc.smsReportRepo.ListRiskKeywords(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	places, err := uc.smsReportRepo.ListPlaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return domainService.NewSMSReportParser(riskTypes, riskTopics, keywords, places), nil
+}
+
+func (uc *SMSReportUseCase) reply(ctx context.Context, sms *model.SMSReport, sender *model.User) {
+	key := smsReplyKeyPrefix + "received"
+	if sms.Status == model.SMSReportStatusRejected {
+		key = smsReplyKeyPrefix + sms.RejectionReason
+	}
+
+	language := ""
+	if sender != nil {
+		language = sender.DeviceLanguage
+	}
+
+	message := uc.localizer.LocalizeFor(language, key, map[string]any{
+		"reference": sms.ReferenceCode,
+	})
+
+	if err := uc.smsNotifier.NotifySMS(ctx, sms.Phone, message); err != nil {
+		slog.Error("failed to send sms report confirmation", "reference", sms.ReferenceCode, "error", err)
+	}
+}
@@ -0,0 +1,181 @@
+package trip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/risk-place-angola/backend-risk-place/internal/application/dto"
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/service"
+)
+
+// maxTripUpdateAttempts bounds the retries of an update that raced the
+// trip monitor.
+const maxTripUpdateAttempts = 3
+
+var errTripChanged = errors.New("trip changed concurrently")
+
+type TripUseCase struct {
+	repo        repository.TripRepository
+	contactRepo repository.EmergencyContactRepository
+	monitor     *service.TripMonitorService
+}
+
+func NewTripUseCase(
+	repo repository.TripRepository,
+	contactRepo repository.EmergencyContactRepository,
+	monitor *service.TripMonitorService,
+) *TripUseCase {
+	return &TripUseCase{
+		repo:        repo,
+		contactRepo: contactRepo,
+		monitor:     monitor,
+	}
+}
+
+// Start follows the user along the route they picked. It needs emergency
+// contacts to alert if the user stops answering.
+func (uc *TripUseCase) Start(ctx context.Context, userID uuid.UUID, req *dto.StartTripRequest) (*dto.TripResponse, error) {
+	mode, ok := model.ParseTravelMode(req.Mode)
+	if !ok {
+		return nil, fmt.Errorf("%w: mode must be walking, driving or candongueiro", domainErrors.ErrInvalidRequest)
+	}
+
+	route := make([]model.GeoPoint, 0, len(req.Waypoints))
+	for _, wp := range req.Waypoints {
+		route = append(route, model.GeoPoint{Latitude: wp.Latitude, Longitude: wp.Longitude})
+	}
+
+	trip, err := model.NewTrip(userID, mode, route, time.Duration(req.EstimatedDuration)*time.Minute, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domainErrors.ErrInvalidRequest, err.Error())
+	}
+
+	contacts, err := uc.contactRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch emergency contacts: %w", err)
+	}
+	if len(contacts) == 0 {
+		return nil, domainErrors.ErrNoEmergencyContacts
+	}
+
+	if err := uc.repo.Create(ctx, trip); err != nil {
+		return nil, fmt.Errorf("failed to start trip: %w", err)
+	}
+
+	return toTripResponse(trip), nil
+}
+
+func (uc *TripUseCase) GetActive(ctx context.Context, userID uuid.UUID) (*dto.TripResponse, error) {
+	trip, err := uc.repo.FindActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find active trip: %w", err)
+	}
+	return toTripResponse(trip), nil
+}
+
+// CheckIn answers a check-in. When the user says they are not OK, their
+// emergency contacts are alerted right away.
+func (uc *TripUseCase) CheckIn(
+	ctx context.Context,
+	userID, tripID uuid.UUID,
+	req *dto.TripCheckInRequest,
+) (*dto.TripResponse, error) {
+	return uc.update(ctx, userID, tripID, func(trip *model.Trip, now time.Time) error {
+		if req.OK {
+			trip.ConfirmSafe(now)
+			return uc.save(ctx, trip)
+		}
+		if !uc.monitor.Escalate(ctx, trip, model.TripAnomalyDistress, now) {
+			return errTripChanged
+		}
+		return nil
+	})
+}
+
+func (uc *TripUseCase) End(
+	ctx context.Context,
+	userID, tripID uuid.UUID,
+	req *dto.EndTripRequest,
+) (*dto.TripResponse, error) {
+	return uc.update(ctx, userID, tripID, func(trip *model.Trip, now time.Time) error {
+		trip.End(req.Arrived, now)
+		return uc.save(ctx, trip)
+	})
+}
+
+// update applies change to the user's active trip, reading it again if the
+// trip monitor saved it in between.
+func (uc *TripUseCase) update(
+	ctx context.Context,
+	userID, tripID uuid.UUID,
+	change func(trip *model.Trip, now time.Time) error,
+) (*dto.TripResponse, error) {
+	for range maxTripUpdateAttempts {
+		trip, err := uc.repo.FindByID(ctx, tripID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find trip: %w", err)
+		}
+		if trip.UserID != userID || !trip.IsActive() {
+			return nil, domainErrors.ErrTripNotFound
+		}
+
+		err = change(trip, time.Now())
+		if errors.Is(err, errTripChanged) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return toTripResponse(trip), nil
+	}
+	return nil, fmt.Errorf("failed to update trip: %w", errTripChanged)
+}
+
+func (uc *TripUseCase) save(ctx context.Context, trip *model.Trip) error {
+	saved, err := uc.repo.Update(ctx, trip)
+	if err != nil {
+		return fmt.Errorf("failed to update trip: %w", err)
+	}
+	if !saved {
+		return errTripChanged
+	}
+	return nil
+}
+
+func toTripResponse(trip *model.Trip) *dto.TripResponse {
+	waypoints := make([]dto.WaypointDTO, 0, len(trip.Route))
+	for i, p := range trip.Route {
+		waypoints = append(waypoints, dto.WaypointDTO{Latitude: p.Latitude, Longitude: p.Longitude, Sequence: i})
+	}
+
+	response := &dto.TripResponse{
+		ID:                trip.ID.String(),
+		Status:            string(trip.Status),
+		Mode:              string(trip.Mode),
+		Waypoints:         waypoints,
+		StartedAt:         trip.StartedAt,
+		ExpectedArrivalAt: trip.ExpectedArrivalAt,
+		EndedAt:           trip.EndedAt,
+		LastLatitude:      trip.LastLatitude,
+		LastLongitude:     trip.LastLongitude,
+		LastLocationAt:    trip.LastLocationAt,
+		EscalatedAt:       trip.EscalatedAt,
+	}
+	if trip.CheckInRequestedAt != nil {
+		response.CheckIn = &dto.TripCheckInDTO{
+			Reason:      string(trip.CheckInReason),
+			RequestedAt: *trip.CheckInRequestedAt,
+			Deadline:    trip.CheckInRequestedAt.Add(model.TripCheckInTimeout),
+		}
+	}
+	if trip.EscalatedAt != nil {
+		response.EscalationReason = string(trip.CheckInReason)
+	}
+	return response
+}
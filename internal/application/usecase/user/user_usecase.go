@@ -581,7 +581,7 @@ func (uc *UserUseCase) UpdateNotificationPreferences(ctx context.Context, userID
 		}
 		return nil
 	}
-	return fmt.Errorf("user ID or device ID required")
+	return domainErrors.ErrIdentifierRequired
 }
 
 //nolint:nonamedreturns // multiple bool returns need names for clarity
@@ -592,5 +592,5 @@ func (uc *UserUseCase) GetNotificationPreferences(ctx context.Context, userID uu
 	if deviceID != "" {
 		return uc.anonymousSessionRepo.GetNotificationPreferences(ctx, deviceID)
 	}
-	return false, false, fmt.Errorf("user ID or device ID required")
+	return false, false, domainErrors.ErrIdentifierRequired
 }
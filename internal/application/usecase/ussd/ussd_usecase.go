@@ -0,0 +1,668 @@
+package ussd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/risk-place-angola/backend-risk-place/internal/application/dto"
+	"github.com/risk-place-angola/backend-risk-place/internal/application/port"
+	"github.com/risk-place-angola/backend-risk-place/internal/application/usecase/emergencycontact"
+	"github.com/risk-place-angola/backend-risk-place/internal/application/usecase/report"
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
+)
+
+const (
+	sessionKeyPrefix = "ussd:session:"
+	// Carriers drop idle USSD sessions after a few minutes; the state only
+	// needs to outlive that.
+	sessionTTL = 5 * time.Minute
+
+	pageSize   = 5
+	optionMore = "9"
+	optionBack = "0"
+
+	alertRadiusMeters = 10000
+	maxAlerts         = 3
+	maxLabelLength    = 32
+
+	// Reports made over USSD are recorded alongside SMS reports so both
+	// channels share reference codes. The session id stands in for the
+	// provider message id.
+	messageIDPrefix = "ussd:"
+)
+
+type step string
+
+const (
+	stepMain               step = "main"
+	stepReportType         step = "report_type"
+	stepReportTopic        step = "report_topic"
+	stepReportMunicipality step = "report_municipality"
+	stepAlertsMunicipality step = "alerts_municipality"
+	stepSOSConfirm         step = "sos_confirm"
+)
+
+// session is the menu state kept in the cache between requests.
+type session struct {
+	Step        step   `json:"step"`
+	Page        int    `json:"page,omitempty"`
+	RiskTypeID  string `json:"risk_type_id,omitempty"`
+	RiskTopicID string `json:"risk_topic_id,omitempty"`
+	TopicLabel  string `json:"topic_label,omitempty"`
+}
+
+// turn is what is known about the caller for the current request.
+type turn struct {
+	sessionID string
+	phone     string
+	sender    *model.User
+	language  string
+}
+
+type option struct {
+	id    string
+	label string
+	place *model.Place
+}
+
+// listStep describes a menu that picks one option from a paged list.
+type listStep struct {
+	titleKey string
+	previous step
+	options  func(ctx context.Context, t *turn, s *session) ([]option, error)
+	selected func(ctx context.Context, t *turn, s *session, o option) dto.USSDResponse
+}
+
+// USSDUseCase drives the USSD menu: reporting an incident, listing the
+// latest alerts in a municipality and sending an SOS to emergency contacts.
+// It is gateway-agnostic; the HTTP adapter translates to and from the
+// gateway's wire format.
+type USSDUseCase struct {
+	cache           port.KVCache
+	reportUseCase   *report.ReportUseCase
+	emergencyAlerts *emergencycontact.EmergencyAlertUseCase
+	smsReportRepo   repository.SMSReportRepository
+	alertRepo       repository.AlertRepository
+	riskTypesRepo   repository.RiskTypesRepository
+	riskTopicsRepo  repository.RiskTopicsRepository
+	userRepo        repository.UserRepository
+	localizer       port.Localizer
+}
+
+func NewUSSDUseCase(
+	cache port.KVCache,
+	reportUseCase *report.ReportUseCase,
+	emergencyAlerts *emergencycontact.EmergencyAlertUseCase,
+	smsReportRepo repository.SMSReportRepository,
+	alertRepo repository.AlertRepository,
+	riskTypesRepo repository.RiskTypesRepository,
+	riskTopicsRepo repository.RiskTopicsRepository,
+	userRepo repository.UserRepository,
+	localizer port.Localizer,
+) *USSDUseCase {
+	return &USSDUseCase{
+		cache:           cache,
+		reportUseCase:   reportUseCase,
+		emergencyAlerts: emergencyAlerts,
+		smsReportRepo:   smsReportRepo,
+		alertRepo:       alertRepo,
+		riskTypesRepo:   riskTypesRepo,
+		riskTopicsRepo:  riskTopicsRepo,
+		userRepo:        userRepo,
+		localizer:       localizer,
+	}
+}
+
+// Handle answers one request of a session. A session unknown to the cache,
+// either new or expired, starts at the main menu whatever the input.
+func (uc *USSDUseCase) Handle(ctx context.Context, req dto.USSDRequest) dto.USSDResponse {
+	t := &turn{
+		sessionID: req.SessionID,
+		phone:     normalizePhone(req.Phone),
+	}
+
+	// Unknown numbers may report and look up alerts; SOS needs an account.
+	if sender, err := uc.userRepo.FindByEmailOrPhone(ctx, t.phone); err == nil && sender != nil {
+		t.sender = sender
+		t.language = sender.DeviceLanguage
+	}
+
+	key := sessionKeyPrefix + req.SessionID
+
+	s, ok := uc.load(ctx, key)
+	var resp dto.USSDResponse
+	if ok {
+		resp = uc.advance(ctx, t, s, lastInput(req.Text))
+	} else {
+		s = &session{Step: stepMain}
+		resp = uc.mainMenu(t, "")
+	}
+
+	if resp.End {
+		if err := uc.cache.Delete(ctx, key); err != nil {
+			slog.Warn("failed to delete ussd session", "session_id", req.SessionID, "error", err)
+		}
+		return resp
+	}
+
+	if err := uc.save(ctx, key, s); err != nil {
+		slog.Error("failed to store ussd session", "session_id", req.SessionID, "error", err)
+		return uc.end(t, "ussd_unavailable", nil)
+	}
+
+	return resp
+}
+
+func (uc *USSDUseCase) advance(ctx context.Context, t *turn, s *session, input string) dto.USSDResponse {
+	switch s.Step {
+	case stepMain:
+		return uc.chooseMain(ctx, t, s, input)
+	case stepSOSConfirm:
+		return uc.confirmSOS(ctx, t, s, input)
+	case stepReportType, stepReportTopic, stepReportMunicipality, stepAlertsMunicipality:
+		return uc.chooseFromList(ctx, t, s, input)
+	}
+
+	return uc.enter(ctx, t, s, stepMain)
+}
+
+func (uc *USSDUseCase) chooseMain(ctx context.Context, t *turn, s *session, input string) dto.USSDResponse {
+	switch input {
+	case "1":
+		return uc.enter(ctx, t, s, stepReportType)
+	case "2":
+		place, err := uc.senderMunicipality(ctx, t)
+		if err != nil {
+			slog.Error("failed to resolve ussd caller municipality", "error", err)
+			return uc.end(t, "ussd_unavailable", nil)
+		}
+		if place != nil {
+			return uc.alertsIn(ctx, t, *place)
+		}
+		return uc.enter(ctx, t, s, stepAlertsMunicipality)
+	case "3":
+		if t.sender == nil {
+			return uc.end(t, "ussd_sos_requires_account", nil)
+		}
+		s.Step = stepSOSConfirm
+		return uc.con(uc.text(t, "ussd_sos_confirm", nil))
+	}
+
+	return uc.mainMenu(t, uc.text(t, "ussd_invalid_option", nil))
+}
+
+// enter moves the session to st and renders its first screen.
+func (uc *USSDUseCase) enter(ctx context.Context, t *turn, s *session, st step) dto.USSDResponse {
+	s.Step = st
+	s.Page = 0
+
+	ls, ok := uc.listStep(st)
+	if !ok {
+		s.Step = stepMain
+		return uc.mainMenu(t, "")
+	}
+
+	opts, err := ls.options(ctx, t, s)
+	if err != nil {
+		slog.Error("failed to load ussd menu options", "step", st, "error", err)
+		return uc.end(t, "ussd_unavailable", nil)
+	}
+	if len(opts) == 0 {
+		return uc.end(t, "ussd_unavailable", nil)
+	}
+
+	return uc.con(uc.renderList(t, ls.titleKey, opts, s.Page, ""))
+}
+
+func (uc *USSDUseCase) chooseFromList(ctx context.Context, t *turn, s *session, input string) dto.USSDResponse {
+	ls, _ := uc.listStep(s.Step)
+
+	opts, err := ls.options(ctx, t, s)
+	if err != nil {
+		slog.Error("failed to load ussd menu options", "step", s.Step, "error", err)
+		return uc.end(t, "ussd_unavailable", nil)
+	}
+
+	notice := ""
+	index, action := choose(input, s.Page, len(opts))
+	switch action {
+	case pickSelected:
+		return ls.selected(ctx, t, s, opts[index])
+	case pickMore:
+		s.Page++
+	case pickBack:
+		if s.Page == 0 {
+			return uc.enter(ctx, t, s, ls.previous)
+		}
+		s.Page--
+	case pickInvalid:
+		notice = uc.text(t, "ussd_invalid_option", nil)
+	}
+
+	return uc.con(uc.renderList(t, ls.titleKey, opts, s.Page, notice))
+}
+
+func (uc *USSDUseCase) listStep(st step) (listStep, bool) {
+	switch st {
+	case stepReportType:
+		return listStep{
+			titleKey: "ussd_choose_risk_type",
+			previous: stepMain,
+			options:  uc.riskTypeOptions,
+			selected: func(ctx context.Context, t *turn, s *session, o option) dto.USSDResponse {
+				s.RiskTypeID = o.id
+				return uc.enter(ctx, t, s, stepReportTopic)
+			},
+		}, true
+	case stepReportTopic:
+		return listStep{
+			titleKey: "ussd_choose_topic",
+			previous: stepReportType,
+			options:  uc.topicOptions,
+			selected: func(ctx context.Context, t *turn, s *session, o option) dto.USSDResponse {
+				s.RiskTopicID = o.id
+				s.TopicLabel = o.label
+				return uc.enter(ctx, t, s, stepReportMunicipality)
+			},
+		}, true
+	case stepReportMunicipality:
+		return listStep{
+			titleKey: "ussd_choose_municipality",
+			previous: stepReportTopic,
+			options:  uc.municipalityOptions,
+			selected: func(ctx context.Context, t *turn, s *session, o option) dto.USSDResponse {
+				return uc.submitReport(ctx, t, s, *o.place)
+			},
+		}, true
+	case stepAlertsMunicipality:
+		return listStep{
+			titleKey: "ussd_choose_municipality",
+			previous: stepMain,
+			options:  uc.municipalityOptions,
+			selected: func(ctx context.Context, t *turn, _ *session, o option) dto.USSDResponse {
+				return uc.alertsIn(ctx, t, *o.place)
+			},
+		}, true
+	case stepMain, stepSOSConfirm:
+	}
+
+	return listStep{}, false
+}
+
+// riskTypeOptions lists the enabled risk types that have at least one
+// topic, since a report needs both.
+func (uc *USSDUseCase) riskTypeOptions(ctx context.Context, _ *turn, _ *session) ([]option, error) {
+	riskTypes, err := uc.riskTypesRepo.ListRiskTypes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list risk types: %w", err)
+	}
+
+	topics, err := uc.riskTopicsRepo.ListRiskTopics(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list risk topics: %w", err)
+	}
+
+	hasTopics := make(map[uuid.UUID]bool, len(riskTypes))
+	for _, topic := range topics {
+		hasTopics[topic.RiskTypeID] = true
+	}
+
+	opts := make([]option, 0, len(riskTypes))
+	for _, rt := range riskTypes {
+		if rt.IsEnabled && hasTopics[rt.ID] {
+			opts = append(opts, option{id: rt.ID.String(), label: label(rt.Description, rt.Name)})
+		}
+	}
+	sortOptions(opts)
+
+	return opts, nil
+}
+
+func (uc *USSDUseCase) topicOptions(ctx context.Context, _ *turn, s *session) ([]option, error) {
+	topics, err := uc.riskTopicsRepo.ListRiskTopics(ctx, &s.RiskTypeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list risk topics: %w", err)
+	}
+
+	opts := make([]option, 0, len(topics))
+	for _, topic := range topics {
+		description := ""
+		if topic.Description != nil {
+			description = *topic.Description
+		}
+		opts = append(opts, option{id: topic.ID.String(), label: label(description, topic.Name)})
+	}
+	sortOptions(opts)
+
+	return opts, nil
+}
+
+// municipalityOptions lists the gazetteer's municipalities, those in the
+// caller's province first.
+func (uc *USSDUseCase) municipalityOptions(ctx context.Context, t *turn, _ *session) ([]option, error) {
+	places, err := uc.smsReportRepo.ListPlaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	province := ""
+	if t.sender != nil {
+		province = model.NormalizeText(t.sender.Address.Province)
+	}
+
+	municipalities := make([]model.Place, 0, len(places))
+	for _, p := range places {
+		if p.Kind == model.PlaceKindMunicipality {
+			municipalities = append(municipalities, p)
+		}
+	}
+
+	sort.SliceStable(municipalities, func(i, j int) bool {
+		a, b := municipalities[i], municipalities[j]
+		aHome := model.NormalizeText(a.Province) == province
+		bHome := model.NormalizeText(b.Province) == province
+		if aHome != bHome {
+			return aHome
+		}
+		return a.Name < b.Name
+	})
+
+	opts := make([]option, 0, len(municipalities))
+	for i := range municipalities {
+		p := &municipalities[i]
+		opts = append(opts, option{id: p.Province + "/" + p.Name, label: label(p.Municipality, p.Name), place: p})
+	}
+
+	return opts, nil
+}
+
+// senderMunicipality finds the gazetteer entry for the municipality on the
+// caller's profile, or nil when it is not known.
+func (uc *USSDUseCase) senderMunicipality(ctx context.Context, t *turn) (*model.Place, error) {
+	if t.sender == nil || t.sender.Address.Municipality == "" {
+		return nil, nil //nolint:nilnil // unknown municipality is not an error
+	}
+
+	opts, err := uc.municipalityOptions(ctx, t, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	name := model.NormalizeText(t.sender.Address.Municipality)
+	for _, o := range opts {
+		if o.place.Name == name {
+			return o.place, nil
+		}
+	}
+
+	return nil, nil //nolint:nilnil // unknown municipality is not an error
+}
+
+func (uc *USSDUseCase) submitReport(ctx context.Context, t *turn, s *session, place model.Place) dto.USSDResponse {
+	municipality := label(place.Municipality, place.Name)
+
+	sms := model.NewSMSReport(t.phone, "USSD: "+s.TopicLabel+", "+municipality, messageIDPrefix+t.sessionID)
+
+	input := dto.ReportCreate{
+		UserID:       model.SMSGatewayUserID,
+		RiskTypeID:   s.RiskTypeID,
+		RiskTopicID:  s.RiskTopicID,
+		Description:  s.TopicLabel,
+		Latitude:     place.Latitude,
+		Longitude:    place.Longitude,
+		Province:     place.Province,
+		Municipality: municipality,
+	}
+	if t.sender != nil {
+		sms.UserID = &t.sender.ID
+		input.UserID = t.sender.ID.String()
+	}
+
+	created, err := uc.reportUseCase.Create(ctx, input)
+	if err != nil {
+		slog.Error("failed to create report from ussd", "reference", sms.ReferenceCode, "error", err)
+		sms.Reject(model.SMSRejectFailed)
+	} else {
+		sms.Accept(created.ID)
+	}
+
+	if err := uc.smsReportRepo.Create(ctx, sms); err != nil {
+		slog.Error("failed to store ussd report", "reference", sms.ReferenceCode, "error", err)
+	}
+
+	slog.Info("ussd report processed",
+		"reference", sms.ReferenceCode,
+		"status", sms.Status,
+		"registered_sender", t.sender != nil)
+
+	if sms.Status == model.SMSReportStatusRejected {
+		return uc.end(t, "ussd_report_failed", nil)
+	}
+	return uc.end(t, "ussd_report_received", map[string]any{"reference": sms.ReferenceCode})
+}
+
+func (uc *USSDUseCase) alertsIn(ctx context.Context, t *turn, place model.Place) dto.USSDResponse {
+	municipality := label(place.Municipality, place.Name)
+
+	alerts, err := uc.alertRepo.ListActiveNear(ctx, place.Latitude, place.Longitude, alertRadiusMeters, maxAlerts)
+	if err != nil {
+		slog.Error("failed to list alerts for ussd", "municipality", municipality, "error", err)
+		return uc.end(t, "ussd_unavailable", nil)
+	}
+
+	params := map[string]any{"municipality": municipality}
+	if len(alerts) == 0 {
+		return uc.end(t, "ussd_alerts_none", params)
+	}
+
+	lines := []string{uc.text(t, "ussd_alerts_header", params)}
+	for _, a := range alerts {
+		what := label(a.RiskTopicName, a.RiskTypeName)
+		where := a.Neighborhood
+		if where == "" {
+			where = a.Municipality
+		}
+		line := "- " + what
+		if where != "" {
+			line += ", " + where
+		}
+		lines = append(lines, truncate(line, maxLabelLength+len("- ")))
+	}
+
+	return dto.USSDResponse{Message: strings.Join(lines, "\n"), End: true}
+}
+
+func (uc *USSDUseCase) confirmSOS(ctx context.Context, t *turn, s *session, input string) dto.USSDResponse {
+	switch input {
+	case "1":
+		return uc.sendSOS(ctx, t)
+	case optionBack:
+		return uc.enter(ctx, t, s, stepMain)
+	}
+
+	return uc.con(uc.text(t, "ussd_invalid_option", nil) + "\n" + uc.text(t, "ussd_sos_confirm", nil))
+}
+
+func (uc *USSDUseCase) sendSOS(ctx context.Context, t *turn) dto.USSDResponse {
+	input := dto.EmergencyAlertInput{
+		Latitude:  t.sender.Latitude,
+		Longitude: t.sender.Longitude,
+	}
+	if input.Latitude == 0 && input.Longitude == 0 && t.sender.HomeAddress != nil {
+		input.Latitude = t.sender.HomeAddress.Latitude
+		input.Longitude = t.sender.HomeAddress.Longitude
+	}
+	if input.Latitude == 0 && input.Longitude == 0 {
+		if place, err := uc.senderMunicipality(ctx, t); err == nil && place != nil {
+			input.Latitude = place.Latitude
+			input.Longitude = place.Longitude
+		}
+	}
+
+	result, err := uc.emergencyAlerts.SendEmergencyAlertToAll(ctx, t.sender.ID, input)
+	if errors.Is(err, domainErrors.ErrNoEmergencyContacts) {
+		return uc.end(t, "ussd_sos_no_contacts", nil)
+	}
+	if err != nil {
+		slog.Error("failed to send sos from ussd", "user_id", t.sender.ID, "error", err)
+		return uc.end(t, "ussd_sos_failed", nil)
+	}
+
+	return uc.end(t, "ussd_sos_sent", map[string]any{"count": result.ContactsNotified})
+}
+
+func (uc *USSDUseCase) mainMenu(t *turn, notice string) dto.USSDResponse {
+	menu := uc.text(t, "ussd_main_menu", nil)
+	if notice != "" {
+		menu = notice + "\n" + menu
+	}
+	return uc.con(menu)
+}
+
+func (uc *USSDUseCase) renderList(t *turn, titleKey string, opts []option, page int, notice string) string {
+	lines := make([]string, 0, pageSize+4)
+	if notice != "" {
+		lines = append(lines, notice)
+	}
+	lines = append(lines, uc.text(t, titleKey, nil))
+
+	start := page * pageSize
+	end := min(start+pageSize, len(opts))
+	for i := start; i < end; i++ {
+		lines = append(lines, strconv.Itoa(i-start+1)+". "+opts[i].label)
+	}
+
+	if end < len(opts) {
+		lines = append(lines, optionMore+". "+uc.text(t, "ussd_more", nil))
+	}
+	lines = append(lines, optionBack+". "+uc.text(t, "ussd_back", nil))
+
+	return strings.Join(lines, "\n")
+}
+
+func (uc *USSDUseCase) text(t *turn, key string, params map[string]any) string {
+	return uc.localizer.LocalizeFor(t.language, key, params)
+}
+
+func (uc *USSDUseCase) con(message string) dto.USSDResponse {
+	return dto.USSDResponse{Message: message}
+}
+
+func (uc *USSDUseCase) end(t *turn, key string, params map[string]any) dto.USSDResponse {
+	return dto.USSDResponse{Message: uc.text(t, key, params), End: true}
+}
+
+func (uc *USSDUseCase) load(ctx context.Context, key string) (*session, bool) {
+	raw, err := uc.cache.Get(ctx, key)
+	if err != nil || raw == "" {
+		return nil, false
+	}
+
+	var s session
+	if err := json.Unmarshal([]byte(raw), &s); err != nil {
+		slog.Warn("discarding unreadable ussd session", "key", key, "error", err)
+		return nil, false
+	}
+
+	return &s, true
+}
+
+func (uc *USSDUseCase) save(ctx context.Context, key string, s *session) error {
+	raw, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to encode ussd session: %w", err)
+	}
+	return uc.cache.Set(ctx, key, string(raw), sessionTTL)
+}
+
+type pick int
+
+const (
+	pickInvalid pick = iota
+	pickSelected
+	pickMore
+	pickBack
+)
+
+// choose interprets input on a page of a list with total options. Options
+// are numbered from 1 on every page.
+func choose(input string, page, total int) (int, pick) {
+	switch input {
+	case optionBack:
+		return 0, pickBack
+	case optionMore:
+		if (page+1)*pageSize < total {
+			return 0, pickMore
+		}
+		return 0, pickInvalid
+	}
+
+	n, err := strconv.Atoi(input)
+	if err != nil || n < 1 || n > pageSize {
+		return 0, pickInvalid
+	}
+
+	index := page*pageSize + n - 1
+	if index >= total {
+		return 0, pickInvalid
+	}
+
+	return index, pickSelected
+}
+
+// lastInput returns the caller's latest entry. Gateways that send the whole
+// path ("1*2*3") and those that send only the last entry are both handled.
+func lastInput(text string) string {
+	if i := strings.LastIndex(text, "*"); i >= 0 {
+		text = text[i+1:]
+	}
+	return strings.TrimSpace(text)
+}
+
+// normalizePhone turns the "244923000000" form some gateways send into the
+// E.164 form stored on accounts.
+func normalizePhone(phone string) string {
+	phone = strings.TrimSpace(phone)
+	if phone == "" || strings.HasPrefix(phone, "+") {
+		return phone
+	}
+	if _, err := strconv.ParseUint(phone, 10, 64); err == nil {
+		return "+" + phone
+	}
+	return phone
+}
+
+// label prefers a human description over a machine name such as
+// "roubo_veiculo", and keeps it short enough for a USSD screen.
+func label(description, name string) string {
+	text := strings.TrimSpace(description)
+	if text == "" {
+		text = strings.ReplaceAll(name, "_", " ")
+		if text != "" {
+			text = strings.ToUpper(text[:1]) + text[1:]
+		}
+	}
+	return truncate(text, maxLabelLength)
+}
+
+func truncate(s string, maxRunes int) string {
+	runes := []rune(s)
+	if len(runes) <= maxRunes {
+		return s
+	}
+	return string(runes[:maxRunes-1]) + "…"
+}
+
+func sortOptions(opts []option) {
+	sort.Slice(opts, func(i, j int) bool { return opts[i].label < opts[j].label })
+}
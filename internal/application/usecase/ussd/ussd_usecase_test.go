@@ -0,0 +1,508 @@
+package ussd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/risk-place-angola/backend-risk-place/internal/application/dto"
+	"github.com/risk-place-angola/backend-risk-place/internal/application/port"
+	"github.com/risk-place-angola/backend-risk-place/internal/application/usecase/emergencycontact"
+	"github.com/risk-place-angola/backend-risk-place/internal/application/usecase/report"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/event"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type memCache struct {
+	values map[string]string
+	ttls   map[string]time.Duration
+}
+
+func (c *memCache) Get(_ context.Context, key string) (string, error) {
+	value, ok := c.values[key]
+	if !ok {
+		return "", errors.New("cache miss")
+	}
+	return value, nil
+}
+
+func (c *memCache) Set(_ context.Context, key, value string, ttl time.Duration) error {
+	c.values[key] = value
+	c.ttls[key] = ttl
+	return nil
+}
+
+func (c *memCache) Delete(_ context.Context, key string) error {
+	delete(c.values, key)
+	return nil
+}
+
+// keyLocalizer renders a message as its catalog key followed by its
+// parameters, so screens can be checked without the catalogs.
+type keyLocalizer struct{}
+
+func (keyLocalizer) Localize(_ context.Context, key string, params map[string]any) string {
+	return keyLocalizer{}.LocalizeFor("", key, params)
+}
+
+func (keyLocalizer) LocalizeFor(_, key string, params map[string]any) string {
+	if len(params) == 0 {
+		return key
+	}
+	return key + " " + fmt.Sprint(params)
+}
+
+type ussdUsers struct {
+	repository.UserRepository
+	byPhone map[string]*model.User
+}
+
+func (r *ussdUsers) FindByEmailOrPhone(_ context.Context, identifier string) (*model.User, error) {
+	user, ok := r.byPhone[identifier]
+	if !ok {
+		return nil, errors.New("user not found")
+	}
+	return user, nil
+}
+
+func (r *ussdUsers) FindByID(_ context.Context, id uuid.UUID) (*model.User, error) {
+	for _, user := range r.byPhone {
+		if user.ID == id {
+			return user, nil
+		}
+	}
+	return nil, errors.New("user not found")
+}
+
+type ussdRiskTypes struct {
+	repository.RiskTypesRepository
+	types []model.RiskType
+}
+
+func (r *ussdRiskTypes) ListRiskTypes(context.Context) ([]model.RiskType, error) {
+	return r.types, nil
+}
+
+func (r *ussdRiskTypes) GetRiskTypeByID(_ context.Context, id string) (model.RiskType, error) {
+	for _, rt := range r.types {
+		if rt.ID.String() == id {
+			return rt, nil
+		}
+	}
+	return model.RiskType{}, errors.New("risk type not found")
+}
+
+type ussdRiskTopics struct {
+	repository.RiskTopicsRepository
+	topics []model.RiskTopic
+}
+
+func (r *ussdRiskTopics) ListRiskTopics(_ context.Context, riskTypeID *string) ([]model.RiskTopic, error) {
+	if riskTypeID == nil {
+		return r.topics, nil
+	}
+	var topics []model.RiskTopic
+	for _, topic := range r.topics {
+		if topic.RiskTypeID.String() == *riskTypeID {
+			topics = append(topics, topic)
+		}
+	}
+	return topics, nil
+}
+
+func (r *ussdRiskTopics) GetRiskTopicByID(_ context.Context, id string) (model.RiskTopic, error) {
+	for _, topic := range r.topics {
+		if topic.ID.String() == id {
+			return topic, nil
+		}
+	}
+	return model.RiskTopic{}, errors.New("risk topic not found")
+}
+
+type ussdSMSReports struct {
+	repository.SMSReportRepository
+	places  []model.Place
+	created []*model.SMSReport
+}
+
+func (r *ussdSMSReports) ListPlaces(context.Context) ([]model.Place, error) {
+	return r.places, nil
+}
+
+func (r *ussdSMSReports) Create(_ context.Context, sms *model.SMSReport) error {
+	r.created = append(r.created, sms)
+	return nil
+}
+
+type ussdAlerts struct {
+	repository.AlertRepository
+	alerts []*model.Alert
+}
+
+func (r *ussdAlerts) ListActiveNear(context.Context, float64, float64, float64, int) ([]*model.Alert, error) {
+	return r.alerts, nil
+}
+
+type ussdReports struct {
+	repository.ReportRepository
+	created []*model.Report
+}
+
+func (r *ussdReports) Create(_ context.Context, created *model.Report) error {
+	r.created = append(r.created, created)
+	return nil
+}
+
+type ussdContacts struct {
+	repository.EmergencyContactRepository
+	byUser map[uuid.UUID][]*model.EmergencyContact
+}
+
+func (r *ussdContacts) FindByUserID(_ context.Context, userID uuid.UUID) ([]*model.EmergencyContact, error) {
+	return r.byUser[userID], nil
+}
+
+type ussdSMS struct {
+	sent []string
+}
+
+func (s *ussdSMS) NotifySMS(_ context.Context, phone string, _ string) error {
+	s.sent = append(s.sent, phone)
+	return nil
+}
+
+type ussdOutbox struct {
+	port.EventOutbox
+}
+
+func (ussdOutbox) Publish(context.Context, event.Event) error { return nil }
+
+type ussdTx struct{}
+
+func (ussdTx) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type ussdGeo struct {
+	port.GeolocationService
+}
+
+func (ussdGeo) ValidateCoordinates(float64, float64) error { return nil }
+
+type ussdLocations struct {
+	port.LocationStore
+}
+
+func (ussdLocations) FindUsersInRadiusWithDistance(context.Context, float64, float64, float64) ([]port.GeoResult, error) {
+	return nil, nil
+}
+
+type ussdSettings struct {
+	repository.SafetySettingsRepository
+}
+
+func (ussdSettings) GetByUserID(context.Context, uuid.UUID) (*model.SafetySettings, error) {
+	return nil, errors.New("settings not found")
+}
+
+type ussdEnv struct {
+	uc         *USSDUseCase
+	cache      *memCache
+	smsReports *ussdSMSReports
+	reports    *ussdReports
+	sms        *ussdSMS
+}
+
+const (
+	registeredPhone = "+244923000001"
+	// unknownPhone is in the form some gateways send, without the "+".
+	unknownPhone = "244923000002"
+)
+
+func newUSSDEnv() *ussdEnv {
+	crime := model.RiskType{ID: uuid.New(), Name: "crime", Description: "Crime", DefaultRadiusMeters: 500, IsEnabled: true}
+	fire := model.RiskType{ID: uuid.New(), Name: "fire", Description: "Incêndio", IsEnabled: true}
+	retired := model.RiskType{ID: uuid.New(), Name: "retired", Description: "Antigo"}
+	robbery := "Assalto"
+	topics := &ussdRiskTopics{topics: []model.RiskTopic{
+		{ID: uuid.New(), RiskTypeID: crime.ID, Name: "assalto", Description: &robbery},
+		{ID: uuid.New(), RiskTypeID: retired.ID, Name: "antigo"},
+	}}
+
+	places := []model.Place{{Name: "luanda", Kind: model.PlaceKindProvince, Province: "Luanda"}}
+	for i, name := range []string{"Viana", "Talatona", "Belas", "Cacuaco", "Cazenga", "Kilamba Kiaxi", "Icolo e Bengo"} {
+		places = append(places, model.Place{
+			Name:         model.NormalizeText(name),
+			Kind:         model.PlaceKindMunicipality,
+			Province:     "Luanda",
+			Municipality: name,
+			Latitude:     -8.8 - float64(i)/100,
+			Longitude:    13.2 + float64(i)/100,
+		})
+	}
+
+	sender := &model.User{
+		ID:        uuid.New(),
+		Name:      "Ana",
+		Phone:     registeredPhone,
+		Latitude:  -8.9,
+		Longitude: 13.3,
+		Address:   model.Address{Province: "Luanda", Municipality: "Viana"},
+	}
+	users := &ussdUsers{byPhone: map[string]*model.User{registeredPhone: sender}}
+	contacts := &ussdContacts{byUser: map[uuid.UUID][]*model.EmergencyContact{sender.ID: {
+		{ID: uuid.New(), UserID: sender.ID, Name: "Mãe", Phone: "+244923000010"},
+		{ID: uuid.New(), UserID: sender.ID, Name: "Irmão", Phone: "+244923000011"},
+	}}}
+
+	env := &ussdEnv{
+		cache:      &memCache{values: map[string]string{}, ttls: map[string]time.Duration{}},
+		smsReports: &ussdSMSReports{places: places},
+		reports:    &ussdReports{},
+		sms:        &ussdSMS{},
+	}
+	riskTypes := &ussdRiskTypes{types: []model.RiskType{crime, fire, retired}}
+	reportUseCase := report.NewReportUseCase(env.reports, ussdOutbox{}, ussdGeo{}, riskTypes, topics, ussdSettings{}, ussdLocations{}, ussdTx{})
+	emergencyAlerts := emergencycontact.NewEmergencyAlertUseCase(contacts, users, env.sms, keyLocalizer{})
+	alerts := &ussdAlerts{alerts: []*model.Alert{{RiskTypeName: "Crime", RiskTopicName: "Assalto", Neighborhood: "Zango"}}}
+
+	env.uc = NewUSSDUseCase(env.cache, reportUseCase, emergencyAlerts, env.smsReports, alerts, riskTypes, topics, users, keyLocalizer{})
+	return env
+}
+
+// ussdStep is one request of a session and what its screen must show.
+type ussdStep struct {
+	text    string
+	want    []string
+	notWant []string
+	end     bool
+}
+
+func TestUSSDUseCase_Handle(t *testing.T) {
+	tests := []struct {
+		name  string
+		phone string
+		steps []ussdStep
+		check func(t *testing.T, env *ussdEnv)
+	}{
+		{
+			name:  "new session opens the main menu",
+			phone: unknownPhone,
+			steps: []ussdStep{{text: "", want: []string{"ussd_main_menu"}}},
+		},
+		{
+			name:  "a new session ignores stale input",
+			phone: unknownPhone,
+			steps: []ussdStep{{text: "1*1*3", want: []string{"ussd_main_menu"}, notWant: []string{"ussd_invalid_option"}}},
+		},
+		{
+			name:  "invalid main option",
+			phone: unknownPhone,
+			steps: []ussdStep{
+				{text: ""},
+				{text: "7", want: []string{"ussd_invalid_option", "ussd_main_menu"}},
+			},
+		},
+		{
+			name:  "report from an unknown number",
+			phone: unknownPhone,
+			steps: []ussdStep{
+				{text: ""},
+				// Only enabled risk types with topics are offered.
+				{text: "1", want: []string{"ussd_choose_risk_type", "1. Crime", "0. ussd_back"}, notWant: []string{"Incêndio", "Antigo", "2."}},
+				{text: "1*1", want: []string{"ussd_choose_topic", "1. Assalto"}},
+				{text: "1*1*1", want: []string{"ussd_choose_municipality", "1. Belas", "5. Kilamba Kiaxi", "9. ussd_more"}},
+				{text: "1*1*1*2", want: []string{"ussd_report_received"}, end: true},
+			},
+			check: func(t *testing.T, env *ussdEnv) {
+				assert.Len(t, env.reports.created, 1)
+				assert.Len(t, env.smsReports.created, 1)
+				if len(env.smsReports.created) == 1 {
+					sms := env.smsReports.created[0]
+					assert.Equal(t, model.SMSReportStatusCreated, sms.Status)
+					assert.Equal(t, "+"+unknownPhone, sms.Phone)
+					assert.Nil(t, sms.UserID)
+					assert.Equal(t, "USSD: Assalto, Cacuaco", sms.Body)
+				}
+				if len(env.reports.created) == 1 {
+					assert.Equal(t, model.SMSGatewayUserID, env.reports.created[0].UserID.String())
+				}
+			},
+		},
+		{
+			name:  "report from a registered number is theirs",
+			phone: registeredPhone,
+			steps: []ussdStep{
+				{text: ""},
+				{text: "1"},
+				{text: "1"},
+				// The caller's province comes first; all are in Luanda here.
+				{text: "1", want: []string{"1. Belas"}},
+				{text: "1", want: []string{"ussd_report_received"}, end: true},
+			},
+			check: func(t *testing.T, env *ussdEnv) {
+				if assert.Len(t, env.smsReports.created, 1) {
+					assert.NotNil(t, env.smsReports.created[0].UserID)
+				}
+			},
+		},
+		{
+			name:  "back from the first list page returns to the previous menu",
+			phone: unknownPhone,
+			steps: []ussdStep{
+				{text: ""},
+				{text: "1"},
+				{text: "1", want: []string{"ussd_choose_topic"}},
+				{text: "0", want: []string{"ussd_choose_risk_type"}},
+				{text: "0", want: []string{"ussd_main_menu"}},
+			},
+		},
+		{
+			name:  "paging through municipalities",
+			phone: unknownPhone,
+			steps: []ussdStep{
+				{text: ""},
+				{text: "2", want: []string{"ussd_choose_municipality", "1. Belas", "5. Kilamba Kiaxi", "9. ussd_more"}, notWant: []string{"Talatona"}},
+				{text: "9", want: []string{"1. Talatona", "2. Viana", "0. ussd_back"}, notWant: []string{"Belas", "9. ussd_more"}},
+				// Past the end of the list, and no further page.
+				{text: "3", want: []string{"ussd_invalid_option", "1. Talatona"}},
+				{text: "9", want: []string{"ussd_invalid_option", "1. Talatona"}},
+				{text: "0", want: []string{"1. Belas"}},
+				{text: "abc", want: []string{"ussd_invalid_option", "1. Belas"}},
+				{text: "2", want: []string{"ussd_alerts_header map[municipality:Cacuaco]", "- Assalto, Zango"}, end: true},
+			},
+		},
+		{
+			name:  "alerts skip the list when the caller's municipality is known",
+			phone: registeredPhone,
+			steps: []ussdStep{
+				{text: ""},
+				{text: "2", want: []string{"ussd_alerts_header map[municipality:Viana]", "- Assalto, Zango"}, end: true},
+			},
+		},
+		{
+			name:  "sos needs an account",
+			phone: unknownPhone,
+			steps: []ussdStep{
+				{text: ""},
+				{text: "3", want: []string{"ussd_sos_requires_account"}, end: true},
+			},
+			check: func(t *testing.T, env *ussdEnv) {
+				assert.Empty(t, env.sms.sent)
+			},
+		},
+		{
+			name:  "sos from a registered number",
+			phone: registeredPhone,
+			steps: []ussdStep{
+				{text: ""},
+				{text: "3", want: []string{"ussd_sos_confirm"}},
+				{text: "3*5", want: []string{"ussd_invalid_option", "ussd_sos_confirm"}},
+				{text: "3*5*1", want: []string{"ussd_sos_sent map[count:2]"}, end: true},
+			},
+			check: func(t *testing.T, env *ussdEnv) {
+				assert.ElementsMatch(t, []string{"+244923000010", "+244923000011"}, env.sms.sent)
+			},
+		},
+		{
+			name:  "sos can be cancelled",
+			phone: registeredPhone,
+			steps: []ussdStep{
+				{text: ""},
+				{text: "3"},
+				{text: "0", want: []string{"ussd_main_menu"}},
+			},
+			check: func(t *testing.T, env *ussdEnv) {
+				assert.Empty(t, env.sms.sent)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newUSSDEnv()
+			sessionID := uuid.NewString()
+			key := sessionKeyPrefix + sessionID
+
+			for i, step := range tt.steps {
+				resp := env.uc.Handle(context.Background(), dto.USSDRequest{SessionID: sessionID, Phone: tt.phone, Text: step.text})
+
+				assert.Equal(t, step.end, resp.End, "step %d: %q", i, resp.Message)
+				for _, want := range step.want {
+					assert.Contains(t, resp.Message, want, "step %d", i)
+				}
+				for _, notWant := range step.notWant {
+					assert.NotContains(t, resp.Message, notWant, "step %d", i)
+				}
+
+				_, stored := env.cache.values[key]
+				assert.Equal(t, !step.end, stored, "step %d: a session is kept until it ends", i)
+				if stored {
+					assert.Equal(t, sessionTTL, env.cache.ttls[key])
+				}
+			}
+
+			if tt.check != nil {
+				tt.check(t, env)
+			}
+		})
+	}
+}
+
+func TestUSSDUseCase_ExpiredSessionRestarts(t *testing.T) {
+	env := newUSSDEnv()
+	sessionID := uuid.NewString()
+	req := dto.USSDRequest{SessionID: sessionID, Phone: unknownPhone}
+
+	env.uc.Handle(context.Background(), req)
+	req.Text = "2"
+	assert.Contains(t, env.uc.Handle(context.Background(), req).Message, "ussd_choose_municipality")
+
+	// The carrier keeps the session, but its state expired from the cache.
+	assert.NoError(t, env.cache.Delete(context.Background(), sessionKeyPrefix+sessionID))
+	req.Text = "2*1"
+	resp := env.uc.Handle(context.Background(), req)
+
+	assert.False(t, resp.End)
+	assert.Contains(t, resp.Message, "ussd_main_menu")
+	assert.Empty(t, env.smsReports.created)
+}
+
+func TestChoose(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		page      int
+		total     int
+		wantIndex int
+		wantPick  pick
+	}{
+		{name: "first option", input: "1", total: 3, wantIndex: 0, wantPick: pickSelected},
+		{name: "option on a later page", input: "2", page: 1, total: 7, wantIndex: 6, wantPick: pickSelected},
+		{name: "past the end of the list", input: "3", page: 1, total: 7, wantPick: pickInvalid},
+		{name: "past the page size", input: "6", total: 10, wantPick: pickInvalid},
+		{name: "zero is back", input: "0", page: 1, total: 7, wantPick: pickBack},
+		{name: "more with a next page", input: "9", total: 6, wantPick: pickMore},
+		{name: "more on the last page", input: "9", page: 1, total: 7, wantPick: pickInvalid},
+		{name: "not a number", input: "x", total: 3, wantPick: pickInvalid},
+		{name: "empty", input: "", total: 3, wantPick: pickInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			index, action := choose(tt.input, tt.page, tt.total)
+			assert.Equal(t, tt.wantPick, action)
+			assert.Equal(t, tt.wantIndex, index)
+		})
+	}
+}
+
+func TestLastInput(t *testing.T) {
+	assert.Equal(t, "", lastInput(""))
+	assert.Equal(t, "3", lastInput("3"))
+	assert.Equal(t, "3", lastInput("1*2* 3 "))
+	assert.Equal(t, "", lastInput("1*2*"))
+}
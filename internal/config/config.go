@@ -12,6 +12,9 @@ import (
 	"github.com/spf13/viper"
 )
 
+// defaultSafeRouteMaxKm keeps safe routes to trips within a province.
+const defaultSafeRouteMaxKm = 150
+
 type Config struct {
 	AppEnv string
 	Port   string
@@ -31,6 +34,24 @@ type Config struct {
 	TwilioConfig   *TwilioConfig
 	AWSConfig      *AWSConfig
 	FrontendURL    string
+	// APIPublicURL is the externally reachable base URL of this API, used to
+	// build links such as email unsubscribe URLs.
+	APIPublicURL string
+	// USSDGatewayToken is the shared secret the USSD gateway sends with every
+	// callback. USSD requests are rejected while it is empty.
+	USSDGatewayToken string
+
+	// TranslationsDir optionally points at locale catalogs that override or
+	// extend the embedded ones.
+	TranslationsDir string
+
+	// OSMExtractPath points at an OpenStreetMap XML extract (.osm, .osm.gz
+	// or .osm.bz2) used to route safe routes along roads. Without it routes
+	// follow the straight line.
+	OSMExtractPath string
+	// SafeRouteMaxKm is the longest straight-line distance between origin
+	// and destination a safe route may be asked for.
+	SafeRouteMaxKm float64
 }
 
 type TwilioConfig struct {
@@ -64,6 +85,9 @@ type EmailConfig struct {
 	From string
 	Ssl  bool
 	Tls  bool
+	// UnsubscribeSecret signs the one-click unsubscribe links in alert
+	// emails. Falls back to JWT_SECRET when unset.
+	UnsubscribeSecret string
 }
 
 type RedisConfig struct {
@@ -103,6 +127,8 @@ func NewEmailConfig() *EmailConfig {
 		From: viper.GetString("EMAIL_FROM"),
 		Ssl:  viper.GetBool("EMAIL_SSL"),
 		Tls:  viper.GetBool("EMAIL_TLS"),
+
+		UnsubscribeSecret: viper.GetString("EMAIL_UNSUBSCRIBE_SECRET"),
 	}
 }
 
@@ -168,6 +194,14 @@ func Load() Config {
 		viper.Set("FRONTEND_URL", "http://localhost:3000")
 	}
 
+	if !viper.IsSet("SAFE_ROUTE_MAX_KM") {
+		viper.Set("SAFE_ROUTE_MAX_KM", defaultSafeRouteMaxKm)
+	}
+
+	if !viper.IsSet("API_PUBLIC_URL") {
+		viper.Set("API_PUBLIC_URL", "http://localhost:8000")
+	}
+
 	cfg := Config{
 		AppEnv:         viper.GetString("APP_ENV"),
 		Port:           viper.GetString("PORT"),
@@ -178,7 +212,12 @@ func Load() Config {
 		TwilioConfig:   NewTwilioConfig(),
 		AWSConfig:      NewAWSConfig(),
 
-		FrontendURL: viper.GetString("FRONTEND_URL"),
+		FrontendURL:      viper.GetString("FRONTEND_URL"),
+		APIPublicURL:     viper.GetString("API_PUBLIC_URL"),
+		USSDGatewayToken: viper.GetString("USSD_GATEWAY_TOKEN"),
+		TranslationsDir:  viper.GetString("TRANSLATIONS_DIR"),
+		OSMExtractPath:   viper.GetString("OSM_EXTRACT_PATH"),
+		SafeRouteMaxKm:   viper.GetFloat64("SAFE_ROUTE_MAX_KM"),
 
 		JWTSecret:    viper.GetString("JWT_SECRET"),
 		JWTIssuer:    viper.GetString("JWT_ISSUER"),
@@ -187,6 +226,10 @@ func Load() Config {
 		Timeout:      viper.GetDuration("TIMEOUT"),
 	}
 
+	if cfg.EmailConfig.UnsubscribeSecret == "" {
+		cfg.EmailConfig.UnsubscribeSecret = cfg.JWTSecret
+	}
+
 	validateConfig(cfg)
 
 	return cfg
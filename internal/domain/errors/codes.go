@@ -0,0 +1,88 @@
+package errors
+
+import "errors"
+
+// Code is a stable, machine readable identifier for an error. Clients switch
+// on it and it keys the localized message catalogs, so existing codes must
+// never be renamed.
+type Code string
+
+const (
+	CodeBadRequest         Code = "BAD_REQUEST"
+	CodeInvalidRequestBody Code = "INVALID_REQUEST_BODY"
+	CodeInvalidID          Code = "INVALID_ID"
+	CodeIDRequired         Code = "ID_REQUIRED"
+	CodeInvalidUserID      Code = "INVALID_USER_ID"
+	CodeLocationRequired   Code = "LOCATION_REQUIRED"
+	CodeInvalidCoordinates Code = "INVALID_COORDINATES"
+	CodeIdentifierRequired Code = "IDENTIFIER_REQUIRED"
+	CodeUnauthorized       Code = "UNAUTHORIZED"
+	CodeForbidden          Code = "FORBIDDEN"
+	CodeNotFound           Code = "NOT_FOUND"
+	CodeSessionNotFound    Code = "SESSION_NOT_FOUND"
+	CodeMethodNotAllowed   Code = "METHOD_NOT_ALLOWED"
+	CodeConflict           Code = "CONFLICT"
+	CodeGone               Code = "GONE"
+	CodeTooManyRequests    Code = "TOO_MANY_REQUESTS"
+	CodeInternal           Code = "INTERNAL_ERROR"
+	CodeUnavailable        Code = "SERVICE_UNAVAILABLE"
+
+	CodeInvalidCredentials       Code = "INVALID_CREDENTIALS"
+	CodeEmailAlreadyExists       Code = "EMAIL_ALREADY_EXISTS"
+	CodeUserNotFound             Code = "USER_NOT_FOUND"
+	CodeUserAccountNotExists     Code = "USER_ACCOUNT_NOT_EXISTS"
+	CodeInvalidCode              Code = "INVALID_CODE"
+	CodeExpiredCode              Code = "EXPIRED_CODE"
+	CodeAccountNotConfirmed      Code = "ACCOUNT_NOT_CONFIRMED"
+	CodeAccountNotVerified       Code = "ACCOUNT_NOT_VERIFIED"
+	CodePersonNotFound           Code = "PERSON_NOT_FOUND"
+	CodePersonAlreadyExists      Code = "PERSON_ALREADY_EXISTS"
+	CodeInvalidSearchQuery       Code = "INVALID_SEARCH_QUERY"
+	CodeAlreadyVerified          Code = "ALREADY_VERIFIED"
+	CodeRateLimited              Code = "RATE_LIMITED"
+	CodeInvalidCurrentPassword   Code = "INVALID_CURRENT_PASSWORD"
+	CodeNoRolesAssigned          Code = "NO_ROLES_ASSIGNED"
+	CodeAlertNotFound            Code = "ALERT_NOT_FOUND"
+	CodeVerificationLocked       Code = "VERIFICATION_LOCKED"
+	CodeVerificationCooldown     Code = "VERIFICATION_COOLDOWN"
+	CodeSentViaEmail             Code = "SENT_VIA_EMAIL"
+	CodeInvalidDigestFrequency   Code = "INVALID_DIGEST_FREQUENCY"
+	CodeDigestChannelRequired    Code = "DIGEST_CHANNEL_REQUIRED"
+	CodeHomeAddressNotConfigured Code = "HOME_ADDRESS_NOT_CONFIGURED"
+	CodeWorkAddressNotConfigured Code = "WORK_ADDRESS_NOT_CONFIGURED"
+	CodeNoEmergencyContacts      Code = "NO_EMERGENCY_CONTACTS"
+	CodeEmergencyAlertNotSent    Code = "EMERGENCY_ALERT_NOT_SENT"
+	CodeLocationSharingExpired   Code = "LOCATION_SHARING_EXPIRED"
+	CodeLocationSharingNotFound  Code = "LOCATION_SHARING_NOT_FOUND"
+	CodeInvalidUnsubscribeToken  Code = "INVALID_UNSUBSCRIBE_TOKEN"
+	CodeEmailRequiresAccount     Code = "EMAIL_REQUIRES_ACCOUNT"
+	CodeDangerZoneNotFound       Code = "DANGER_ZONE_NOT_FOUND"
+	CodeRouteNotFound            Code = "ROUTE_NOT_FOUND"
+	CodeWeightRuleNotFound       Code = "WEIGHT_RULE_NOT_FOUND"
+	CodeTripNotFound             Code = "TRIP_NOT_FOUND"
+	CodeTripAlreadyActive        Code = "TRIP_ALREADY_ACTIVE"
+)
+
+// CodedError is a domain error with a stable code. Message is the English
+// text used in logs and as the last resort when no translation exists.
+type CodedError struct {
+	Code    Code
+	Message string
+}
+
+func (e *CodedError) Error() string {
+	return e.Message
+}
+
+func New(code Code, message string) error {
+	return &CodedError{Code: code, Message: message}
+}
+
+// CodeOf returns the code of the first CodedError in err's chain.
+func CodeOf(err error) (Code, bool) {
+	var coded *CodedError
+	if errors.As(err, &coded) {
+		return coded.Code, true
+	}
+	return "", false
+}
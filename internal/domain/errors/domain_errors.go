@@ -1,25 +1,58 @@
 package errors
 
-import "errors"
-
 var (
-	ErrInvalidCredentials       = errors.New("invalid email or password")
-	ErrEmailAlreadyExists       = errors.New("email already registered")
-	ErrUserNotFound             = errors.New("user not found")
-	ErrUserAccountNotExists     = errors.New("user account does not exist")
-	ErrInvalidCode              = errors.New("invalid or unverified code")
-	ErrExpiredCode              = errors.New("code has expired")
-	ErrAccountNotConfirmed      = errors.New("account not confirmed, please check your phone")
-	ErrAccountNotVerified       = errors.New("account not verified, please verify your account")
-	ErrPersonNotFound           = errors.New("person information not found for the user")
-	ErrPersonAlreadyExists      = errors.New("person information already exists for the user")
-	ErrInvalidSearchQuery       = errors.New("search query is empty or too short")
-	ErrAlreadyVerified          = errors.New("email already verified, no action needed")
-	ErrRateLimited              = errors.New("rate limit exceeded, please try again later")
-	ErrInvalidCurrentPassword   = errors.New("current password is incorrect")
-	ErrNoRolesAssigned          = errors.New("no roles assigned to the user")
-	ErrAlertNotFound            = errors.New("alert not found")
-	ErrVerificationLocked       = errors.New("too many incorrect attempts")
-	ErrVerificationCooldown     = errors.New("wait before resending")
-	ErrSentViaEmail             = errors.New("sent via email")
+	ErrInvalidCredentials       = New(CodeInvalidCredentials, "invalid email or password")
+	ErrEmailAlreadyExists       = New(CodeEmailAlreadyExists, "email already registered")
+	ErrUserNotFound             = New(CodeUserNotFound, "user not found")
+	ErrUserAccountNotExists     = New(CodeUserAccountNotExists, "user account does not exist")
+	ErrInvalidCode              = New(CodeInvalidCode, "invalid or unverified code")
+	ErrExpiredCode              = New(CodeExpiredCode, "code has expired")
+	ErrAccountNotConfirmed      = New(CodeAccountNotConfirmed, "account not confirmed, please check your phone")
+	ErrAccountNotVerified       = New(CodeAccountNotVerified, "account not verified, please verify your account")
+	ErrPersonNotFound           = New(CodePersonNotFound, "person information not found for the user")
+	ErrPersonAlreadyExists      = New(CodePersonAlreadyExists, "person information already exists for the user")
+	ErrInvalidSearchQuery       = New(CodeInvalidSearchQuery, "search query is empty or too short")
+	ErrAlreadyVerified          = New(CodeAlreadyVerified, "email already verified, no action needed")
+	ErrRateLimited              = New(CodeRateLimited, "rate limit exceeded, please try again later")
+	ErrInvalidCurrentPassword   = New(CodeInvalidCurrentPassword, "current password is incorrect")
+	ErrNoRolesAssigned          = New(CodeNoRolesAssigned, "no roles assigned to the user")
+	ErrAlertNotFound            = New(CodeAlertNotFound, "alert not found")
+	ErrVerificationLocked       = New(CodeVerificationLocked, "too many incorrect attempts")
+	ErrVerificationCooldown     = New(CodeVerificationCooldown, "wait before resending")
+	ErrSentViaEmail             = New(CodeSentViaEmail, "sent via email")
+	ErrInvalidDigestFrequency   = New(CodeInvalidDigestFrequency, "invalid digest frequency, expected off, daily or weekly")
+	ErrDigestChannelRequired    = New(CodeDigestChannelRequired, "at least one digest channel must be enabled")
+	ErrHomeAddressNotConfigured = New(CodeHomeAddressNotConfigured, "home address not configured")
+	ErrWorkAddressNotConfigured = New(CodeWorkAddressNotConfigured, "work address not configured")
+	ErrNoEmergencyContacts      = New(CodeNoEmergencyContacts, "no emergency contacts configured")
+	ErrEmergencyAlertNotSent    = New(CodeEmergencyAlertNotSent, "failed to send emergency alerts to any contact")
+	ErrInvalidUnsubscribeToken  = New(CodeInvalidUnsubscribeToken, "invalid or expired unsubscribe link")
+	ErrEmailRequiresAccount     = New(CodeEmailRequiresAccount, "email notifications require a registered account")
+	ErrDangerZoneNotFound       = New(CodeDangerZoneNotFound, "danger zone not found")
+	ErrRouteNotFound            = New(CodeRouteNotFound, "no road route found between origin and destination")
+	ErrWeightRuleNotFound       = New(CodeWeightRuleNotFound, "incident weight rule not found")
+	ErrTripNotFound             = New(CodeTripNotFound, "trip not found")
+	ErrTripAlreadyActive        = New(CodeTripAlreadyActive, "a trip is already in progress")
+
+	ErrInvalidRequestBody      = New(CodeInvalidRequestBody, "invalid request body")
+	ErrInvalidUserID           = New(CodeInvalidUserID, "invalid user ID")
+	ErrInvalidID               = New(CodeInvalidID, "invalid id")
+	ErrIDRequired              = New(CodeIDRequired, "id is required")
+	ErrLocationRequired        = New(CodeLocationRequired, "latitude and longitude are required")
+	ErrInvalidCoordinates      = New(CodeInvalidCoordinates, "invalid latitude or longitude")
+	ErrIdentifierRequired      = New(CodeIdentifierRequired, "device_id or authentication required")
+	ErrCredentialsRequired     = New(CodeUnauthorized, "unauthorized: JWT or X-Device-ID required")
+	ErrSessionNotFound         = New(CodeSessionNotFound, "anonymous session not found")
+	ErrLocationSharingNotFound = New(CodeLocationSharingNotFound, "location sharing not found")
+	ErrLocationSharingExpired  = New(CodeLocationSharingExpired, "location sharing is expired or inactive")
 )
+
+// InvalidID reports a malformed ID of the named entity, e.g. InvalidID("trip").
+func InvalidID(entity string) error {
+	return New(CodeInvalidID, "invalid "+entity+" ID")
+}
+
+// IDRequired reports a missing ID of the named entity.
+func IDRequired(entity string) error {
+	return New(CodeIDRequired, entity+" ID is required")
+}
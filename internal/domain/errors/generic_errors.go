@@ -4,21 +4,21 @@ import "errors"
 
 // Generic / technical errors
 var (
-	ErrInvalidRequest       = errors.New("invalid request")
-	ErrInternalServer       = errors.New("internal server error")
-	ErrNotFound             = errors.New("not found")
-	ErrUnauthorized         = errors.New("unauthorized")
-	ErrForbidden            = errors.New("forbidden")
-	ErrConflict             = errors.New("conflict")
-	ErrBadRequest           = errors.New("bad request")
-	ErrServiceUnavailable   = errors.New("application unavailable")
+	ErrInvalidRequest       = New(CodeBadRequest, "invalid request")
+	ErrInternalServer       = New(CodeInternal, "internal server error")
+	ErrNotFound             = New(CodeNotFound, "not found")
+	ErrUnauthorized         = New(CodeUnauthorized, "unauthorized")
+	ErrForbidden            = New(CodeForbidden, "forbidden")
+	ErrConflict             = New(CodeConflict, "conflict")
+	ErrBadRequest           = New(CodeBadRequest, "bad request")
+	ErrServiceUnavailable   = New(CodeUnavailable, "application unavailable")
 	ErrGatewayTimeout       = errors.New("gateway timeout")
-	ErrTooManyRequests      = errors.New("too many requests")
-	ErrMethodNotAllowed     = errors.New("method not allowed")
+	ErrTooManyRequests      = New(CodeTooManyRequests, "too many requests")
+	ErrMethodNotAllowed     = New(CodeMethodNotAllowed, "method not allowed")
 	ErrNotImplemented       = errors.New("not implemented")
 	ErrUnprocessableEntity  = errors.New("unprocessable entity")
 	ErrUnsupportedMediaType = errors.New("unsupported media type")
-	ErrRateLimitExceeded    = errors.New("rate limit exceeded")
+	ErrRateLimitExceeded    = New(CodeRateLimited, "rate limit exceeded")
 	ErrInvalidInput         = errors.New("invalid input")
 	ErrExpiredToken         = errors.New("token has expired")
 	ErrInvalidToken         = errors.New("invalid token")
@@ -3,14 +3,19 @@ package event
 import "github.com/google/uuid"
 
 type AlertCreatedEvent struct {
-	AlertID   uuid.UUID
-	UserID    []uuid.UUID
-	Message   string
-	Latitude  float64
-	Longitude float64
-	Radius    float64
-	RiskType  string
-	Severity  string
+	AlertID uuid.UUID
+	UserID  []uuid.UUID
+	// UserDistances holds each user's distance from the alert in metres, in
+	// the order of UserID.
+	UserDistances []float64
+	Message       string
+	Latitude      float64
+	Longitude     float64
+	Radius        float64
+	RiskType      string
+	RiskTypeID    uuid.UUID
+	RiskTopicID   uuid.UUID
+	Severity      string
 }
 
 func (e AlertCreatedEvent) Name() string { return "AlertCreated" }
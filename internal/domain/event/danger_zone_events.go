@@ -10,6 +10,7 @@ type DangerZoneEnteredEvent struct {
 	Latitude     float64
 	Longitude    float64
 	ZoneID       uuid.UUID
+	GridCellID   string
 	RiskLevel    string
 	IncidentCount int
 }
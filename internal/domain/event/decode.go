@@ -0,0 +1,39 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Decode rebuilds an event persisted as JSON under its Name. Every event that
+// goes through the outbox must be listed here.
+func Decode(name string, payload []byte) (Event, error) {
+	switch name {
+	case AlertCreatedEvent{}.Name():
+		return decodeAs[AlertCreatedEvent](payload)
+	case ReportCreatedEvent{}.Name():
+		return decodeAs[ReportCreatedEvent](payload)
+	case ReportVerifiedEvent{}.Name():
+		return decodeAs[ReportVerifiedEvent](payload)
+	case ReportResolvedEvent{}.Name():
+		return decodeAs[ReportResolvedEvent](payload)
+	case HighRiskNudgeEvent{}.Name():
+		return decodeAs[HighRiskNudgeEvent](payload)
+	case DangerZoneEnteredEvent{}.Name():
+		return decodeAs[DangerZoneEnteredEvent](payload)
+	case TripCheckInRequestedEvent{}.Name():
+		return decodeAs[TripCheckInRequestedEvent](payload)
+	case TripEscalatedEvent{}.Name():
+		return decodeAs[TripEscalatedEvent](payload)
+	default:
+		return nil, fmt.Errorf("unknown event %q", name)
+	}
+}
+
+func decodeAs[T Event](payload []byte) (Event, error) {
+	var ev T
+	if err := json.Unmarshal(payload, &ev); err != nil {
+		return nil, fmt.Errorf("failed to decode event: %w", err)
+	}
+	return ev, nil
+}
@@ -1,36 +1,93 @@
 package event
 
-import "sync"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+)
 
 type Event interface {
 	Name() string
 }
 
-type EventHandler func(event Event)
+// EventHandler handles an event and returns an error when it could not
+// deliver it, so the outbox relay retries the handler.
+type EventHandler func(ctx context.Context, event Event) error
+
+type namedHandler struct {
+	name    string
+	handler EventHandler
+}
 
 type EventDispatcher struct {
-	handlers map[string][]EventHandler
+	handlers map[string][]namedHandler
 	mu       sync.RWMutex
 }
 
 func NewEventDispatcher() *EventDispatcher {
 	return &EventDispatcher{
-		handlers: make(map[string][]EventHandler),
+		handlers: make(map[string][]namedHandler),
 	}
 }
 
-func (d *EventDispatcher) Register(eventName string, handler EventHandler) {
+// Register adds a handler for eventName. handlerName identifies it among the
+// handlers of that event, so its delivery is tracked on its own.
+func (d *EventDispatcher) Register(eventName, handlerName string, handler EventHandler) {
 	d.mu.Lock()
 	defer d.mu.Unlock()
-	d.handlers[eventName] = append(d.handlers[eventName], handler)
+	d.handlers[eventName] = append(d.handlers[eventName], namedHandler{name: handlerName, handler: handler})
 }
 
 func (d *EventDispatcher) Dispatch(event Event) {
 	d.mu.RLock()
 	defer d.mu.RUnlock()
-	if handlers, ok := d.handlers[event.Name()]; ok {
-		for _, h := range handlers {
-			go h(event)
+	for _, h := range d.handlers[event.Name()] {
+		go func(h namedHandler) {
+			if err := runHandler(context.Background(), h, event); err != nil {
+				slog.Error("event handler failed", "event", event.Name(), "error", err)
+			}
+		}(h)
+	}
+}
+
+// Deliver runs the handlers for event one after another, skipping those
+// named in delivered, and returns the names of every handler that has now
+// handled the event. Failing or panicking handlers are reported in the
+// error, so callers such as the outbox relay retry only those.
+func (d *EventDispatcher) Deliver(ctx context.Context, event Event, delivered []string) ([]string, error) {
+	d.mu.RLock()
+	handlers := append([]namedHandler(nil), d.handlers[event.Name()]...)
+	d.mu.RUnlock()
+
+	done := append([]string(nil), delivered...)
+	var errs []error
+	for _, h := range handlers {
+		if slices.Contains(delivered, h.name) {
+			continue
 		}
+		if err := runHandler(ctx, h, event); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		done = append(done, h.name)
+	}
+
+	return done, errors.Join(errs...)
+}
+
+//nolint:nonamedreturns // recover can only set the error through a named return
+func runHandler(ctx context.Context, h namedHandler, event Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%s handler for %s panicked: %v", h.name, event.Name(), r)
+		}
+	}()
+
+	if err := h.handler(ctx, event); err != nil {
+		return fmt.Errorf("%s handler for %s: %w", h.name, event.Name(), err)
 	}
+	return nil
 }
@@ -0,0 +1,72 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeliver_ReportsHandlerPanic(t *testing.T) {
+	dispatcher := NewEventDispatcher()
+
+	called := false
+	dispatcher.Register("ReportVerified", "websocket", func(context.Context, Event) error { called = true; return nil })
+	dispatcher.Register("ReportVerified", "push", func(context.Context, Event) error { panic("push provider down") })
+
+	delivered, err := dispatcher.Deliver(context.Background(), ReportVerifiedEvent{ReportID: uuid.New()}, nil)
+
+	assert.True(t, called)
+	assert.Equal(t, []string{"websocket"}, delivered)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "push provider down")
+}
+
+func TestDeliver_RetriesOnlyFailedHandlers(t *testing.T) {
+	dispatcher := NewEventDispatcher()
+
+	calls := map[string]int{}
+	smsDown := true
+	dispatcher.Register("ReportVerified", "push", func(context.Context, Event) error { calls["push"]++; return nil })
+	dispatcher.Register("ReportVerified", "sms", func(context.Context, Event) error {
+		calls["sms"]++
+		if smsDown {
+			return errors.New("twilio unavailable")
+		}
+		return nil
+	})
+	ev := ReportVerifiedEvent{ReportID: uuid.New()}
+
+	delivered, err := dispatcher.Deliver(context.Background(), ev, nil)
+	assert.ErrorContains(t, err, "sms handler for ReportVerified: twilio unavailable")
+	assert.Equal(t, []string{"push"}, delivered)
+
+	smsDown = false
+	delivered, err = dispatcher.Deliver(context.Background(), ev, delivered)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"push", "sms"}, delivered)
+	assert.Equal(t, map[string]int{"push": 1, "sms": 2}, calls)
+}
+
+func TestDecode_RoundTrip(t *testing.T) {
+	original := AlertCreatedEvent{
+		AlertID:  uuid.New(),
+		UserID:   []uuid.UUID{uuid.New()},
+		Message:  "Tiroteio",
+		RiskType: "violence",
+		Severity: "critical",
+	}
+
+	payload, err := json.Marshal(original)
+	assert.NoError(t, err)
+
+	decoded, err := Decode(original.Name(), payload)
+	assert.NoError(t, err)
+	assert.Equal(t, original, decoded)
+
+	_, err = Decode("Unknown", payload)
+	assert.Error(t, err)
+}
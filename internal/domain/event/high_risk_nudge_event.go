@@ -0,0 +1,18 @@
+package event
+
+import "github.com/google/uuid"
+
+// HighRiskNudgeEvent is raised when a user inside their configured high risk
+// time window moves close to recent verified incidents or a high risk zone.
+type HighRiskNudgeEvent struct {
+	UserID                uuid.UUID
+	DeviceID              string
+	Latitude              float64
+	Longitude             float64
+	IncidentCount         int
+	RiskTypeCounts        map[string]int
+	NearestIncidentMeters float64
+	DangerZoneRiskLevel   string
+}
+
+func (e HighRiskNudgeEvent) Name() string { return "HighRiskNudge" }
@@ -12,14 +12,19 @@ func (r ReportVerifiedEvent) Name() string {
 }
 
 type ReportCreatedEvent struct {
-	ReportID   uuid.UUID
-	UserID     []uuid.UUID
-	Message    string
-	Latitude   float64
-	Longitude  float64
-	Radius     float64
-	RiskType   string
-	IsVerified bool
+	ReportID uuid.UUID
+	UserID   []uuid.UUID
+	// UserDistances holds each user's distance from the report in metres, in
+	// the order of UserID.
+	UserDistances []float64
+	Message       string
+	Latitude      float64
+	Longitude     float64
+	Radius        float64
+	RiskType      string
+	RiskTypeID    uuid.UUID
+	RiskTopicID   uuid.UUID
+	IsVerified    bool
 }
 
 func (e ReportCreatedEvent) Name() string { return "ReportCreated" }
@@ -0,0 +1,27 @@
+package event
+
+import "github.com/google/uuid"
+
+// TripCheckInRequestedEvent asks a traveller to confirm they are OK after
+// their trip showed an anomaly.
+type TripCheckInRequestedEvent struct {
+	TripID    uuid.UUID
+	UserID    uuid.UUID
+	Anomaly   string
+	Latitude  float64
+	Longitude float64
+}
+
+func (e TripCheckInRequestedEvent) Name() string { return "TripCheckInRequested" }
+
+// TripEscalatedEvent alerts a traveller's emergency contacts after a
+// check-in went unanswered or the traveller said they need help.
+type TripEscalatedEvent struct {
+	TripID    uuid.UUID
+	UserID    uuid.UUID
+	Anomaly   string
+	Latitude  float64
+	Longitude float64
+}
+
+func (e TripEscalatedEvent) Name() string { return "TripEscalated" }
@@ -11,9 +11,16 @@ type DangerZone struct {
 	CellLat      float64   `json:"cell_lat"`
 	CellLon      float64   `json:"cell_lon"`
 	GridCellID   string    `json:"grid_cell_id"`
+	Precision    int       `json:"precision"`
 	IncidentCount int       `json:"incident_count"`
 	RiskScore    float64   `json:"risk_score"`
 	RiskLevel    string    `json:"risk_level"`
+	// TimeProfile holds the zone's risk per weekday/weekend four-hour slot,
+	// indexed like DangerZoneTimeSlotIndex.
+	TimeProfile  []DangerZoneTimeSlot `json:"time_profile,omitempty"`
+	// Manual is set when the zone was drawn by an authority rather than
+	// computed from reports.
+	Manual       *ManualDangerZone `json:"manual,omitempty"`
 	CalculatedAt time.Time `json:"calculated_at"`
 	ExpiresAt    time.Time `json:"expires_at"`
 }
@@ -39,18 +46,44 @@ const (
 )
 
 func (dz *DangerZone) CalculateRiskLevel() {
+	dz.RiskLevel = DangerZoneRiskLevel(dz.RiskScore)
+}
+
+// DangerZoneRiskLevel maps a 0-10 risk score to its level.
+func DangerZoneRiskLevel(score float64) string {
 	switch {
-	case dz.RiskScore >= riskScoreCritical:
-		dz.RiskLevel = "critical"
-	case dz.RiskScore >= riskScoreHigh:
-		dz.RiskLevel = "high"
-	case dz.RiskScore >= riskScoreMedium:
-		dz.RiskLevel = "medium"
+	case score >= riskScoreCritical:
+		return "critical"
+	case score >= riskScoreHigh:
+		return "high"
+	case score >= riskScoreMedium:
+		return "medium"
 	default:
-		dz.RiskLevel = "low"
+		return "low"
 	}
 }
 
+// DistanceFrom is how far the point is from the zone: from its cell's
+// center, or from the polygon's edge for manual zones.
+func (dz *DangerZone) DistanceFrom(lat, lon float64) float64 {
+	if dz.Manual != nil {
+		return dz.Manual.DistanceMeters(lat, lon)
+	}
+	return DistanceMeters(lat, lon, dz.CellLat, dz.CellLon)
+}
+
 func (dz *DangerZone) IsExpired() bool {
 	return time.Now().After(dz.ExpiresAt)
 }
+
+// DangerZoneIncident is a report as seen by the danger zone grid.
+type DangerZoneIncident struct {
+	ReportID  uuid.UUID
+	RiskType  string
+	RiskTopic string
+	Latitude  float64
+	Longitude float64
+	Verified  bool
+	IsPrivate bool
+	CreatedAt time.Time
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DangerZoneContribution is one report's share of a zone's risk score.
+type DangerZoneContribution struct {
+	ReportID           uuid.UUID `json:"report_id"`
+	RiskType           string    `json:"risk_type"`
+	RiskTopic          string    `json:"risk_topic,omitempty"`
+	Verified           bool      `json:"verified"`
+	Private            bool      `json:"private"`
+	CreatedAt          time.Time `json:"created_at"`
+	SeverityWeight     float64   `json:"severity_weight"`
+	RecencyWeight      float64   `json:"recency_weight"`
+	VerificationWeight float64   `json:"verification_weight"`
+	Weight             float64   `json:"weight"`
+}
+
+// Redacted hides what could identify a private report: its ID and the exact
+// time it was made. Its weight still counts.
+func (c DangerZoneContribution) Redacted() DangerZoneContribution {
+	if !c.Private {
+		return c
+	}
+	year, month, day := c.CreatedAt.In(LocalTimezone()).Date()
+	c.ReportID = uuid.Nil
+	c.CreatedAt = time.Date(year, month, day, 0, 0, 0, 0, LocalTimezone())
+	return c
+}
+
+// DangerZoneRiskTypeBreakdown sums the contributions of one risk type.
+type DangerZoneRiskTypeBreakdown struct {
+	RiskType      string  `json:"risk_type"`
+	IncidentCount int     `json:"incident_count"`
+	Weight        float64 `json:"weight"`
+}
+
+// DangerZoneExplanation records how a zone's risk score was reached.
+type DangerZoneExplanation struct {
+	GridCellID    string  `json:"grid_cell_id"`
+	Precision     int     `json:"precision"`
+	CellLat       float64 `json:"cell_lat"`
+	CellLon       float64 `json:"cell_lon"`
+	IncidentCount int     `json:"incident_count"`
+	// RawScore is the sum of the contributions' weights; RiskScore is the
+	// same capped at MaxRiskScore.
+	RawScore      float64                       `json:"raw_score"`
+	RiskScore     float64                       `json:"risk_score"`
+	MaxRiskScore  float64                       `json:"max_risk_score"`
+	RiskLevel     string                        `json:"risk_level"`
+	Breakdown     []DangerZoneRiskTypeBreakdown `json:"breakdown"`
+	Contributions []DangerZoneContribution      `json:"contributions"`
+	CalculatedAt  time.Time                     `json:"calculated_at"`
+}
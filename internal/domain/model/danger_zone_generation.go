@@ -0,0 +1,50 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DangerZoneCacheNamespace prefixes every danger zone cache key.
+const DangerZoneCacheNamespace = "danger_zones"
+
+// DangerZoneGeneration is one complete calculation of the danger zone grid as
+// published to the cache. Each generation writes its zones under its own
+// keys; readers follow the current generation, so replacing it is a single
+// write and cells that are no longer zones disappear with the old keys.
+type DangerZoneGeneration struct {
+	ID            string    `json:"id"`
+	PreviousID    string    `json:"previous_id,omitempty"`
+	StartedAt     time.Time `json:"started_at"`
+	CompletedAt   time.Time `json:"completed_at"`
+	IncidentCount int       `json:"incident_count"`
+	ZoneCount     int       `json:"zone_count"`
+	// ZonesPerPrecision counts the zones of each grid level.
+	ZonesPerPrecision map[int]int `json:"zones_per_precision"`
+}
+
+// GeoKey is the geo set indexing the zones of one grid level.
+func (g *DangerZoneGeneration) GeoKey(precision int) string {
+	return fmt.Sprintf("%s:%s:p%d", DangerZoneCacheNamespace, g.ID, precision)
+}
+
+// ZonesKey is the hash of zones by grid cell ID.
+func (g *DangerZoneGeneration) ZonesKey() string {
+	return fmt.Sprintf("%s:%s:zones", DangerZoneCacheNamespace, g.ID)
+}
+
+// Keys lists every key the generation wrote.
+func (g *DangerZoneGeneration) Keys() []string {
+	precisions := make([]int, 0, len(g.ZonesPerPrecision))
+	for precision := range g.ZonesPerPrecision {
+		precisions = append(precisions, precision)
+	}
+	sort.Ints(precisions)
+
+	keys := []string{g.ZonesKey()}
+	for _, precision := range precisions {
+		keys = append(keys, g.GeoKey(precision))
+	}
+	return keys
+}
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDangerZoneGeneration_Keys(t *testing.T) {
+	generation := &DangerZoneGeneration{
+		ID:                "gen1",
+		ZonesPerPrecision: map[int]int{7: 12, 5: 0, 6: 3},
+	}
+
+	assert.Equal(t, []string{
+		"danger_zones:gen1:zones",
+		"danger_zones:gen1:p5",
+		"danger_zones:gen1:p6",
+		"danger_zones:gen1:p7",
+	}, generation.Keys())
+
+	// Another generation never shares a key, so replacing one cannot leave
+	// the other half-written.
+	other := &DangerZoneGeneration{ID: "gen2", ZonesPerPrecision: generation.ZonesPerPrecision}
+	for _, key := range other.Keys() {
+		assert.NotContains(t, generation.Keys(), key)
+	}
+}
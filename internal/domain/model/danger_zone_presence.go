@@ -0,0 +1,83 @@
+package model
+
+import "time"
+
+const (
+	// DangerZoneEnterRadiusMeters is how close to a zone's center a user has
+	// to be to count as inside it.
+	DangerZoneEnterRadiusMeters = 200.0
+	// DangerZoneExitRadiusMeters is how far a user has to move from the
+	// center before they count as outside again. The gap between the two
+	// radii keeps GPS jitter at the edge from flapping in and out.
+	DangerZoneExitRadiusMeters = 350.0
+	// DangerZoneAlertCooldown is the minimum time between two entry alerts.
+	DangerZoneAlertCooldown = 5 * time.Minute
+	// DangerZoneReentryCooldown is how long re-entering the zone that was
+	// last alerted on stays silent.
+	DangerZoneReentryCooldown = 30 * time.Minute
+)
+
+// DangerZonePresence tracks which high risk zone a user is in between
+// location updates. Distances are to a computed zone's center or to the
+// edge of a manual zone's polygon.
+type DangerZonePresence struct {
+	GridCellID    string    `json:"grid_cell_id"`
+	CellLat       float64   `json:"cell_lat"`
+	CellLon       float64   `json:"cell_lon"`
+	EnteredAt     time.Time `json:"entered_at"`
+	LastAlertCell string    `json:"last_alert_cell"`
+	LastAlertAt   time.Time `json:"last_alert_at"`
+}
+
+func (p *DangerZonePresence) Inside() bool {
+	return p.GridCellID != ""
+}
+
+// Update moves the presence to the user's new position. zones are the zones
+// around the position; only high and critical ones can be entered. It
+// returns the zone to alert on, if the user just entered one and no cooldown
+// applies, and whether the user left the zone they were in.
+//
+//nolint:nonamedreturns // both results describe the same transition
+func (p *DangerZonePresence) Update(zones []*DangerZone, lat, lon float64, now time.Time) (alert *DangerZone, exited bool) {
+	if p.Inside() {
+		distance := DistanceMeters(lat, lon, p.CellLat, p.CellLon)
+		for _, zone := range zones {
+			if zone.GridCellID == p.GridCellID {
+				distance = zone.DistanceFrom(lat, lon)
+				break
+			}
+		}
+		if distance <= DangerZoneExitRadiusMeters {
+			return nil, false
+		}
+		p.GridCellID, p.CellLat, p.CellLon, p.EnteredAt = "", 0, 0, time.Time{}
+		exited = true
+	}
+
+	var nearest *DangerZone
+	nearestDistance := DangerZoneEnterRadiusMeters
+	for _, zone := range zones {
+		if zone.RiskLevel != "high" && zone.RiskLevel != "critical" {
+			continue
+		}
+		if d := zone.DistanceFrom(lat, lon); d <= nearestDistance {
+			nearest, nearestDistance = zone, d
+		}
+	}
+	if nearest == nil {
+		return nil, exited
+	}
+
+	p.GridCellID, p.CellLat, p.CellLon, p.EnteredAt = nearest.GridCellID, nearest.CellLat, nearest.CellLon, now
+
+	if now.Sub(p.LastAlertAt) < DangerZoneAlertCooldown {
+		return nil, exited
+	}
+	if nearest.GridCellID == p.LastAlertCell && now.Sub(p.LastAlertAt) < DangerZoneReentryCooldown {
+		return nil, exited
+	}
+
+	p.LastAlertCell, p.LastAlertAt = nearest.GridCellID, now
+	return nearest, exited
+}
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDangerZonePresence_HysteresisAndCooldown(t *testing.T) {
+	zone := &DangerZone{GridCellID: "kq3mdzz", CellLat: -8.8290, CellLon: 13.2405, RiskLevel: "high"}
+	zones := []*DangerZone{zone}
+	start := time.Date(2025, 5, 2, 20, 0, 0, 0, time.UTC)
+
+	// About 110 m, 280 m and 450 m north of the zone's center.
+	const inside, edge, outside = -8.8280, -8.8265, -8.8250
+
+	p := &DangerZonePresence{}
+
+	alert, exited := p.Update(zones, outside, zone.CellLon, start)
+	assert.Nil(t, alert)
+	assert.False(t, exited)
+	assert.False(t, p.Inside())
+
+	alert, _ = p.Update(zones, inside, zone.CellLon, start.Add(time.Minute))
+	assert.Equal(t, zone, alert)
+	assert.True(t, p.Inside())
+
+	// Jitter between the enter and exit radii neither leaves nor re-alerts.
+	alert, exited = p.Update(zones, edge, zone.CellLon, start.Add(2*time.Minute))
+	assert.Nil(t, alert)
+	assert.False(t, exited)
+	assert.True(t, p.Inside())
+
+	alert, exited = p.Update(zones, outside, zone.CellLon, start.Add(3*time.Minute))
+	assert.Nil(t, alert)
+	assert.True(t, exited)
+
+	// Coming back soon after is silent, but counts as inside again.
+	alert, _ = p.Update(zones, inside, zone.CellLon, start.Add(10*time.Minute))
+	assert.Nil(t, alert)
+	assert.True(t, p.Inside())
+
+	p.Update(zones, outside, zone.CellLon, start.Add(20*time.Minute))
+	alert, _ = p.Update(zones, inside, zone.CellLon, start.Add(40*time.Minute))
+	assert.Equal(t, zone, alert, "re-entry alerts again once the cooldown is over")
+}
+
+func TestDangerZonePresence_IgnoresLowerRiskZones(t *testing.T) {
+	zones := []*DangerZone{{GridCellID: "kq3mdzz", CellLat: -8.8290, CellLon: 13.2405, RiskLevel: "medium"}}
+
+	p := &DangerZonePresence{}
+	alert, _ := p.Update(zones, -8.8290, 13.2405, time.Now())
+	assert.Nil(t, alert)
+	assert.False(t, p.Inside())
+}
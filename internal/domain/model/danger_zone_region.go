@@ -0,0 +1,137 @@
+package model
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultDangerZoneDaysBack = 30
+	// dangerZoneCellsAcross is roughly how many cells should span the
+	// diameter of a query: fewer and zones are too coarse to be useful,
+	// more and a phone has to draw thousands of them.
+	dangerZoneCellsAcross = 16
+	earthCircumference    = 40075016.0
+)
+
+// DangerZoneLevel is one resolution of the danger zone grid: the geohash
+// precision of its cells and how many incidents make a cell a zone.
+type DangerZoneLevel struct {
+	Precision    int `json:"precision"`
+	MinIncidents int `json:"min_incidents"`
+}
+
+// DangerZoneRegion holds the grid parameters for one area. Bounds is nil for
+// the fallback region that covers everywhere else.
+type DangerZoneRegion struct {
+	ID       uuid.UUID
+	Name     string
+	Bounds   *BoundingBox
+	Priority int
+	DaysBack int
+	// Levels are sorted from coarse to fine.
+	Levels []DangerZoneLevel
+}
+
+// DefaultDangerZoneRegion is used when no region is configured.
+func DefaultDangerZoneRegion() *DangerZoneRegion {
+	return &DangerZoneRegion{
+		Name:     "default",
+		DaysBack: defaultDangerZoneDaysBack,
+		Levels: []DangerZoneLevel{
+			{Precision: 5, MinIncidents: 10}, //nolint:mnd // ~4.9 km cells
+			{Precision: 6, MinIncidents: 5},  //nolint:mnd // ~1.2 km cells
+			{Precision: 7, MinIncidents: 3},  //nolint:mnd // ~150 m cells
+		},
+	}
+}
+
+func (r *DangerZoneRegion) Validate() error {
+	if r.Name == "" {
+		return errors.New("danger zone region name is required")
+	}
+	if r.DaysBack < 1 {
+		return errors.New("danger zone region days_back must be positive")
+	}
+	if len(r.Levels) == 0 {
+		return errors.New("danger zone region needs at least one level")
+	}
+	for _, level := range r.Levels {
+		if level.Precision < 1 || level.Precision > geohashMaxPrecision {
+			return errors.New("danger zone level precision must be between 1 and 12")
+		}
+		if level.MinIncidents < 1 {
+			return errors.New("danger zone level min_incidents must be positive")
+		}
+	}
+	return nil
+}
+
+// SortLevels orders the levels from coarse to fine.
+func (r *DangerZoneRegion) SortLevels() {
+	sort.Slice(r.Levels, func(i, j int) bool { return r.Levels[i].Precision < r.Levels[j].Precision })
+}
+
+func (r *DangerZoneRegion) Covers(lat, lon float64) bool {
+	return r.Bounds == nil || r.Bounds.Contains(lat, lon)
+}
+
+// LevelFor returns the configured level closest to the wanted precision,
+// preferring the finer one on a tie.
+func (r *DangerZoneRegion) LevelFor(precision int) DangerZoneLevel {
+	best := r.Levels[0]
+	for _, level := range r.Levels[1:] {
+		if abs(level.Precision-precision) <= abs(best.Precision-precision) {
+			best = level
+		}
+	}
+	return best
+}
+
+// DangerZoneRegionAt returns the highest priority region covering the point,
+// falling back to DefaultDangerZoneRegion.
+func DangerZoneRegionAt(regions []*DangerZoneRegion, lat, lon float64) *DangerZoneRegion {
+	var found *DangerZoneRegion
+	for _, region := range regions {
+		if !region.Covers(lat, lon) {
+			continue
+		}
+		if found == nil || region.Priority > found.Priority {
+			found = region
+		}
+	}
+	if found == nil {
+		return DefaultDangerZoneRegion()
+	}
+	return found
+}
+
+// GeohashPrecisionForRadius picks the finest precision at which a circle of
+// radiusMeters is still only about dangerZoneCellsAcross cells wide.
+func GeohashPrecisionForRadius(radiusMeters float64) int {
+	minCellWidth := 2 * radiusMeters / dangerZoneCellsAcross //nolint:mnd // diameter
+	for precision := geohashMaxPrecision; precision > 1; precision-- {
+		width, _ := GeohashCellSizeMeters(precision)
+		if width >= minCellWidth {
+			return precision
+		}
+	}
+	return 1
+}
+
+// GeohashPrecisionForZoom maps a web map zoom level (0 to 22) to a precision,
+// taking the visible radius as about one 256px tile.
+func GeohashPrecisionForZoom(zoom int) int {
+	zoom = max(0, min(zoom, 22)) //nolint:mnd // deepest zoom of common tile servers
+	tileWidth := earthCircumference / float64(uint(1)<<zoom)
+	return GeohashPrecisionForRadius(tileWidth)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,149 @@
+package model
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DangerZoneTrend string
+
+const (
+	DangerZoneTrendRising  DangerZoneTrend = "rising"
+	DangerZoneTrendCooling DangerZoneTrend = "cooling"
+	DangerZoneTrendStable  DangerZoneTrend = "stable"
+
+	// dangerZoneTrendThreshold is how many risk score points the weekly
+	// average has to move to count as a trend rather than noise.
+	dangerZoneTrendThreshold = 0.5
+)
+
+// DangerZoneSnapshot is a zone as one calculation run saw it.
+type DangerZoneSnapshot struct {
+	RunID         uuid.UUID
+	GridCellID    string
+	Precision     int
+	CellLat       float64
+	CellLon       float64
+	IncidentCount int
+	RiskScore     float64
+	RiskLevel     string
+	CalculatedAt  time.Time
+}
+
+func NewDangerZoneSnapshot(runID uuid.UUID, zone *DangerZone) DangerZoneSnapshot {
+	return DangerZoneSnapshot{
+		RunID:         runID,
+		GridCellID:    zone.GridCellID,
+		Precision:     zone.Precision,
+		CellLat:       zone.CellLat,
+		CellLon:       zone.CellLon,
+		IncidentCount: zone.IncidentCount,
+		RiskScore:     zone.RiskScore,
+		RiskLevel:     zone.RiskLevel,
+		CalculatedAt:  zone.CalculatedAt,
+	}
+}
+
+// DangerZoneDailyScore summarizes a cell's snapshots of one day, in Luanda
+// time. The average is over every run of the day, so runs in which the cell
+// was not a zone count as zero.
+type DangerZoneDailyScore struct {
+	GridCellID       string
+	Precision        int
+	CellLat          float64
+	CellLon          float64
+	Day              time.Time
+	AverageRiskScore float64
+	MaxRiskScore     float64
+	MaxIncidentCount int
+}
+
+// DangerZoneWeekComparison compares the average daily score of the last
+// seven days with the seven before. Days without a snapshot count as zero,
+// since the cell was not a zone then.
+type DangerZoneWeekComparison struct {
+	CurrentWeekScore  float64
+	PreviousWeekScore float64
+	Change            float64
+}
+
+func (c DangerZoneWeekComparison) Trend() DangerZoneTrend {
+	switch {
+	case c.Change >= dangerZoneTrendThreshold:
+		return DangerZoneTrendRising
+	case c.Change <= -dangerZoneTrendThreshold:
+		return DangerZoneTrendCooling
+	default:
+		return DangerZoneTrendStable
+	}
+}
+
+// DangerZoneTrendSeries is one cell's daily scores, oldest first, with its
+// week-over-week comparison.
+type DangerZoneTrendSeries struct {
+	GridCellID string
+	Precision  int
+	CellLat    float64
+	CellLon    float64
+	Days       []DangerZoneDailyScore
+	Comparison DangerZoneWeekComparison
+	Trend      DangerZoneTrend
+}
+
+// BuildDangerZoneTrends groups daily scores by cell and compares each cell's
+// last two weeks up to now. The series come back with the fastest rising
+// cells first.
+func BuildDangerZoneTrends(scores []DangerZoneDailyScore, now time.Time) []DangerZoneTrendSeries {
+	byCell := make(map[string]*DangerZoneTrendSeries)
+	var order []string
+	for _, score := range scores {
+		series, ok := byCell[score.GridCellID]
+		if !ok {
+			series = &DangerZoneTrendSeries{
+				GridCellID: score.GridCellID,
+				Precision:  score.Precision,
+				CellLat:    score.CellLat,
+				CellLon:    score.CellLon,
+			}
+			byCell[score.GridCellID] = series
+			order = append(order, score.GridCellID)
+		}
+		series.Days = append(series.Days, score)
+	}
+
+	year, month, day := now.In(LocalTimezone()).Date()
+	today := time.Date(year, month, day, 0, 0, 0, 0, LocalTimezone())
+	currentFrom := today.AddDate(0, 0, 1-daysPerWeek)
+	previousFrom := currentFrom.AddDate(0, 0, -daysPerWeek)
+
+	trends := make([]DangerZoneTrendSeries, 0, len(order))
+	for _, cell := range order {
+		series := byCell[cell]
+		sort.Slice(series.Days, func(i, j int) bool { return series.Days[i].Day.Before(series.Days[j].Day) })
+
+		var current, previous float64
+		for _, d := range series.Days {
+			switch {
+			case !d.Day.Before(currentFrom) && !d.Day.After(today):
+				current += d.AverageRiskScore
+			case !d.Day.Before(previousFrom) && d.Day.Before(currentFrom):
+				previous += d.AverageRiskScore
+			}
+		}
+
+		series.Comparison = DangerZoneWeekComparison{
+			CurrentWeekScore:  current / daysPerWeek,
+			PreviousWeekScore: previous / daysPerWeek,
+		}
+		series.Comparison.Change = series.Comparison.CurrentWeekScore - series.Comparison.PreviousWeekScore
+		series.Trend = series.Comparison.Trend()
+		trends = append(trends, *series)
+	}
+
+	sort.SliceStable(trends, func(i, j int) bool {
+		return trends[i].Comparison.Change > trends[j].Comparison.Change
+	})
+	return trends
+}
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildDangerZoneTrends_WeekOverWeek(t *testing.T) {
+	luanda := LocalTimezone()
+	now := time.Date(2025, 6, 14, 18, 0, 0, 0, luanda)
+	day := func(daysAgo int) time.Time {
+		return time.Date(2025, 6, 14-daysAgo, 0, 0, 0, 0, luanda)
+	}
+
+	var scores []DangerZoneDailyScore
+	for i := range 14 {
+		// Rising: quiet last week, every day this week.
+		if i < 7 {
+			scores = append(scores, DangerZoneDailyScore{GridCellID: "kq3mdz", Day: day(i), AverageRiskScore: 7})
+		}
+		// Cooling: every day both weeks, but half as bad this week.
+		score := 8.0
+		if i < 7 {
+			score = 4
+		}
+		scores = append(scores, DangerZoneDailyScore{GridCellID: "kq3mdy", Day: day(i), AverageRiskScore: score})
+		// Stable.
+		scores = append(scores, DangerZoneDailyScore{GridCellID: "kq3mdx", Day: day(i), AverageRiskScore: 5})
+	}
+
+	trends := BuildDangerZoneTrends(scores, now)
+	if !assert.Len(t, trends, 3) {
+		return
+	}
+
+	assert.Equal(t, "kq3mdz", trends[0].GridCellID)
+	assert.Equal(t, DangerZoneTrendRising, trends[0].Trend)
+	assert.InDelta(t, 7.0, trends[0].Comparison.CurrentWeekScore, 0.001)
+	assert.InDelta(t, 0.0, trends[0].Comparison.PreviousWeekScore, 0.001)
+
+	assert.Equal(t, "kq3mdx", trends[1].GridCellID)
+	assert.Equal(t, DangerZoneTrendStable, trends[1].Trend)
+
+	assert.Equal(t, "kq3mdy", trends[2].GridCellID)
+	assert.Equal(t, DangerZoneTrendCooling, trends[2].Trend)
+	assert.InDelta(t, -4.0, trends[2].Comparison.Change, 0.001)
+	assert.True(t, trends[2].Days[0].Day.Before(trends[2].Days[13].Day), "days are oldest first")
+}
@@ -0,0 +1,116 @@
+package model
+
+import "time"
+
+type DayType string
+
+const (
+	DayTypeWeekday DayType = "weekday"
+	DayTypeWeekend DayType = "weekend"
+
+	dangerZoneSlotHours   = 4
+	dangerZoneSlotsPerDay = 24 / dangerZoneSlotHours
+	// DangerZoneTimeSlots is the number of buckets in a time profile: six
+	// four-hour slots for weekdays followed by six for weekends.
+	DangerZoneTimeSlots = 2 * dangerZoneSlotsPerDay
+
+	hoursPerWeek    = 168.0
+	weekdaysPerWeek = 5
+	weekendPerWeek  = 2
+
+	sameTimeSlotWeight  = 1.5
+	otherTimeSlotWeight = 0.75
+)
+
+// DangerZoneTimeSlot is a zone's risk during one four-hour slot of a weekday
+// or weekend day, in Luanda time.
+type DangerZoneTimeSlot struct {
+	DayType       DayType `json:"day_type"`
+	StartHour     int     `json:"start_hour"`
+	EndHour       int     `json:"end_hour"`
+	IncidentCount int     `json:"incident_count"`
+	RiskScore     float64 `json:"risk_score"`
+	RiskLevel     string  `json:"risk_level"`
+}
+
+// NewDangerZoneTimeProfile returns the empty slots of a time profile, indexed
+// like DangerZoneTimeSlotIndex.
+func NewDangerZoneTimeProfile() []DangerZoneTimeSlot {
+	profile := make([]DangerZoneTimeSlot, DangerZoneTimeSlots)
+	for i := range profile {
+		dayType := DayTypeWeekday
+		if i >= dangerZoneSlotsPerDay {
+			dayType = DayTypeWeekend
+		}
+		start := (i % dangerZoneSlotsPerDay) * dangerZoneSlotHours
+		profile[i] = DangerZoneTimeSlot{
+			DayType:   dayType,
+			StartHour: start,
+			EndHour:   start + dangerZoneSlotHours,
+			RiskLevel: "low",
+		}
+	}
+	return profile
+}
+
+// DangerZoneTimeSlotIndex returns the profile slot that t falls in.
+func DangerZoneTimeSlotIndex(t time.Time) int {
+	local := t.In(LocalTimezone())
+	index := local.Hour() / dangerZoneSlotHours
+	if weekday := local.Weekday(); weekday == time.Saturday || weekday == time.Sunday {
+		index += dangerZoneSlotsPerDay
+	}
+	return index
+}
+
+// IncidentTimeSlotWeight favours incidents that happened in the same
+// weekday/weekend four-hour slot as the trip.
+func IncidentTimeSlotWeight(incidentAt, departureAt time.Time) float64 {
+	if DangerZoneTimeSlotIndex(incidentAt) == DangerZoneTimeSlotIndex(departureAt) {
+		return sameTimeSlotWeight
+	}
+	return otherTimeSlotWeight
+}
+
+// HoursPerWeek is how many hours of a week the slot covers, used to compare
+// slots of different lengths on the same scale.
+func (s DangerZoneTimeSlot) HoursPerWeek() float64 {
+	if s.DayType == DayTypeWeekend {
+		return weekendPerWeek * dangerZoneSlotHours
+	}
+	return weekdaysPerWeek * dangerZoneSlotHours
+}
+
+// WeeklyShare is the fraction of the week the slot covers.
+func (s DangerZoneTimeSlot) WeeklyShare() float64 {
+	return s.HoursPerWeek() / hoursPerWeek
+}
+
+// Riskiest returns the slot with the highest risk score. It reports false
+// when the zone has no time profile.
+func (dz *DangerZone) Riskiest() (DangerZoneTimeSlot, bool) {
+	if len(dz.TimeProfile) == 0 {
+		return DangerZoneTimeSlot{}, false
+	}
+	best := dz.TimeProfile[0]
+	for _, slot := range dz.TimeProfile[1:] {
+		if slot.RiskScore > best.RiskScore {
+			best = slot
+		}
+	}
+	return best, true
+}
+
+// AtTime returns a copy of the zone scored for the slot t falls in. A zero
+// t, or a zone without a time profile, returns the zone unchanged.
+func (dz *DangerZone) AtTime(t time.Time) *DangerZone {
+	if t.IsZero() || len(dz.TimeProfile) != DangerZoneTimeSlots {
+		return dz
+	}
+	slot := dz.TimeProfile[DangerZoneTimeSlotIndex(t)]
+
+	zone := *dz
+	zone.RiskScore = slot.RiskScore
+	zone.CalculateRiskLevel()
+	return &zone
+}
@@ -0,0 +1,22 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDangerZoneTimeSlotIndex_UsesLuandaTime(t *testing.T) {
+	// 23:30 UTC on a Friday is 00:30 on Saturday in Luanda.
+	fridayNightUTC := time.Date(2025, 5, 2, 23, 30, 0, 0, time.UTC)
+	slot := NewDangerZoneTimeProfile()[DangerZoneTimeSlotIndex(fridayNightUTC)]
+	assert.Equal(t, DayTypeWeekend, slot.DayType)
+	assert.Equal(t, 0, slot.StartHour)
+	assert.Equal(t, 4, slot.EndHour)
+
+	mondayNoon := time.Date(2025, 5, 5, 12, 0, 0, 0, LocalTimezone())
+	slot = NewDangerZoneTimeProfile()[DangerZoneTimeSlotIndex(mondayNoon)]
+	assert.Equal(t, DayTypeWeekday, slot.DayType)
+	assert.Equal(t, 12, slot.StartHour)
+}
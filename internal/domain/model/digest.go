@@ -0,0 +1,161 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
+)
+
+type DigestFrequency string
+
+const (
+	DigestFrequencyOff    DigestFrequency = "off"
+	DigestFrequencyDaily  DigestFrequency = "daily"
+	DigestFrequencyWeekly DigestFrequency = "weekly"
+)
+
+type DigestPlace string
+
+const (
+	DigestPlaceHome    DigestPlace = "home"
+	DigestPlaceWork    DigestPlace = "work"
+	DigestPlaceCurrent DigestPlace = "current"
+)
+
+const (
+	hoursPerDay  = 24
+	daysPerWeek  = 7
+	dailyPeriod  = hoursPerDay * time.Hour
+	weeklyPeriod = daysPerWeek * dailyPeriod
+)
+
+// DigestSubscription is a user's opt-in to periodic safety digests.
+// KnownDangerZones holds the grid cells reported in the previous digest so the
+// next one can tell which zones are new and which have cooled down.
+type DigestSubscription struct {
+	UserID           uuid.UUID
+	Frequency        DigestFrequency
+	PushEnabled      bool
+	EmailEnabled     bool
+	LastSentAt       *time.Time
+	KnownDangerZones []string
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+func NewDigestSubscription(userID uuid.UUID) *DigestSubscription {
+	now := time.Now()
+	return &DigestSubscription{
+		UserID:           userID,
+		Frequency:        DigestFrequencyOff,
+		PushEnabled:      true,
+		EmailEnabled:     false,
+		KnownDangerZones: []string{},
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+}
+
+func (s *DigestSubscription) Validate() error {
+	switch s.Frequency {
+	case DigestFrequencyOff, DigestFrequencyDaily, DigestFrequencyWeekly:
+	default:
+		return domainErrors.ErrInvalidDigestFrequency
+	}
+
+	if s.Frequency != DigestFrequencyOff && !s.PushEnabled && !s.EmailEnabled {
+		return domainErrors.ErrDigestChannelRequired
+	}
+
+	return nil
+}
+
+// Period returns the time covered by one digest, or zero when digests are off.
+func (s *DigestSubscription) Period() time.Duration {
+	return DigestPeriod(s.Frequency)
+}
+
+func DigestPeriod(frequency DigestFrequency) time.Duration {
+	switch frequency {
+	case DigestFrequencyDaily:
+		return dailyPeriod
+	case DigestFrequencyWeekly:
+		return weeklyPeriod
+	case DigestFrequencyOff:
+		return 0
+	default:
+		return 0
+	}
+}
+
+// Since returns the start of the window the next digest should summarise.
+func (s *DigestSubscription) Since(now time.Time) time.Time {
+	if s.LastSentAt != nil {
+		return *s.LastSentAt
+	}
+	return now.Add(-s.Period())
+}
+
+// DigestAreaActivity is what happened around one of the user's places since
+// the previous digest.
+type DigestAreaActivity struct {
+	Place              DigestPlace
+	VerifiedReports    int
+	ActiveAlerts       int
+	DangerZones        []string
+	NewDangerZoneCount int
+}
+
+type Digest struct {
+	UserID           uuid.UUID
+	Frequency        DigestFrequency
+	Areas            []DigestAreaActivity
+	ClearedZoneCount int
+	GeneratedAt      time.Time
+}
+
+func (d *Digest) TotalVerifiedReports() int {
+	total := 0
+	for _, a := range d.Areas {
+		total += a.VerifiedReports
+	}
+	return total
+}
+
+func (d *Digest) TotalActiveAlerts() int {
+	total := 0
+	for _, a := range d.Areas {
+		total += a.ActiveAlerts
+	}
+	return total
+}
+
+func (d *Digest) TotalNewDangerZones() int {
+	total := 0
+	for _, a := range d.Areas {
+		total += a.NewDangerZoneCount
+	}
+	return total
+}
+
+// DangerZoneCells returns every high risk cell seen across the digest areas,
+// without duplicates.
+func (d *Digest) DangerZoneCells() []string {
+	seen := make(map[string]struct{})
+	cells := make([]string, 0)
+	for _, a := range d.Areas {
+		for _, cell := range a.DangerZones {
+			if _, ok := seen[cell]; ok {
+				continue
+			}
+			seen[cell] = struct{}{}
+			cells = append(cells, cell)
+		}
+	}
+	return cells
+}
+
+func (d *Digest) HasActivity() bool {
+	return d.TotalVerifiedReports() > 0 || d.TotalActiveAlerts() > 0 || d.TotalNewDangerZones() > 0 || d.ClearedZoneCount > 0
+}
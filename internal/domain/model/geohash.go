@@ -0,0 +1,149 @@
+package model
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+const (
+	geohashAlphabet     = "0123456789bcdefghjkmnpqrstuvwxyz"
+	geohashMaxPrecision = 12
+	geohashBitsPerChar  = 5
+
+	metersPerDegreeLat = 111320.0
+)
+
+var ErrInvalidGeohash = errors.New("invalid geohash")
+
+// BoundingBox is a latitude/longitude rectangle.
+type BoundingBox struct {
+	MinLat float64 `json:"min_lat"`
+	MinLon float64 `json:"min_lon"`
+	MaxLat float64 `json:"max_lat"`
+	MaxLon float64 `json:"max_lon"`
+}
+
+func (b BoundingBox) Contains(lat, lon float64) bool {
+	return lat >= b.MinLat && lat <= b.MaxLat && lon >= b.MinLon && lon <= b.MaxLon
+}
+
+func (b BoundingBox) Center() (float64, float64) {
+	return (b.MinLat + b.MaxLat) / 2, (b.MinLon + b.MaxLon) / 2 //nolint:mnd // midpoint
+}
+
+// BoundingBoxAround returns the box that encloses a circle of radiusMeters
+// around lat, lon.
+func BoundingBoxAround(lat, lon, radiusMeters float64) BoundingBox {
+	dLat := radiusMeters / metersPerDegreeLat
+	dLon := radiusMeters / (metersPerDegreeLat * math.Max(math.Cos(lat*math.Pi/180), 0.01)) //nolint:mnd // degrees to radians, avoid the poles
+	return BoundingBox{MinLat: lat - dLat, MinLon: lon - dLon, MaxLat: lat + dLat, MaxLon: lon + dLon}
+}
+
+// EncodeGeohash returns the geohash cell of the given precision (1 to 12
+// characters) containing lat, lon. Each extra character splits a cell into
+// 32, so a cell's parent is its hash minus the last character.
+func EncodeGeohash(lat, lon float64, precision int) string {
+	precision = max(1, min(precision, geohashMaxPrecision))
+
+	latRange := [2]float64{-90, 90}
+	lonRange := [2]float64{-180, 180}
+
+	var sb strings.Builder
+	sb.Grow(precision)
+
+	bit, ch, even := 0, 0, true
+	for sb.Len() < precision {
+		if even {
+			mid := (lonRange[0] + lonRange[1]) / 2 //nolint:mnd // bisect
+			if lon >= mid {
+				ch |= 1 << (geohashBitsPerChar - 1 - bit)
+				lonRange[0] = mid
+			} else {
+				lonRange[1] = mid
+			}
+		} else {
+			mid := (latRange[0] + latRange[1]) / 2 //nolint:mnd // bisect
+			if lat >= mid {
+				ch |= 1 << (geohashBitsPerChar - 1 - bit)
+				latRange[0] = mid
+			} else {
+				latRange[1] = mid
+			}
+		}
+		even = !even
+
+		bit++
+		if bit == geohashBitsPerChar {
+			sb.WriteByte(geohashAlphabet[ch])
+			bit, ch = 0, 0
+		}
+	}
+
+	return sb.String()
+}
+
+// DecodeGeohash returns the bounds of a geohash cell.
+func DecodeGeohash(hash string) (BoundingBox, error) {
+	if hash == "" || len(hash) > geohashMaxPrecision {
+		return BoundingBox{}, ErrInvalidGeohash
+	}
+
+	latRange := [2]float64{-90, 90}
+	lonRange := [2]float64{-180, 180}
+	even := true
+
+	for i := range len(hash) {
+		idx := strings.IndexByte(geohashAlphabet, hash[i])
+		if idx < 0 {
+			return BoundingBox{}, ErrInvalidGeohash
+		}
+		for bit := geohashBitsPerChar - 1; bit >= 0; bit-- {
+			set := idx&(1<<bit) != 0
+			if even {
+				mid := (lonRange[0] + lonRange[1]) / 2 //nolint:mnd // bisect
+				if set {
+					lonRange[0] = mid
+				} else {
+					lonRange[1] = mid
+				}
+			} else {
+				mid := (latRange[0] + latRange[1]) / 2 //nolint:mnd // bisect
+				if set {
+					latRange[0] = mid
+				} else {
+					latRange[1] = mid
+				}
+			}
+			even = !even
+		}
+	}
+
+	return BoundingBox{MinLat: latRange[0], MinLon: lonRange[0], MaxLat: latRange[1], MaxLon: lonRange[1]}, nil
+}
+
+// GeohashCellSizeMeters approximates the width and height of a cell of the
+// given precision at the equator, which is close enough for Angola.
+func GeohashCellSizeMeters(precision int) (float64, float64) {
+	precision = max(1, min(precision, geohashMaxPrecision))
+
+	bits := precision * geohashBitsPerChar
+	lonBits := (bits + 1) / 2 //nolint:mnd // longitude takes the odd bit
+	latBits := bits / 2       //nolint:mnd // latitude takes the even bit
+
+	width := 360 / math.Pow(2, float64(lonBits)) * metersPerDegreeLat
+	height := 180 / math.Pow(2, float64(latBits)) * metersPerDegreeLat
+	return width, height
+}
+
+// DistanceMeters is the great-circle distance between two points.
+func DistanceMeters(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadiusMeters = 6371000.0
+	toRad := math.Pi / 180 //nolint:mnd // degrees to radians
+
+	dLat := (lat2 - lat1) * toRad
+	dLon := (lon2 - lon1) * toRad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + //nolint:mnd // haversine
+		math.Cos(lat1*toRad)*math.Cos(lat2*toRad)*math.Sin(dLon/2)*math.Sin(dLon/2) //nolint:mnd // haversine
+	return 2 * earthRadiusMeters * math.Asin(math.Sqrt(a)) //nolint:mnd // haversine
+}
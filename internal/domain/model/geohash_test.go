@@ -0,0 +1,58 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeGeohash_KnownCells(t *testing.T) {
+	assert.Equal(t, "ezs42", EncodeGeohash(42.6, -5.6, 5))
+	assert.Equal(t, "u4pruydqqvj", EncodeGeohash(57.64911, 10.40744, 11))
+}
+
+func TestDecodeGeohash_ContainsEncodedPoint(t *testing.T) {
+	lat, lon := -8.8383, 13.2344
+
+	for precision := 1; precision <= 9; precision++ {
+		hash := EncodeGeohash(lat, lon, precision)
+		bounds, err := DecodeGeohash(hash)
+		assert.NoError(t, err)
+		assert.True(t, bounds.Contains(lat, lon), "precision %d", precision)
+
+		if precision > 1 {
+			parent, err := DecodeGeohash(hash[:precision-1])
+			assert.NoError(t, err)
+			centerLat, centerLon := bounds.Center()
+			assert.True(t, parent.Contains(centerLat, centerLon), "cells nest in their parent")
+		}
+	}
+
+	_, err := DecodeGeohash("kq3ma")
+	assert.ErrorIs(t, err, ErrInvalidGeohash)
+}
+
+func TestDangerZoneRegion_PicksResolution(t *testing.T) {
+	luanda := &DangerZoneRegion{
+		Name:     "luanda",
+		Bounds:   &BoundingBox{MinLat: -9.25, MinLon: 12.95, MaxLat: -8.75, MaxLon: 13.55},
+		Priority: 10,
+		DaysBack: 30,
+		Levels:   []DangerZoneLevel{{Precision: 5, MinIncidents: 15}, {Precision: 6, MinIncidents: 5}, {Precision: 7, MinIncidents: 3}},
+	}
+	country := &DangerZoneRegion{
+		Name:     "angola",
+		DaysBack: 60,
+		Levels:   []DangerZoneLevel{{Precision: 4, MinIncidents: 8}, {Precision: 5, MinIncidents: 4}},
+	}
+	regions := []*DangerZoneRegion{country, luanda}
+
+	assert.Equal(t, "luanda", DangerZoneRegionAt(regions, -8.83, 13.24).Name)
+	assert.Equal(t, "angola", DangerZoneRegionAt(regions, -12.58, 13.40).Name)
+
+	assert.Equal(t, 7, luanda.LevelFor(GeohashPrecisionForRadius(500)).Precision)
+	assert.Equal(t, 5, luanda.LevelFor(GeohashPrecisionForRadius(10000)).Precision)
+	assert.Equal(t, 5, country.LevelFor(GeohashPrecisionForRadius(500)).Precision, "rural roads stay on coarse cells")
+
+	assert.Greater(t, GeohashPrecisionForZoom(16), GeohashPrecisionForZoom(10))
+}
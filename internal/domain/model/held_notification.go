@@ -0,0 +1,48 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HeldNotification is a non-critical push notification deferred because the
+// recipient was inside their night mode quiet hours. Held notifications are
+// delivered together as a morning digest once ReleaseAt has passed.
+type HeldNotification struct {
+	ID          uuid.UUID
+	UserID      *uuid.UUID
+	DeviceID    *string
+	FCMToken    string
+	Language    string
+	EventKey    string
+	RiskType    string
+	ReferenceID string
+	ReleaseAt   time.Time
+	CreatedAt   time.Time
+}
+
+func NewHeldNotification(token DeviceToken, eventKey, riskType, referenceID string, releaseAt time.Time) *HeldNotification {
+	n := &HeldNotification{
+		ID:          uuid.New(),
+		FCMToken:    token.FCMToken,
+		Language:    token.Language,
+		EventKey:    eventKey,
+		RiskType:    riskType,
+		ReferenceID: referenceID,
+		ReleaseAt:   releaseAt,
+		CreatedAt:   time.Now(),
+	}
+
+	if token.UserID != uuid.Nil {
+		userID := token.UserID
+		n.UserID = &userID
+	}
+
+	if token.DeviceID != "" {
+		deviceID := token.DeviceID
+		n.DeviceID = &deviceID
+	}
+
+	return n
+}
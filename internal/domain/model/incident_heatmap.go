@@ -0,0 +1,22 @@
+package model
+
+// HeatmapPoint sums the reports of one risk type made at one spot.
+type HeatmapPoint struct {
+	Latitude     float64
+	Longitude    float64
+	Weight       float64
+	IncidentType string
+	ReportCount  int
+}
+
+// HeatmapIncidentGroup counts the reports of one risk type and topic made at
+// one spot. Decay sums the part of each report's recency that fades,
+// 0.5^(age/half-life), so the group can be weighed without its reports.
+type HeatmapIncidentGroup struct {
+	Latitude    float64
+	Longitude   float64
+	RiskType    string
+	RiskTopic   string
+	ReportCount int
+	Decay       float64
+}
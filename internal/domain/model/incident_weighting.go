@@ -0,0 +1,133 @@
+package model
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Defaults for risk types without a rule of their own.
+	DefaultIncidentSeverity               = 1.0
+	DefaultIncidentHalfLifeDays           = 7.0
+	DefaultIncidentProximityFalloffMeters = 1000.0
+
+	// maxIncidentRecencyBoost is how much more a brand-new incident weighs
+	// than a long-past one.
+	maxIncidentRecencyBoost = 3.0
+	maxIncidentSeverity     = 10.0
+)
+
+// IncidentWeightRule sets how heavily the incidents of a risk type, or of one
+// of its topics, count towards route, heatmap and danger zone scores.
+type IncidentWeightRule struct {
+	ID          uuid.UUID
+	RiskTypeID  uuid.UUID
+	RiskTopicID *uuid.UUID
+	// RiskType and RiskTopic are the names incidents are matched on.
+	RiskType  string
+	RiskTopic string
+	// Severity multiplies every incident's weight; 1 is an ordinary
+	// incident.
+	Severity float64
+	// HalfLifeDays is how long it takes the extra weight of a recent
+	// incident to halve.
+	HalfLifeDays float64
+	// ProximityFalloffMeters is the distance from a route at which an
+	// incident stops weighing extra for being close.
+	ProximityFalloffMeters float64
+	UpdatedAt              time.Time
+}
+
+func (r *IncidentWeightRule) Validate() error {
+	if r.RiskTypeID == uuid.Nil {
+		return errors.New("risk type is required")
+	}
+	if r.Severity <= 0 || r.Severity > maxIncidentSeverity {
+		return errors.New("severity must be above 0 and at most 10")
+	}
+	if r.HalfLifeDays <= 0 {
+		return errors.New("half-life must be positive")
+	}
+	if r.ProximityFalloffMeters < 0 {
+		return errors.New("proximity falloff cannot be negative")
+	}
+	return nil
+}
+
+// Recency is 3 for an incident that just happened and approaches 1 as it
+// ages, the extra weight halving every half-life.
+func (r *IncidentWeightRule) Recency(age time.Duration) float64 {
+	days := max(age.Hours()/hoursPerDay, 0)
+	return 1 + (maxIncidentRecencyBoost-1)*math.Pow(0.5, days/r.HalfLifeDays) //nolint:mnd // half-life decay
+}
+
+// Proximity is 2 for an incident on the route, falling linearly to 1 at the
+// falloff distance.
+func (r *IncidentWeightRule) Proximity(distanceMeters float64) float64 {
+	if r.ProximityFalloffMeters <= 0 {
+		return 1
+	}
+	return 1 + math.Max(0, 1-distanceMeters/r.ProximityFalloffMeters)
+}
+
+func DefaultIncidentWeightRule() IncidentWeightRule {
+	return IncidentWeightRule{
+		Severity:               DefaultIncidentSeverity,
+		HalfLifeDays:           DefaultIncidentHalfLifeDays,
+		ProximityFalloffMeters: DefaultIncidentProximityFalloffMeters,
+	}
+}
+
+// IncidentWeighting picks the rule for an incident: its topic's if there is
+// one, else its risk type's, else the default.
+type IncidentWeighting struct {
+	byType  map[string]IncidentWeightRule
+	byTopic map[[2]string]IncidentWeightRule
+}
+
+func NewIncidentWeighting(rules []*IncidentWeightRule) *IncidentWeighting {
+	w := &IncidentWeighting{
+		byType:  make(map[string]IncidentWeightRule),
+		byTopic: make(map[[2]string]IncidentWeightRule),
+	}
+	for _, rule := range rules {
+		if rule.RiskTopicID != nil {
+			w.byTopic[[2]string{rule.RiskType, rule.RiskTopic}] = *rule
+		} else {
+			w.byType[rule.RiskType] = *rule
+		}
+	}
+	return w
+}
+
+func (w *IncidentWeighting) Rule(riskType, riskTopic string) IncidentWeightRule {
+	if rule, ok := w.byTopic[[2]string{riskType, riskTopic}]; ok {
+		return rule
+	}
+	if rule, ok := w.byType[riskType]; ok {
+		return rule
+	}
+	return DefaultIncidentWeightRule()
+}
+
+// Weight is an incident's severity times its recency.
+func (w *IncidentWeighting) Weight(riskType, riskTopic string, age time.Duration) float64 {
+	rule := w.Rule(riskType, riskTopic)
+	return rule.Severity * rule.Recency(age)
+}
+
+// GroupWeight is the summed Weight of count incidents of one risk type and
+// topic whose recency decays add up to decay.
+func (w *IncidentWeighting) GroupWeight(riskType, riskTopic string, count int, decay float64) float64 {
+	rule := w.Rule(riskType, riskTopic)
+	return rule.Severity * (float64(count) + (maxIncidentRecencyBoost-1)*decay)
+}
+
+// RouteWeight also weighs how close to the route the incident happened.
+func (w *IncidentWeighting) RouteWeight(riskType, riskTopic string, age time.Duration, distanceMeters float64) float64 {
+	rule := w.Rule(riskType, riskTopic)
+	return rule.Severity * rule.Recency(age) * rule.Proximity(distanceMeters)
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIncidentWeighting_TopicRuleOverridesType(t *testing.T) {
+	topicID := uuid.New()
+	weighting := NewIncidentWeighting([]*IncidentWeightRule{
+		{RiskType: "crime", Severity: 2, HalfLifeDays: 14, ProximityFalloffMeters: 500},
+		{RiskType: "crime", RiskTopicID: &topicID, RiskTopic: "tiroteio", Severity: 3, HalfLifeDays: 7, ProximityFalloffMeters: 500},
+	})
+
+	assert.InDelta(t, 3.0, weighting.Rule("crime", "tiroteio").Severity, 0.001)
+	assert.InDelta(t, 2.0, weighting.Rule("crime", "furto").Severity, 0.001)
+	assert.InDelta(t, DefaultIncidentSeverity, weighting.Rule("fire", "tiroteio").Severity, 0.001, "topics only match within their type")
+
+	// A fresh incident weighs three times its severity, halving its extra
+	// weight every half-life.
+	assert.InDelta(t, 9.0, weighting.Weight("crime", "tiroteio", 0), 0.001)
+	assert.InDelta(t, 6.0, weighting.Weight("crime", "tiroteio", 7*24*time.Hour), 0.001)
+	assert.InDelta(t, 4.0, weighting.Weight("crime", "furto", 14*24*time.Hour), 0.001)
+
+	// Right on the route it counts double, fading to nothing extra at the
+	// falloff distance.
+	assert.InDelta(t, 8.0, weighting.RouteWeight("crime", "furto", 14*24*time.Hour, 0), 0.001)
+	assert.InDelta(t, 6.0, weighting.RouteWeight("crime", "furto", 14*24*time.Hour, 250), 0.001)
+	assert.InDelta(t, 4.0, weighting.RouteWeight("crime", "furto", 14*24*time.Hour, 800), 0.001)
+}
+
+func TestIncidentWeightRule_Validate(t *testing.T) {
+	rule := DefaultIncidentWeightRule()
+	assert.Error(t, rule.Validate(), "a risk type is required")
+
+	rule.RiskTypeID = uuid.New()
+	assert.NoError(t, rule.Validate())
+
+	rule.Severity = 11
+	assert.Error(t, rule.Validate())
+
+	rule.Severity, rule.HalfLifeDays = 2, 0
+	assert.Error(t, rule.Validate())
+}
@@ -0,0 +1,193 @@
+package model
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ManualDangerZoneCellPrefix = "manual:"
+
+	minPolygonVertices = 3
+	maxPolygonVertices = 500
+	maxManualZoneText  = 500
+	// routeSampleMeters is the spacing of the points checked along a route
+	// leg against a zone's polygon.
+	routeSampleMeters = 50.0
+)
+
+// GeoPoint is a polygon vertex.
+type GeoPoint struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// ManualDangerZone is an area marked dangerous by an authority, such as a
+// flood plain in the rainy season, rather than derived from reports.
+type ManualDangerZone struct {
+	ID         uuid.UUID  `json:"id"`
+	Polygon    []GeoPoint `json:"polygon"`
+	RiskLevel  string     `json:"risk_level"`
+	Reason     string     `json:"reason"`
+	Source     string     `json:"source"`
+	ValidFrom  time.Time  `json:"valid_from"`
+	ValidUntil *time.Time `json:"valid_until,omitempty"`
+	CreatedBy  uuid.UUID  `json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+func (z *ManualDangerZone) Validate() error {
+	if len(z.Polygon) < minPolygonVertices || len(z.Polygon) > maxPolygonVertices {
+		return errors.New("danger zone polygon needs between 3 and 500 vertices")
+	}
+	for _, p := range z.Polygon {
+		if p.Latitude < -90 || p.Latitude > 90 || p.Longitude < -180 || p.Longitude > 180 {
+			return errors.New("danger zone polygon has an invalid coordinate")
+		}
+	}
+	switch z.RiskLevel {
+	case "low", "medium", "high", "critical":
+	default:
+		return errors.New("danger zone risk level must be low, medium, high or critical")
+	}
+	if z.Reason == "" || len(z.Reason) > maxManualZoneText {
+		return errors.New("danger zone reason is required and limited to 500 characters")
+	}
+	if z.Source == "" || len(z.Source) > maxManualZoneText {
+		return errors.New("danger zone source is required and limited to 500 characters")
+	}
+	if z.ValidUntil != nil && !z.ValidUntil.After(z.ValidFrom) {
+		return errors.New("danger zone valid_until must be after valid_from")
+	}
+	return nil
+}
+
+func (z *ManualDangerZone) IsActive(at time.Time) bool {
+	return !at.Before(z.ValidFrom) && (z.ValidUntil == nil || at.Before(*z.ValidUntil))
+}
+
+func (z *ManualDangerZone) Bounds() BoundingBox {
+	b := BoundingBox{MinLat: 90, MinLon: 180, MaxLat: -90, MaxLon: -180}
+	for _, p := range z.Polygon {
+		b.MinLat = math.Min(b.MinLat, p.Latitude)
+		b.MinLon = math.Min(b.MinLon, p.Longitude)
+		b.MaxLat = math.Max(b.MaxLat, p.Latitude)
+		b.MaxLon = math.Max(b.MaxLon, p.Longitude)
+	}
+	return b
+}
+
+// Contains reports whether the point is inside the polygon, by ray casting.
+func (z *ManualDangerZone) Contains(lat, lon float64) bool {
+	inside := false
+	for i, j := 0, len(z.Polygon)-1; i < len(z.Polygon); j, i = i, i+1 {
+		a, b := z.Polygon[i], z.Polygon[j]
+		if (a.Latitude > lat) != (b.Latitude > lat) &&
+			lon < (b.Longitude-a.Longitude)*(lat-a.Latitude)/(b.Latitude-a.Latitude)+a.Longitude {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// DistanceMeters is how far the point is from the polygon, zero inside it.
+// Edges are measured on a flat projection around the point, which is
+// accurate enough at city scale.
+func (z *ManualDangerZone) DistanceMeters(lat, lon float64) float64 {
+	if z.Contains(lat, lon) {
+		return 0
+	}
+
+	metersPerDegreeLon := metersPerDegreeLat * math.Cos(lat*math.Pi/180) //nolint:mnd // degrees to radians
+	project := func(p GeoPoint) (float64, float64) {
+		return (p.Longitude - lon) * metersPerDegreeLon, (p.Latitude - lat) * metersPerDegreeLat
+	}
+
+	best := math.Inf(1)
+	for i, j := 0, len(z.Polygon)-1; i < len(z.Polygon); j, i = i, i+1 {
+		ax, ay := project(z.Polygon[j])
+		bx, by := project(z.Polygon[i])
+		best = math.Min(best, distanceToSegment(ax, ay, bx, by))
+	}
+	return best
+}
+
+// Centroid is the mean of the vertices, used to place the zone on a map.
+func (z *ManualDangerZone) Centroid() (float64, float64) {
+	var lat, lon float64
+	for _, p := range z.Polygon {
+		lat += p.Latitude
+		lon += p.Longitude
+	}
+	n := float64(len(z.Polygon))
+	return lat / n, lon / n
+}
+
+// NearRoute reports whether any point of the route passes within
+// bufferMeters of the polygon.
+func (z *ManualDangerZone) NearRoute(waypoints []Waypoint, bufferMeters float64) bool {
+	for i, wp := range waypoints {
+		if z.DistanceMeters(wp.Latitude, wp.Longitude) <= bufferMeters {
+			return true
+		}
+		if i == 0 {
+			continue
+		}
+
+		prev := waypoints[i-1]
+		steps := int(DistanceMeters(prev.Latitude, prev.Longitude, wp.Latitude, wp.Longitude) / routeSampleMeters)
+		for s := 1; s < steps; s++ {
+			f := float64(s) / float64(steps)
+			lat := prev.Latitude + (wp.Latitude-prev.Latitude)*f
+			lon := prev.Longitude + (wp.Longitude-prev.Longitude)*f
+			if z.DistanceMeters(lat, lon) <= bufferMeters {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ToDangerZone presents the manual zone alongside the computed ones.
+func (z *ManualDangerZone) ToDangerZone() *DangerZone {
+	lat, lon := z.Centroid()
+
+	zone := NewDangerZone(lat, lon, ManualDangerZoneCellPrefix+z.ID.String())
+	zone.ID = z.ID
+	zone.RiskLevel = z.RiskLevel
+	zone.RiskScore = manualZoneRiskScore(z.RiskLevel)
+	zone.CalculatedAt = z.UpdatedAt
+	if z.ValidUntil != nil {
+		zone.ExpiresAt = *z.ValidUntil
+	}
+	zone.Manual = z
+	return zone
+}
+
+// manualZoneRiskScore places a manual level in the middle of the score band
+// computed zones of that level fall in.
+func manualZoneRiskScore(level string) float64 {
+	switch level {
+	case "critical":
+		return 9.0
+	case "high":
+		return 7.0
+	case "medium":
+		return 5.0
+	default:
+		return 2.0
+	}
+}
+
+func distanceToSegment(ax, ay, bx, by float64) float64 {
+	dx, dy := bx-ax, by-ay
+	if dx == 0 && dy == 0 {
+		return math.Hypot(ax, ay)
+	}
+	t := math.Max(0, math.Min(1, -(ax*dx+ay*dy)/(dx*dx+dy*dy)))
+	return math.Hypot(ax+t*dx, ay+t*dy)
+}
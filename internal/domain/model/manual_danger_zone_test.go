@@ -0,0 +1,70 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// squareZone is roughly 220 m by 220 m around central Luanda.
+func squareZone() *ManualDangerZone {
+	return &ManualDangerZone{
+		Polygon: []GeoPoint{
+			{Latitude: -8.830, Longitude: 13.240},
+			{Latitude: -8.830, Longitude: 13.242},
+			{Latitude: -8.828, Longitude: 13.242},
+			{Latitude: -8.828, Longitude: 13.240},
+		},
+		RiskLevel: "high",
+		Reason:    "flooding",
+		Source:    "Protecção Civil",
+		ValidFrom: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestManualDangerZone_ContainsAndDistance(t *testing.T) {
+	zone := squareZone()
+
+	assert.True(t, zone.Contains(-8.829, 13.241))
+	assert.False(t, zone.Contains(-8.827, 13.241))
+	assert.InDelta(t, 0, zone.DistanceMeters(-8.829, 13.241), 0.001)
+
+	// 0.001 degrees north of the northern edge is about 110 m.
+	assert.InDelta(t, 110.6, zone.DistanceMeters(-8.827, 13.241), 1)
+}
+
+func TestManualDangerZone_NearRoute(t *testing.T) {
+	zone := squareZone()
+
+	// Both ends are far from the zone but the leg between them crosses it.
+	crossing := []Waypoint{
+		{Latitude: -8.829, Longitude: 13.230},
+		{Latitude: -8.829, Longitude: 13.250},
+	}
+	assert.True(t, zone.NearRoute(crossing, 0))
+
+	parallel := []Waypoint{
+		{Latitude: -8.825, Longitude: 13.230},
+		{Latitude: -8.825, Longitude: 13.250},
+	}
+	assert.False(t, zone.NearRoute(parallel, 50))
+	assert.True(t, zone.NearRoute(parallel, 400))
+}
+
+func TestManualDangerZone_ValidateAndActive(t *testing.T) {
+	zone := squareZone()
+	assert.NoError(t, zone.Validate())
+
+	until := zone.ValidFrom.Add(-time.Hour)
+	zone.ValidUntil = &until
+	assert.Error(t, zone.Validate())
+
+	until = zone.ValidFrom.Add(24 * time.Hour)
+	assert.True(t, zone.IsActive(zone.ValidFrom.Add(time.Hour)))
+	assert.False(t, zone.IsActive(until))
+	assert.False(t, zone.IsActive(zone.ValidFrom.Add(-time.Second)))
+
+	zone.Polygon = zone.Polygon[:2]
+	assert.Error(t, zone.Validate())
+}
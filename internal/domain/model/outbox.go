@@ -0,0 +1,32 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboxMessage is a serialized domain event waiting to be delivered to the
+// registered handlers. DeliveredHandlers names the handlers that already
+// handled it, which a retry skips.
+type OutboxMessage struct {
+	ID                uuid.UUID
+	EventName         string
+	Payload           []byte
+	Attempts          int
+	NextAttemptAt     time.Time
+	LastError         string
+	DeliveredHandlers []string
+	CreatedAt         time.Time
+}
+
+func NewOutboxMessage(eventName string, payload []byte) *OutboxMessage {
+	now := time.Now()
+	return &OutboxMessage{
+		ID:            uuid.New(),
+		EventName:     eventName,
+		Payload:       payload,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+}
@@ -0,0 +1,101 @@
+package model
+
+import "math"
+
+// maxRiskFieldCells bounds the memory of a field; larger areas get coarser
+// cells.
+const maxRiskFieldCells = 250_000
+
+// RiskField is a grid of risk values over an area, used to price road
+// segments by the incidents and danger zones around them.
+type RiskField struct {
+	bounds     BoundingBox
+	cellLat    float64
+	cellLon    float64
+	rows, cols int
+	values     []float64
+}
+
+// NewRiskField covers bounds with cells of about cellMeters.
+func NewRiskField(bounds BoundingBox, cellMeters float64) *RiskField {
+	centerLat, _ := bounds.Center()
+	heightMeters := (bounds.MaxLat - bounds.MinLat) * metersPerDegreeLat
+	widthMeters := (bounds.MaxLon - bounds.MinLon) * metersPerDegreeLat * math.Cos(centerLat*math.Pi/180) //nolint:mnd // degrees to radians
+
+	if cells := (heightMeters / cellMeters) * (widthMeters / cellMeters); cells > maxRiskFieldCells {
+		cellMeters *= math.Sqrt(cells / maxRiskFieldCells)
+	}
+
+	f := &RiskField{
+		bounds:  bounds,
+		cellLat: cellMeters / metersPerDegreeLat,
+		cellLon: cellMeters / (metersPerDegreeLat * math.Cos(centerLat*math.Pi/180)), //nolint:mnd // degrees to radians
+		rows:    int(math.Ceil(heightMeters/cellMeters)) + 1,
+		cols:    int(math.Ceil(widthMeters/cellMeters)) + 1,
+	}
+	f.values = make([]float64, f.rows*f.cols)
+	return f
+}
+
+// AddPoint spreads weight around a point, falling off linearly to zero at
+// radiusMeters.
+func (f *RiskField) AddPoint(lat, lon, weight, radiusMeters float64) {
+	f.add(BoundingBoxAround(lat, lon, radiusMeters), weight, radiusMeters, func(cellLat, cellLon float64) float64 {
+		return DistanceMeters(lat, lon, cellLat, cellLon)
+	})
+}
+
+// AddZone spreads weight over a danger zone: in full within reachMeters of
+// its center, or inside a manual zone's polygon, and falling off over
+// falloffMeters beyond that.
+func (f *RiskField) AddZone(zone *DangerZone, weight, reachMeters, falloffMeters float64) {
+	area := BoundingBoxAround(zone.CellLat, zone.CellLon, reachMeters+falloffMeters)
+	if zone.Manual != nil {
+		bounds := zone.Manual.Bounds()
+		area = BoundingBoxAround(bounds.MinLat, bounds.MinLon, falloffMeters)
+		corner := BoundingBoxAround(bounds.MaxLat, bounds.MaxLon, falloffMeters)
+		area.MaxLat, area.MaxLon = corner.MaxLat, corner.MaxLon
+		reachMeters = 0
+	}
+
+	f.add(area, weight, falloffMeters, func(cellLat, cellLon float64) float64 {
+		return math.Max(0, zone.DistanceFrom(cellLat, cellLon)-reachMeters)
+	})
+}
+
+func (f *RiskField) add(area BoundingBox, weight, falloffMeters float64, distance func(lat, lon float64) float64) {
+	minRow, minCol := f.cell(area.MinLat, area.MinLon)
+	maxRow, maxCol := f.cell(area.MaxLat, area.MaxLon)
+	minRow, minCol = max(minRow, 0), max(minCol, 0)
+	maxRow, maxCol = min(maxRow, f.rows-1), min(maxCol, f.cols-1)
+
+	for row := minRow; row <= maxRow; row++ {
+		for col := minCol; col <= maxCol; col++ {
+			cellLat := f.bounds.MinLat + (float64(row)+0.5)*f.cellLat //nolint:mnd // cell center
+			cellLon := f.bounds.MinLon + (float64(col)+0.5)*f.cellLon //nolint:mnd // cell center
+
+			d := distance(cellLat, cellLon)
+			if d >= falloffMeters && d > 0 {
+				continue
+			}
+			share := 1.0
+			if falloffMeters > 0 {
+				share = 1 - d/falloffMeters
+			}
+			f.values[row*f.cols+col] += weight * share
+		}
+	}
+}
+
+// At returns the risk at a point; points outside the field carry none.
+func (f *RiskField) At(lat, lon float64) float64 {
+	row, col := f.cell(lat, lon)
+	if row < 0 || col < 0 || row >= f.rows || col >= f.cols {
+		return 0
+	}
+	return f.values[row*f.cols+col]
+}
+
+func (f *RiskField) cell(lat, lon float64) (int, int) {
+	return int(math.Floor((lat - f.bounds.MinLat) / f.cellLat)), int(math.Floor((lon - f.bounds.MinLon) / f.cellLon))
+}
@@ -0,0 +1,230 @@
+package model
+
+import (
+	"math"
+	"sort"
+)
+
+// RoadClass groups OpenStreetMap highway types by how they can be used.
+type RoadClass uint8
+
+const (
+	RoadClassMotorway RoadClass = iota
+	RoadClassTrunk
+	RoadClassPrimary
+	RoadClassSecondary
+	RoadClassTertiary
+	RoadClassResidential
+	RoadClassService
+	RoadClassTrack
+	// RoadClassPedestrian covers footways, paths, steps and pedestrian
+	// streets, which vehicles cannot use.
+	RoadClassPedestrian
+)
+
+const (
+	// roadIndexCellDegrees is the size of the spatial index cells, about
+	// 1.1 km at the equator.
+	roadIndexCellDegrees = 0.01
+	WalkingSpeedKmh      = 5.0
+)
+
+// RoadClassFromHighway maps an OSM highway tag to its class. The bool is
+// false for highways that are not roads, such as proposed or abandoned ones.
+func RoadClassFromHighway(highway string) (RoadClass, bool) {
+	switch highway {
+	case "motorway", "motorway_link":
+		return RoadClassMotorway, true
+	case "trunk", "trunk_link":
+		return RoadClassTrunk, true
+	case "primary", "primary_link":
+		return RoadClassPrimary, true
+	case "secondary", "secondary_link":
+		return RoadClassSecondary, true
+	case "tertiary", "tertiary_link":
+		return RoadClassTertiary, true
+	case "residential", "unclassified", "living_street", "road":
+		return RoadClassResidential, true
+	case "service":
+		return RoadClassService, true
+	case "track":
+		return RoadClassTrack, true
+	case "pedestrian", "footway", "path", "steps", "cycleway", "bridleway":
+		return RoadClassPedestrian, true
+	default:
+		return 0, false
+	}
+}
+
+// DrivingSpeedKmh is the typical driving speed on the class when the road
+// has no speed limit of its own; it is below the legal limits because of
+// Luanda's traffic.
+func (c RoadClass) DrivingSpeedKmh() float64 {
+	switch c {
+	case RoadClassMotorway:
+		return 80
+	case RoadClassTrunk:
+		return 60
+	case RoadClassPrimary:
+		return 45
+	case RoadClassSecondary:
+		return 35
+	case RoadClassTertiary:
+		return 30
+	case RoadClassResidential:
+		return 20
+	case RoadClassService, RoadClassTrack:
+		return 15
+	case RoadClassPedestrian:
+		return WalkingSpeedKmh
+	default:
+		return WalkingSpeedKmh
+	}
+}
+
+// RoadEdge is a directed road segment between two graph nodes.
+type RoadEdge struct {
+	To           int32
+	LengthMeters float32
+	Class        RoadClass
+	// AgainstOneway is set on the edge that runs against a one-way road,
+	// which only pedestrians may use.
+	AgainstOneway bool
+	// MaxSpeedKmh is the road's posted limit, zero when unknown.
+	MaxSpeedKmh float32
+}
+
+// DrivingSpeedKmh is the speed a car travels the edge at.
+func (e RoadEdge) DrivingSpeedKmh() float64 {
+	speed := e.Class.DrivingSpeedKmh()
+	if e.MaxSpeedKmh > 0 {
+		speed = math.Min(speed, float64(e.MaxSpeedKmh))
+	}
+	return speed
+}
+
+// RoadGraph is a read-only road network. Edges are stored per source node in
+// one slice, and nodes are indexed on a coarse grid to snap points to roads.
+type RoadGraph struct {
+	lats    []float64
+	lons    []float64
+	offsets []int32
+	edges   []RoadEdge
+	index   map[[2]int32][]int32
+}
+
+func (g *RoadGraph) NodeCount() int {
+	return len(g.lats)
+}
+
+func (g *RoadGraph) EdgeCount() int {
+	return len(g.edges)
+}
+
+func (g *RoadGraph) Node(node int32) (float64, float64) {
+	return g.lats[node], g.lons[node]
+}
+
+// Edges returns the edges leaving the node.
+func (g *RoadGraph) Edges(node int32) []RoadEdge {
+	return g.edges[g.offsets[node]:g.offsets[node+1]]
+}
+
+// Nearest returns the node closest to the point, within maxMeters, that has
+// at least one edge for which usable returns true.
+func (g *RoadGraph) Nearest(lat, lon, maxMeters float64, usable func(RoadEdge) bool) (int32, bool) {
+	key := roadIndexKey(lat, lon)
+	best, bestDistance := int32(-1), maxMeters
+
+	// The surrounding cells cover at least one cell width in every
+	// direction, which is more than maxMeters for sensible values.
+	for dLat := int32(-1); dLat <= 1; dLat++ {
+		for dLon := int32(-1); dLon <= 1; dLon++ {
+			for _, node := range g.index[[2]int32{key[0] + dLat, key[1] + dLon}] {
+				d := DistanceMeters(lat, lon, g.lats[node], g.lons[node])
+				if d > bestDistance || !g.hasUsableEdge(node, usable) {
+					continue
+				}
+				best, bestDistance = node, d
+			}
+		}
+	}
+	return best, best >= 0
+}
+
+func (g *RoadGraph) hasUsableEdge(node int32, usable func(RoadEdge) bool) bool {
+	for _, edge := range g.Edges(node) {
+		if usable(edge) {
+			return true
+		}
+	}
+	return false
+}
+
+func roadIndexKey(lat, lon float64) [2]int32 {
+	return [2]int32{int32(math.Floor(lat / roadIndexCellDegrees)), int32(math.Floor(lon / roadIndexCellDegrees))}
+}
+
+// RoadGraphBuilder collects nodes and ways and turns them into a RoadGraph.
+type RoadGraphBuilder struct {
+	lats  []float64
+	lons  []float64
+	from  []int32
+	edges []RoadEdge
+}
+
+func (b *RoadGraphBuilder) AddNode(lat, lon float64) int32 {
+	b.lats = append(b.lats, lat)
+	b.lons = append(b.lons, lon)
+	return int32(len(b.lats) - 1) //nolint:gosec // graphs stay far below 2^31 nodes
+}
+
+// AddWay links consecutive nodes of a road. oneway is 1 when traffic flows
+// in the order of the nodes, -1 against it and 0 both ways.
+func (b *RoadGraphBuilder) AddWay(nodes []int32, class RoadClass, oneway int, maxSpeedKmh float64) {
+	for i := 1; i < len(nodes); i++ {
+		from, to := nodes[i-1], nodes[i]
+		if from == to {
+			continue
+		}
+		length := float32(DistanceMeters(b.lats[from], b.lons[from], b.lats[to], b.lons[to]))
+
+		b.from = append(b.from, from, to)
+		b.edges = append(b.edges,
+			RoadEdge{To: to, LengthMeters: length, Class: class, AgainstOneway: oneway < 0, MaxSpeedKmh: float32(maxSpeedKmh)},
+			RoadEdge{To: from, LengthMeters: length, Class: class, AgainstOneway: oneway > 0, MaxSpeedKmh: float32(maxSpeedKmh)},
+		)
+	}
+}
+
+func (b *RoadGraphBuilder) Build() *RoadGraph {
+	order := make([]int, len(b.edges))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return b.from[order[i]] < b.from[order[j]] })
+
+	g := &RoadGraph{
+		lats:    b.lats,
+		lons:    b.lons,
+		offsets: make([]int32, len(b.lats)+1),
+		edges:   make([]RoadEdge, len(b.edges)),
+		index:   make(map[[2]int32][]int32),
+	}
+	for i, idx := range order {
+		g.edges[i] = b.edges[idx]
+		g.offsets[b.from[idx]+1]++
+	}
+	for i := 1; i < len(g.offsets); i++ {
+		g.offsets[i] += g.offsets[i-1]
+	}
+
+	for node := range g.lats {
+		if g.offsets[node] == g.offsets[node+1] {
+			continue
+		}
+		key := roadIndexKey(g.lats[node], g.lons[node])
+		g.index[key] = append(g.index[key], int32(node)) //nolint:gosec // see AddNode
+	}
+	return g
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"math"
 	"time"
 
 	"github.com/google/uuid"
@@ -23,6 +24,8 @@ const (
 	lowRiskThreshold      = 60.0
 	moderateRiskThreshold = 40.0
 	highRiskThreshold     = 20.0
+
+	minWaypoints = 2
 )
 
 type Waypoint struct {
@@ -41,6 +44,8 @@ type IncidentNearRoute struct {
 	CreatedAt    time.Time
 	DaysAgo      int
 	WeightFactor float64
+	// IsPrivate incidents weigh on the route but are never listed.
+	IsPrivate bool
 }
 
 type SafeRoute struct {
@@ -49,6 +54,7 @@ type SafeRoute struct {
 	OriginLon         float64
 	DestinationLat    float64
 	DestinationLon    float64
+	Mode              TravelMode
 	Waypoints         []Waypoint
 	DistanceKm        float64
 	EstimatedDuration int
@@ -56,7 +62,11 @@ type SafeRoute struct {
 	RiskLevel         RiskLevel
 	IncidentCount     int
 	Incidents         []IncidentNearRoute
-	CalculatedAt      time.Time
+	ManualZones       []*ManualDangerZone
+	// Labels says what the route is best at among the alternatives returned
+	// with it.
+	Labels       []RouteLabel
+	CalculatedAt time.Time
 }
 
 func NewSafeRoute(originLat, originLon, destLat, destLon float64) *SafeRoute {
@@ -73,7 +83,7 @@ func NewSafeRoute(originLat, originLon, destLat, destLon float64) *SafeRoute {
 }
 
 func (sr *SafeRoute) CalculateSafetyScore() {
-	if sr.IncidentCount == 0 {
+	if sr.IncidentCount == 0 && len(sr.ManualZones) == 0 {
 		sr.SafetyScore = perfectScore
 		sr.RiskLevel = RiskLevelVeryLow
 		return
@@ -83,6 +93,9 @@ func (sr *SafeRoute) CalculateSafetyScore() {
 	for _, incident := range sr.Incidents {
 		totalWeight += incident.WeightFactor
 	}
+	for _, zone := range sr.ManualZones {
+		totalWeight += manualZoneRouteWeight(zone.RiskLevel)
+	}
 
 	penalty := totalWeight * penaltyMultiplier
 	if penalty > maxPenalty {
@@ -113,7 +126,76 @@ func (sr *SafeRoute) AddWaypoint(lat, lon float64, sequence int) {
 	})
 }
 
+// AddManualZones records the manual danger zones the route crosses; the
+// safety score has to be recalculated afterwards.
+func (sr *SafeRoute) AddManualZones(zones []*ManualDangerZone) {
+	sr.ManualZones = append(sr.ManualZones, zones...)
+}
+
+// manualZoneRouteWeight is what crossing a manual zone costs a route, in
+// incident weights.
+func manualZoneRouteWeight(level string) float64 {
+	switch level {
+	case "critical":
+		return 4.0
+	case "high":
+		return 3.0
+	case "medium":
+		return 2.0
+	default:
+		return 1.0
+	}
+}
+
 func (sr *SafeRoute) AddIncident(incident IncidentNearRoute) {
 	sr.Incidents = append(sr.Incidents, incident)
 	sr.IncidentCount = len(sr.Incidents)
 }
+
+// SimplifyWaypoints drops the points that lie within toleranceMeters of the
+// line through their neighbours (Douglas-Peucker) and renumbers the rest.
+func SimplifyWaypoints(points []Waypoint, toleranceMeters float64) []Waypoint {
+	if len(points) <= minWaypoints {
+		return points
+	}
+
+	keep := make([]bool, len(points))
+	keep[0], keep[len(points)-1] = true, true
+
+	type span struct{ first, last int }
+	stack := []span{{0, len(points) - 1}}
+	for len(stack) > 0 {
+		s := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		farthest, farthestDistance := -1, toleranceMeters
+		for i := s.first + 1; i < s.last; i++ {
+			if d := offsetFromLine(points[i], points[s.first], points[s.last]); d > farthestDistance {
+				farthest, farthestDistance = i, d
+			}
+		}
+		if farthest < 0 {
+			continue
+		}
+		keep[farthest] = true
+		stack = append(stack, span{s.first, farthest}, span{farthest, s.last})
+	}
+
+	simplified := make([]Waypoint, 0, len(points))
+	for i, point := range points {
+		if keep[i] {
+			point.Sequence = len(simplified)
+			simplified = append(simplified, point)
+		}
+	}
+	return simplified
+}
+
+// offsetFromLine is the distance in meters from p to the segment a-b, on a
+// flat projection around p.
+func offsetFromLine(p, a, b Waypoint) float64 {
+	metersPerDegreeLon := metersPerDegreeLat * math.Cos(p.Latitude*math.Pi/180) //nolint:mnd // degrees to radians
+	ax, ay := (a.Longitude-p.Longitude)*metersPerDegreeLon, (a.Latitude-p.Latitude)*metersPerDegreeLat
+	bx, by := (b.Longitude-p.Longitude)*metersPerDegreeLon, (b.Latitude-p.Latitude)*metersPerDegreeLat
+	return distanceToSegment(ax, ay, bx, by)
+}
@@ -0,0 +1,59 @@
+package model
+
+import (
+	"math"
+	"sort"
+)
+
+// RouteLabel names what an alternative route is best at.
+type RouteLabel string
+
+const (
+	RouteLabelFastest  RouteLabel = "fastest"
+	RouteLabelSafest   RouteLabel = "safest"
+	RouteLabelBalanced RouteLabel = "balanced"
+
+	// DefaultRouteSafetyWeight ranks safety and speed equally.
+	DefaultRouteSafetyWeight = 0.5
+)
+
+// RankSafeRoutes labels the fastest, safest and balanced alternatives and
+// sorts the routes best first. safetyWeight runs from 0, which ranks by
+// duration alone, to 1, which ranks by safety score alone.
+func RankSafeRoutes(routes []*SafeRoute, safetyWeight float64) {
+	if len(routes) == 0 {
+		return
+	}
+
+	fastest, safest, balanced := routes[0], routes[0], routes[0]
+	for _, route := range routes {
+		route.Labels = nil
+		if route.EstimatedDuration < fastest.EstimatedDuration ||
+			(route.EstimatedDuration == fastest.EstimatedDuration && route.DistanceKm < fastest.DistanceKm) {
+			fastest = route
+		}
+	}
+	for _, route := range routes {
+		if route.SafetyScore > safest.SafetyScore ||
+			(route.SafetyScore == safest.SafetyScore && route.EstimatedDuration < safest.EstimatedDuration) {
+			safest = route
+		}
+		if routeTradeOff(route, fastest, DefaultRouteSafetyWeight) > routeTradeOff(balanced, fastest, DefaultRouteSafetyWeight) {
+			balanced = route
+		}
+	}
+	fastest.Labels = append(fastest.Labels, RouteLabelFastest)
+	safest.Labels = append(safest.Labels, RouteLabelSafest)
+	balanced.Labels = append(balanced.Labels, RouteLabelBalanced)
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		return routeTradeOff(routes[i], fastest, safetyWeight) > routeTradeOff(routes[j], fastest, safetyWeight)
+	})
+}
+
+// routeTradeOff blends the safety score with how close the route comes to
+// the fastest one's duration, both on a 0-1 scale.
+func routeTradeOff(route, fastest *SafeRoute, safetyWeight float64) float64 {
+	speed := float64(max(fastest.EstimatedDuration, 1)) / float64(max(route.EstimatedDuration, 1))
+	return safetyWeight*route.SafetyScore/perfectScore + (1-safetyWeight)*math.Min(speed, 1)
+}
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRankSafeRoutes(t *testing.T) {
+	direct := &SafeRoute{EstimatedDuration: 10, DistanceKm: 4, SafetyScore: 40}
+	detour := &SafeRoute{EstimatedDuration: 12, DistanceKm: 5, SafetyScore: 80}
+	longWay := &SafeRoute{EstimatedDuration: 25, DistanceKm: 9, SafetyScore: 95}
+
+	routes := []*SafeRoute{longWay, direct, detour}
+	RankSafeRoutes(routes, DefaultRouteSafetyWeight)
+
+	assert.Equal(t, []RouteLabel{RouteLabelFastest}, direct.Labels)
+	assert.Equal(t, []RouteLabel{RouteLabelBalanced}, detour.Labels)
+	assert.Equal(t, []RouteLabel{RouteLabelSafest}, longWay.Labels)
+	assert.Equal(t, []*SafeRoute{detour, direct, longWay}, routes)
+
+	RankSafeRoutes(routes, 0)
+	assert.Equal(t, direct, routes[0])
+
+	RankSafeRoutes(routes, 1)
+	assert.Equal(t, longWay, routes[0])
+}
+
+func TestRankSafeRoutes_SingleRouteCarriesEveryLabel(t *testing.T) {
+	route := &SafeRoute{EstimatedDuration: 10, SafetyScore: 70}
+	RankSafeRoutes([]*SafeRoute{route}, DefaultRouteSafetyWeight)
+
+	assert.Equal(t, []RouteLabel{RouteLabelFastest, RouteLabelSafest, RouteLabelBalanced}, route.Labels)
+}
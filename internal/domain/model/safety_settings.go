@@ -10,8 +10,27 @@ import (
 const (
 	defaultAlertRadiusMins  = 1000
 	defaultReportRadiusMins = 500
+
+	minNotificationRadiusMeters = 100
+	maxNotificationRadiusMeters = 10000
+
+	localTimezoneName   = "Africa/Luanda"
+	localTimezoneOffset = 1 * 60 * 60 // WAT, UTC+1 with no daylight saving
+	minutesPerHour      = 60
+	minutesPerDay       = 24 * minutesPerHour
 )
 
+// LocalTimezone returns the Africa/Luanda location used to interpret the
+// wall-clock times stored in safety settings. It falls back to a fixed UTC+1
+// zone when the tz database is not available in the container.
+func LocalTimezone() *time.Location {
+	loc, err := time.LoadLocation(localTimezoneName)
+	if err != nil {
+		return time.FixedZone("WAT", localTimezoneOffset)
+	}
+	return loc
+}
+
 type ProfileVisibility string
 
 const (
@@ -31,6 +50,7 @@ type SafetySettings struct {
 	NotificationAlertRadiusMins  int
 	NotificationReportTypes      []string
 	NotificationReportRadiusMins int
+	RiskPreferences              []RiskNotificationPreference
 
 	LocationSharingEnabled bool
 	LocationHistoryEnabled bool
@@ -53,6 +73,18 @@ type SafetySettings struct {
 	UpdatedAt time.Time
 }
 
+// RiskNotificationPreference opts a risk type, or a single topic of it, in
+// or out of notifications. A preference without a topic covers the whole
+// type; one with a topic takes precedence for that topic, so a user can mute
+// "traffic" and still hear about one of its topics. RadiusMeters, when set,
+// replaces the general alert and report radius for matching notifications.
+type RiskNotificationPreference struct {
+	RiskTypeID   uuid.UUID
+	RiskTopicID  *uuid.UUID
+	Enabled      bool
+	RadiusMeters *int
+}
+
 // IsAnonymous returns true if these settings belong to an anonymous user
 func (s *SafetySettings) IsAnonymous() bool {
 	return s.AnonymousSessionID != nil && s.DeviceID != nil
@@ -167,6 +199,10 @@ func (s *SafetySettings) Validate() error {
 		return errors.New("notification_report_radius_mins must be between 100 and 10000")
 	}
 
+	if err := validateRiskPreferences(s.RiskPreferences); err != nil {
+		return err
+	}
+
 	validVisibilities := map[ProfileVisibility]bool{
 		ProfileVisibilityPublic:  true,
 		ProfileVisibilityFriends: true,
@@ -179,3 +215,120 @@ func (s *SafetySettings) Validate() error {
 
 	return nil
 }
+
+func validateRiskPreferences(prefs []RiskNotificationPreference) error {
+	seen := make(map[[2]uuid.UUID]bool, len(prefs))
+	for _, p := range prefs {
+		if p.RiskTypeID == uuid.Nil {
+			return errors.New("risk_preferences: risk_type_id is required")
+		}
+
+		key := [2]uuid.UUID{p.RiskTypeID, uuid.Nil}
+		if p.RiskTopicID != nil {
+			key[1] = *p.RiskTopicID
+		}
+		if seen[key] {
+			return errors.New("risk_preferences: duplicate preference for the same risk type and topic")
+		}
+		seen[key] = true
+
+		if p.RadiusMeters != nil && (*p.RadiusMeters < minNotificationRadiusMeters || *p.RadiusMeters > maxNotificationRadiusMeters) {
+			return errors.New("risk_preferences: radius_meters must be between 100 and 10000")
+		}
+	}
+	return nil
+}
+
+// RiskPreference returns the preference that applies to a notification about
+// riskTypeID and riskTopicID: the topic's own preference if there is one,
+// otherwise the preference for the whole type.
+func (s *SafetySettings) RiskPreference(riskTypeID, riskTopicID uuid.UUID) (RiskNotificationPreference, bool) {
+	var typeLevel *RiskNotificationPreference
+	for i := range s.RiskPreferences {
+		p := &s.RiskPreferences[i]
+		if p.RiskTypeID != riskTypeID {
+			continue
+		}
+		if p.RiskTopicID == nil {
+			typeLevel = p
+			continue
+		}
+		if riskTopicID != uuid.Nil && *p.RiskTopicID == riskTopicID {
+			return *p, true
+		}
+	}
+
+	if typeLevel != nil {
+		return *typeLevel, true
+	}
+	return RiskNotificationPreference{}, false
+}
+
+// NotificationRadiusFor reports whether notifications about the risk are
+// wanted and within which radius. baseRadius is the general alert or report
+// radius, used unless the matching preference overrides it.
+func (s *SafetySettings) NotificationRadiusFor(baseRadius int, riskTypeID, riskTopicID uuid.UUID) (int, bool) {
+	pref, ok := s.RiskPreference(riskTypeID, riskTopicID)
+	if !ok {
+		return baseRadius, true
+	}
+	if !pref.Enabled {
+		return 0, false
+	}
+	if pref.RadiusMeters != nil {
+		return *pref.RadiusMeters, true
+	}
+	return baseRadius, true
+}
+
+// IsInNightMode reports whether now falls inside the user's quiet hours.
+func (s *SafetySettings) IsInNightMode(now time.Time) bool {
+	if !s.NightModeEnabled {
+		return false
+	}
+	return isWithinDailyWindow(s.NightModeStartTime, s.NightModeEndTime, now)
+}
+
+// NightModeEndsAt returns the next moment (after now) at which quiet hours end.
+func (s *SafetySettings) NightModeEndsAt(now time.Time) time.Time {
+	return nextClockTime(s.NightModeEndTime, now)
+}
+
+// IsInHighRiskWindow reports whether now falls inside the user's high risk window.
+func (s *SafetySettings) IsInHighRiskWindow(now time.Time) bool {
+	if !s.TimeBasedAlertsEnabled {
+		return false
+	}
+	return isWithinDailyWindow(s.HighRiskStartTime, s.HighRiskEndTime, now)
+}
+
+// isWithinDailyWindow checks a daily [start, end) window expressed as Luanda
+// wall-clock times. Windows where start is after end cross midnight.
+func isWithinDailyWindow(start, end, now time.Time) bool {
+	startMin := clockMinutes(start)
+	endMin := clockMinutes(end)
+	nowMin := clockMinutes(now.In(LocalTimezone()))
+
+	if startMin == endMin {
+		return false
+	}
+
+	if startMin > endMin {
+		return nowMin >= startMin || nowMin < endMin
+	}
+
+	return nowMin >= startMin && nowMin < endMin
+}
+
+func nextClockTime(clock, now time.Time) time.Time {
+	local := now.In(LocalTimezone())
+	next := time.Date(local.Year(), local.Month(), local.Day(), clock.Hour(), clock.Minute(), 0, 0, local.Location())
+	if !next.After(local) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+func clockMinutes(t time.Time) int {
+	return (t.Hour()*minutesPerHour + t.Minute()) % minutesPerDay
+}
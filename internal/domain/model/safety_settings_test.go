@@ -0,0 +1,122 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func clock(t *testing.T, value string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse("15:04", value)
+	assert.NoError(t, err)
+	return parsed
+}
+
+func TestIsInNightMode_CrossesMidnight(t *testing.T) {
+	settings, err := NewSafetySettings(uuid.New())
+	assert.NoError(t, err)
+
+	settings.NightModeEnabled = true
+	settings.NightModeStartTime = clock(t, "22:00")
+	settings.NightModeEndTime = clock(t, "06:00")
+
+	luanda := LocalTimezone()
+
+	testCases := []struct {
+		name     string
+		now      time.Time
+		expected bool
+	}{
+		{"before start", time.Date(2025, 1, 10, 21, 59, 0, 0, luanda), false},
+		{"at start", time.Date(2025, 1, 10, 22, 0, 0, 0, luanda), true},
+		{"after midnight", time.Date(2025, 1, 11, 3, 0, 0, 0, luanda), true},
+		{"at end", time.Date(2025, 1, 11, 6, 0, 0, 0, luanda), false},
+		{"midday", time.Date(2025, 1, 11, 12, 0, 0, 0, luanda), false},
+		// 02:30 UTC is 03:30 in Luanda
+		{"utc input", time.Date(2025, 1, 11, 2, 30, 0, 0, time.UTC), true},
+		// 21:30 UTC is 22:30 in Luanda
+		{"utc evening", time.Date(2025, 1, 10, 21, 30, 0, 0, time.UTC), true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, settings.IsInNightMode(tc.now))
+		})
+	}
+}
+
+func TestIsInNightMode_Disabled(t *testing.T) {
+	settings, err := NewSafetySettings(uuid.New())
+	assert.NoError(t, err)
+
+	settings.NightModeEnabled = false
+	now := time.Date(2025, 1, 11, 3, 0, 0, 0, LocalTimezone())
+
+	assert.False(t, settings.IsInNightMode(now))
+}
+
+func TestNightModeEndsAt(t *testing.T) {
+	settings, err := NewSafetySettings(uuid.New())
+	assert.NoError(t, err)
+
+	settings.NightModeEndTime = clock(t, "06:00")
+	luanda := LocalTimezone()
+
+	beforeMidnight := time.Date(2025, 1, 10, 23, 0, 0, 0, luanda)
+	assert.True(t, time.Date(2025, 1, 11, 6, 0, 0, 0, luanda).Equal(settings.NightModeEndsAt(beforeMidnight)))
+
+	afterMidnight := time.Date(2025, 1, 11, 2, 0, 0, 0, luanda)
+	assert.True(t, time.Date(2025, 1, 11, 6, 0, 0, 0, luanda).Equal(settings.NightModeEndsAt(afterMidnight)))
+}
+
+func TestNotificationRadiusFor_TopicOverridesType(t *testing.T) {
+	settings, err := NewSafetySettings(uuid.New())
+	assert.NoError(t, err)
+
+	crime := uuid.New()
+	robbery := uuid.New()
+	traffic := uuid.New()
+	wide := 5000
+
+	settings.RiskPreferences = []RiskNotificationPreference{
+		{RiskTypeID: crime, Enabled: false},
+		{RiskTypeID: crime, RiskTopicID: &robbery, Enabled: true, RadiusMeters: &wide},
+	}
+	assert.NoError(t, settings.Validate())
+
+	testCases := []struct {
+		name     string
+		typeID   uuid.UUID
+		topicID  uuid.UUID
+		radius   int
+		expected bool
+	}{
+		{"type opted out", crime, uuid.New(), 0, false},
+		{"topic opted back in with its own radius", crime, robbery, wide, true},
+		{"no preference keeps base radius", traffic, uuid.Nil, 1000, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			radius, ok := settings.NotificationRadiusFor(1000, tc.typeID, tc.topicID)
+			assert.Equal(t, tc.expected, ok)
+			assert.Equal(t, tc.radius, radius)
+		})
+	}
+}
+
+func TestValidate_RejectsDuplicateRiskPreference(t *testing.T) {
+	settings, err := NewSafetySettings(uuid.New())
+	assert.NoError(t, err)
+
+	crime := uuid.New()
+	settings.RiskPreferences = []RiskNotificationPreference{
+		{RiskTypeID: crime, Enabled: true},
+		{RiskTypeID: crime, Enabled: false},
+	}
+
+	assert.Error(t, settings.Validate())
+}
@@ -0,0 +1,157 @@
+package model
+
+import (
+	"crypto/rand"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// SMSGatewayUserID authors reports sent from numbers that are not linked to
+// an account. It is created by migration 000009.
+const SMSGatewayUserID = "5e5e0000-0000-4000-8000-000000000001"
+
+type SMSReportStatus string
+
+const (
+	SMSReportStatusCreated  SMSReportStatus = "created"
+	SMSReportStatusRejected SMSReportStatus = "rejected"
+)
+
+// Reasons an inbound SMS did not become a report. They select the reply
+// sent back to the sender.
+const (
+	SMSRejectUnknownRisk     = "unknown_risk"
+	SMSRejectUnknownLocation = "unknown_location"
+	SMSRejectFailed          = "failed"
+)
+
+const (
+	smsReferencePrefix   = "RP-"
+	smsReferenceLength   = 6
+	smsReferenceAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789" // no 0/O or 1/I
+)
+
+// SMSReport is an inbound SMS and what became of it. ReferenceCode is sent
+// back to the sender so they can quote it when following up.
+type SMSReport struct {
+	ID                uuid.UUID
+	ReferenceCode     string
+	ProviderMessageID string
+	Phone             string
+	Body              string
+	UserID            *uuid.UUID
+	ReportID          *uuid.UUID
+	Status            SMSReportStatus
+	RejectionReason   string
+	CreatedAt         time.Time
+}
+
+func NewSMSReport(phone, body, providerMessageID string) *SMSReport {
+	return &SMSReport{
+		ID:                uuid.New(),
+		ReferenceCode:     NewSMSReferenceCode(),
+		ProviderMessageID: providerMessageID,
+		Phone:             phone,
+		Body:              body,
+		CreatedAt:         time.Now(),
+	}
+}
+
+func (r *SMSReport) Accept(reportID uuid.UUID) {
+	r.ReportID = &reportID
+	r.Status = SMSReportStatusCreated
+	r.RejectionReason = ""
+}
+
+func (r *SMSReport) Reject(reason string) {
+	r.ReportID = nil
+	r.Status = SMSReportStatusRejected
+	r.RejectionReason = reason
+}
+
+// NewSMSReferenceCode returns a short code such as "RP-7K3QXM" that is easy
+// to read out and type on a keypad.
+func NewSMSReferenceCode() string {
+	buf := make([]byte, smsReferenceLength)
+	_, _ = rand.Read(buf)
+
+	code := make([]byte, smsReferenceLength)
+	for i, b := range buf {
+		code[i] = smsReferenceAlphabet[int(b)%len(smsReferenceAlphabet)]
+	}
+	return smsReferencePrefix + string(code)
+}
+
+// RiskKeyword maps a word people use in messages onto a risk type and,
+// optionally, a specific topic.
+type RiskKeyword struct {
+	Keyword     string
+	RiskTypeID  uuid.UUID
+	RiskTopicID *uuid.UUID
+}
+
+type PlaceKind string
+
+const (
+	PlaceKindProvince     PlaceKind = "province"
+	PlaceKindMunicipality PlaceKind = "municipality"
+	PlaceKindNeighborhood PlaceKind = "neighborhood"
+)
+
+// Place is a gazetteer entry. Name is normalized with NormalizeText.
+type Place struct {
+	Name         string
+	Kind         PlaceKind
+	Province     string
+	Municipality string
+	Latitude     float64
+	Longitude    float64
+}
+
+// NormalizeText lower-cases s, strips Portuguese diacritics and collapses
+// punctuation to single spaces so free text can be matched against
+// keywords and place names.
+func NormalizeText(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+
+	space := false
+	for _, r := range strings.ToLower(s) {
+		r = foldDiacritic(r)
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteRune(r)
+			space = false
+			continue
+		}
+		space = true
+	}
+
+	return b.String()
+}
+
+func foldDiacritic(r rune) rune {
+	switch r {
+	case 'á', 'à', 'â', 'ã', 'ä':
+		return 'a'
+	case 'é', 'è', 'ê', 'ë':
+		return 'e'
+	case 'í', 'ì', 'î', 'ï':
+		return 'i'
+	case 'ó', 'ò', 'ô', 'õ', 'ö':
+		return 'o'
+	case 'ú', 'ù', 'û', 'ü':
+		return 'u'
+	case 'ç':
+		return 'c'
+	case 'ñ':
+		return 'n'
+	default:
+		return r
+	}
+}
@@ -0,0 +1,146 @@
+package model
+
+// TravelMode is how the user makes a trip. It decides which roads a route
+// may use, how fast they are travelled and which incidents matter most.
+type TravelMode string
+
+const (
+	TravelModeWalking TravelMode = "walking"
+	TravelModeDriving TravelMode = "driving"
+	// TravelModeCandongueiro is a ride in a shared minibus taxi.
+	TravelModeCandongueiro TravelMode = "candongueiro"
+
+	DefaultTravelMode = TravelModeDriving
+
+	// drivingStraightLineSpeedKmh and candongueiroStraightLineSpeedKmh are
+	// the average speeds used when there is no road network to route on.
+	drivingStraightLineSpeedKmh      = 40.0
+	candongueiroStraightLineSpeedKmh = 25.0
+	// candongueiroSpeedFactor accounts for the stops to pick up and drop
+	// off passengers.
+	candongueiroSpeedFactor = 0.75
+)
+
+// ParseTravelMode reads a mode from a request; empty means the default.
+func ParseTravelMode(value string) (TravelMode, bool) {
+	switch mode := TravelMode(value); mode {
+	case "":
+		return DefaultTravelMode, true
+	case TravelModeWalking, TravelModeDriving, TravelModeCandongueiro:
+		return mode, true
+	default:
+		return "", false
+	}
+}
+
+// CanUse reports whether the mode may travel the edge. Pedestrians may walk
+// against one-way traffic but not along motorways; candongueiros keep to
+// public roads.
+func (m TravelMode) CanUse(edge RoadEdge) bool {
+	switch m {
+	case TravelModeWalking:
+		return edge.Class != RoadClassMotorway
+	case TravelModeCandongueiro:
+		return !edge.AgainstOneway && edge.Class != RoadClassPedestrian && edge.Class != RoadClassService
+	case TravelModeDriving:
+		return !edge.AgainstOneway && edge.Class != RoadClassPedestrian
+	default:
+		return !edge.AgainstOneway && edge.Class != RoadClassPedestrian
+	}
+}
+
+// SpeedKmh is the speed the mode travels the edge at.
+func (m TravelMode) SpeedKmh(edge RoadEdge) float64 {
+	switch m {
+	case TravelModeWalking:
+		return WalkingSpeedKmh
+	case TravelModeCandongueiro:
+		return edge.DrivingSpeedKmh() * candongueiroSpeedFactor
+	case TravelModeDriving:
+		return edge.DrivingSpeedKmh()
+	default:
+		return edge.DrivingSpeedKmh()
+	}
+}
+
+// StraightLineSpeedKmh is the average speed of a trip estimated along the
+// straight line between its ends.
+func (m TravelMode) StraightLineSpeedKmh() float64 {
+	switch m {
+	case TravelModeWalking:
+		return WalkingSpeedKmh
+	case TravelModeCandongueiro:
+		return candongueiroStraightLineSpeedKmh
+	case TravelModeDriving:
+		return drivingStraightLineSpeedKmh
+	default:
+		return drivingStraightLineSpeedKmh
+	}
+}
+
+// IncidentWeight scales an incident's weight by how exposed the mode is to
+// it: pedestrians to muggings, drivers to carjackings and crashes,
+// candongueiro passengers to pickpockets and minibus crashes.
+func (m TravelMode) IncidentWeight(riskType, riskTopic string) float64 {
+	switch m {
+	case TravelModeWalking:
+		return walkingIncidentWeight(riskType, riskTopic)
+	case TravelModeCandongueiro:
+		return candongueiroIncidentWeight(riskType, riskTopic)
+	case TravelModeDriving:
+		return drivingIncidentWeight(riskType, riskTopic)
+	default:
+		return 1
+	}
+}
+
+func walkingIncidentWeight(riskType, riskTopic string) float64 {
+	switch riskTopic {
+	case "assalto", "assalto_mao_armada", "zona_assalto", "roubo", "furtos", "furto_carteira", "furto_telemovel",
+		"atropelamento", "rua_escura":
+		return 1.5
+	case "roubo_veiculo", "incendio_veiculo", "colisao_transito", "capotamento", "congestionamento":
+		return 0.5
+	}
+	switch riskType {
+	case "crime", "violence":
+		return 1.5
+	case "accident", "traffic":
+		return 0.75
+	}
+	return 1
+}
+
+func drivingIncidentWeight(riskType, riskTopic string) float64 {
+	switch riskTopic {
+	case "roubo_veiculo":
+		return 2
+	case "assalto_mao_armada", "tiroteio", "buraco_via", "semaforo_avariado", "via_bloqueada":
+		return 1.5
+	case "furto_carteira", "furto_telemovel", "rua_escura":
+		return 0.5
+	}
+	switch riskType {
+	case "accident":
+		return 1.5
+	case "traffic":
+		return 1.25
+	}
+	return 1
+}
+
+func candongueiroIncidentWeight(riskType, riskTopic string) float64 {
+	switch riskTopic {
+	case "furto_carteira", "furto_telemovel", "furtos", "assalto":
+		return 1.5
+	case "roubo_veiculo", "rua_escura":
+		return 0.75
+	}
+	switch riskType {
+	case "accident":
+		return 1.5
+	case "crime", "traffic":
+		return 1.25
+	}
+	return 1
+}
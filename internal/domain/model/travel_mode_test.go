@@ -0,0 +1,54 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTravelMode(t *testing.T) {
+	mode, ok := ParseTravelMode("")
+	assert.True(t, ok)
+	assert.Equal(t, TravelModeDriving, mode)
+
+	mode, ok = ParseTravelMode("candongueiro")
+	assert.True(t, ok)
+	assert.Equal(t, TravelModeCandongueiro, mode)
+
+	_, ok = ParseTravelMode("bicycle")
+	assert.False(t, ok)
+}
+
+func TestTravelMode_CanUse(t *testing.T) {
+	againstOneway := RoadEdge{Class: RoadClassPrimary, AgainstOneway: true}
+	footway := RoadEdge{Class: RoadClassPedestrian}
+	motorway := RoadEdge{Class: RoadClassMotorway}
+
+	assert.True(t, TravelModeWalking.CanUse(againstOneway))
+	assert.True(t, TravelModeWalking.CanUse(footway))
+	assert.False(t, TravelModeWalking.CanUse(motorway))
+
+	assert.False(t, TravelModeDriving.CanUse(againstOneway))
+	assert.False(t, TravelModeDriving.CanUse(footway))
+	assert.True(t, TravelModeDriving.CanUse(motorway))
+
+	assert.False(t, TravelModeCandongueiro.CanUse(RoadEdge{Class: RoadClassService}))
+}
+
+func TestTravelMode_SpeedKmh(t *testing.T) {
+	edge := RoadEdge{Class: RoadClassPrimary}
+
+	assert.InDelta(t, WalkingSpeedKmh, TravelModeWalking.SpeedKmh(edge), 0.001)
+	assert.InDelta(t, 45, TravelModeDriving.SpeedKmh(edge), 0.001)
+	assert.Less(t, TravelModeCandongueiro.SpeedKmh(edge), TravelModeDriving.SpeedKmh(edge))
+}
+
+func TestTravelMode_IncidentWeight(t *testing.T) {
+	assert.Greater(t,
+		TravelModeWalking.IncidentWeight("crime", "furto_telemovel"),
+		TravelModeDriving.IncidentWeight("crime", "furto_telemovel"))
+	assert.Greater(t,
+		TravelModeDriving.IncidentWeight("crime", "roubo_veiculo"),
+		TravelModeWalking.IncidentWeight("crime", "roubo_veiculo"))
+	assert.InDelta(t, 1, TravelModeDriving.IncidentWeight("health", "emergencia_medica"), 0.001)
+}
@@ -0,0 +1,260 @@
+package model
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TripStatus string
+
+const (
+	TripStatusActive    TripStatus = "active"
+	TripStatusCompleted TripStatus = "completed"
+	TripStatusCancelled TripStatus = "cancelled"
+	// TripStatusEscalated is a trip whose traveller did not answer a
+	// check-in, or said they were not OK, so their contacts were alerted.
+	TripStatusEscalated TripStatus = "escalated"
+)
+
+// TripAnomaly is why a traveller is asked to confirm they are OK.
+type TripAnomaly string
+
+const (
+	TripAnomalyRouteDeviation TripAnomaly = "route_deviation"
+	TripAnomalyLongStop       TripAnomaly = "long_stop"
+	TripAnomalyMissedETA      TripAnomaly = "missed_eta"
+	// TripAnomalyDistress is a traveller answering a check-in, or reaching
+	// out on their own, to say they are not OK.
+	TripAnomalyDistress TripAnomaly = "distress"
+)
+
+const (
+	// TripCheckInTimeout is how long a traveller has to answer a check-in
+	// before their emergency contacts are alerted.
+	TripCheckInTimeout = 5 * time.Minute
+	// tripCheckInSnooze is how long a traveller who said they are OK is
+	// left alone.
+	tripCheckInSnooze = 15 * time.Minute
+
+	// tripWalkingDeviationMeters and tripDeviationMeters are how far from
+	// the route a traveller may stray; vehicles get more room for detours
+	// around traffic.
+	tripWalkingDeviationMeters = 150.0
+	tripDeviationMeters        = 300.0
+	// tripStopRadiusMeters is how far a traveller has to move to count as
+	// moving, above GPS jitter.
+	tripStopRadiusMeters = 50.0
+	// tripMaxStop is how long a traveller may stay put before being asked;
+	// long enough for a traffic jam or a candongueiro filling up.
+	tripMaxStop = 15 * time.Minute
+	// tripArrivalMeters is how close to the destination ends the trip.
+	tripArrivalMeters = 100.0
+	// tripMinETAGrace and tripETAGraceRatio set how late a traveller may
+	// be: a quarter of the expected duration, at least ten minutes.
+	tripMinETAGrace   = 10 * time.Minute
+	tripETAGraceRatio = 0.25
+
+	maxTripRoutePoints = 5000
+	maxTripDuration    = 24 * time.Hour
+)
+
+// Trip follows a traveller along a safe route from the location updates
+// they send, asking them to confirm they are OK when they stray from the
+// route, stop for long or run late.
+type Trip struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+	Status TripStatus
+	Mode   TravelMode
+	Route  []GeoPoint
+
+	StartedAt         time.Time
+	ExpectedArrivalAt time.Time
+	EndedAt           *time.Time
+
+	LastLatitude   float64
+	LastLongitude  float64
+	LastLocationAt time.Time
+	// StopLatitude, StopLongitude and StoppedSince are where and since when
+	// the traveller has stayed within tripStopRadiusMeters.
+	StopLatitude  float64
+	StopLongitude float64
+	StoppedSince  time.Time
+
+	// CheckInReason and CheckInRequestedAt describe the check-in waiting
+	// for an answer; QuietUntil silences new ones after an answer.
+	CheckInReason      TripAnomaly
+	CheckInRequestedAt *time.Time
+	QuietUntil         time.Time
+	EscalatedAt        *time.Time
+
+	// Version guards against two instances updating the trip at once.
+	Version   int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewTrip starts a trip along route, expected to take duration, from the
+// first point of the route.
+func NewTrip(userID uuid.UUID, mode TravelMode, route []GeoPoint, duration time.Duration, now time.Time) (*Trip, error) {
+	if len(route) < minWaypoints || len(route) > maxTripRoutePoints {
+		return nil, errors.New("trip route needs between 2 and 5000 points")
+	}
+	for _, p := range route {
+		if p.Latitude < -90 || p.Latitude > 90 || p.Longitude < -180 || p.Longitude > 180 {
+			return nil, errors.New("trip route has an invalid coordinate")
+		}
+	}
+	if duration <= 0 || duration > maxTripDuration {
+		return nil, errors.New("trip duration must be positive and at most 24 hours")
+	}
+
+	origin := route[0]
+	return &Trip{
+		ID:                uuid.New(),
+		UserID:            userID,
+		Status:            TripStatusActive,
+		Mode:              mode,
+		Route:             route,
+		StartedAt:         now,
+		ExpectedArrivalAt: now.Add(duration),
+		LastLatitude:      origin.Latitude,
+		LastLongitude:     origin.Longitude,
+		LastLocationAt:    now,
+		StopLatitude:      origin.Latitude,
+		StopLongitude:     origin.Longitude,
+		StoppedSince:      now,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}, nil
+}
+
+func (t *Trip) IsActive() bool {
+	return t.Status == TripStatusActive
+}
+
+func (t *Trip) Destination() GeoPoint {
+	return t.Route[len(t.Route)-1]
+}
+
+func (t *Trip) CheckInPending() bool {
+	return t.CheckInRequestedAt != nil
+}
+
+// TrackLocation moves the traveller to a new position. It ends the trip on
+// arrival and returns the anomaly to ask about, or "" if there is none.
+func (t *Trip) TrackLocation(lat, lon float64, now time.Time) TripAnomaly {
+	if !t.IsActive() {
+		return ""
+	}
+
+	t.LastLatitude, t.LastLongitude, t.LastLocationAt = lat, lon, now
+	t.UpdatedAt = now
+
+	destination := t.Destination()
+	if DistanceMeters(lat, lon, destination.Latitude, destination.Longitude) <= tripArrivalMeters {
+		t.End(true, now)
+		return ""
+	}
+
+	if DistanceMeters(lat, lon, t.StopLatitude, t.StopLongitude) > tripStopRadiusMeters {
+		t.StopLatitude, t.StopLongitude, t.StoppedSince = lat, lon, now
+	}
+
+	if t.DistanceFromRoute(lat, lon) > t.deviationMeters() {
+		return t.requestCheckIn(TripAnomalyRouteDeviation, now)
+	}
+	return t.Check(now)
+}
+
+// Check looks for the anomalies that show without a location update: a
+// traveller who has not moved for long, or who is running late.
+func (t *Trip) Check(now time.Time) TripAnomaly {
+	if !t.IsActive() {
+		return ""
+	}
+	if now.Sub(t.StoppedSince) >= tripMaxStop {
+		return t.requestCheckIn(TripAnomalyLongStop, now)
+	}
+	if now.After(t.ExpectedArrivalAt.Add(t.etaGrace())) {
+		return t.requestCheckIn(TripAnomalyMissedETA, now)
+	}
+	return ""
+}
+
+// CheckInOverdue reports whether a check-in went unanswered for too long.
+func (t *Trip) CheckInOverdue(now time.Time) bool {
+	return t.IsActive() && t.CheckInPending() && now.Sub(*t.CheckInRequestedAt) >= TripCheckInTimeout
+}
+
+// ConfirmSafe answers the pending check-in, if any, and silences new ones
+// for a while. A stop is counted afresh from now.
+func (t *Trip) ConfirmSafe(now time.Time) {
+	t.CheckInReason, t.CheckInRequestedAt = "", nil
+	t.QuietUntil = now.Add(tripCheckInSnooze)
+	t.StoppedSince = now
+	t.UpdatedAt = now
+}
+
+// Escalate stops following the trip once the traveller's contacts are to
+// be alerted. reason is the unanswered check-in's, or distress.
+func (t *Trip) Escalate(reason TripAnomaly, now time.Time) {
+	t.Status = TripStatusEscalated
+	t.CheckInReason = reason
+	t.CheckInRequestedAt = nil
+	t.EscalatedAt = &now
+	t.UpdatedAt = now
+}
+
+// End finishes the trip, as completed on arrival or cancelled otherwise.
+func (t *Trip) End(arrived bool, now time.Time) {
+	t.Status = TripStatusCancelled
+	if arrived {
+		t.Status = TripStatusCompleted
+	}
+	t.CheckInReason, t.CheckInRequestedAt = "", nil
+	t.EndedAt = &now
+	t.UpdatedAt = now
+}
+
+// DistanceFromRoute is how far the point is from the nearest leg of the
+// route, measured on a flat projection around the point.
+func (t *Trip) DistanceFromRoute(lat, lon float64) float64 {
+	metersPerDegreeLon := metersPerDegreeLat * math.Cos(lat*math.Pi/180) //nolint:mnd // degrees to radians
+	project := func(p GeoPoint) (float64, float64) {
+		return (p.Longitude - lon) * metersPerDegreeLon, (p.Latitude - lat) * metersPerDegreeLat
+	}
+
+	best := math.Inf(1)
+	for i := 1; i < len(t.Route); i++ {
+		ax, ay := project(t.Route[i-1])
+		bx, by := project(t.Route[i])
+		best = math.Min(best, distanceToSegment(ax, ay, bx, by))
+	}
+	return best
+}
+
+func (t *Trip) requestCheckIn(reason TripAnomaly, now time.Time) TripAnomaly {
+	if t.CheckInPending() || now.Before(t.QuietUntil) {
+		return ""
+	}
+	t.CheckInReason = reason
+	t.CheckInRequestedAt = &now
+	t.UpdatedAt = now
+	return reason
+}
+
+func (t *Trip) deviationMeters() float64 {
+	if t.Mode == TravelModeWalking {
+		return tripWalkingDeviationMeters
+	}
+	return tripDeviationMeters
+}
+
+func (t *Trip) etaGrace() time.Duration {
+	grace := time.Duration(float64(t.ExpectedArrivalAt.Sub(t.StartedAt)) * tripETAGraceRatio)
+	return max(grace, tripMinETAGrace)
+}
@@ -0,0 +1,120 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestTrip walks about 2.2 km east along a straight street in Luanda,
+// expected to take 30 minutes.
+func newTestTrip(t *testing.T, now time.Time) *Trip {
+	t.Helper()
+	trip, err := NewTrip(uuid.New(), TravelModeWalking, []GeoPoint{
+		{Latitude: -8.8383, Longitude: 13.2344},
+		{Latitude: -8.8383, Longitude: 13.2444},
+		{Latitude: -8.8383, Longitude: 13.2544},
+	}, 30*time.Minute, now)
+	assert.NoError(t, err)
+	return trip
+}
+
+func TestNewTrip_Validates(t *testing.T) {
+	now := time.Now()
+	route := []GeoPoint{{Latitude: -8.8383, Longitude: 13.2344}, {Latitude: -8.8383, Longitude: 13.2544}}
+
+	_, err := NewTrip(uuid.New(), TravelModeWalking, route[:1], time.Hour, now)
+	assert.Error(t, err, "a route needs two points")
+	_, err = NewTrip(uuid.New(), TravelModeWalking, []GeoPoint{route[0], {Latitude: 91, Longitude: 0}}, time.Hour, now)
+	assert.Error(t, err)
+	_, err = NewTrip(uuid.New(), TravelModeWalking, route, 0, now)
+	assert.Error(t, err)
+	_, err = NewTrip(uuid.New(), TravelModeWalking, route, 25*time.Hour, now)
+	assert.Error(t, err)
+}
+
+func TestTrip_TrackLocation_RouteDeviation(t *testing.T) {
+	now := time.Now()
+	trip := newTestTrip(t, now)
+
+	// About 100 m north of the street is within a walker's leeway.
+	assert.Empty(t, trip.TrackLocation(-8.8374, 13.2400, now.Add(time.Minute)))
+	assert.False(t, trip.CheckInPending())
+
+	// About 330 m north is not.
+	assert.Equal(t, TripAnomalyRouteDeviation, trip.TrackLocation(-8.8353, 13.2420, now.Add(2*time.Minute)))
+	assert.True(t, trip.CheckInPending())
+
+	// One check-in at a time.
+	assert.Empty(t, trip.TrackLocation(-8.8343, 13.2430, now.Add(3*time.Minute)))
+}
+
+func TestTrip_TrackLocation_DrivingHasMoreLeeway(t *testing.T) {
+	now := time.Now()
+	trip := newTestTrip(t, now)
+	trip.Mode = TravelModeDriving
+
+	assert.Empty(t, trip.TrackLocation(-8.8363, 13.2420, now.Add(time.Minute)), "about 220 m off")
+}
+
+func TestTrip_Check_LongStop(t *testing.T) {
+	now := time.Now()
+	trip := newTestTrip(t, now)
+
+	assert.Empty(t, trip.TrackLocation(-8.8383, 13.2400, now.Add(time.Minute)))
+	// Jitter within the stop radius does not count as moving.
+	assert.Empty(t, trip.TrackLocation(-8.8384, 13.2401, now.Add(10*time.Minute)))
+	assert.Empty(t, trip.Check(now.Add(15*time.Minute)))
+	assert.Equal(t, TripAnomalyLongStop, trip.Check(now.Add(16*time.Minute)))
+}
+
+func TestTrip_Check_MissedETA(t *testing.T) {
+	now := time.Now()
+	trip := newTestTrip(t, now)
+
+	// Half an hour expected gives ten minutes of grace; keep moving so no
+	// stop is flagged.
+	assert.Empty(t, trip.TrackLocation(-8.8383, 13.2400, now.Add(30*time.Minute)))
+	assert.Empty(t, trip.Check(now.Add(39*time.Minute)))
+	trip.TrackLocation(-8.8383, 13.2450, now.Add(40*time.Minute))
+	assert.Equal(t, TripAnomalyMissedETA, trip.Check(now.Add(41*time.Minute)))
+}
+
+func TestTrip_TrackLocation_ArrivalCompletes(t *testing.T) {
+	now := time.Now()
+	trip := newTestTrip(t, now)
+
+	assert.Empty(t, trip.TrackLocation(-8.8383, 13.2540, now.Add(25*time.Minute)))
+	assert.Equal(t, TripStatusCompleted, trip.Status)
+	assert.NotNil(t, trip.EndedAt)
+	assert.Empty(t, trip.Check(now.Add(time.Hour)))
+}
+
+func TestTrip_ConfirmSafe_SilencesCheckIns(t *testing.T) {
+	now := time.Now()
+	trip := newTestTrip(t, now)
+
+	assert.Equal(t, TripAnomalyLongStop, trip.Check(now.Add(15*time.Minute)))
+	assert.False(t, trip.CheckInOverdue(now.Add(19*time.Minute)))
+	assert.True(t, trip.CheckInOverdue(now.Add(20*time.Minute)))
+
+	trip.ConfirmSafe(now.Add(19 * time.Minute))
+	assert.False(t, trip.CheckInPending())
+	assert.False(t, trip.CheckInOverdue(now.Add(20*time.Minute)))
+	assert.Empty(t, trip.TrackLocation(-8.8353, 13.2420, now.Add(25*time.Minute)), "still snoozed")
+	assert.Equal(t, TripAnomalyRouteDeviation, trip.TrackLocation(-8.8353, 13.2420, now.Add(35*time.Minute)))
+}
+
+func TestTrip_Escalate(t *testing.T) {
+	now := time.Now()
+	trip := newTestTrip(t, now)
+
+	trip.Check(now.Add(15 * time.Minute))
+	trip.Escalate(trip.CheckInReason, now.Add(20*time.Minute))
+
+	assert.Equal(t, TripStatusEscalated, trip.Status)
+	assert.Equal(t, TripAnomalyLongStop, trip.CheckInReason)
+	assert.False(t, trip.CheckInOverdue(now.Add(30*time.Minute)), "an escalated trip is no longer followed")
+}
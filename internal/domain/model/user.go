@@ -43,6 +43,24 @@ type DeviceToken struct {
 	DeviceID string
 }
 
+// EmailRecipient is a user who opted in to alerts by email.
+type EmailRecipient struct {
+	UserID   uuid.UUID
+	Email    string
+	Name     string
+	Language string
+}
+
+// EmailAlertFilter describes an alert or report so email recipients can be
+// checked against the same notification settings as push recipients.
+type EmailAlertFilter struct {
+	Report      bool
+	Severity    string
+	IsVerified  bool
+	RiskTypeID  uuid.UUID
+	RiskTopicID uuid.UUID
+}
+
 type User struct {
 	ID                  uuid.UUID
 	Name                string
@@ -13,6 +13,7 @@ type AlertRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*model.Alert, error)
 	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*model.Alert, error)
 	GetSubscribedAlerts(ctx context.Context, userID uuid.UUID) ([]*model.Alert, error)
+	ListActiveNear(ctx context.Context, lat, lon, radiusMeters float64, limit int) ([]*model.Alert, error)
 	Update(ctx context.Context, alert *model.Alert) error
 	Delete(ctx context.Context, id, userID uuid.UUID) error
 	SubscribeToAlert(ctx context.Context, subscription *model.AlertSubscription) error
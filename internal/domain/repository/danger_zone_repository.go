@@ -2,19 +2,39 @@ package repository
 
 import (
 	"context"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
 )
 
-type DangerZoneCalculationResult struct {
-	GridCellID   string
-	CellLat      float64
-	CellLon      float64
-	IncidentCount int
-	RiskScore    float64
+// DangerZoneSnapshotFilter selects snapshots of one cell, or of the cells of
+// a precision inside Bounds. Limit keeps the cells rising fastest over the
+// last week.
+type DangerZoneSnapshotFilter struct {
+	GridCellID string
+	Bounds     *model.BoundingBox
+	Precision  int
+	Since      time.Time
+	Limit      int
 }
 
 type DangerZoneRepository interface {
-	CalculateDangerZones(ctx context.Context, gridSizeKm float64, minIncidents int, daysBack int) ([]DangerZoneCalculationResult, error)
-	GetNearbyDangerZones(ctx context.Context, lat, lon, radiusKm float64) ([]*model.DangerZone, error)
+	// ListIncidents returns the verified and pending reports created since
+	// the given time, limited to bounds when it is not nil.
+	ListIncidents(ctx context.Context, since time.Time, bounds *model.BoundingBox) ([]model.DangerZoneIncident, error)
+	ListRegions(ctx context.Context) ([]*model.DangerZoneRegion, error)
+	SaveSnapshots(ctx context.Context, snapshots []model.DangerZoneSnapshot) error
+	DeleteSnapshotsBefore(ctx context.Context, before time.Time) error
+	// SaveExplanations stores what the run started at calculatedAt saw for
+	// each of its zones.
+	SaveExplanations(ctx context.Context, runID uuid.UUID, calculatedAt time.Time, explanations []*model.DangerZoneExplanation) error
+	// GetExplanation returns the cell's explanation from the latest run
+	// started at or before at, or ErrDangerZoneNotFound when that run did not
+	// make the cell a zone.
+	GetExplanation(ctx context.Context, gridCellID string, at time.Time) (*model.DangerZoneExplanation, error)
+	DeleteExplanationsBefore(ctx context.Context, before time.Time) error
+	// ListDailyScores summarizes the matching snapshots per cell and day,
+	// counting the day's runs that did not make the cell a zone as zero.
+	ListDailyScores(ctx context.Context, filter DangerZoneSnapshotFilter) ([]model.DangerZoneDailyScore, error)
 }
@@ -0,0 +1,13 @@
+package repository
+
+import "context"
+
+type DeviceTokenRepository interface {
+	// ClearFCMTokens removes push tokens the provider reported as permanently
+	// invalid from users and anonymous sessions, returning how many rows changed.
+	ClearFCMTokens(ctx context.Context, tokens []string) (int64, error)
+	// ListTokenOwners maps each known push token to a key for its owner: the
+	// user, including through a device linked to the account, or else the
+	// anonymous session's device. Unknown tokens are left out.
+	ListTokenOwners(ctx context.Context, tokens []string) (map[string]string, error)
+}
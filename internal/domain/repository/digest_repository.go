@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+)
+
+type DigestRepository interface {
+	GetSubscription(ctx context.Context, userID uuid.UUID) (*model.DigestSubscription, error)
+	UpsertSubscription(ctx context.Context, subscription *model.DigestSubscription) error
+	ListDueSubscriptions(ctx context.Context, now time.Time, limit int) ([]*model.DigestSubscription, error)
+	MarkSent(ctx context.Context, userID uuid.UUID, sentAt time.Time, knownDangerZones []string) error
+	CountVerifiedReportsNear(ctx context.Context, lat, lon, radiusMeters float64, since time.Time) (int, error)
+	CountActiveAlertsNear(ctx context.Context, lat, lon, radiusMeters float64) (int, error)
+}
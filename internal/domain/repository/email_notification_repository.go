@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+)
+
+type EmailNotificationRepository interface {
+	IsEnabled(ctx context.Context, userID uuid.UUID) (bool, error)
+	SetEnabled(ctx context.Context, userID uuid.UUID, enabled bool) error
+	ListRecipientsByUserIDs(ctx context.Context, userIDs []uuid.UUID) ([]model.EmailRecipient, error)
+	// ListRecipientsNear returns opted-in users among userIDs, whose live
+	// location is distancesMeters[i] from the event, plus those whose home or
+	// work address lies within the radius, so subscribers without a live
+	// location still get the alert. Users whose notification settings turn
+	// off the alert type, the risk type or their distance are left out.
+	ListRecipientsNear(ctx context.Context, userIDs []uuid.UUID, distancesMeters []int, lat, lon, radiusMeters float64, filter model.EmailAlertFilter) ([]model.EmailRecipient, error)
+}
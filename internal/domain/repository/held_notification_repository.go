@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+)
+
+type HeldNotificationRepository interface {
+	// Hold stores notifications for their digest, once per device, event key
+	// and reference, so a retried broadcast does not hold them twice.
+	Hold(ctx context.Context, notifications []*model.HeldNotification) error
+	// ListDue returns all notifications due before the given time for at
+	// most limit devices.
+	ListDue(ctx context.Context, before time.Time, limit int) ([]*model.HeldNotification, error)
+	DeleteByIDs(ctx context.Context, ids []uuid.UUID) error
+}
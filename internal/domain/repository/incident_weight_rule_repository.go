@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+)
+
+type IncidentWeightRuleRepository interface {
+	// List returns every rule with the names of its risk type and topic.
+	List(ctx context.Context) ([]*model.IncidentWeightRule, error)
+	// Save creates the rule of its risk type or topic, replacing the one
+	// already there, and sets its ID.
+	Save(ctx context.Context, rule *model.IncidentWeightRule) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
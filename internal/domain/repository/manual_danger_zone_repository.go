@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+)
+
+type ManualDangerZoneRepository interface {
+	Create(ctx context.Context, zone *model.ManualDangerZone) error
+	Update(ctx context.Context, zone *model.ManualDangerZone) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	FindByID(ctx context.Context, id uuid.UUID) (*model.ManualDangerZone, error)
+	// List returns every zone, expired ones included, newest first.
+	List(ctx context.Context) ([]*model.ManualDangerZone, error)
+	// ListUnexpired returns the zones still valid or not yet valid at the
+	// given time.
+	ListUnexpired(ctx context.Context, at time.Time) ([]*model.ManualDangerZone, error)
+}
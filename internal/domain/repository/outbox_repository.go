@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+)
+
+type OutboxRepository interface {
+	Enqueue(ctx context.Context, message *model.OutboxMessage) error
+	// ClaimDue leases up to limit due messages until now+lease and counts the
+	// attempt, so a crashed relay lets them become due again.
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*model.OutboxMessage, error)
+	MarkDelivered(ctx context.Context, id uuid.UUID) error
+	// MarkFailed schedules the next attempt and records the handlers that
+	// have handled the message so far.
+	MarkFailed(ctx context.Context, id uuid.UUID, nextAttemptAt time.Time, lastError string, deliveredHandlers []string) error
+	MoveToDeadLetter(ctx context.Context, message *model.OutboxMessage, lastError string) error
+}
@@ -48,6 +48,7 @@ type ReportRepository interface {
 	CreateReportNotification(ctx context.Context, reportID uuid.UUID, userID uuid.UUID) error
 	FindByRadius(ctx context.Context, lat float64, lon float64, radiusMeters float64) ([]*model.Report, error)
 	FindByRadiusWithDistance(ctx context.Context, lat float64, lon float64, radiusMeters float64, limit int) ([]ReportWithDistance, error)
+	FindVerifiedNearSince(ctx context.Context, lat, lon, radiusMeters float64, since time.Time, limit int) ([]ReportWithDistance, error)
 
 	AddVote(ctx context.Context, vote *model.ReportVote) error
 	RemoveVote(ctx context.Context, reportID, userID uuid.UUID) error
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
 )
@@ -12,6 +13,11 @@ type RouteCalculationParams struct {
 	DestinationLat float64
 	DestinationLon float64
 	MaxRoutes      int
+	// DepartureAt weighs incidents that happened in the same time slot of
+	// the week more heavily; zero ignores the time of day.
+	DepartureAt time.Time
+	// Mode picks the roads, speeds and incident weighting of the trip.
+	Mode model.TravelMode
 }
 
 type IncidentHeatmapParams struct {
@@ -24,16 +30,13 @@ type IncidentHeatmapParams struct {
 	RiskTypeID   string
 }
 
-type HeatmapPoint struct {
-	Latitude     float64
-	Longitude    float64
-	Weight       float64
-	IncidentType string
-	ReportCount  int
-}
-
+// SafeRouteRepository finds the reports that routes and heatmaps are scored
+// on. It leaves their WeightFactor unset: weighing them is up to the
+// incident weighting model.
 type SafeRouteRepository interface {
-	CalculateSafeRoute(ctx context.Context, params RouteCalculationParams) (*model.SafeRoute, error)
 	GetIncidentsForRoute(ctx context.Context, waypoints []model.Waypoint, corridorWidthKm float64) ([]model.IncidentNearRoute, error)
-	GetIncidentsHeatmap(ctx context.Context, params IncidentHeatmapParams) ([]HeatmapPoint, error)
+	ListIncidentsInArea(ctx context.Context, bounds model.BoundingBox) ([]model.IncidentNearRoute, error)
+	// ListHeatmapIncidents groups the heatmap's reports by spot, risk type
+	// and topic, decaying them by their weight rule's half-life.
+	ListHeatmapIncidents(ctx context.Context, params IncidentHeatmapParams) ([]model.HeatmapIncidentGroup, error)
 }
@@ -12,4 +12,7 @@ type SafetySettingsRepository interface {
 	Upsert(ctx context.Context, settings *model.SafetySettings) error
 	GetByDeviceID(ctx context.Context, deviceID string) (*model.SafetySettings, error)
 	UpsertAnonymous(ctx context.Context, settings *model.SafetySettings) error
+	// ListNightModes returns the settings of the given users and devices
+	// that have night mode on, with only the night mode fields loaded.
+	ListNightModes(ctx context.Context, userIDs []uuid.UUID, deviceIDs []string) ([]*model.SafetySettings, error)
 }
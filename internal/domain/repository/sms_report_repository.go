@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+)
+
+type SMSReportRepository interface {
+	Create(ctx context.Context, report *model.SMSReport) error
+	// FindByProviderMessageID returns nil, nil when the message has not been
+	// seen, so provider retries can be answered without a second report.
+	FindByProviderMessageID(ctx context.Context, providerMessageID string) (*model.SMSReport, error)
+	ListRiskKeywords(ctx context.Context) ([]model.RiskKeyword, error)
+	ListPlaces(ctx context.Context) ([]model.Place, error)
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+)
+
+type TripRepository interface {
+	// Create fails with ErrTripAlreadyActive if the user has an active trip.
+	Create(ctx context.Context, trip *model.Trip) error
+	// Update saves the trip unless it changed since it was read, reporting
+	// whether it did, and bumps its version.
+	Update(ctx context.Context, trip *model.Trip) (bool, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*model.Trip, error)
+	FindActiveByUserID(ctx context.Context, userID uuid.UUID) (*model.Trip, error)
+	ListActive(ctx context.Context) ([]*model.Trip, error)
+	// ListEscalationDeliveries returns the emergency contacts already sent
+	// the trip's escalation SMS.
+	ListEscalationDeliveries(ctx context.Context, tripID uuid.UUID) (map[uuid.UUID]bool, error)
+	RecordEscalationDelivery(ctx context.Context, tripID, contactID uuid.UUID) error
+}
@@ -20,8 +20,11 @@ type UserRepository interface {
 	ListAllDeviceTokensExceptUser(ctx context.Context, excludeUserID uuid.UUID) ([]string, error)
 	UpdateUserDeviceInfo(ctx context.Context, userID uuid.UUID, fcmToken string, language string) error
 	ListDeviceTokensByUserIDs(ctx context.Context, userIDs []uuid.UUID) ([]string, error)
-	ListDeviceTokensForAlertNotification(ctx context.Context, userIDs []uuid.UUID, severityLevel string, distanceMeters int) ([]model.DeviceToken, error)
-	ListDeviceTokensForReportNotification(ctx context.Context, userIDs []uuid.UUID, isVerified bool, distanceMeters int) ([]model.DeviceToken, error)
+	// ListDeviceTokensForAlertNotification and ListDeviceTokensForReportNotification
+	// return the tokens of users whose settings let the event through at
+	// distancesMeters[i], the distance of userIDs[i] from the incident.
+	ListDeviceTokensForAlertNotification(ctx context.Context, userIDs []uuid.UUID, distancesMeters []int, severityLevel string, riskTypeID, riskTopicID uuid.UUID) ([]model.DeviceToken, error)
+	ListDeviceTokensForReportNotification(ctx context.Context, userIDs []uuid.UUID, distancesMeters []int, isVerified bool, riskTypeID, riskTopicID uuid.UUID) ([]model.DeviceToken, error)
 	UpdateSavedLocations(ctx context.Context, userID uuid.UUID, homeAddress, workAddress *model.SavedLocation) error
 	UpdateNotificationPreferences(ctx context.Context, userID uuid.UUID, pushEnabled, smsEnabled bool) error
 	GetNotificationPreferences(ctx context.Context, userID uuid.UUID) (pushEnabled, smsEnabled bool, err error)
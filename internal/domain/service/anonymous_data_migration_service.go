@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
 	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
 )
 
@@ -65,7 +66,7 @@ func (s *AnonymousDataMigrationService) MigrateAnonymousUser(
 
 	session, err := s.anonymousSessionRepo.GetByDeviceID(ctx, deviceID)
 	if err != nil {
-		return nil, errors.New("anonymous session not found")
+		return nil, domainErrors.ErrSessionNotFound
 	}
 
 	if session.MigratedToUserID != nil {
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"sort"
+	"time"
+
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+)
+
+const (
+	dangerZoneMaxRiskScore     = 10.0
+	dangerZoneMaxZonesPerLevel = 1000
+	verifiedIncidentMultiplier = 2.0
+
+	// dangerZoneSlotPriorIncidents is how many incidents' worth of an even
+	// spread over the week each zone's time profile starts from, so a few
+	// reports in one slot do not make it look far riskier than the zone.
+	dangerZoneSlotPriorIncidents = 8.0
+	// dangerZoneSlotMaxMultiplier caps how much riskier a slot can be than
+	// its zone.
+	dangerZoneSlotMaxMultiplier = 2.0
+)
+
+// IncidentRiskWeight is how much one incident adds to its cell's risk score:
+// its weight under the weighting model, doubled when it is verified.
+func IncidentRiskWeight(weighting *model.IncidentWeighting, incident model.DangerZoneIncident, now time.Time) float64 {
+	return weighting.Weight(incident.RiskType, incident.RiskTopic, now.Sub(incident.CreatedAt)) *
+		IncidentVerificationWeight(incident.Verified)
+}
+
+func IncidentVerificationWeight(verified bool) float64 {
+	if verified {
+		return verifiedIncidentMultiplier
+	}
+	return 1.0
+}
+
+// GroupIncidentsByCell buckets incidents by their geohash cell.
+func GroupIncidentsByCell(incidents []model.DangerZoneIncident, precision int) map[string][]model.DangerZoneIncident {
+	cells := make(map[string][]model.DangerZoneIncident)
+	for _, incident := range incidents {
+		hash := model.EncodeGeohash(incident.Latitude, incident.Longitude, precision)
+		cells[hash] = append(cells[hash], incident)
+	}
+	return cells
+}
+
+// BuildDangerZones groups incidents into geohash cells of the level's
+// precision and returns the cells with at least level.MinIncidents, highest
+// risk first.
+func BuildDangerZones(
+	incidents []model.DangerZoneIncident,
+	level model.DangerZoneLevel,
+	weighting *model.IncidentWeighting,
+	now time.Time,
+) []*model.DangerZone {
+	type cell struct {
+		count      int
+		score      float64
+		slotCounts [model.DangerZoneTimeSlots]int
+		slotScores [model.DangerZoneTimeSlots]float64
+	}
+
+	cells := make(map[string]*cell)
+	for hash, cellIncidents := range GroupIncidentsByCell(incidents, level.Precision) {
+		c := &cell{count: len(cellIncidents)}
+		for _, incident := range cellIncidents {
+			weight := IncidentRiskWeight(weighting, incident, now)
+			slot := model.DangerZoneTimeSlotIndex(incident.CreatedAt)
+			c.score += weight
+			c.slotCounts[slot]++
+			c.slotScores[slot] += weight
+		}
+		cells[hash] = c
+	}
+
+	zones := make([]*model.DangerZone, 0, len(cells))
+	for hash, c := range cells {
+		if c.count < level.MinIncidents {
+			continue
+		}
+
+		bounds, err := model.DecodeGeohash(hash)
+		if err != nil {
+			continue
+		}
+		centerLat, centerLon := bounds.Center()
+
+		zone := model.NewDangerZone(centerLat, centerLon, hash)
+		zone.Precision = level.Precision
+		zone.IncidentCount = c.count
+		zone.RiskScore = min(c.score, dangerZoneMaxRiskScore)
+		zone.CalculateRiskLevel()
+		zone.TimeProfile = buildTimeProfile(zone.RiskScore, c.score, c.slotCounts, c.slotScores)
+		zones = append(zones, zone)
+	}
+
+	sort.Slice(zones, func(i, j int) bool {
+		if zones[i].RiskScore == zones[j].RiskScore {
+			return zones[i].GridCellID < zones[j].GridCellID
+		}
+		return zones[i].RiskScore > zones[j].RiskScore
+	})
+
+	if len(zones) > dangerZoneMaxZonesPerLevel {
+		zones = zones[:dangerZoneMaxZonesPerLevel]
+	}
+	return zones
+}
+
+// buildTimeProfile scales the zone's score for each slot by how much of the
+// zone's weight falls in the slot compared with how much of the week it
+// covers: a slot with its fair share scores the same as the zone, busier
+// slots score higher and quiet ones lower. The shares are shrunk toward an
+// even spread by dangerZoneSlotPriorIncidents, so sparse zones stay close to
+// their overall score, and no slot scores more than
+// dangerZoneSlotMaxMultiplier times the zone.
+func buildTimeProfile(
+	zoneScore, totalWeight float64,
+	counts [model.DangerZoneTimeSlots]int,
+	weights [model.DangerZoneTimeSlots]float64,
+) []model.DangerZoneTimeSlot {
+	incidents := 0
+	for _, count := range counts {
+		incidents += count
+	}
+
+	profile := model.NewDangerZoneTimeProfile()
+	for i := range profile {
+		profile[i].IncidentCount = counts[i]
+		if totalWeight > 0 && incidents > 0 {
+			prior := dangerZoneSlotPriorIncidents * totalWeight / float64(incidents)
+			share := (weights[i] + prior*profile[i].WeeklyShare()) / (totalWeight + prior)
+			multiplier := min(share/profile[i].WeeklyShare(), dangerZoneSlotMaxMultiplier)
+			profile[i].RiskScore = min(zoneScore*multiplier, dangerZoneMaxRiskScore)
+		}
+		profile[i].RiskLevel = model.DangerZoneRiskLevel(profile[i].RiskScore)
+	}
+	return profile
+}
+
+// ExplainDangerZone lists how each of the cell's incidents adds to its score,
+// heaviest first, together with the totals per risk type.
+func ExplainDangerZone(
+	zone *model.DangerZone,
+	incidents []model.DangerZoneIncident,
+	weighting *model.IncidentWeighting,
+	now time.Time,
+) *model.DangerZoneExplanation {
+	explanation := &model.DangerZoneExplanation{
+		GridCellID:    zone.GridCellID,
+		Precision:     zone.Precision,
+		CellLat:       zone.CellLat,
+		CellLon:       zone.CellLon,
+		IncidentCount: len(incidents),
+		MaxRiskScore:  dangerZoneMaxRiskScore,
+		Contributions: make([]model.DangerZoneContribution, 0, len(incidents)),
+		CalculatedAt:  zone.CalculatedAt,
+	}
+
+	byType := make(map[string]*model.DangerZoneRiskTypeBreakdown)
+	for _, incident := range incidents {
+		rule := weighting.Rule(incident.RiskType, incident.RiskTopic)
+		contribution := model.DangerZoneContribution{
+			ReportID:           incident.ReportID,
+			RiskType:           incident.RiskType,
+			RiskTopic:          incident.RiskTopic,
+			Verified:           incident.Verified,
+			Private:            incident.IsPrivate,
+			CreatedAt:          incident.CreatedAt,
+			SeverityWeight:     rule.Severity,
+			RecencyWeight:      rule.Recency(now.Sub(incident.CreatedAt)),
+			VerificationWeight: IncidentVerificationWeight(incident.Verified),
+		}
+		contribution.Weight = contribution.SeverityWeight * contribution.RecencyWeight * contribution.VerificationWeight
+		explanation.Contributions = append(explanation.Contributions, contribution)
+		explanation.RawScore += contribution.Weight
+
+		breakdown, ok := byType[incident.RiskType]
+		if !ok {
+			breakdown = &model.DangerZoneRiskTypeBreakdown{RiskType: incident.RiskType}
+			byType[incident.RiskType] = breakdown
+		}
+		breakdown.IncidentCount++
+		breakdown.Weight += contribution.Weight
+	}
+
+	explanation.RiskScore = min(explanation.RawScore, dangerZoneMaxRiskScore)
+	explanation.RiskLevel = model.DangerZoneRiskLevel(explanation.RiskScore)
+
+	sort.SliceStable(explanation.Contributions, func(i, j int) bool {
+		a, b := explanation.Contributions[i], explanation.Contributions[j]
+		if a.Weight == b.Weight {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		return a.Weight > b.Weight
+	})
+
+	explanation.Breakdown = make([]model.DangerZoneRiskTypeBreakdown, 0, len(byType))
+	for _, breakdown := range byType {
+		explanation.Breakdown = append(explanation.Breakdown, *breakdown)
+	}
+	sort.Slice(explanation.Breakdown, func(i, j int) bool {
+		a, b := explanation.Breakdown[i], explanation.Breakdown[j]
+		if a.Weight == b.Weight {
+			return a.RiskType < b.RiskType
+		}
+		return a.Weight > b.Weight
+	})
+
+	return explanation
+}
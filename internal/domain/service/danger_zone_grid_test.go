@@ -0,0 +1,130 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildDangerZones_GroupsByCellAndLevel(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	weighting := model.NewIncidentWeighting(nil)
+
+	// Four reports within a couple of blocks in Maianga, one in Samba.
+	incidents := []model.DangerZoneIncident{
+		{Latitude: -8.8290, Longitude: 13.2405, Verified: true, CreatedAt: now.Add(-24 * time.Hour)},
+		{Latitude: -8.8292, Longitude: 13.2407, CreatedAt: now.Add(-48 * time.Hour)},
+		{Latitude: -8.8294, Longitude: 13.2409, CreatedAt: now.Add(-10 * 24 * time.Hour)},
+		{Latitude: -8.8291, Longitude: 13.2406, CreatedAt: now.Add(-20 * 24 * time.Hour)},
+		{Latitude: -8.8600, Longitude: 13.2100, CreatedAt: now.Add(-24 * time.Hour)},
+	}
+
+	fine := BuildDangerZones(incidents, model.DangerZoneLevel{Precision: 7, MinIncidents: 3}, weighting, now)
+	if assert.Len(t, fine, 1) {
+		zone := fine[0]
+		assert.Equal(t, 7, zone.Precision)
+		assert.Len(t, zone.GridCellID, 7)
+		assert.Equal(t, 4, zone.IncidentCount)
+		// 5.62 (verified, a day old) + 2.64 + 1.74 + 1.28
+		assert.InDelta(t, 10.0, zone.RiskScore, 0.001, "score is capped")
+		assert.Equal(t, "critical", zone.RiskLevel)
+	}
+
+	coarse := BuildDangerZones(incidents, model.DangerZoneLevel{Precision: 4, MinIncidents: 5}, weighting, now)
+	if assert.Len(t, coarse, 1) {
+		assert.Equal(t, 5, coarse[0].IncidentCount)
+		assert.Equal(t, fine[0].GridCellID[:4], coarse[0].GridCellID, "coarse cells contain fine ones")
+	}
+
+	assert.Empty(t, BuildDangerZones(incidents, model.DangerZoneLevel{Precision: 7, MinIncidents: 5}, weighting, now))
+}
+
+func TestBuildDangerZones_TimeProfile(t *testing.T) {
+	luanda := model.LocalTimezone()
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, luanda)
+	weighting := model.NewIncidentWeighting(nil)
+
+	// One report on a Friday night and one on a Saturday morning, both old
+	// and unverified so each weighs about 1.
+	incidents := []model.DangerZoneIncident{
+		{Latitude: -8.8290, Longitude: 13.2405, CreatedAt: time.Date(2025, 5, 2, 22, 30, 0, 0, luanda)},
+		{Latitude: -8.8291, Longitude: 13.2406, CreatedAt: time.Date(2025, 5, 3, 9, 15, 0, 0, luanda)},
+	}
+
+	zones := BuildDangerZones(incidents, model.DangerZoneLevel{Precision: 7, MinIncidents: 2}, weighting, now)
+	if !assert.Len(t, zones, 1) {
+		return
+	}
+	zone := zones[0]
+	assert.InDelta(t, 2.0, zone.RiskScore, 0.02)
+	assert.Len(t, zone.TimeProfile, model.DangerZoneTimeSlots)
+
+	// Half the weight in a slot that covers 20 of the week's 168 hours, but
+	// two reports are too few to move far from the zone's score.
+	friday := zone.TimeProfile[model.DangerZoneTimeSlotIndex(incidents[0].CreatedAt)]
+	assert.Equal(t, model.DayTypeWeekday, friday.DayType)
+	assert.Equal(t, 20, friday.StartHour)
+	assert.Equal(t, 1, friday.IncidentCount)
+	assert.InDelta(t, 3.28, friday.RiskScore, 0.04)
+
+	riskiest, ok := zone.Riskiest()
+	assert.True(t, ok)
+	assert.Equal(t, model.DayTypeWeekend, riskiest.DayType, "weekend slots are shorter, so the same count weighs more")
+	assert.Equal(t, 8, riskiest.StartHour)
+	assert.InDelta(t, 2*zone.RiskScore, riskiest.RiskScore, 0.001, "a slot scores at most twice its zone")
+	assert.Equal(t, "medium", riskiest.RiskLevel)
+
+	wednesdayNight := zone.AtTime(time.Date(2025, 7, 2, 21, 0, 0, 0, luanda))
+	assert.NotEqual(t, "critical", wednesdayNight.RiskLevel)
+
+	tuesdayMorning := zone.AtTime(time.Date(2025, 7, 1, 9, 0, 0, 0, luanda))
+	assert.InDelta(t, 0.8*zone.RiskScore, tuesdayMorning.RiskScore, 0.01, "an empty slot keeps most of a sparse zone's score")
+	assert.Equal(t, "low", tuesdayMorning.RiskLevel)
+	assert.InDelta(t, 2.0, zone.RiskScore, 0.02, "AtTime leaves the zone untouched")
+}
+
+func TestExplainDangerZone_WeightsAndBreakdown(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	private := uuid.New()
+	weighting := model.NewIncidentWeighting(nil)
+
+	incidents := []model.DangerZoneIncident{
+		{ReportID: uuid.New(), RiskType: "robbery", Verified: true, CreatedAt: now.Add(-24 * time.Hour)},
+		{ReportID: uuid.New(), RiskType: "robbery", CreatedAt: now.Add(-20 * 24 * time.Hour)},
+		{ReportID: private, RiskType: "assault", IsPrivate: true, CreatedAt: now.Add(-48 * time.Hour)},
+	}
+	zone := model.NewDangerZone(-8.829, 13.2405, "kq3mdzz")
+	zone.Precision = 7
+
+	explanation := ExplainDangerZone(zone, incidents, weighting, now)
+
+	assert.Equal(t, 3, explanation.IncidentCount)
+	// 2.81 * 2 (verified, a day old) + 1.28 + 2.64
+	assert.InDelta(t, 9.54, explanation.RawScore, 0.001)
+	assert.InDelta(t, 9.54, explanation.RiskScore, 0.001)
+	assert.Equal(t, "critical", explanation.RiskLevel)
+
+	if assert.Len(t, explanation.Contributions, 3) {
+		first := explanation.Contributions[0]
+		assert.InDelta(t, 1.0, first.SeverityWeight, 0.001)
+		assert.InDelta(t, 2.811, first.RecencyWeight, 0.001)
+		assert.InDelta(t, 2.0, first.VerificationWeight, 0.001)
+		assert.InDelta(t, 5.623, first.Weight, 0.001)
+	}
+
+	if assert.Len(t, explanation.Breakdown, 2) {
+		assert.Equal(t, "robbery", explanation.Breakdown[0].RiskType)
+		assert.Equal(t, 2, explanation.Breakdown[0].IncidentCount)
+		assert.InDelta(t, 6.899, explanation.Breakdown[0].Weight, 0.001)
+		assert.Equal(t, "assault", explanation.Breakdown[1].RiskType)
+	}
+
+	redacted := explanation.Contributions[1].Redacted()
+	assert.Equal(t, private, explanation.Contributions[1].ReportID)
+	assert.Equal(t, uuid.Nil, redacted.ReportID)
+	assert.Zero(t, redacted.CreatedAt.In(model.LocalTimezone()).Hour())
+	assert.InDelta(t, 2.641, redacted.Weight, 0.001, "a redacted report still counts")
+}
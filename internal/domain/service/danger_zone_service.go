@@ -2,8 +2,10 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
 )
 
 type DangerZoneParams struct {
@@ -15,7 +17,21 @@ type DangerZoneParams struct {
 
 type DangerZoneService interface {
 	CalculateDangerZones(ctx context.Context) error
-	GetDangerZonesNearby(ctx context.Context, lat, lon, radiusMeters float64) ([]*model.DangerZone, error)
-	IsInDangerZone(ctx context.Context, lat, lon float64) (*model.DangerZone, error)
+	// GetDangerZonesNearby returns the zones within radiusMeters on the grid
+	// resolution that suits a map at zoom, or the radius itself when zoom is 0.
+	// A non-zero at scores each zone for the time slot at falls in instead of
+	// over the whole week. Manual zones active at that time, or now, come
+	// first.
+	GetDangerZonesNearby(ctx context.Context, lat, lon, radiusMeters float64, zoom int, at time.Time) ([]*model.DangerZone, error)
+	IsInDangerZone(ctx context.Context, lat, lon float64, at time.Time) (*model.DangerZone, error)
+	// ExplainDangerZone returns the reports and weights behind a zone as the
+	// calculation in effect at the given time, or the latest, recorded them.
+	ExplainDangerZone(ctx context.Context, gridCellID string, at time.Time) (*model.DangerZoneExplanation, error)
+	GetDangerZoneTrends(ctx context.Context, filter repository.DangerZoneSnapshotFilter) ([]model.DangerZoneTrendSeries, error)
+	// ManualZonesAlongRoute returns the manual zones active at the given
+	// time that the route passes through or close to.
+	ManualZonesAlongRoute(ctx context.Context, waypoints []model.Waypoint, at time.Time) []*model.ManualDangerZone
+	// RefreshManualZones reloads the manual zones after they change.
+	RefreshManualZones(ctx context.Context) error
 	InvalidateCache(ctx context.Context) error
 }
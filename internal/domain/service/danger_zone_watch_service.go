@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/event"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+)
+
+const (
+	dangerZonePresenceTTL            = 12 * time.Hour
+	dangerZonePresenceCacheKeyPrefix = "danger_zone_presence"
+)
+
+// DangerZoneWatchService follows users through the danger zone grid as their
+// location updates come in and dispatches a DangerZoneEnteredEvent when one
+// walks into a high risk zone.
+type DangerZoneWatchService struct {
+	dangerZone DangerZoneService
+	cache      CacheService
+	publisher  EventPublisher
+}
+
+func NewDangerZoneWatchService(
+	dangerZone DangerZoneService,
+	cache CacheService,
+	publisher EventPublisher,
+) *DangerZoneWatchService {
+	return &DangerZoneWatchService{
+		dangerZone: dangerZone,
+		cache:      cache,
+		publisher:  publisher,
+	}
+}
+
+func (s *DangerZoneWatchService) CheckLocation(ctx context.Context, userID uuid.UUID, deviceID string, lat, lon float64) {
+	now := time.Now()
+
+	zones, err := s.dangerZone.GetDangerZonesNearby(ctx, lat, lon, model.DangerZoneExitRadiusMeters, 0, now)
+	if err != nil {
+		slog.Debug("failed to get danger zones for presence check", "error", err, "user_id", userID.String())
+		return
+	}
+
+	key := s.presenceKey(userID, deviceID)
+	presence := s.loadPresence(ctx, key)
+
+	alert, exited := presence.Update(zones, lat, lon, now)
+
+	if exited {
+		slog.Debug("user left danger zone", "user_id", userID.String(), "device_id", deviceID)
+	}
+
+	data, err := json.Marshal(presence)
+	if err == nil {
+		err = s.cache.Set(ctx, key, string(data), dangerZonePresenceTTL)
+	}
+	if err != nil {
+		// Without the state the next update could alert again, so skip
+		// this one rather than risk a duplicate.
+		slog.Debug("failed to store danger zone presence", "error", err, "user_id", userID.String())
+		return
+	}
+
+	if alert == nil {
+		return
+	}
+
+	slog.Info("user entered danger zone",
+		"user_id", userID.String(),
+		"grid_cell_id", alert.GridCellID,
+		"risk_level", alert.RiskLevel,
+		"incident_count", alert.IncidentCount)
+
+	s.publisher.Dispatch(event.DangerZoneEnteredEvent{
+		UserID:        userID,
+		DeviceID:      deviceID,
+		Latitude:      lat,
+		Longitude:     lon,
+		ZoneID:        alert.ID,
+		GridCellID:    alert.GridCellID,
+		RiskLevel:     alert.RiskLevel,
+		IncidentCount: alert.IncidentCount,
+	})
+}
+
+func (s *DangerZoneWatchService) loadPresence(ctx context.Context, key string) *model.DangerZonePresence {
+	presence := &model.DangerZonePresence{}
+
+	data, err := s.cache.Get(ctx, key)
+	if err != nil || data == "" {
+		return presence
+	}
+	if err := json.Unmarshal([]byte(data), presence); err != nil {
+		slog.Debug("discarding invalid danger zone presence", "error", err, "key", key)
+		return &model.DangerZonePresence{}
+	}
+	return presence
+}
+
+func (s *DangerZoneWatchService) presenceKey(userID uuid.UUID, deviceID string) string {
+	if userID != uuid.Nil {
+		return fmt.Sprintf("%s:%s", dangerZonePresenceCacheKeyPrefix, userID.String())
+	}
+	return fmt.Sprintf("%s:device:%s", dangerZonePresenceCacheKeyPrefix, deviceID)
+}
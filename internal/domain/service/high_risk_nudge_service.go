@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/event"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
+)
+
+const (
+	highRiskNudgeCooldown       = 30 * time.Minute
+	highRiskNudgeRadiusMeters   = 500.0
+	highRiskNudgeIncidentWindow = 72 * time.Hour
+	highRiskNudgeMaxReports     = 50
+	highRiskNudgeCacheKeyPrefix = "high_risk_nudge"
+)
+
+// EventPublisher is the subset of the event dispatcher used by domain services.
+type EventPublisher interface {
+	Dispatch(event event.Event)
+}
+
+// HighRiskNudgeService sends a proactive safety nudge to users who are inside
+// their high risk time window and close to recent verified incidents or a
+// high risk danger zone.
+type HighRiskNudgeService struct {
+	reportRepo repository.ReportRepository
+	dangerZone DangerZoneService
+	cache      CacheService
+	publisher  EventPublisher
+}
+
+func NewHighRiskNudgeService(
+	reportRepo repository.ReportRepository,
+	dangerZone DangerZoneService,
+	cache CacheService,
+	publisher EventPublisher,
+) *HighRiskNudgeService {
+	return &HighRiskNudgeService{
+		reportRepo: reportRepo,
+		dangerZone: dangerZone,
+		cache:      cache,
+		publisher:  publisher,
+	}
+}
+
+func (s *HighRiskNudgeService) CheckAndNudge(ctx context.Context, userID uuid.UUID, deviceID string, lat, lon float64) {
+	cooldownKey := s.cooldownKey(userID, deviceID)
+	if last, err := s.cache.Get(ctx, cooldownKey); err == nil && last != "" {
+		return
+	}
+
+	ev := event.HighRiskNudgeEvent{
+		UserID:         userID,
+		DeviceID:       deviceID,
+		Latitude:       lat,
+		Longitude:      lon,
+		RiskTypeCounts: make(map[string]int),
+	}
+
+	s.collectNearbyIncidents(ctx, &ev)
+
+	zone, err := s.dangerZone.IsInDangerZone(ctx, lat, lon, time.Now())
+	if err == nil && zone != nil {
+		ev.DangerZoneRiskLevel = zone.RiskLevel
+	}
+
+	if ev.IncidentCount == 0 && ev.DangerZoneRiskLevel == "" {
+		return
+	}
+
+	// REST and websocket location updates can race past the Get above; only
+	// the caller that claims the cooldown key sends the nudge.
+	claimed, err := s.cache.SetNX(ctx, cooldownKey, time.Now().Format(time.RFC3339), highRiskNudgeCooldown)
+	if err != nil {
+		slog.Debug("failed to claim high risk nudge cooldown", "error", err, "user_id", userID.String())
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	slog.Info("sending high risk nudge",
+		"user_id", userID.String(),
+		"incident_count", ev.IncidentCount,
+		"danger_zone_level", ev.DangerZoneRiskLevel)
+
+	s.publisher.Dispatch(ev)
+}
+
+func (s *HighRiskNudgeService) collectNearbyIncidents(ctx context.Context, ev *event.HighRiskNudgeEvent) {
+	since := time.Now().Add(-highRiskNudgeIncidentWindow)
+	reports, err := s.reportRepo.FindVerifiedNearSince(ctx, ev.Latitude, ev.Longitude, highRiskNudgeRadiusMeters, since, highRiskNudgeMaxReports)
+	if err != nil {
+		slog.Debug("failed to find reports for high risk nudge", "error", err)
+		return
+	}
+
+	for _, r := range reports {
+		ev.IncidentCount++
+		ev.RiskTypeCounts[r.Report.RiskTypeName]++
+
+		if ev.NearestIncidentMeters == 0 || r.Distance < ev.NearestIncidentMeters {
+			ev.NearestIncidentMeters = r.Distance
+		}
+	}
+}
+
+func (s *HighRiskNudgeService) cooldownKey(userID uuid.UUID, deviceID string) string {
+	if userID != uuid.Nil {
+		return fmt.Sprintf("%s:%s", highRiskNudgeCacheKeyPrefix, userID.String())
+	}
+	return fmt.Sprintf("%s:device:%s", highRiskNudgeCacheKeyPrefix, deviceID)
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/event"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type verifiedReportRepo struct {
+	repository.ReportRepository
+}
+
+func (verifiedReportRepo) FindVerifiedNearSince(context.Context, float64, float64, float64, time.Time, int) ([]repository.ReportWithDistance, error) {
+	return []repository.ReportWithDistance{
+		{Report: &model.Report{RiskTypeName: "crime", Status: model.ReportStatusVerified}, Distance: 120},
+	}, nil
+}
+
+type noZoneService struct {
+	DangerZoneService
+}
+
+func (noZoneService) IsInDangerZone(context.Context, float64, float64, time.Time) (*model.DangerZone, error) {
+	return nil, errors.New("not in a zone")
+}
+
+// lockedCache is an in-memory cache whose SetNX is atomic like Redis.
+type lockedCache struct {
+	CacheService
+	mu   sync.Mutex
+	keys map[string]string
+}
+
+func (c *lockedCache) Get(_ context.Context, key string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.keys[key], nil
+}
+
+func (c *lockedCache) SetNX(_ context.Context, key, value string, _ time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.keys[key]; ok {
+		return false, nil
+	}
+	c.keys[key] = value
+	return true, nil
+}
+
+type countingPublisher struct {
+	sent atomic.Int32
+}
+
+func (p *countingPublisher) Dispatch(event.Event) { p.sent.Add(1) }
+
+func TestHighRiskNudge_ConcurrentUpdatesNudgeOnce(t *testing.T) {
+	cache := &lockedCache{keys: make(map[string]string)}
+	publisher := &countingPublisher{}
+	s := NewHighRiskNudgeService(verifiedReportRepo{}, noZoneService{}, cache, publisher)
+	userID := uuid.New()
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.CheckAndNudge(context.Background(), userID, "", -8.8383, 13.2344)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), publisher.sent.Load())
+}
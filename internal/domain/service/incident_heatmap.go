@@ -0,0 +1,52 @@
+package service
+
+import (
+	"sort"
+
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+)
+
+const (
+	heatmapWeightDivisor = 10.0
+	heatmapMaxWeight     = 10.0
+	heatmapMaxPoints     = 500
+)
+
+// BuildIncidentHeatmap merges the report groups of each spot and risk type. A
+// point's weight is the sum of its reports' weights, scaled down and capped at
+// 10; the points with the most reports come first.
+func BuildIncidentHeatmap(groups []model.HeatmapIncidentGroup, weighting *model.IncidentWeighting) []model.HeatmapPoint {
+	type spot struct {
+		lat, lon float64
+		riskType string
+	}
+
+	points := make(map[spot]*model.HeatmapPoint)
+	var order []spot
+	for _, group := range groups {
+		key := spot{group.Latitude, group.Longitude, group.RiskType}
+		point, ok := points[key]
+		if !ok {
+			point = &model.HeatmapPoint{Latitude: key.lat, Longitude: key.lon, IncidentType: key.riskType}
+			points[key] = point
+			order = append(order, key)
+		}
+		point.ReportCount += group.ReportCount
+		point.Weight += weighting.GroupWeight(group.RiskType, group.RiskTopic, group.ReportCount, group.Decay)
+	}
+
+	heatmap := make([]model.HeatmapPoint, 0, len(points))
+	for _, key := range order {
+		point := points[key]
+		point.Weight = min(point.Weight/heatmapWeightDivisor, heatmapMaxWeight)
+		heatmap = append(heatmap, *point)
+	}
+	sort.SliceStable(heatmap, func(i, j int) bool {
+		return heatmap[i].ReportCount > heatmap[j].ReportCount
+	})
+
+	if len(heatmap) > heatmapMaxPoints {
+		heatmap = heatmap[:heatmapMaxPoints]
+	}
+	return heatmap
+}
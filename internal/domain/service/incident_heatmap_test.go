@@ -0,0 +1,31 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildIncidentHeatmap_WeighsByRule(t *testing.T) {
+	weighting := model.NewIncidentWeighting([]*model.IncidentWeightRule{
+		{RiskType: "crime", Severity: 2, HalfLifeDays: 7},
+	})
+
+	// A fire just now; a crime just now and one a half-life ago, reported
+	// under different topics.
+	groups := []model.HeatmapIncidentGroup{
+		{RiskType: "fire", Latitude: -8.83, Longitude: 13.24, ReportCount: 1, Decay: 1},
+		{RiskType: "crime", RiskTopic: "theft", Latitude: -8.81, Longitude: 13.23, ReportCount: 1, Decay: 1},
+		{RiskType: "crime", RiskTopic: "robbery", Latitude: -8.81, Longitude: 13.23, ReportCount: 1, Decay: 0.5},
+	}
+
+	heatmap := BuildIncidentHeatmap(groups, weighting)
+	if assert.Len(t, heatmap, 2) {
+		assert.Equal(t, "crime", heatmap[0].IncidentType, "the busiest spot comes first")
+		assert.Equal(t, 2, heatmap[0].ReportCount)
+		// (2 * 3 + 2 * 2) / 10
+		assert.InDelta(t, 1.0, heatmap[0].Weight, 0.001)
+		assert.InDelta(t, 0.3, heatmap[1].Weight, 0.001)
+	}
+}
@@ -0,0 +1,17 @@
+package service
+
+import (
+	"context"
+
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+)
+
+// IncidentWeightingService hands out the weighting model that safe routes,
+// heatmaps and danger zones share.
+type IncidentWeightingService interface {
+	// Weighting returns the current model. It never fails: when the rules
+	// cannot be loaded it keeps the last ones, or the defaults.
+	Weighting(ctx context.Context) *model.IncidentWeighting
+	// Refresh reloads the rules, e.g. after an admin changed them.
+	Refresh(ctx context.Context) error
+}
@@ -27,6 +27,7 @@ type LocationHistoryService interface {
 type CacheService interface {
 	Get(ctx context.Context, key string) (string, error)
 	Set(ctx context.Context, key, value string, ttl time.Duration) error
+	SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error)
 	Delete(ctx context.Context, key string) error
 	HGet(ctx context.Context, key, field string) (string, error)
 	HGetAll(ctx context.Context, key string) (map[string]string, error)
@@ -34,6 +35,7 @@ type CacheService interface {
 	HExpire(ctx context.Context, key string, expiration time.Duration, fields ...string)
 	GeoAdd(ctx context.Context, key string, longitude, latitude float64, member string) error
 	GeoSearchWithDistance(ctx context.Context, key string, longitude, latitude, radiusMeters float64) ([]GeoResult, error)
+	Expire(ctx context.Context, key string, ttl time.Duration) error
 }
 
 const (
@@ -51,7 +53,9 @@ type NearbyUsersServiceV2 struct {
 	cache           CacheService
 	locationHistory LocationHistoryService
 	settingsChecker SettingsChecker
-	dangerZone      DangerZoneService
+	dangerZoneWatch *DangerZoneWatchService
+	highRiskNudge   *HighRiskNudgeService
+	tripMonitor     *TripMonitorService
 	useRedis        bool
 	fallbackToPG    bool
 	cacheHits       int64
@@ -65,7 +69,9 @@ func NewNearbyUsersServiceV2(
 	cache CacheService,
 	locationHistory LocationHistoryService,
 	settingsChecker SettingsChecker,
-	dangerZone DangerZoneService,
+	dangerZoneWatch *DangerZoneWatchService,
+	highRiskNudge *HighRiskNudgeService,
+	tripMonitor *TripMonitorService,
 	useRedis bool,
 ) NearbyUsersService {
 	return &NearbyUsersServiceV2{
@@ -74,7 +80,9 @@ func NewNearbyUsersServiceV2(
 		cache:           cache,
 		locationHistory: locationHistory,
 		settingsChecker: settingsChecker,
-		dangerZone:      dangerZone,
+		dangerZoneWatch: dangerZoneWatch,
+		highRiskNudge:   highRiskNudge,
+		tripMonitor:     tripMonitor,
 		useRedis:        useRedis,
 		fallbackToPG:    true,
 	}
@@ -100,12 +108,28 @@ func (s *NearbyUsersServiceV2) UpdateUserLocation(
 		go s.invalidateNearbyCache(context.WithoutCancel(ctx), userID)
 	}
 
-	if s.settingsChecker.CanSaveLocationHistory(ctx, userID, deviceID) {
+	// Anonymous callers pass their device UUID as userID; their settings
+	// are stored by device.
+	settingsUserID := userID
+	if isAnonymous {
+		settingsUserID = uuid.Nil
+	}
+
+	if s.settingsChecker.CanSaveLocationHistory(ctx, settingsUserID, deviceID) {
 		go s.saveLocationHistory(context.WithoutCancel(ctx), userID, lat, lon, speed, heading, deviceID)
 	}
 
-	if s.settingsChecker.HasDangerZonesEnabled(ctx, userID, deviceID) {
-		go s.checkDangerZone(context.WithoutCancel(ctx), userID, lat, lon)
+	if s.dangerZoneWatch != nil && s.settingsChecker.HasDangerZonesEnabled(ctx, settingsUserID, deviceID) {
+		go s.dangerZoneWatch.CheckLocation(context.WithoutCancel(ctx), settingsUserID, deviceID, lat, lon)
+	}
+
+	if s.highRiskNudge != nil && s.settingsChecker.IsInHighRiskTime(ctx, settingsUserID, deviceID) {
+		go s.highRiskNudge.CheckAndNudge(context.WithoutCancel(ctx), settingsUserID, deviceID, lat, lon)
+	}
+
+	// Trips need an account, for the emergency contacts.
+	if s.tripMonitor != nil && !isAnonymous {
+		go s.tripMonitor.TrackLocation(context.WithoutCancel(ctx), userID, lat, lon)
 	}
 
 	return nil
@@ -397,22 +421,6 @@ func (s *NearbyUsersServiceV2) CleanupStaleLocations(ctx context.Context) error
 	return s.repo.DeleteStale(ctx, staleLocationThresholdSeconds)
 }
 
-func (s *NearbyUsersServiceV2) checkDangerZone(ctx context.Context, userID uuid.UUID, lat, lon float64) {
-	zone, err := s.dangerZone.IsInDangerZone(ctx, lat, lon)
-	if err != nil {
-		slog.Debug("failed to check danger zone", "error", err, "user_id", userID.String())
-		return
-	}
-
-	if zone != nil {
-		slog.Info("user entered danger zone", 
-			"user_id", userID.String(), 
-			"zone_id", zone.ID.String(),
-			"risk_level", zone.RiskLevel,
-			"incident_count", zone.IncidentCount)
-	}
-}
-
 func (s *NearbyUsersServiceV2) GetMetrics() map[string]interface{} {
 	total := s.cacheHits + s.cacheMisses
 	hitRate := float64(0)
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubLocationRepo struct {
+	repository.UserLocationRepository
+}
+
+func (stubLocationRepo) Upsert(context.Context, *model.UserLocation) error { return nil }
+
+// deviceSettingsRepo stores settings for anonymous devices only, the way
+// an account-less device is seen by the real repository.
+type deviceSettingsRepo struct {
+	repository.SafetySettingsRepository
+	devices map[string]*model.SafetySettings
+}
+
+func (r *deviceSettingsRepo) GetByUserID(context.Context, uuid.UUID) (*model.SafetySettings, error) {
+	return nil, errors.New("settings not found")
+}
+
+func (r *deviceSettingsRepo) GetByDeviceID(_ context.Context, deviceID string) (*model.SafetySettings, error) {
+	settings, ok := r.devices[deviceID]
+	if !ok {
+		return nil, errors.New("settings not found")
+	}
+	return settings, nil
+}
+
+// zoneLookupRecorder signals every presence check the watch service makes,
+// then fails it so the check stops there.
+type zoneLookupRecorder struct {
+	DangerZoneService
+	checked chan struct{}
+}
+
+func (z *zoneLookupRecorder) GetDangerZonesNearby(context.Context, float64, float64, float64, int, time.Time) ([]*model.DangerZone, error) {
+	z.checked <- struct{}{}
+	return nil, errors.New("lookup recorded")
+}
+
+func TestUpdateUserLocation_AnonymousDangerZoneOptOut(t *testing.T) {
+	tests := []struct {
+		name        string
+		enabled     bool
+		wantChecked bool
+	}{
+		{name: "opted out device is not watched", enabled: false},
+		{name: "opted in device is watched", enabled: true, wantChecked: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deviceID := uuid.NewString()
+			settings := &deviceSettingsRepo{devices: map[string]*model.SafetySettings{
+				deviceID: {DangerZonesEnabled: tt.enabled},
+			}}
+			zones := &zoneLookupRecorder{checked: make(chan struct{}, 1)}
+			s := NewNearbyUsersServiceV2(
+				stubLocationRepo{},
+				settings,
+				nil,
+				nil,
+				NewSettingsChecker(settings, nil),
+				NewDangerZoneWatchService(zones, nil, nil),
+				nil,
+				nil,
+				false,
+			)
+
+			// Anonymous callers pass their device UUID as the user ID.
+			err := s.UpdateUserLocation(context.Background(), uuid.MustParse(deviceID), deviceID, -8.8383, 13.2344, 0, 0, true)
+			assert.NoError(t, err)
+
+			select {
+			case <-zones.checked:
+				assert.True(t, tt.wantChecked, "an opted-out device must not get danger zone checks")
+			case <-time.After(100 * time.Millisecond):
+				assert.False(t, tt.wantChecked, "an opted-in device must get danger zone checks")
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"container/heap"
+	"context"
+	"math"
+
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+)
+
+const (
+	// maxRoadSearchNodes stops a search that would otherwise crawl the whole
+	// country, e.g. between two points with no road between them.
+	maxRoadSearchNodes = 2_000_000
+	// roadSearchCheckEvery is how many nodes a search settles between
+	// checks that its context is still live.
+	roadSearchCheckEvery = 4096
+	// maxRouteOverlap is the share of its length an alternative may have in
+	// common with another before it counts as the same route.
+	maxRouteOverlap = 0.7
+	// routeReusePenalty raises the cost of roads an earlier alternative took,
+	// steering the next search elsewhere.
+	routeReusePenalty = 1.5
+)
+
+// RoadGraphProvider hands out the road network. It returns nil while the
+// network is still loading or when none is configured.
+type RoadGraphProvider interface {
+	RoadGraph() *model.RoadGraph
+}
+
+// RoadEdgeCost prices an edge leaving from. The bool is false when the edge
+// may not be used at all.
+type RoadEdgeCost func(from int32, edge model.RoadEdge) (float64, bool)
+
+// RoadPath is a path through the graph; Edges[i] leads from Nodes[i] to
+// Nodes[i+1].
+type RoadPath struct {
+	Nodes []int32
+	Edges []model.RoadEdge
+	Cost  float64
+}
+
+type roadEdgeKey struct {
+	from, to int32
+}
+
+type roadPathStep struct {
+	from int32
+	edge model.RoadEdge
+}
+
+// FindRoadPath runs A* between two nodes. minCostPerMeter is the lowest cost
+// any edge can have per meter of length; it scales the straight-line
+// heuristic so the search stays exact. The search gives up, finding
+// nothing, once ctx is done.
+func FindRoadPath(ctx context.Context, graph *model.RoadGraph, from, to int32, cost RoadEdgeCost, minCostPerMeter float64) (RoadPath, bool) {
+	toLat, toLon := graph.Node(to)
+	heuristic := func(node int32) float64 {
+		lat, lon := graph.Node(node)
+		return model.DistanceMeters(lat, lon, toLat, toLon) * minCostPerMeter
+	}
+
+	best := map[int32]float64{from: 0}
+	came := make(map[int32]roadPathStep)
+	closed := make(map[int32]bool)
+	open := &roadQueue{{node: from, priority: heuristic(from)}}
+
+	for open.Len() > 0 && len(closed) < maxRoadSearchNodes {
+		current := heap.Pop(open).(roadQueueItem) //nolint:errcheck,forcetypeassert // the queue only holds roadQueueItem
+		if closed[current.node] {
+			continue
+		}
+		if current.node == to {
+			return buildRoadPath(came, from, to, best[to]), true
+		}
+		closed[current.node] = true
+		if len(closed)%roadSearchCheckEvery == 0 && ctx.Err() != nil {
+			return RoadPath{}, false
+		}
+
+		for _, edge := range graph.Edges(current.node) {
+			if closed[edge.To] {
+				continue
+			}
+			edgeCost, ok := cost(current.node, edge)
+			if !ok {
+				continue
+			}
+			candidate := best[current.node] + edgeCost
+			if known, seen := best[edge.To]; seen && candidate >= known {
+				continue
+			}
+			best[edge.To] = candidate
+			came[edge.To] = roadPathStep{from: current.node, edge: edge}
+			heap.Push(open, roadQueueItem{node: edge.To, priority: candidate + heuristic(edge.To)})
+		}
+	}
+	return RoadPath{}, false
+}
+
+// alternativeRoadPaths looks for up to maxRoutes distinct paths. It searches
+// with a balanced, then no, then a strong aversion to risk, and after that
+// keeps penalizing the roads already taken. costFor builds the edge cost for
+// an aversion, multiplied by the penalty of the edges in penalties.
+func alternativeRoadPaths(
+	ctx context.Context,
+	graph *model.RoadGraph,
+	from, to int32,
+	maxRoutes int,
+	costFor func(aversion float64, penalties map[roadEdgeKey]float64) RoadEdgeCost,
+) []RoadPath {
+	aversions := []float64{1, 0, 4} //nolint:mnd // balanced, shortest, safest
+	penalties := make(map[roadEdgeKey]float64)
+
+	var paths []RoadPath
+	for attempt := 0; len(paths) < maxRoutes && attempt < maxRoutes+len(aversions); attempt++ {
+		aversion, attemptPenalties := 1.0, penalties
+		if attempt < len(aversions) {
+			aversion, attemptPenalties = aversions[attempt], nil
+		}
+
+		path, found := FindRoadPath(ctx, graph, from, to, costFor(aversion, attemptPenalties), 1)
+		if !found {
+			// Costs never change which edges are usable, so a search that
+			// found nothing was either unreachable or out of budget, and
+			// another one would be too.
+			break
+		}
+
+		for i, edge := range path.Edges {
+			key := roadEdgeKey{path.Nodes[i], edge.To}
+			if _, ok := penalties[key]; !ok {
+				penalties[key] = 1
+			}
+			penalties[key] *= routeReusePenalty
+		}
+
+		distinct := true
+		for _, other := range paths {
+			if roadPathOverlap(path, other) > maxRouteOverlap {
+				distinct = false
+				break
+			}
+		}
+		if distinct {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// roadPathOverlap is the length two paths share, as a share of the shorter
+// one.
+func roadPathOverlap(a, b RoadPath) float64 {
+	inB := make(map[roadEdgeKey]bool, len(b.Edges))
+	var lengthB float64
+	for i, edge := range b.Edges {
+		inB[roadEdgeKey{b.Nodes[i], edge.To}] = true
+		lengthB += float64(edge.LengthMeters)
+	}
+
+	var lengthA, shared float64
+	for i, edge := range a.Edges {
+		lengthA += float64(edge.LengthMeters)
+		if inB[roadEdgeKey{a.Nodes[i], edge.To}] {
+			shared += float64(edge.LengthMeters)
+		}
+	}
+
+	shorter := math.Min(lengthA, lengthB)
+	if shorter == 0 {
+		return 1
+	}
+	return shared / shorter
+}
+
+func buildRoadPath(came map[int32]roadPathStep, from, to int32, cost float64) RoadPath {
+	path := RoadPath{Nodes: []int32{to}, Cost: cost}
+	for node := to; node != from; {
+		step := came[node]
+		path.Nodes = append(path.Nodes, step.from)
+		path.Edges = append(path.Edges, step.edge)
+		node = step.from
+	}
+
+	for i, j := 0, len(path.Nodes)-1; i < j; i, j = i+1, j-1 {
+		path.Nodes[i], path.Nodes[j] = path.Nodes[j], path.Nodes[i]
+	}
+	for i, j := 0, len(path.Edges)-1; i < j; i, j = i+1, j-1 {
+		path.Edges[i], path.Edges[j] = path.Edges[j], path.Edges[i]
+	}
+	return path
+}
+
+type roadQueueItem struct {
+	node     int32
+	priority float64
+}
+
+type roadQueue []roadQueueItem
+
+func (q *roadQueue) Len() int           { return len(*q) }
+func (q *roadQueue) Less(i, j int) bool { return (*q)[i].priority < (*q)[j].priority }
+func (q *roadQueue) Swap(i, j int)      { (*q)[i], (*q)[j] = (*q)[j], (*q)[i] }
+
+func (q *roadQueue) Push(x any) {
+	*q = append(*q, x.(roadQueueItem)) //nolint:errcheck,forcetypeassert // heap.Push only passes roadQueueItem
+}
+
+func (q *roadQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindRoadPath_PrefersSaferDetour(t *testing.T) {
+	builder := &model.RoadGraphBuilder{}
+	origin := builder.AddNode(-8.8300, 13.2300)
+	risky := builder.AddNode(-8.8300, 13.2350)
+	detour := builder.AddNode(-8.8340, 13.2350)
+	destination := builder.AddNode(-8.8300, 13.2400)
+	builder.AddWay([]int32{origin, risky, destination}, model.RoadClassResidential, 0, 0)
+	builder.AddWay([]int32{origin, detour, destination}, model.RoadClassResidential, 0, 0)
+	graph := builder.Build()
+
+	length := func(_ int32, edge model.RoadEdge) (float64, bool) {
+		return float64(edge.LengthMeters), true
+	}
+	shortest, found := FindRoadPath(context.Background(), graph, origin, destination, length, 1)
+	if assert.True(t, found) {
+		assert.Equal(t, []int32{origin, risky, destination}, shortest.Nodes)
+		assert.Len(t, shortest.Edges, 2)
+	}
+
+	avoidRisk := func(from int32, edge model.RoadEdge) (float64, bool) {
+		cost := float64(edge.LengthMeters)
+		if edge.To == risky || from == risky {
+			cost *= 3
+		}
+		return cost, true
+	}
+	safest, found := FindRoadPath(context.Background(), graph, origin, destination, avoidRisk, 1)
+	if assert.True(t, found) {
+		assert.Equal(t, []int32{origin, detour, destination}, safest.Nodes)
+		assert.Greater(t, safest.Cost, shortest.Cost)
+	}
+}
+
+func TestFindRoadPath_RespectsOneway(t *testing.T) {
+	builder := &model.RoadGraphBuilder{}
+	a := builder.AddNode(-8.8300, 13.2300)
+	b := builder.AddNode(-8.8300, 13.2350)
+	builder.AddWay([]int32{a, b}, model.RoadClassPrimary, 1, 0)
+	graph := builder.Build()
+
+	driving := func(_ int32, edge model.RoadEdge) (float64, bool) {
+		return float64(edge.LengthMeters), !edge.AgainstOneway
+	}
+	_, found := FindRoadPath(context.Background(), graph, a, b, driving, 1)
+	assert.True(t, found)
+	_, found = FindRoadPath(context.Background(), graph, b, a, driving, 1)
+	assert.False(t, found)
+}
+
+func TestAlternativeRoadPaths_ReturnsDistinctRoutes(t *testing.T) {
+	builder := &model.RoadGraphBuilder{}
+	origin := builder.AddNode(-8.8300, 13.2300)
+	north := builder.AddNode(-8.8280, 13.2350)
+	south := builder.AddNode(-8.8330, 13.2350)
+	destination := builder.AddNode(-8.8300, 13.2400)
+	builder.AddWay([]int32{origin, north, destination}, model.RoadClassResidential, 0, 0)
+	builder.AddWay([]int32{origin, south, destination}, model.RoadClassResidential, 0, 0)
+	graph := builder.Build()
+
+	costFor := func(_ float64, penalties map[roadEdgeKey]float64) RoadEdgeCost {
+		return func(from int32, edge model.RoadEdge) (float64, bool) {
+			cost := float64(edge.LengthMeters)
+			if penalty, ok := penalties[roadEdgeKey{from, edge.To}]; ok {
+				cost *= penalty
+			}
+			return cost, true
+		}
+	}
+
+	paths := alternativeRoadPaths(context.Background(), graph, origin, destination, 3, costFor)
+	if assert.Len(t, paths, 2) {
+		assert.Equal(t, north, paths[0].Nodes[1])
+		assert.Equal(t, south, paths[1].Nodes[1])
+	}
+
+	assert.Len(t, alternativeRoadPaths(context.Background(), graph, origin, destination, 1, costFor), 1)
+}
+
+func TestFindRoadPath_StopsWhenContextIsDone(t *testing.T) {
+	builder := &model.RoadGraphBuilder{}
+	nodes := make([]int32, 0, 3*roadSearchCheckEvery)
+	for i := range 3 * roadSearchCheckEvery {
+		nodes = append(nodes, builder.AddNode(-8.8300, 13.2300+float64(i)*0.0001))
+	}
+	builder.AddWay(nodes, model.RoadClassResidential, 0, 0)
+	graph := builder.Build()
+
+	length := func(_ int32, edge model.RoadEdge) (float64, bool) {
+		return float64(edge.LengthMeters), true
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, found := FindRoadPath(ctx, graph, nodes[0], nodes[len(nodes)-1], length, 1)
+	assert.False(t, found)
+
+	_, found = FindRoadPath(context.Background(), graph, nodes[0], nodes[len(nodes)-1], length, 1)
+	assert.True(t, found)
+}
+
+func TestAlternativeRoadPaths_StopsAfterUnreachableSearch(t *testing.T) {
+	builder := &model.RoadGraphBuilder{}
+	origin := builder.AddNode(-8.8300, 13.2300)
+	island := builder.AddNode(-8.8300, 13.2400)
+	builder.AddWay([]int32{origin, builder.AddNode(-8.8300, 13.2350)}, model.RoadClassResidential, 0, 0)
+	graph := builder.Build()
+
+	searches := 0
+	costFor := func(float64, map[roadEdgeKey]float64) RoadEdgeCost {
+		searches++
+		return func(_ int32, edge model.RoadEdge) (float64, bool) {
+			return float64(edge.LengthMeters), true
+		}
+	}
+
+	assert.Empty(t, alternativeRoadPaths(context.Background(), graph, origin, island, 3, costFor))
+	assert.Equal(t, 1, searches)
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
+)
+
+const (
+	// routeSnapMeters is how far from a road origin and destination may be.
+	routeSnapMeters = 1000.0
+	// routeAreaBufferMeters widens the area priced around the straight line,
+	// leaving room for detours.
+	routeAreaBufferMeters = 3000.0
+	// routeZoneSearchMeters bounds the danger zone lookup of long trips.
+	routeZoneSearchMeters = 20000.0
+	routeCorridorKm       = 2.0
+	// routeSearchTimeout bounds the road searches of one request.
+	routeSearchTimeout = 5 * time.Second
+	// routeSimplifyMeters is how far the returned waypoints may stray from
+	// the road geometry.
+	routeSimplifyMeters = 5.0
+
+	// straightLineSpacingMeters and straightLineMaxWaypoints place the
+	// waypoints of a route without a road network.
+	straightLineSpacingMeters = 2000.0
+	straightLineMaxWaypoints  = 10
+
+	riskFieldCellMeters      = 50.0
+	incidentRiskRadiusMeters = 250.0
+	zoneRiskFalloffMeters    = 150.0
+	// incidentRiskPerWeight and zoneRiskPerScore convert an incident's
+	// weight and a zone's risk score into extra cost per meter of road: a
+	// road through a critical zone counts about three times its length.
+	incidentRiskPerWeight = 0.1
+	zoneRiskPerScore      = 0.2
+)
+
+// SafeRoutePlanner finds routes along the road network that trade distance
+// for safety, pricing each road segment by the incidents and danger zones
+// around it.
+type SafeRoutePlanner struct {
+	graphs     RoadGraphProvider
+	repo       repository.SafeRouteRepository
+	dangerZone DangerZoneService
+	weights    IncidentWeightingService
+	// maxTripMeters rejects trips whose ends are further apart; zero allows
+	// any length.
+	maxTripMeters float64
+}
+
+func NewSafeRoutePlanner(
+	graphs RoadGraphProvider,
+	repo repository.SafeRouteRepository,
+	dangerZone DangerZoneService,
+	weights IncidentWeightingService,
+	maxTripKm float64,
+) *SafeRoutePlanner {
+	return &SafeRoutePlanner{
+		graphs:        graphs,
+		repo:          repo,
+		dangerZone:    dangerZone,
+		weights:       weights,
+		maxTripMeters: maxTripKm * 1000, //nolint:mnd // km to meters
+	}
+}
+
+// PlanRoutes returns up to params.MaxRoutes road routes that differ
+// meaningfully, from the shortest road to the one that avoids the most risk.
+// Until the road network has loaded it falls back to scoring the straight
+// line.
+func (p *SafeRoutePlanner) PlanRoutes(ctx context.Context, params repository.RouteCalculationParams) ([]*model.SafeRoute, error) {
+	if p.maxTripMeters > 0 {
+		distance := model.DistanceMeters(params.OriginLat, params.OriginLon, params.DestinationLat, params.DestinationLon)
+		if distance > p.maxTripMeters {
+			return nil, fmt.Errorf("%w: origin and destination are more than %.0f km apart",
+				domainErrors.ErrInvalidRequest, p.maxTripMeters/1000) //nolint:mnd // meters to km
+		}
+	}
+
+	weighting := p.weights.Weighting(ctx)
+
+	graph := p.graphs.RoadGraph()
+	if graph == nil {
+		route := straightLineRoute(params)
+		if err := p.scoreRoute(ctx, route, weighting, params); err != nil {
+			return nil, err
+		}
+		return []*model.SafeRoute{route}, nil
+	}
+
+	usable := params.Mode.CanUse
+	from, fromOK := graph.Nearest(params.OriginLat, params.OriginLon, routeSnapMeters, usable)
+	to, toOK := graph.Nearest(params.DestinationLat, params.DestinationLon, routeSnapMeters, usable)
+	if !fromOK || !toOK {
+		return nil, domainErrors.ErrRouteNotFound
+	}
+
+	field, err := p.riskField(ctx, weighting, params)
+	if err != nil {
+		return nil, err
+	}
+
+	searchCtx, cancel := context.WithTimeout(ctx, routeSearchTimeout)
+	defer cancel()
+
+	paths := alternativeRoadPaths(searchCtx, graph, from, to, max(params.MaxRoutes, 1), func(aversion float64, penalties map[roadEdgeKey]float64) RoadEdgeCost {
+		return func(from int32, edge model.RoadEdge) (float64, bool) {
+			if !usable(edge) {
+				return 0, false
+			}
+			lat, lon := graph.Node(edge.To)
+			cost := float64(edge.LengthMeters) * (1 + aversion*field.At(lat, lon))
+			if penalty, ok := penalties[roadEdgeKey{from, edge.To}]; ok {
+				cost *= penalty
+			}
+			return cost, true
+		}
+	})
+	if len(paths) == 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return nil, domainErrors.ErrRouteNotFound
+	}
+
+	routes := make([]*model.SafeRoute, 0, len(paths))
+	for _, path := range paths {
+		route := p.buildRoute(graph, path, params)
+		if err := p.scoreRoute(ctx, route, weighting, params); err != nil {
+			return nil, err
+		}
+		routes = append(routes, route)
+	}
+	return routes, nil
+}
+
+func (p *SafeRoutePlanner) scoreRoute(
+	ctx context.Context,
+	route *model.SafeRoute,
+	weighting *model.IncidentWeighting,
+	params repository.RouteCalculationParams,
+) error {
+	incidents, err := p.repo.GetIncidentsForRoute(ctx, route.Waypoints, routeCorridorKm)
+	if err != nil {
+		return fmt.Errorf("failed to get incidents for route: %w", err)
+	}
+
+	now := time.Now()
+	for _, incident := range incidents {
+		weight := weighting.RouteWeight(incident.RiskType, incident.RiskTopic, now.Sub(incident.CreatedAt), incident.DistanceKm*1000) //nolint:mnd // km to meters
+		incident.WeightFactor = tripIncidentWeight(weight, incident, params)
+		route.AddIncident(incident)
+	}
+	route.CalculateSafetyScore()
+	return nil
+}
+
+// tripIncidentWeight adjusts an incident's weight for the travel mode and,
+// when the departure time is known, the time of day.
+func tripIncidentWeight(weight float64, incident model.IncidentNearRoute, params repository.RouteCalculationParams) float64 {
+	weight *= params.Mode.IncidentWeight(incident.RiskType, incident.RiskTopic)
+	if !params.DepartureAt.IsZero() {
+		weight *= model.IncidentTimeSlotWeight(incident.CreatedAt, params.DepartureAt)
+	}
+	return weight
+}
+
+// riskField prices the area around the straight line between origin and
+// destination.
+func (p *SafeRoutePlanner) riskField(
+	ctx context.Context,
+	weighting *model.IncidentWeighting,
+	params repository.RouteCalculationParams,
+) (*model.RiskField, error) {
+	centerLat := (params.OriginLat + params.DestinationLat) / 2 //nolint:mnd // midpoint
+	centerLon := (params.OriginLon + params.DestinationLon) / 2 //nolint:mnd // midpoint
+	radius := model.DistanceMeters(params.OriginLat, params.OriginLon, params.DestinationLat, params.DestinationLon)/2 +
+		routeAreaBufferMeters
+
+	area := model.BoundingBoxAround(centerLat, centerLon, radius)
+	field := model.NewRiskField(area, riskFieldCellMeters)
+
+	incidents, err := p.repo.ListIncidentsInArea(ctx, area)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load incidents for route: %w", err)
+	}
+	now := time.Now()
+	for _, incident := range incidents {
+		weight := tripIncidentWeight(weighting.Weight(incident.RiskType, incident.RiskTopic, now.Sub(incident.CreatedAt)), incident, params)
+		field.AddPoint(incident.Latitude, incident.Longitude, weight*incidentRiskPerWeight, incidentRiskRadiusMeters)
+	}
+
+	zones, err := p.dangerZone.GetDangerZonesNearby(ctx, centerLat, centerLon, math.Min(radius, routeZoneSearchMeters), 0, params.DepartureAt)
+	if err != nil {
+		// Incidents alone still give a risk-aware route.
+		slog.Warn("failed to load danger zones for route", "error", err)
+		return field, nil
+	}
+	for _, zone := range zones {
+		cellWidth, cellHeight := model.GeohashCellSizeMeters(zone.Precision)
+		field.AddZone(zone, zone.RiskScore*zoneRiskPerScore, math.Max(cellWidth, cellHeight)/2, zoneRiskFalloffMeters) //nolint:mnd // half a cell
+	}
+
+	return field, nil
+}
+
+func (p *SafeRoutePlanner) buildRoute(graph *model.RoadGraph, path RoadPath, params repository.RouteCalculationParams) *model.SafeRoute {
+	route := model.NewSafeRoute(params.OriginLat, params.OriginLon, params.DestinationLat, params.DestinationLon)
+	route.Mode = params.Mode
+
+	points := make([]model.Waypoint, 0, len(path.Nodes)+2) //nolint:mnd // origin and destination
+	points = append(points, model.Waypoint{Latitude: params.OriginLat, Longitude: params.OriginLon})
+	for _, node := range path.Nodes {
+		lat, lon := graph.Node(node)
+		points = append(points, model.Waypoint{Latitude: lat, Longitude: lon})
+	}
+	points = append(points, model.Waypoint{Latitude: params.DestinationLat, Longitude: params.DestinationLon})
+
+	var meters, seconds float64
+	for _, edge := range path.Edges {
+		meters += float64(edge.LengthMeters)
+		seconds += roadSeconds(float64(edge.LengthMeters), params.Mode.SpeedKmh(edge))
+	}
+
+	// The legs between the trip's ends and the road are walked.
+	firstLat, firstLon := graph.Node(path.Nodes[0])
+	lastLat, lastLon := graph.Node(path.Nodes[len(path.Nodes)-1])
+	access := model.DistanceMeters(params.OriginLat, params.OriginLon, firstLat, firstLon) +
+		model.DistanceMeters(lastLat, lastLon, params.DestinationLat, params.DestinationLon)
+	meters += access
+	seconds += roadSeconds(access, model.WalkingSpeedKmh)
+
+	for _, wp := range model.SimplifyWaypoints(points, routeSimplifyMeters) {
+		route.AddWaypoint(wp.Latitude, wp.Longitude, wp.Sequence)
+	}
+	route.DistanceKm = meters / 1000 //nolint:mnd // meters to km
+	route.EstimatedDuration = int(math.Ceil(seconds / time.Minute.Seconds()))
+	return route
+}
+
+// straightLineRoute follows the straight line between origin and destination,
+// with a waypoint every couple of kilometers, for when there is no road
+// network to route on.
+func straightLineRoute(params repository.RouteCalculationParams) *model.SafeRoute {
+	route := model.NewSafeRoute(params.OriginLat, params.OriginLon, params.DestinationLat, params.DestinationLon)
+	route.Mode = params.Mode
+
+	meters := model.DistanceMeters(params.OriginLat, params.OriginLon, params.DestinationLat, params.DestinationLon)
+	steps := min(int(meters/straightLineSpacingMeters), straightLineMaxWaypoints) + 1
+	for i := 0; i <= steps; i++ {
+		ratio := float64(i) / float64(steps)
+		route.AddWaypoint(
+			params.OriginLat+ratio*(params.DestinationLat-params.OriginLat),
+			params.OriginLon+ratio*(params.DestinationLon-params.OriginLon),
+			i,
+		)
+	}
+
+	route.DistanceKm = meters / 1000 //nolint:mnd // meters to km
+	route.EstimatedDuration = int(route.DistanceKm / params.Mode.StraightLineSpeedKmh() * time.Hour.Minutes())
+	return route
+}
+
+func roadSeconds(meters, speedKmh float64) float64 {
+	return meters / (speedKmh / 3.6) //nolint:mnd // km/h to m/s
+}
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanRoutes_RejectsTripsLongerThanTheLimit(t *testing.T) {
+	planner := NewSafeRoutePlanner(nil, nil, nil, nil, 150)
+
+	// Luanda to Lubango, about 700 km.
+	_, err := planner.PlanRoutes(context.Background(), repository.RouteCalculationParams{
+		OriginLat:      -8.8383,
+		OriginLon:      13.2344,
+		DestinationLat: -14.9177,
+		DestinationLon: 13.4925,
+		MaxRoutes:      3,
+	})
+
+	assert.ErrorIs(t, err, domainErrors.ErrInvalidRequest)
+}
@@ -13,12 +13,14 @@ import (
 
 type SettingsChecker interface {
 	CanReceiveNotifications(ctx context.Context, userID uuid.UUID, deviceID string) bool
-	CanReceiveAlerts(ctx context.Context, userID uuid.UUID, deviceID string, severity string, distance int) bool
-	CanReceiveReports(ctx context.Context, userID uuid.UUID, deviceID string, isVerified bool, distance int) bool
+	CanReceiveAlerts(ctx context.Context, userID uuid.UUID, deviceID string, severity string, riskTypeID, riskTopicID uuid.UUID, distance int) bool
+	CanReceiveReports(ctx context.Context, userID uuid.UUID, deviceID string, isVerified bool, riskTypeID, riskTopicID uuid.UUID, distance int) bool
 	CanShareLocation(ctx context.Context, userID uuid.UUID, deviceID string) bool
 	CanSaveLocationHistory(ctx context.Context, userID uuid.UUID, deviceID string) bool
 	ShouldShowOnline(ctx context.Context, userID uuid.UUID) bool
 	IsInHighRiskTime(ctx context.Context, userID uuid.UUID, deviceID string) bool
+	QuietHoursUntil(ctx context.Context, userID uuid.UUID, deviceID string) (time.Time, bool)
+	QuietHoursUntilAll(ctx context.Context, recipients []model.DeviceToken) []time.Time
 	HasDangerZonesEnabled(ctx context.Context, userID uuid.UUID, deviceID string) bool
 }
 
@@ -45,7 +47,7 @@ func (s *settingsChecker) CanReceiveNotifications(ctx context.Context, userID uu
 	return settings.NotificationsEnabled
 }
 
-func (s *settingsChecker) CanReceiveAlerts(ctx context.Context, userID uuid.UUID, deviceID string, severity string, distance int) bool {
+func (s *settingsChecker) CanReceiveAlerts(ctx context.Context, userID uuid.UUID, deviceID string, severity string, riskTypeID, riskTopicID uuid.UUID, distance int) bool {
 	settings, err := s.getSettings(ctx, userID, deviceID)
 	if err != nil || settings == nil {
 		return true
@@ -55,7 +57,8 @@ func (s *settingsChecker) CanReceiveAlerts(ctx context.Context, userID uuid.UUID
 		return false
 	}
 
-	if distance > settings.NotificationAlertRadiusMins {
+	radius, wanted := settings.NotificationRadiusFor(settings.NotificationAlertRadiusMins, riskTypeID, riskTopicID)
+	if !wanted || distance > radius {
 		return false
 	}
 
@@ -68,7 +71,7 @@ func (s *settingsChecker) CanReceiveAlerts(ctx context.Context, userID uuid.UUID
 	return false
 }
 
-func (s *settingsChecker) CanReceiveReports(ctx context.Context, userID uuid.UUID, deviceID string, isVerified bool, distance int) bool {
+func (s *settingsChecker) CanReceiveReports(ctx context.Context, userID uuid.UUID, deviceID string, isVerified bool, riskTypeID, riskTopicID uuid.UUID, distance int) bool {
 	settings, err := s.getSettings(ctx, userID, deviceID)
 	if err != nil || settings == nil {
 		return true
@@ -78,7 +81,8 @@ func (s *settingsChecker) CanReceiveReports(ctx context.Context, userID uuid.UUI
 		return false
 	}
 
-	if distance > settings.NotificationReportRadiusMins {
+	radius, wanted := settings.NotificationRadiusFor(settings.NotificationReportRadiusMins, riskTypeID, riskTopicID)
+	if !wanted || distance > radius {
 		return false
 	}
 
@@ -125,21 +129,70 @@ func (s *settingsChecker) IsInHighRiskTime(ctx context.Context, userID uuid.UUID
 		return false
 	}
 
-	if !settings.TimeBasedAlertsEnabled {
-		return false
+	return settings.IsInHighRiskWindow(time.Now())
+}
+
+// QuietHoursUntil returns when the user's night mode ends if they are
+// currently inside their quiet hours.
+func (s *settingsChecker) QuietHoursUntil(ctx context.Context, userID uuid.UUID, deviceID string) (time.Time, bool) {
+	settings, err := s.getSettings(ctx, userID, deviceID)
+	if err != nil || settings == nil {
+		return time.Time{}, false
 	}
 
-	now := time.Now().UTC()
-	currentTime := now.Format("15:04")
+	now := time.Now()
+	if !settings.IsInNightMode(now) {
+		return time.Time{}, false
+	}
 
-	startTime := settings.HighRiskStartTime.UTC().Format("15:04")
-	endTime := settings.HighRiskEndTime.UTC().Format("15:04")
+	return settings.NightModeEndsAt(now), true
+}
 
-	if startTime > endTime {
-		return currentTime >= startTime || currentTime <= endTime
+// QuietHoursUntilAll is QuietHoursUntil for a whole broadcast, loading the
+// night mode of every recipient in one query. A recipient outside quiet
+// hours gets the zero time.
+func (s *settingsChecker) QuietHoursUntilAll(ctx context.Context, recipients []model.DeviceToken) []time.Time {
+	releaseAt := make([]time.Time, len(recipients))
+
+	var userIDs []uuid.UUID
+	var deviceIDs []string
+	for _, recipient := range recipients {
+		switch {
+		case recipient.UserID != uuid.Nil:
+			userIDs = append(userIDs, recipient.UserID)
+		case recipient.DeviceID != "":
+			deviceIDs = append(deviceIDs, recipient.DeviceID)
+		}
 	}
 
-	return currentTime >= startTime && currentTime <= endTime
+	nightModes, err := s.settingsRepo.ListNightModes(ctx, userIDs, deviceIDs)
+	if err != nil {
+		slog.Error("failed to list night mode settings", "error", err)
+		return releaseAt
+	}
+
+	byUser := make(map[uuid.UUID]*model.SafetySettings, len(nightModes))
+	byDevice := make(map[string]*model.SafetySettings, len(nightModes))
+	for _, settings := range nightModes {
+		if settings.UserID != nil {
+			byUser[*settings.UserID] = settings
+		}
+		if settings.DeviceID != nil {
+			byDevice[*settings.DeviceID] = settings
+		}
+	}
+
+	now := time.Now()
+	for i, recipient := range recipients {
+		settings := byDevice[recipient.DeviceID]
+		if recipient.UserID != uuid.Nil {
+			settings = byUser[recipient.UserID]
+		}
+		if settings != nil && settings.IsInNightMode(now) {
+			releaseAt[i] = settings.NightModeEndsAt(now)
+		}
+	}
+	return releaseAt
 }
 
 func (s *settingsChecker) HasDangerZonesEnabled(ctx context.Context, userID uuid.UUID, deviceID string) bool {
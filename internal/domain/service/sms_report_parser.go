@@ -0,0 +1,242 @@
+package service
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+)
+
+const (
+	// Longest phrase, in words, tried when matching keywords and places.
+	smsMaxPhraseWords = 4
+	// Single words taken from topic names shorter than this are too generic
+	// ("de", "em", "via") to identify a topic on their own.
+	smsMinTopicWordLength = 4
+)
+
+// SMSReportParser turns free text such as "ASSALTO Cazenga rua 12" into a
+// risk type, topic and location. It is built once from the current risk
+// vocabulary and gazetteer and is safe for concurrent use.
+type SMSReportParser struct {
+	keywords      map[string]riskMatch
+	defaultTopics map[uuid.UUID]uuid.UUID
+	places        map[string][]model.Place
+}
+
+type riskMatch struct {
+	riskTypeID  uuid.UUID
+	riskTopicID uuid.UUID
+}
+
+// ParsedSMSReport is what could be understood from a message. Place is nil
+// when no known location was mentioned; Address holds the words that were
+// neither the risk nor the place, e.g. "rua 12".
+type ParsedSMSReport struct {
+	RiskTypeID  uuid.UUID
+	RiskTopicID uuid.UUID
+	Place       *model.Place
+	Address     string
+}
+
+func NewSMSReportParser(
+	riskTypes []model.RiskType,
+	riskTopics []model.RiskTopic,
+	keywords []model.RiskKeyword,
+	places []model.Place,
+) *SMSReportParser {
+	p := &SMSReportParser{
+		keywords:      make(map[string]riskMatch),
+		defaultTopics: make(map[uuid.UUID]uuid.UUID),
+		places:        make(map[string][]model.Place),
+	}
+
+	enabled := make(map[uuid.UUID]bool, len(riskTypes))
+	for _, rt := range riskTypes {
+		enabled[rt.ID] = rt.IsEnabled
+	}
+
+	p.indexTopics(riskTopics, enabled)
+
+	for _, rt := range riskTypes {
+		if rt.IsEnabled {
+			p.keywords[model.NormalizeText(rt.Name)] = riskMatch{riskTypeID: rt.ID}
+		}
+	}
+
+	// Curated keywords win over anything derived from names.
+	for _, kw := range keywords {
+		if !enabled[kw.RiskTypeID] {
+			continue
+		}
+		m := riskMatch{riskTypeID: kw.RiskTypeID}
+		if kw.RiskTopicID != nil {
+			m.riskTopicID = *kw.RiskTopicID
+		}
+		p.keywords[model.NormalizeText(kw.Keyword)] = m
+	}
+
+	for _, place := range places {
+		name := model.NormalizeText(place.Name)
+		p.places[name] = append(p.places[name], place)
+	}
+
+	return p
+}
+
+// indexTopics registers each topic's full name and its distinctive words.
+// A word shared by topics of the same type ("roubo") still identifies the
+// type; one shared across types is dropped.
+func (p *SMSReportParser) indexTopics(topics []model.RiskTopic, enabled map[uuid.UUID]bool) {
+	sorted := append([]model.RiskTopic(nil), topics...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	words := make(map[string]riskMatch)
+	ambiguous := make(map[string]bool)
+
+	for _, topic := range sorted {
+		if !enabled[topic.RiskTypeID] {
+			continue
+		}
+		if _, ok := p.defaultTopics[topic.RiskTypeID]; !ok {
+			p.defaultTopics[topic.RiskTypeID] = topic.ID
+		}
+
+		name := model.NormalizeText(topic.Name)
+		p.keywords[name] = riskMatch{riskTypeID: topic.RiskTypeID, riskTopicID: topic.ID}
+
+		for _, word := range strings.Fields(name) {
+			if len(word) < smsMinTopicWordLength || ambiguous[word] {
+				continue
+			}
+			prev, seen := words[word]
+			switch {
+			case !seen:
+				words[word] = riskMatch{riskTypeID: topic.RiskTypeID, riskTopicID: topic.ID}
+			case prev.riskTypeID == topic.RiskTypeID:
+				words[word] = riskMatch{riskTypeID: topic.RiskTypeID}
+			default:
+				delete(words, word)
+				ambiguous[word] = true
+			}
+		}
+	}
+
+	for word, m := range words {
+		if _, exists := p.keywords[word]; !exists {
+			p.keywords[word] = m
+		}
+	}
+}
+
+// Parse reports false when no risk could be recognised. senderProvince, if
+// known, breaks ties between places with the same name.
+func (p *SMSReportParser) Parse(body, senderProvince string) (ParsedSMSReport, bool) {
+	words := strings.Fields(model.NormalizeText(body))
+	used := make([]bool, len(words))
+
+	var parsed ParsedSMSReport
+
+	start, length, risk, ok := findPhrase(words, used, p.keywords)
+	if !ok {
+		return parsed, false
+	}
+	markUsed(used, start, length)
+
+	parsed.RiskTypeID = risk.riskTypeID
+	parsed.RiskTopicID = risk.riskTopicID
+	if parsed.RiskTopicID == uuid.Nil {
+		parsed.RiskTopicID = p.defaultTopics[risk.riskTypeID]
+	}
+
+	if start, length, place, ok := p.findPlace(words, used, senderProvince); ok {
+		markUsed(used, start, length)
+		parsed.Place = &place
+	}
+
+	var rest []string
+	for i, w := range words {
+		if !used[i] {
+			rest = append(rest, w)
+		}
+	}
+	parsed.Address = strings.Join(rest, " ")
+
+	return parsed, true
+}
+
+// findPhrase returns the earliest, then longest, run of unused words that is
+// a key of index.
+//
+//nolint:nonamedreturns // start and length are both ints
+func findPhrase[T any](words []string, used []bool, index map[string]T) (start, length int, value T, ok bool) {
+	for i := range words {
+		for n := min(smsMaxPhraseWords, len(words)-i); n > 0; n-- {
+			if anyUsed(used[i : i+n]) {
+				continue
+			}
+			if v, found := index[strings.Join(words[i:i+n], " ")]; found {
+				return i, n, v, true
+			}
+		}
+	}
+	return 0, 0, value, false
+}
+
+func anyUsed(used []bool) bool {
+	for _, u := range used {
+		if u {
+			return true
+		}
+	}
+	return false
+}
+
+func markUsed(used []bool, start, length int) {
+	for i := start; i < start+length; i++ {
+		used[i] = true
+	}
+}
+
+// findPlace picks the best place mentioned anywhere in the message: one in
+// the sender's province first, then the most specific kind, then the longest
+// name. "Viana Zango 2" therefore resolves to the Zango 2 neighbourhood.
+//
+//nolint:nonamedreturns // start and length are both ints
+func (p *SMSReportParser) findPlace(words []string, used []bool, senderProvince string) (start, length int, place model.Place, ok bool) {
+	bestRank := -1
+	for i := range words {
+		for n := min(smsMaxPhraseWords, len(words)-i); n > 0; n-- {
+			if anyUsed(used[i : i+n]) {
+				continue
+			}
+			candidates, found := p.places[strings.Join(words[i:i+n], " ")]
+			if !found {
+				continue
+			}
+			for _, c := range candidates {
+				rank := placeRank(c, senderProvince)*smsMaxPhraseWords + n
+				if rank > bestRank {
+					bestRank, start, length, place, ok = rank, i, n, c, true
+				}
+			}
+		}
+	}
+	return start, length, place, ok
+}
+
+func placeRank(pl model.Place, senderProvince string) int {
+	score := 0
+	if senderProvince != "" && model.NormalizeText(pl.Province) == model.NormalizeText(senderProvince) {
+		score += 10
+	}
+	switch pl.Kind {
+	case model.PlaceKindNeighborhood:
+		score += 2
+	case model.PlaceKindMunicipality:
+		score++
+	case model.PlaceKindProvince:
+	}
+	return score
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSMSReportParser_Parse(t *testing.T) {
+	crime := model.RiskType{ID: uuid.New(), Name: "crime", IsEnabled: true}
+	fire := model.RiskType{ID: uuid.New(), Name: "fire", IsEnabled: true}
+	assalto := model.RiskTopic{ID: uuid.New(), RiskTypeID: crime.ID, Name: "assalto_mao_armada"}
+	roubo := model.RiskTopic{ID: uuid.New(), RiskTypeID: crime.ID, Name: "roubo_residencia"}
+	incendio := model.RiskTopic{ID: uuid.New(), RiskTypeID: fire.ID, Name: "incendio_mercado"}
+
+	places := []model.Place{
+		{Name: "cazenga", Kind: model.PlaceKindMunicipality, Province: "Luanda", Municipality: "Cazenga"},
+		{Name: "viana", Kind: model.PlaceKindMunicipality, Province: "Luanda", Municipality: "Viana"},
+		{Name: "zango 2", Kind: model.PlaceKindNeighborhood, Province: "Luanda", Municipality: "Viana"},
+		{Name: "benguela", Kind: model.PlaceKindProvince, Province: "Benguela"},
+	}
+	keywords := []model.RiskKeyword{{Keyword: "fogo", RiskTypeID: fire.ID, RiskTopicID: &incendio.ID}}
+
+	parser := NewSMSReportParser(
+		[]model.RiskType{crime, fire},
+		[]model.RiskTopic{assalto, roubo, incendio},
+		keywords,
+		places,
+	)
+
+	parsed, ok := parser.Parse("ASSALTO Cazenga rua 12", "")
+	assert.True(t, ok)
+	assert.Equal(t, crime.ID, parsed.RiskTypeID)
+	assert.Equal(t, assalto.ID, parsed.RiskTopicID)
+	assert.Equal(t, "cazenga", parsed.Place.Name)
+	assert.Equal(t, "rua 12", parsed.Address)
+
+	// The more specific place wins and curated keywords are accent-insensitive.
+	parsed, ok = parser.Parse("Fôgo no mercado, Viana Zango 2", "")
+	assert.True(t, ok)
+	assert.Equal(t, incendio.ID, parsed.RiskTopicID)
+	assert.Equal(t, "zango 2", parsed.Place.Name)
+
+	// Without a place the sender's registered area is used by the caller.
+	parsed, ok = parser.Parse("roubo perto da escola", "Luanda")
+	assert.True(t, ok)
+	assert.Equal(t, crime.ID, parsed.RiskTypeID)
+	assert.Nil(t, parsed.Place)
+
+	_, ok = parser.Parse("ola bom dia", "")
+	assert.False(t, ok)
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	domainErrors "github.com/risk-place-angola/backend-risk-place/internal/domain/errors"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/event"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
+)
+
+// TransactionRunner runs fn in a transaction carried by its context.
+type TransactionRunner interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// EventOutbox writes an event within the transaction carried by ctx, if any.
+type EventOutbox interface {
+	Publish(ctx context.Context, event event.Event) error
+}
+
+// TripMonitorService follows active trips as location updates come in and
+// on a timer, publishing a TripCheckInRequestedEvent when a trip shows an
+// anomaly and a TripEscalatedEvent when the check-in goes unanswered. The
+// event commits together with the trip change that caused it.
+type TripMonitorService struct {
+	repo      repository.TripRepository
+	txManager TransactionRunner
+	outbox    EventOutbox
+}
+
+func NewTripMonitorService(repo repository.TripRepository, txManager TransactionRunner, outbox EventOutbox) *TripMonitorService {
+	return &TripMonitorService{
+		repo:      repo,
+		txManager: txManager,
+		outbox:    outbox,
+	}
+}
+
+// TrackLocation moves the user's active trip, if they have one, to their new
+// position.
+func (s *TripMonitorService) TrackLocation(ctx context.Context, userID uuid.UUID, lat, lon float64) {
+	trip, err := s.repo.FindActiveByUserID(ctx, userID)
+	if errors.Is(err, domainErrors.ErrTripNotFound) {
+		return
+	}
+	if err != nil {
+		slog.Debug("failed to find active trip", "error", err, "user_id", userID.String())
+		return
+	}
+
+	anomaly := trip.TrackLocation(lat, lon, time.Now())
+	if !s.save(ctx, trip, checkInRequest(trip, anomaly)) {
+		return
+	}
+	if trip.Status == model.TripStatusCompleted {
+		slog.Info("trip completed on arrival", "trip_id", trip.ID.String(), "user_id", userID.String())
+	}
+	logCheckIn(trip, anomaly)
+}
+
+// CheckTrips escalates the check-ins that went unanswered and looks for
+// travellers who stopped or are running late.
+func (s *TripMonitorService) CheckTrips(ctx context.Context) error {
+	trips, err := s.repo.ListActive(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list active trips: %w", err)
+	}
+
+	now := time.Now()
+	for _, trip := range trips {
+		if trip.CheckInOverdue(now) {
+			s.Escalate(ctx, trip, trip.CheckInReason, now)
+			continue
+		}
+
+		anomaly := trip.Check(now)
+		if anomaly == "" || !s.save(ctx, trip, checkInRequest(trip, anomaly)) {
+			continue
+		}
+		logCheckIn(trip, anomaly)
+	}
+	return nil
+}
+
+// Escalate ends the monitoring of trip and alerts the traveller's contacts.
+// It reports whether the trip was escalated, which it is not if it changed
+// meanwhile.
+func (s *TripMonitorService) Escalate(ctx context.Context, trip *model.Trip, reason model.TripAnomaly, now time.Time) bool {
+	trip.Escalate(reason, now)
+	escalated := event.TripEscalatedEvent{
+		TripID:    trip.ID,
+		UserID:    trip.UserID,
+		Anomaly:   string(reason),
+		Latitude:  trip.LastLatitude,
+		Longitude: trip.LastLongitude,
+	}
+	if !s.save(ctx, trip, escalated) {
+		return false
+	}
+
+	slog.Warn("trip escalated to emergency contacts",
+		"trip_id", trip.ID.String(),
+		"user_id", trip.UserID.String(),
+		"reason", reason)
+	return true
+}
+
+// checkInRequest returns the event asking the traveller to check in, or nil
+// when the trip shows no anomaly.
+func checkInRequest(trip *model.Trip, anomaly model.TripAnomaly) event.Event {
+	if anomaly == "" {
+		return nil
+	}
+	return event.TripCheckInRequestedEvent{
+		TripID:    trip.ID,
+		UserID:    trip.UserID,
+		Anomaly:   string(anomaly),
+		Latitude:  trip.LastLatitude,
+		Longitude: trip.LastLongitude,
+	}
+}
+
+func logCheckIn(trip *model.Trip, anomaly model.TripAnomaly) {
+	if anomaly == "" {
+		return
+	}
+	slog.Info("trip check-in requested",
+		"trip_id", trip.ID.String(),
+		"user_id", trip.UserID.String(),
+		"anomaly", anomaly)
+}
+
+// save reports whether the trip was saved, together with ev when it is not
+// nil, so the trip never leaves a state without the event that announces it.
+// One that changed meanwhile is left to whoever changed it; the next update
+// or check looks at it again.
+func (s *TripMonitorService) save(ctx context.Context, trip *model.Trip, ev event.Event) bool {
+	version := trip.Version
+	saved := false
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		ok, err := s.repo.Update(ctx, trip)
+		if err != nil || !ok {
+			return err
+		}
+		if ev != nil {
+			if err := s.outbox.Publish(ctx, ev); err != nil {
+				return err
+			}
+		}
+		saved = true
+		return nil
+	})
+	if err != nil {
+		// The update rolled back with the transaction.
+		trip.Version = version
+		slog.Error("failed to update trip", "error", err, "trip_id", trip.ID.String())
+		return false
+	}
+	return saved
+}
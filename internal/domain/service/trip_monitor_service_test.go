@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/event"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/model"
+	"github.com/risk-place-angola/backend-risk-place/internal/domain/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type versionedTripRepo struct {
+	repository.TripRepository
+}
+
+func (versionedTripRepo) Update(_ context.Context, trip *model.Trip) (bool, error) {
+	trip.Version++
+	return true, nil
+}
+
+// recordingTx commits what fn did only when it returns no error.
+type recordingTx struct {
+	committed bool
+}
+
+func (tx *recordingTx) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := fn(ctx); err != nil {
+		return err
+	}
+	tx.committed = true
+	return nil
+}
+
+type recordingOutbox struct {
+	err    error
+	events []event.Event
+}
+
+func (o *recordingOutbox) Publish(_ context.Context, ev event.Event) error {
+	if o.err != nil {
+		return o.err
+	}
+	o.events = append(o.events, ev)
+	return nil
+}
+
+func TestTripMonitor_EscalateCommitsWithItsEvent(t *testing.T) {
+	tests := []struct {
+		name          string
+		outboxErr     error
+		wantEscalated bool
+	}{
+		{name: "trip and event commit together", wantEscalated: true},
+		{name: "outbox failure rolls the trip back", outboxErr: errors.New("outbox down")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trip := &model.Trip{ID: uuid.New(), UserID: uuid.New(), Status: model.TripStatusActive, Version: 3}
+			tx := &recordingTx{}
+			outbox := &recordingOutbox{err: tt.outboxErr}
+			s := NewTripMonitorService(versionedTripRepo{}, tx, outbox)
+
+			escalated := s.Escalate(context.Background(), trip, model.TripAnomalyDistress, time.Now())
+
+			assert.Equal(t, tt.wantEscalated, escalated)
+			assert.Equal(t, tt.wantEscalated, tx.committed)
+			if tt.wantEscalated {
+				assert.Len(t, outbox.events, 1)
+				assert.Equal(t, event.TripEscalatedEvent{}.Name(), outbox.events[0].Name())
+				assert.Equal(t, 4, trip.Version)
+			} else {
+				assert.Empty(t, outbox.events)
+				assert.Equal(t, 3, trip.Version, "the version must match the rolled back row")
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package bootstrap
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 
 	"github.com/risk-place-angola/backend-risk-place/internal/adapter/cache"
@@ -11,6 +12,7 @@ import (
 	"github.com/risk-place-angola/backend-risk-place/internal/adapter/notifier"
 	"github.com/risk-place-angola/backend-risk-place/internal/adapter/repository/postgres"
 	"github.com/risk-place-angola/backend-risk-place/internal/adapter/repository/postgres/sqlc"
+	"github.com/risk-place-angola/backend-risk-place/internal/adapter/routing"
 	"github.com/risk-place-angola/backend-risk-place/internal/adapter/service"
 	"github.com/risk-place-angola/backend-risk-place/internal/adapter/websocket"
 	"github.com/risk-place-angola/backend-risk-place/internal/application"
@@ -47,13 +49,21 @@ type Container struct {
 	StorageHandler          *handler.StorageHandler
 	NearbyUsersHandler      *handler.NearbyUsersHandler
 	DangerZoneHandler       *handler.DangerZoneHandler
+	DigestHandler           *handler.DigestHandler
+	DeliveryMetricsHandler  *handler.DeliveryMetricsHandler
+	SMSInboundHandler       *handler.SMSInboundHandler
+	USSDHandler             *handler.USSDHandler
+	TripHandler             *handler.TripHandler
 
 	UserApp *application.Application
 
 	Hub                     *websocket.Hub
 	AuthMiddleware          *middleware.AuthMiddleware
 	OptionalAuthMiddleware  *middleware.OptionalAuthMiddleware
+	LocaleMiddleware        *middleware.LocaleMiddleware
 	AuthorizationMiddleware *middleware.AuthorizationMiddleware
+	TwilioSignature         *middleware.TwilioSignatureMiddleware
+	GatewayToken            *middleware.GatewayTokenMiddleware
 }
 
 func NewContainer() (*Container, error) {
@@ -93,6 +103,16 @@ func NewContainer() (*Container, error) {
 	migrationRepoPG := postgres.NewAnonymousMigrationRepository(database)
 	userLocationRepoPG := postgres.NewUserLocationRepository(database)
 	dangerZoneRepoPG := postgres.NewDangerZoneRepoPG(database)
+	manualDangerZoneRepoPG := postgres.NewManualDangerZoneRepoPG(database)
+	incidentWeightRuleRepoPG := postgres.NewIncidentWeightRuleRepoPG(database)
+	heldNotificationRepoPG := postgres.NewHeldNotificationRepository(database)
+	digestRepoPG := postgres.NewDigestRepository(database)
+	outboxRepoPG := postgres.NewOutboxRepository(database)
+	txManager := postgres.NewTxManager(database)
+	deviceTokenRepoPG := postgres.NewDeviceTokenRepository(database)
+	emailNotificationRepoPG := postgres.NewEmailNotificationRepository(database)
+	smsReportRepoPG := postgres.NewSMSReportRepository(database)
+	tripRepoPG := postgres.NewTripRepoPG(database)
 
 	emailService := notifier.NewSmtpEmailService(cfg)
 	tokenService := service.NewJwtTokenService(cfg)
@@ -105,7 +125,19 @@ func NewContainer() (*Container, error) {
 	const locationHistoryRetentionDays = 7
 	locationHistoryService := service.NewLocationHistoryService(locationHistoryCacheAdapter, true, locationHistoryRetentionDays)
 	settingsCheckerService := domainService.NewSettingsChecker(safetySettingsRepoPG, anonymousSessionRepoPG)
-	dangerZoneService := service.NewDangerZoneService(dangerZoneRepoPG, cacheAdapter)
+	incidentWeighting := service.NewIncidentWeightingService(incidentWeightRuleRepoPG)
+	dangerZoneService := service.NewDangerZoneService(dangerZoneRepoPG, manualDangerZoneRepoPG, incidentWeighting, cacheAdapter)
+
+	roadGraph := routing.NewOSMRoadGraph(cfg.OSMExtractPath)
+	roadGraph.LoadInBackground()
+	safeRoutePlanner := domainService.NewSafeRoutePlanner(roadGraph, safeRouteRepoPG, dangerZoneService, incidentWeighting, cfg.SafeRouteMaxKm)
+
+	dispatcher := event.NewEventDispatcher()
+	outboxDispatcher := service.NewOutboxDispatcher(outboxRepoPG, dispatcher)
+	outboxRelay := service.NewOutboxRelay(outboxRepoPG, dispatcher)
+	highRiskNudgeService := domainService.NewHighRiskNudgeService(reportRepoPG, dangerZoneService, cacheAdapter, outboxDispatcher)
+	dangerZoneWatchService := domainService.NewDangerZoneWatchService(dangerZoneService, cacheAdapter, outboxDispatcher)
+	tripMonitorService := domainService.NewTripMonitorService(tripRepoPG, txManager, outboxDispatcher)
 
 	nearbyUsersDomainService := domainService.NewNearbyUsersServiceV2(
 		userLocationRepoPG,
@@ -113,7 +145,9 @@ func NewContainer() (*Container, error) {
 		cacheAdapter,
 		locationHistoryService,
 		settingsCheckerService,
-		dangerZoneService,
+		dangerZoneWatchService,
+		highRiskNudgeService,
+		tripMonitorService,
 		true,
 	)
 	nearbyUsersService := service.NewNearbyUsersAdapter(nearbyUsersDomainService)
@@ -127,15 +161,21 @@ func NewContainer() (*Container, error) {
 		locationSharingRepoPG,
 	)
 
-	dispatcher := event.NewEventDispatcher()
-
 	hub := websocket.NewHub(locationStore, geoService, nearbyUsersService, settingsCheckerService)
 	go hub.Run()
 
-	notifierFCM := notifier.NewFCMNotifier(firebaseApp)
-	notifierSMS := notifier.NewSMSNotifier(twilioSMS, cfg.TwilioConfig)
+	deliveryMetrics := notifier.NewDeliveryMetrics()
+	notifierFCM := notifier.NewThrottledPushNotifier(
+		notifier.NewResilientPushNotifier(notifier.NewFCMNotifier(firebaseApp), deviceTokenRepoPG, deliveryMetrics),
+		notifier.NewRedisThrottleStore(rdb),
+		deviceTokenRepoPG,
+	)
+	notifierSMS := notifier.NewResilientSMSNotifier(notifier.NewSMSNotifier(twilioSMS, cfg.TwilioConfig), deliveryMetrics)
 
-	translationService := service.NewTranslationService()
+	translationService, err := service.NewTranslationService(cfg.TranslationsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load translations: %w", err)
+	}
 
 	verificationService := service.NewVerificationService(
 		rdb,
@@ -147,15 +187,45 @@ func NewContainer() (*Container, error) {
 	)
 	reportVerificationService := service.NewReportVerificationService(reportRepoPG)
 
+	unsubscribeSigner := service.NewUnsubscribeTokenService(cfg.EmailConfig.UnsubscribeSecret)
+	emailAlertService, err := service.NewEmailAlertService(emailService, translationService, unsubscribeSigner, cfg.APIPublicURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load email templates: %w", err)
+	}
+	notificationService := service.NewNotificationService(
+		translationService,
+		notifierFCM,
+		notifierSMS,
+		userRepoPG,
+		anonymousSessionRepoPG,
+		emailNotificationRepoPG,
+		emailAlertService,
+	)
+
 	eventlistener.RegisterEventListeners(
 		dispatcher,
 		hub,
 		userRepoPG,
 		anonymousSessionRepoPG,
 		safetySettingsRepoPG,
+		heldNotificationRepoPG,
+		emergencyContactRepoPG,
+		tripRepoPG,
 		notifierFCM,
 		notifierSMS,
 		translationService,
+		notificationService,
+	)
+
+	quietHoursDigestService := service.NewQuietHoursDigestService(heldNotificationRepoPG, notifierFCM, translationService)
+	periodicDigestService := service.NewPeriodicDigestService(
+		digestRepoPG,
+		userRepoPG,
+		userLocationRepoPG,
+		dangerZoneService,
+		notifierFCM,
+		emailService,
+		translationService,
 	)
 
 	userApp := application.NewUserApplication(
@@ -170,24 +240,40 @@ func NewContainer() (*Container, error) {
 		safeRouteRepoPG,
 		emergencyContactRepoPG,
 		safetySettingsRepoPG,
+		digestRepoPG,
+		emailNotificationRepoPG,
+		smsReportRepoPG,
+		manualDangerZoneRepoPG,
+		incidentWeightRuleRepoPG,
+		tripRepoPG,
 		tokenService,
 		hashService,
 		emailService,
 		notifierSMS,
+		translationService,
+		unsubscribeSigner,
+		cacheAdapter,
 		&cfg,
 		locationStore,
 		geoService,
-		dispatcher,
+		outboxDispatcher,
+		txManager,
 		migrationService,
 		verificationService,
 		storageService,
 		dangerZoneService,
+		safeRoutePlanner,
+		incidentWeighting,
+		tripMonitorService,
 	)
 
 	authzService := domainService.NewAuthorizationService(permissionRepoPG)
 	authMW := middleware.NewAuthMiddleware(cfg)
 	optionalAuthMW := middleware.NewOptionalAuthMiddleware(authMW)
+	localeMW := middleware.NewLocaleMiddleware(translationService, authMW, userRepoPG, anonymousSessionRepoPG)
 	authzMW := middleware.NewAuthorizationMiddleware(authzService)
+	twilioSignatureMW := middleware.NewTwilioSignatureMiddleware(cfg.TwilioConfig.AuthToken, cfg.APIPublicURL)
+	gatewayTokenMW := middleware.NewGatewayTokenMiddleware(cfg.USSDGatewayToken)
 
 	registerDeviceUC := device.NewRegisterDeviceUseCase(anonymousSessionRepoPG)
 	updateDeviceLocationUC := device.NewUpdateDeviceLocationUseCase(anonymousSessionRepoPG, locationStore)
@@ -211,16 +297,28 @@ func NewContainer() (*Container, error) {
 	storageHandler := handler.NewStorageHandler(storageService, userApp)
 	nearbyUsersHandler := handler.NewNearbyUsersHandler(nearbyUsersService)
 	dangerZoneHandler := handler.NewDangerZoneHandler(userApp)
+	digestHandler := handler.NewDigestHandler(userApp)
+	deliveryMetricsHandler := handler.NewDeliveryMetricsHandler(deliveryMetrics)
+	smsInboundHandler := handler.NewSMSInboundHandler(userApp)
+	ussdHandler := handler.NewUSSDHandler(userApp)
+	tripHandler := handler.NewTripHandler(userApp)
 
 	handler.StartCleanupJob(context.Background(), nearbyUsersService)
 	handler.StartDangerZoneCalculationJob(context.Background(), dangerZoneService)
+	handler.StartQuietHoursDigestJob(context.Background(), quietHoursDigestService)
+	handler.StartPeriodicDigestJob(context.Background(), periodicDigestService)
+	handler.StartOutboxRelayJob(context.Background(), outboxRelay)
+	handler.StartTripMonitorJob(context.Background(), tripMonitorService)
 
 	return &Container{
 		UserApp:                 userApp,
 		UserHandler:             userHandler,
 		AuthMiddleware:          authMW,
 		OptionalAuthMiddleware:  optionalAuthMW,
+		LocaleMiddleware:        localeMW,
 		AuthorizationMiddleware: authzMW,
+		TwilioSignature:         twilioSignatureMW,
+		GatewayToken:            gatewayTokenMW,
 		WSHandler:               wsHandler,
 		Hub:                     hub,
 		Cfg:                     &cfg,
@@ -237,5 +335,10 @@ func NewContainer() (*Container, error) {
 		StorageHandler:          storageHandler,
 		NearbyUsersHandler:      nearbyUsersHandler,
 		DangerZoneHandler:       dangerZoneHandler,
+		DigestHandler:           digestHandler,
+		DeliveryMetricsHandler:  deliveryMetricsHandler,
+		SMSInboundHandler:       smsInboundHandler,
+		USSDHandler:             ussdHandler,
+		TripHandler:             tripHandler,
 	}, nil
 }
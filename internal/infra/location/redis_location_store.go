@@ -33,6 +33,27 @@ func (s *RedisLocationStore) FindUsersInRadius(ctx context.Context, lat, lon flo
 	return users, nil
 }
 
+// FindUsersInRadiusWithDistance returns the users in the radius with their
+// distance from the point, in metres.
+func (s *RedisLocationStore) FindUsersInRadiusWithDistance(ctx context.Context, lat, lon float64, radiusMeters float64) ([]port.GeoResult, error) {
+	results, err := s.cache.GeoSearchWithDistance(ctx, "user_locations", lon, lat, radiusMeters)
+	if err != nil {
+		slog.Error("failed to find users with distance", "error", err)
+		return nil, err
+	}
+
+	geoResults := make([]port.GeoResult, len(results))
+	for i, r := range results {
+		geoResults[i] = port.GeoResult{
+			Member:   r.Member,
+			Distance: r.Distance,
+		}
+	}
+
+	slog.Info("found users in radius with distances", "count", len(geoResults))
+	return geoResults, nil
+}
+
 // UpdateReportLocation adiciona ou atualiza a localização de um report no Redis
 func (s *RedisLocationStore) UpdateReportLocation(ctx context.Context, reportID string, lat, lon float64) error {
 	return s.cache.GeoAdd(ctx, "report_locations", lon, lat, reportID)
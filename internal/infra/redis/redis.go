@@ -90,6 +90,12 @@ func (r *Redis) Set(ctx context.Context, key, value string, timer time.Duration)
 	return err
 }
 
+// SetNX stores a key-value pair only if the key does not exist yet, reporting
+// whether it was stored. The check and the write happen atomically in Redis.
+func (r *Redis) SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error) {
+	return r.client.SetNX(ctx, key, value, ttl).Result()
+}
+
 // HSet stores a key-value pair in the Redis cache with an optional expiration timer.
 // If the timer is less than or equal to zero, the key will persist indefinitely.
 //
@@ -220,3 +226,8 @@ func (r *Redis) ZRemRangeByScore(ctx context.Context, key string, minScore, maxS
 func (r *Redis) Expire(ctx context.Context, key string, ttl time.Duration) error {
 	return r.client.Expire(ctx, key, ttl).Err()
 }
+
+// RunScript runs a Lua script atomically, loading it on first use.
+func (r *Redis) RunScript(ctx context.Context, script string, keys []string, args ...any) (any, error) {
+	return redis.NewScript(script).Run(ctx, r.client, keys, args...).Result()
+}
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_held_notifications_fcm_token;
+DROP INDEX IF EXISTS idx_held_notifications_release_at;
+DROP TABLE IF EXISTS held_notifications;
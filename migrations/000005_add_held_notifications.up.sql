@@ -0,0 +1,17 @@
+-- Notifications deferred by night mode (quiet hours).
+-- Rows are released as a single morning digest per device once release_at passes.
+CREATE TABLE IF NOT EXISTS held_notifications (
+    id uuid DEFAULT gen_random_uuid() NOT NULL PRIMARY KEY,
+    user_id uuid REFERENCES users(id) ON DELETE CASCADE,
+    device_id text,
+    fcm_token text NOT NULL,
+    language text DEFAULT 'pt'::text NOT NULL,
+    event_key text NOT NULL,
+    risk_type text,
+    reference_id text,
+    release_at timestamp with time zone NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_held_notifications_release_at ON held_notifications(release_at);
+CREATE INDEX IF NOT EXISTS idx_held_notifications_fcm_token ON held_notifications(fcm_token);
@@ -0,0 +1,18 @@
+-- Opt-in periodic (daily/weekly) safety digests for authenticated users.
+-- known_danger_zones keeps the grid cells sent in the last digest so the next
+-- one can report new and cleared zones.
+CREATE TABLE IF NOT EXISTS digest_subscriptions (
+    user_id uuid NOT NULL PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    frequency text DEFAULT 'off'::text NOT NULL,
+    push_enabled boolean DEFAULT true NOT NULL,
+    email_enabled boolean DEFAULT false NOT NULL,
+    last_sent_at timestamp with time zone,
+    known_danger_zones text[] DEFAULT '{}'::text[] NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT digest_subscriptions_frequency_check CHECK (frequency IN ('off', 'daily', 'weekly'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_digest_subscriptions_due
+    ON digest_subscriptions(frequency, last_sent_at)
+    WHERE frequency <> 'off';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_outbox_dead_letters_event_name;
+DROP TABLE IF EXISTS outbox_dead_letters;
+DROP INDEX IF EXISTS idx_outbox_events_next_attempt_at;
+DROP TABLE IF EXISTS outbox_events;
@@ -0,0 +1,27 @@
+-- Transactional outbox for domain events.
+-- Events are written in the same transaction as the change that raised them
+-- and delivered at least once by the relay worker.
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id uuid DEFAULT gen_random_uuid() NOT NULL PRIMARY KEY,
+    event_name text NOT NULL,
+    payload jsonb NOT NULL,
+    attempts integer DEFAULT 0 NOT NULL,
+    next_attempt_at timestamp with time zone DEFAULT now() NOT NULL,
+    last_error text,
+    created_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_events_next_attempt_at ON outbox_events(next_attempt_at);
+
+-- Events that exhausted their delivery attempts.
+CREATE TABLE IF NOT EXISTS outbox_dead_letters (
+    id uuid NOT NULL PRIMARY KEY,
+    event_name text NOT NULL,
+    payload jsonb NOT NULL,
+    attempts integer NOT NULL,
+    last_error text,
+    created_at timestamp with time zone NOT NULL,
+    failed_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_dead_letters_event_name ON outbox_dead_letters(event_name);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_users_email_notifications;
+ALTER TABLE users DROP COLUMN IF EXISTS email_notification_enabled;
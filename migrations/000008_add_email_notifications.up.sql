@@ -0,0 +1,6 @@
+-- Email as an alert channel. Off by default; users opt in from their
+-- notification preferences and can leave through a signed unsubscribe link.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_notification_enabled boolean DEFAULT false NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_users_email_notifications ON users(id)
+    WHERE email_notification_enabled = true AND deleted_at IS NULL;
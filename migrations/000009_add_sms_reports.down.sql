@@ -0,0 +1,5 @@
+DELETE FROM users WHERE id = '5e5e0000-0000-4000-8000-000000000001'
+    AND NOT EXISTS (SELECT 1 FROM reports WHERE user_id = '5e5e0000-0000-4000-8000-000000000001');
+DROP TABLE IF EXISTS places;
+DROP TABLE IF EXISTS risk_keywords;
+DROP TABLE IF EXISTS sms_reports;
@@ -0,0 +1,102 @@
+-- Inbound SMS reporting for feature phones.
+--
+-- sms_reports keeps every inbound message, accepted or not, so moderators can
+-- trace a report back to the sender and so provider retries are idempotent.
+CREATE TABLE IF NOT EXISTS sms_reports (
+    id uuid DEFAULT gen_random_uuid() NOT NULL PRIMARY KEY,
+    reference_code text NOT NULL UNIQUE,
+    provider_message_id text UNIQUE,
+    phone text NOT NULL,
+    body text NOT NULL,
+    user_id uuid REFERENCES users(id) ON DELETE SET NULL,
+    report_id uuid REFERENCES reports(id) ON DELETE SET NULL,
+    status text NOT NULL,
+    rejection_reason text,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT sms_reports_status_check CHECK (status IN ('created', 'rejected'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_sms_reports_phone_created ON sms_reports(phone, created_at DESC);
+
+-- Synonyms people actually type ("assalto", "fogo", "cheia") mapped onto risk
+-- types and, optionally, a specific topic. Keywords are stored lower-case
+-- without accents.
+CREATE TABLE IF NOT EXISTS risk_keywords (
+    id uuid DEFAULT gen_random_uuid() NOT NULL PRIMARY KEY,
+    keyword text NOT NULL UNIQUE,
+    risk_type_id uuid NOT NULL REFERENCES risk_types(id) ON DELETE CASCADE,
+    risk_topic_id uuid REFERENCES risk_topics(id) ON DELETE CASCADE,
+    created_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+-- Gazetteer used to turn "Cazenga" or "Viana Zango 2" into coordinates.
+-- Names are stored lower-case without accents.
+CREATE TABLE IF NOT EXISTS places (
+    id uuid DEFAULT gen_random_uuid() NOT NULL PRIMARY KEY,
+    name text NOT NULL,
+    kind text NOT NULL,
+    province text NOT NULL,
+    municipality text,
+    latitude double precision NOT NULL,
+    longitude double precision NOT NULL,
+    CONSTRAINT places_kind_check CHECK (kind IN ('province', 'municipality', 'neighborhood')),
+    CONSTRAINT places_name_kind_province_key UNIQUE (name, kind, province)
+);
+
+INSERT INTO places (name, kind, province, municipality, latitude, longitude) VALUES
+    ('luanda', 'province', 'Luanda', NULL, -8.838333, 13.234444),
+    ('bengo', 'province', 'Bengo', NULL, -8.580000, 13.660000),
+    ('benguela', 'province', 'Benguela', NULL, -12.578300, 13.407200),
+    ('huambo', 'province', 'Huambo', NULL, -12.776100, 15.739200),
+    ('huila', 'province', 'Huíla', NULL, -14.917200, 13.492500),
+    ('bie', 'province', 'Bié', NULL, -12.383300, 16.933300),
+    ('malanje', 'province', 'Malanje', NULL, -9.540000, 16.341000),
+    ('uige', 'province', 'Uíge', NULL, -7.608700, 15.061300),
+    ('cabinda', 'province', 'Cabinda', NULL, -5.550000, 12.200000),
+    ('zaire', 'province', 'Zaire', NULL, -6.266700, 14.250000),
+    ('cuanza norte', 'province', 'Cuanza Norte', NULL, -9.297800, 14.911700),
+    ('cuanza sul', 'province', 'Cuanza Sul', NULL, -11.205000, 13.843100),
+    ('namibe', 'province', 'Namibe', NULL, -15.196100, 12.152200),
+    ('cunene', 'province', 'Cunene', NULL, -17.066700, 15.733300),
+    ('moxico', 'province', 'Moxico', NULL, -11.783300, 19.916700),
+    ('lunda norte', 'province', 'Lunda Norte', NULL, -7.383300, 20.833300),
+    ('lunda sul', 'province', 'Lunda Sul', NULL, -9.660800, 20.391600),
+    ('cuando cubango', 'province', 'Cuando Cubango', NULL, -14.658500, 17.691000),
+    ('belas', 'municipality', 'Luanda', 'Belas', -8.999700, 13.266000),
+    ('cacuaco', 'municipality', 'Luanda', 'Cacuaco', -8.776700, 13.366700),
+    ('cazenga', 'municipality', 'Luanda', 'Cazenga', -8.823300, 13.286100),
+    ('icolo e bengo', 'municipality', 'Luanda', 'Icolo e Bengo', -9.083300, 13.733300),
+    ('kilamba kiaxi', 'municipality', 'Luanda', 'Kilamba Kiaxi', -8.893000, 13.250000),
+    ('quilamba quiaxi', 'municipality', 'Luanda', 'Kilamba Kiaxi', -8.893000, 13.250000),
+    ('quissama', 'municipality', 'Luanda', 'Quiçama', -9.733300, 13.816700),
+    ('talatona', 'municipality', 'Luanda', 'Talatona', -8.916700, 13.183300),
+    ('viana', 'municipality', 'Luanda', 'Viana', -8.903500, 13.374700),
+    ('sambizanga', 'municipality', 'Luanda', 'Sambizanga', -8.803900, 13.247900),
+    ('rangel', 'municipality', 'Luanda', 'Rangel', -8.830600, 13.258300),
+    ('maianga', 'municipality', 'Luanda', 'Maianga', -8.830000, 13.230000),
+    ('ingombota', 'municipality', 'Luanda', 'Ingombota', -8.815000, 13.232000),
+    ('samba', 'municipality', 'Luanda', 'Samba', -8.855000, 13.215000),
+    ('hoji ya henda', 'neighborhood', 'Luanda', 'Cazenga', -8.815000, 13.292000),
+    ('tala hady', 'neighborhood', 'Luanda', 'Cazenga', -8.827000, 13.297000),
+    ('cuca', 'neighborhood', 'Luanda', 'Cazenga', -8.813000, 13.280000),
+    ('zango', 'neighborhood', 'Luanda', 'Viana', -8.940000, 13.420000),
+    ('zango 2', 'neighborhood', 'Luanda', 'Viana', -8.960000, 13.450000),
+    ('estalagem', 'neighborhood', 'Luanda', 'Viana', -8.890000, 13.340000),
+    ('benfica', 'neighborhood', 'Luanda', 'Talatona', -8.950000, 13.160000),
+    ('gamek', 'neighborhood', 'Luanda', 'Talatona', -8.870000, 13.220000),
+    ('morro bento', 'neighborhood', 'Luanda', 'Samba', -8.890000, 13.190000),
+    ('sequele', 'neighborhood', 'Luanda', 'Cacuaco', -8.830000, 13.430000),
+    ('palanca', 'neighborhood', 'Luanda', 'Kilamba Kiaxi', -8.880000, 13.265000),
+    ('golfe', 'neighborhood', 'Luanda', 'Kilamba Kiaxi', -8.870000, 13.250000),
+    ('mutamba', 'neighborhood', 'Luanda', 'Ingombota', -8.814000, 13.234000),
+    ('marcal', 'neighborhood', 'Luanda', 'Rangel', -8.830000, 13.260000),
+    ('prenda', 'neighborhood', 'Luanda', 'Maianga', -8.845000, 13.230000),
+    ('kilamba', 'neighborhood', 'Luanda', 'Belas', -8.995000, 13.275000)
+ON CONFLICT (name, kind, province) DO NOTHING;
+
+-- Reports need an author. Messages from numbers that are not linked to an
+-- account are filed under this system user; the sender's number is kept in
+-- sms_reports. The password is not a valid bcrypt hash, so it cannot log in.
+INSERT INTO users (id, name, email, password, email_verified, country)
+VALUES ('5e5e0000-0000-4000-8000-000000000001', 'SMS Gateway', 'sms-gateway@riskplace.invalid', '!', false, 'Angola')
+ON CONFLICT (id) DO NOTHING;
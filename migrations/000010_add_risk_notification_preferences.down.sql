@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_notification_risk_preferences_lookup;
+DROP INDEX IF EXISTS idx_notification_risk_preferences_unique;
+DROP TABLE IF EXISTS notification_risk_preferences;
@@ -0,0 +1,21 @@
+-- Per risk type and per topic notification preferences. A row without a
+-- topic applies to the whole risk type; a row with a topic overrides it for
+-- that topic only. radius_meters, when set, replaces the general alert and
+-- report radius of the owning settings.
+CREATE TABLE IF NOT EXISTS notification_risk_preferences (
+    id uuid DEFAULT gen_random_uuid() NOT NULL PRIMARY KEY,
+    settings_id uuid NOT NULL REFERENCES user_safety_settings(id) ON DELETE CASCADE,
+    risk_type_id uuid NOT NULL REFERENCES risk_types(id) ON DELETE CASCADE,
+    risk_topic_id uuid REFERENCES risk_topics(id) ON DELETE CASCADE,
+    enabled boolean NOT NULL,
+    radius_meters integer,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT notification_risk_preferences_radius_check CHECK (radius_meters IS NULL OR radius_meters BETWEEN 100 AND 10000)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notification_risk_preferences_unique
+    ON notification_risk_preferences (settings_id, risk_type_id, COALESCE(risk_topic_id, '00000000-0000-0000-0000-000000000000'::uuid));
+
+CREATE INDEX IF NOT EXISTS idx_notification_risk_preferences_lookup
+    ON notification_risk_preferences (settings_id, risk_type_id);
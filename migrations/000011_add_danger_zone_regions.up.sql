@@ -0,0 +1,33 @@
+-- Danger zones are computed on a geohash grid at several precisions. Each
+-- region sets which precisions it uses and how many incidents make a cell a
+-- zone at each of them, so dense bairros can use small cells while sparse
+-- rural roads still surface zones on coarse ones. A region without bounds is
+-- the fallback for everything no other region covers; among overlapping
+-- regions the highest priority wins.
+CREATE TABLE IF NOT EXISTS danger_zone_regions (
+    id uuid DEFAULT gen_random_uuid() NOT NULL PRIMARY KEY,
+    name text NOT NULL UNIQUE,
+    min_lat double precision,
+    min_lon double precision,
+    max_lat double precision,
+    max_lon double precision,
+    priority integer DEFAULT 0 NOT NULL,
+    days_back integer DEFAULT 30 NOT NULL,
+    -- [{"precision": 6, "min_incidents": 5}, ...]
+    levels jsonb NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT danger_zone_regions_bounds_check CHECK (
+        (min_lat IS NULL AND min_lon IS NULL AND max_lat IS NULL AND max_lon IS NULL) OR
+        (min_lat IS NOT NULL AND min_lon IS NOT NULL AND max_lat IS NOT NULL AND max_lon IS NOT NULL
+            AND min_lat < max_lat AND min_lon < max_lon)
+    ),
+    CONSTRAINT danger_zone_regions_days_back_check CHECK (days_back BETWEEN 1 AND 365)
+);
+
+INSERT INTO danger_zone_regions (name, min_lat, min_lon, max_lat, max_lon, priority, days_back, levels) VALUES
+    ('angola', NULL, NULL, NULL, NULL, 0, 60,
+        '[{"precision": 4, "min_incidents": 8}, {"precision": 5, "min_incidents": 4}, {"precision": 6, "min_incidents": 3}]'),
+    ('luanda', -9.25, 12.95, -8.75, 13.55, 10, 30,
+        '[{"precision": 5, "min_incidents": 15}, {"precision": 6, "min_incidents": 5}, {"precision": 7, "min_incidents": 3}]')
+ON CONFLICT (name) DO NOTHING;
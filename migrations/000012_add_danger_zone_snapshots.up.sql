@@ -0,0 +1,19 @@
+-- Every danger zone calculation run is kept, one row per zone, so the API can
+-- show whether an area is getting safer or worse. A cell missing from a run
+-- was below its zone threshold at that time.
+CREATE TABLE IF NOT EXISTS danger_zone_snapshots (
+    id uuid DEFAULT gen_random_uuid() NOT NULL PRIMARY KEY,
+    run_id uuid NOT NULL,
+    grid_cell_id text NOT NULL,
+    precision smallint NOT NULL,
+    cell_lat double precision NOT NULL,
+    cell_lon double precision NOT NULL,
+    incident_count integer NOT NULL,
+    risk_score double precision NOT NULL,
+    risk_level text NOT NULL,
+    calculated_at timestamp with time zone NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_danger_zone_snapshots_cell ON danger_zone_snapshots (grid_cell_id, calculated_at);
+CREATE INDEX IF NOT EXISTS idx_danger_zone_snapshots_area ON danger_zone_snapshots (precision, calculated_at, cell_lat, cell_lon);
+CREATE INDEX IF NOT EXISTS idx_danger_zone_snapshots_calculated_at ON danger_zone_snapshots (calculated_at);
@@ -0,0 +1,24 @@
+-- Danger zones drawn by police or civil protection, e.g. a flood plain during
+-- the rainy season, shown next to the zones computed from reports. The
+-- polygon is a JSON array of {"latitude", "longitude"} vertices; its bounding
+-- box is stored alongside for area queries.
+CREATE TABLE IF NOT EXISTS manual_danger_zones (
+    id uuid DEFAULT gen_random_uuid() NOT NULL PRIMARY KEY,
+    polygon jsonb NOT NULL,
+    min_lat double precision NOT NULL,
+    min_lon double precision NOT NULL,
+    max_lat double precision NOT NULL,
+    max_lon double precision NOT NULL,
+    risk_level text NOT NULL,
+    reason text NOT NULL,
+    source text NOT NULL,
+    valid_from timestamp with time zone NOT NULL,
+    valid_until timestamp with time zone,
+    created_by uuid REFERENCES users(id) ON DELETE SET NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT manual_danger_zones_risk_level_check CHECK (risk_level IN ('low', 'medium', 'high', 'critical')),
+    CONSTRAINT manual_danger_zones_validity_check CHECK (valid_until IS NULL OR valid_until > valid_from)
+);
+
+CREATE INDEX IF NOT EXISTS idx_manual_danger_zones_valid_until ON manual_danger_zones (valid_until);
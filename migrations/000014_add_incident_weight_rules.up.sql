@@ -0,0 +1,20 @@
+-- How heavily incidents count towards safe routes, heatmaps and danger zones,
+-- per risk type or per topic. A topic's rule takes precedence over its
+-- type's; types without a rule use the built-in defaults.
+CREATE TABLE IF NOT EXISTS incident_weight_rules (
+    id uuid DEFAULT gen_random_uuid() NOT NULL PRIMARY KEY,
+    risk_type_id uuid NOT NULL REFERENCES risk_types(id) ON DELETE CASCADE,
+    risk_topic_id uuid REFERENCES risk_topics(id) ON DELETE CASCADE,
+    severity double precision NOT NULL,
+    half_life_days double precision NOT NULL,
+    proximity_falloff_meters double precision NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT incident_weight_rules_severity_check CHECK (severity > 0 AND severity <= 10),
+    CONSTRAINT incident_weight_rules_half_life_check CHECK (half_life_days > 0),
+    CONSTRAINT incident_weight_rules_falloff_check CHECK (proximity_falloff_meters >= 0)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_incident_weight_rules_type
+    ON incident_weight_rules (risk_type_id) WHERE risk_topic_id IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_incident_weight_rules_topic
+    ON incident_weight_rules (risk_topic_id) WHERE risk_topic_id IS NOT NULL;
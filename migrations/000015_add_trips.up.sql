@@ -0,0 +1,30 @@
+-- Trips followed along a safe route from the traveller's location updates.
+-- The route is a JSON array of {"latitude", "longitude"} points. version is
+-- bumped on every update so two instances cannot both act on the same
+-- check-in.
+CREATE TABLE IF NOT EXISTS trips (
+    id uuid DEFAULT gen_random_uuid() NOT NULL PRIMARY KEY,
+    user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status text NOT NULL,
+    mode text NOT NULL,
+    route jsonb NOT NULL,
+    started_at timestamp with time zone NOT NULL,
+    expected_arrival_at timestamp with time zone NOT NULL,
+    ended_at timestamp with time zone,
+    last_latitude double precision NOT NULL,
+    last_longitude double precision NOT NULL,
+    last_location_at timestamp with time zone NOT NULL,
+    stop_latitude double precision NOT NULL,
+    stop_longitude double precision NOT NULL,
+    stopped_since timestamp with time zone NOT NULL,
+    check_in_reason text NOT NULL DEFAULT '',
+    check_in_requested_at timestamp with time zone,
+    quiet_until timestamp with time zone NOT NULL DEFAULT 'epoch',
+    escalated_at timestamp with time zone,
+    version integer NOT NULL DEFAULT 0,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT trips_status_check CHECK (status IN ('active', 'completed', 'cancelled', 'escalated'))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_trips_active_user ON trips (user_id) WHERE status = 'active';
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_held_notifications_token_event_reference;
+ALTER TABLE outbox_dead_letters DROP COLUMN IF EXISTS delivered_handlers;
+ALTER TABLE outbox_events DROP COLUMN IF EXISTS delivered_handlers;
@@ -0,0 +1,15 @@
+-- Handlers that already handled an outbox event, so a retry only runs the
+-- ones that failed instead of repeating pushes, SMS and emails.
+ALTER TABLE outbox_events ADD COLUMN IF NOT EXISTS delivered_handlers text[] DEFAULT '{}'::text[] NOT NULL;
+ALTER TABLE outbox_dead_letters ADD COLUMN IF NOT EXISTS delivered_handlers text[] DEFAULT '{}'::text[] NOT NULL;
+
+-- A retried broadcast holds each notification once per device.
+DELETE FROM held_notifications a
+USING held_notifications b
+WHERE a.fcm_token = b.fcm_token
+  AND a.event_key = b.event_key
+  AND a.reference_id = b.reference_id
+  AND a.id > b.id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_held_notifications_token_event_reference
+    ON held_notifications(fcm_token, event_key, reference_id);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS danger_zone_explanations;
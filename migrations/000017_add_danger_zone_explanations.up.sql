@@ -0,0 +1,15 @@
+-- What each danger zone calculation run saw for every zone it produced: the
+-- reports behind the zone and the weight each added. All rows of a run share
+-- its start time, so the explanation in effect at any moment is the one of
+-- the latest run started before it.
+CREATE TABLE IF NOT EXISTS danger_zone_explanations (
+    id uuid DEFAULT gen_random_uuid() NOT NULL PRIMARY KEY,
+    run_id uuid NOT NULL,
+    grid_cell_id text NOT NULL,
+    precision smallint NOT NULL,
+    explanation jsonb NOT NULL,
+    calculated_at timestamp with time zone NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_danger_zone_explanations_run_cell ON danger_zone_explanations (run_id, grid_cell_id);
+CREATE INDEX IF NOT EXISTS idx_danger_zone_explanations_calculated_at ON danger_zone_explanations (calculated_at);
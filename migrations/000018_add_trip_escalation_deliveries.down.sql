@@ -0,0 +1 @@
+DROP TABLE IF EXISTS trip_escalation_deliveries;
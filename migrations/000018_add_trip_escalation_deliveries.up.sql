@@ -0,0 +1,8 @@
+-- Emergency contacts that got the SMS of an escalated trip, so retrying the
+-- escalation only texts the ones that have not.
+CREATE TABLE IF NOT EXISTS trip_escalation_deliveries (
+    trip_id uuid NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+    contact_id uuid NOT NULL REFERENCES emergency_contacts(id) ON DELETE CASCADE,
+    delivered_at timestamp with time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (trip_id, contact_id)
+);